@@ -1,15 +1,60 @@
 package main
 
 import (
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
 
 	"bibliography/src/cmd/bib/addcmd"
+	"bibliography/src/internal/sanitize"
+	"bibliography/src/internal/schema"
 )
 
 // newAddCmd constructs the root "add" command grouping subcommands for each type.
 func newAddCmd() *cobra.Command {
-	cmd := &cobra.Command{Use: "add", Short: "Add annotated citations via providers (OpenLibrary/DOI; OpenAI only for article URL fallbacks)"}
-	b := addcmd.New(commitAndPush)
+	var batchFile string
+	var normalizeUnicode, autoKeywords, force, dryRun, strict, noCommit bool
+	var maxAuthors, workers int
+	var commitTemplate string
+	b := addcmd.New(optionalCommit(&noCommit))
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add annotated citations via providers (OpenLibrary/DOI; OpenAI only for article URL fallbacks)",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			sanitize.SetNormalizeUnicode(normalizeUnicode)
+			addcmd.SetAutoKeywords(autoKeywords)
+			if !cmd.Flags().Changed("max-authors-stored") {
+				maxAuthors = addcmd.MaxAuthorsStoredDefault()
+			}
+			addcmd.SetMaxAuthorsStored(maxAuthors)
+			addcmd.SetForce(force)
+			addcmd.SetDryRun(dryRun)
+			addcmd.SetStrict(strict)
+			addcmd.SetCommitTemplate(commitTemplate)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(batchFile) == "" {
+				return cmd.Help()
+			}
+			f, err := os.Open(batchFile)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			return b.Batch(cmd, f, workers)
+		},
+	}
+	cmd.PersistentFlags().BoolVar(&normalizeUnicode, "normalize-unicode", false, "Fold smart quotes, dashes, and ligatures and compose accented letters in added entries")
+	cmd.PersistentFlags().BoolVar(&autoKeywords, "auto-keywords", false, "Merge generated keywords into added entries (OpenAI when configured, else local title/summary extraction)")
+	cmd.PersistentFlags().IntVar(&maxAuthors, "max-authors-stored", schema.DefaultMaxAuthorsStored, "Keep at most N authors from provider metadata, recording the full count (0 = no cap; default $BIB_MAX_AUTHORS_STORED, else 25)")
+	cmd.PersistentFlags().BoolVar(&force, "force", false, "Update the existing entry when the DOI or ISBN is already in the library (default: refuse as a duplicate)")
+	cmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Fetch and build the entry and print it as YAML without writing the library or committing")
+	cmd.PersistentFlags().BoolVar(&strict, "strict", false, "Fail when an added entry misses a field its type needs or should have (a standard must name its standards body)")
+	cmd.PersistentFlags().StringVar(&commitTemplate, "commit-template", "", "Commit message for added entries with {id}, {type}, and {title} placeholders (default $BIB_COMMIT_TEMPLATE, else \"add citation: {id}\")")
+	cmd.PersistentFlags().BoolVar(&noCommit, "no-commit", false, noCommitHelp)
+	cmd.Flags().StringVar(&batchFile, "batch-file", "", "Add many items from a file of type<TAB>identifier-or-url lines")
+	addcmd.AddConcurrencyFlag(cmd, &workers)
 	cmd.AddCommand(
 		b.Site(),
 		b.Book(),
@@ -19,6 +64,11 @@ func newAddCmd() *cobra.Command {
 		b.Video(),
 		b.Patent(),
 		b.RFC(),
+		b.Standard(),
+		b.Dataset(),
+		b.Software(),
+		b.Podcast(),
+		b.BatchFile(),
 	)
 	return cmd
 }
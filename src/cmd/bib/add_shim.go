@@ -17,5 +17,5 @@ func getEnv(key, def string) string {
 
 // doAdd is a convenience wrapper used in tests; delegates to addcmd implementation.
 func doAdd(ctx context.Context, typ string, hints map[string]string) error {
-	return addcmd.AddWithKeywords(ctx, commitAndPush, typ, hints, nil)
+	return addcmd.AddWithKeywords(ctx, optionalCommit(new(bool)), typ, hints, nil)
 }
@@ -7,18 +7,29 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"bibliography/src/internal/arxiv"
 	"bibliography/src/internal/booksearch"
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/datacite"
 	"bibliography/src/internal/dates"
 	"bibliography/src/internal/doi"
+	"bibliography/src/internal/isbn"
 	moviefetch "bibliography/src/internal/movie"
+	"bibliography/src/internal/pages"
+	"bibliography/src/internal/podcast"
+	"bibliography/src/internal/pubmed"
 	rfcpkg "bibliography/src/internal/rfc"
+	"bibliography/src/internal/sanitize"
 	"bibliography/src/internal/schema"
 	songfetch "bibliography/src/internal/song"
 	"bibliography/src/internal/store"
+	"bibliography/src/internal/stringsx"
 	"bibliography/src/internal/summarize"
 	youtube "bibliography/src/internal/video"
 	"bibliography/src/internal/webfetch"
@@ -40,8 +51,10 @@ func AddWithKeywords(ctx context.Context, commit CommitFunc, typ string, hints m
 
 const (
 	msgCommaDelimitedKeywords = "comma-delimited keywords to set on the entry"
+	msgDateFlag               = "date as YYYY, YYYY-MM, or YYYY-MM-DD (also \"May 1999\", \"1999/05/03\")"
 	msgWrote                  = "wrote %s\n"
 	msgAddCitation            = "add citation: %s"
+	msgUpdateCitation         = "update citation: %s"
 )
 
 // Site returns the "add site" subcommand.
@@ -53,28 +66,44 @@ func (b Builder) Site() *cobra.Command {
 		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) >= 1 && strings.TrimSpace(args[0]) != "" {
-				store.SetWriteSource("web")
+				setWriteSource(cmd, "web")
 				thisUrl := args[0]
 				return doAddWithKeywords(cmd.Context(), b.Commit, "website", map[string]string{"url": thisUrl}, parseKeywordsCSV(siteKeywords))
 			}
-			store.SetWriteSource("manual")
+			setWriteSource(cmd, "manual")
 			return manualAdd(cmd, b.Commit, "website", parseKeywordsCSV(siteKeywords))
 		},
 	}
-	c.Flags().StringVar(&siteKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	addKeywordsFlag(c, &siteKeywords)
 	return c
 }
 
 // Book returns the "add book" subcommand.
 func (b Builder) Book() *cobra.Command {
-	var bookName, bookAuthor, bookISBN, bookKeywords string
-	var bookLookup bool
+	var bookName, bookAuthor, bookISBN, bookDate, bookKeywords, bookFormat, bookNarrator string
+	var bookLookup, bookAudio bool
 	c := &cobra.Command{
 		Use:   "book",
 		Short: "Add a book (flags or manual entry)",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if bookAudio {
+				bookFormat = "audiobook"
+			}
+			bookFormat = strings.ToLower(strings.TrimSpace(bookFormat))
+			if !schema.IsValidMedium(bookFormat) {
+				return fmt.Errorf("invalid --format %q (want print, ebook, or audiobook)", bookFormat)
+			}
+			date, err := normalizeDateFlag(bookDate)
+			if err != nil {
+				return err
+			}
 			if strings.TrimSpace(bookISBN) != "" {
-				e, provider, attempts, err := booksearch.LookupBookByISBN(cmd.Context(), bookISBN)
+				// Reject typos before spending a round of provider lookups on them.
+				norm, err := isbn.Parse(bookISBN)
+				if err != nil {
+					return err
+				}
+				e, provider, attempts, err := booksearch.LookupBookByISBN(cmd.Context(), norm)
 				// Print per-provider attempt status (found/not found)
 				for _, a := range attempts {
 					status := "status: found"
@@ -93,13 +122,15 @@ func (b Builder) Book() *cobra.Command {
 					if _, perr := fmt.Fprintf(cmd.OutOrStdout(), "source: %s\n", provider); perr != nil {
 						return perr
 					}
-					store.SetWriteSource(provider)
+					setWriteSource(cmd, provider)
 				}
 				applyKeywordsOverride(&e, bookKeywords)
+				applyMedium(&e, hintsMedium(bookFormat, bookNarrator))
+				fillDate(&e, date)
 				return b.writeCommitPrint(cmd, e)
 			}
 			if strings.TrimSpace(bookName) == "" && strings.TrimSpace(bookAuthor) == "" {
-				store.SetWriteSource("manual")
+				setWriteSource(cmd, "manual")
 				return manualAdd(cmd, b.Commit, "book", parseKeywordsCSV(bookKeywords))
 			}
 			// If title/author provided and lookup enabled, try online lookup chain
@@ -119,24 +150,33 @@ func (b Builder) Book() *cobra.Command {
 						if _, perr := fmt.Fprintf(cmd.OutOrStdout(), "source: %s\n", provider); perr != nil {
 							return perr
 						}
-						store.SetWriteSource(provider)
+						setWriteSource(cmd, provider)
 					}
 					applyKeywordsOverride(&e, bookKeywords)
 					ensureTypeKeyword(&e, "book")
+					applyMedium(&e, hintsMedium(bookFormat, bookNarrator))
+					fillDate(&e, date)
 					return b.writeCommitPrint(cmd, e)
 				}
 				// fall through to manual/hints if lookup failed
 			}
-			store.SetWriteSource("manual")
-			hints := hintsBook(bookName, bookAuthor, bookISBN)
+			setWriteSource(cmd, "manual")
+			hints := hintsBook(bookName, bookAuthor, bookISBN, date)
+			for k, v := range hintsMedium(bookFormat, bookNarrator) {
+				hints[k] = v
+			}
 			return doAddWithKeywords(cmd.Context(), b.Commit, "book", hints, parseKeywordsCSV(bookKeywords))
 		},
 	}
 	c.Flags().StringVar(&bookName, "name", "", "Book title")
 	c.Flags().StringVar(&bookAuthor, "author", "", "Author (Family, Given)")
 	c.Flags().StringVar(&bookISBN, "isbn", "", "ISBN")
-	c.Flags().StringVar(&bookKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	c.Flags().StringVar(&bookDate, "date", "", msgDateFlag)
+	addKeywordsFlag(c, &bookKeywords)
 	c.Flags().BoolVar(&bookLookup, "lookup", false, "Attempt online lookup when title/author are provided")
+	c.Flags().StringVar(&bookFormat, "format", "print", "Book format: print, ebook, or audiobook")
+	c.Flags().BoolVar(&bookAudio, "audiobook", false, "Shorthand for --format audiobook")
+	c.Flags().StringVar(&bookNarrator, "narrator", "", "Narrator(s) of an audiobook (Family, Given; semicolon-separated)")
 	return c
 }
 
@@ -148,24 +188,28 @@ func (b Builder) Movie() *cobra.Command {
 		Short: "Add a movie (name or manual entry)",
 		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			date, err := normalizeDateFlag(movieDate)
+			if err != nil {
+				return err
+			}
 			if len(args) > 0 {
 				title := strings.Join(args, " ")
-				if e, ok := getMovieEntry(cmd.Context(), title, movieDate); ok {
+				if e, ok := getMovieEntry(cmd.Context(), title, date); ok {
 					// provider unknown; default to manual for now
-					store.SetWriteSource("manual")
+					setWriteSource(cmd, "manual")
 					applyKeywordsOverride(&e, movieKeywords)
 					ensureTypeKeyword(&e, "movie")
 					return b.writeCommitPrint(cmd, e)
 				}
-				store.SetWriteSource("manual")
-				return doAddWithKeywords(cmd.Context(), b.Commit, "movie", hintsMovie(title, movieDate), parseKeywordsCSV(movieKeywords))
+				setWriteSource(cmd, "manual")
+				return doAddWithKeywords(cmd.Context(), b.Commit, "movie", hintsMovie(title, date), parseKeywordsCSV(movieKeywords))
 			}
-			store.SetWriteSource("manual")
+			setWriteSource(cmd, "manual")
 			return manualAdd(cmd, b.Commit, "movie", parseKeywordsCSV(movieKeywords))
 		},
 	}
-	c.Flags().StringVar(&movieDate, "date", "", "release date YYYY-MM-DD")
-	c.Flags().StringVar(&movieKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	c.Flags().StringVar(&movieDate, "date", "", msgDateFlag)
+	addKeywordsFlag(c, &movieKeywords)
 	return c
 }
 
@@ -177,41 +221,69 @@ func (b Builder) Song() *cobra.Command {
 		Short: "Add a song (title/artist or manual entry)",
 		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			date, err := normalizeDateFlag(songDate)
+			if err != nil {
+				return err
+			}
 			if len(args) > 0 {
 				title := strings.Join(args, " ")
-				if e, ok := getSongEntry(cmd.Context(), title, songArtist, songDate); ok {
-					store.SetWriteSource("itunes")
+				if e, provider, ok, err := getSongEntry(cmd, title, songArtist, date); err != nil {
+					return err
+				} else if ok {
+					setWriteSource(cmd, provider)
 					applyKeywordsOverride(&e, songKeywords)
 					ensureTypeKeyword(&e, "song")
 					return b.writeCommitPrint(cmd, e)
 				}
-				store.SetWriteSource("manual")
-				return doAddWithKeywords(cmd.Context(), b.Commit, "song", hintsSong(title, songArtist, songDate), parseKeywordsCSV(songKeywords))
+				setWriteSource(cmd, "manual")
+				return doAddWithKeywords(cmd.Context(), b.Commit, "song", hintsSong(title, songArtist, date), parseKeywordsCSV(songKeywords))
 			}
-			store.SetWriteSource("manual")
+			setWriteSource(cmd, "manual")
 			return manualAdd(cmd, b.Commit, "song", parseKeywordsCSV(songKeywords))
 		},
 	}
 	c.Flags().StringVar(&songArtist, "artist", "", "Artist/performer name")
-	c.Flags().StringVar(&songDate, "date", "", "release date YYYY-MM-DD")
-	c.Flags().StringVar(&songKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	c.Flags().StringVar(&songDate, "date", "", msgDateFlag)
+	addKeywordsFlag(c, &songKeywords)
 	return c
 }
 
 // Article returns the "add article" subcommand.
 func (b Builder) Article() *cobra.Command {
-	var artDOI, artURL, artTitle, artAuthor, artJournal, artDate, artKeywords string
+	var artDOI, artArXiv, artPMID, artURL, artTitle, artAuthor, artJournal, artDate, artKeywords string
+	var artMaxKeywords int
 	c := &cobra.Command{
 		Use:   "article",
 		Short: "Add a journal or magazine article (flags or manual entry)",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
+			date, err := normalizeDateFlag(artDate)
+			if err != nil {
+				return err
+			}
 			if strings.TrimSpace(artDOI) != "" {
+				doi.SetMaxKeywords(artMaxKeywords)
 				e, err := getArticleByDOI(ctx, artDOI)
 				if err != nil {
 					return err
 				}
-				store.SetWriteSource("doi.org")
+				setWriteSource(cmd, "doi.org")
+				return b.finalizeAndWrite(cmd, e, "article", artKeywords)
+			}
+			if strings.TrimSpace(artArXiv) != "" {
+				e, err := arxiv.FetchArXiv(ctx, artArXiv)
+				if err != nil {
+					return err
+				}
+				setWriteSource(cmd, "arxiv")
+				return b.finalizeAndWrite(cmd, e, "article", artKeywords)
+			}
+			if strings.TrimSpace(artPMID) != "" {
+				e, err := pubmed.FetchByPMID(ctx, artPMID)
+				if err != nil {
+					return err
+				}
+				setWriteSource(cmd, "pubmed")
 				return b.finalizeAndWrite(cmd, e, "article", artKeywords)
 			}
 			if strings.TrimSpace(artURL) != "" {
@@ -219,10 +291,10 @@ func (b Builder) Article() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				store.SetWriteSource("web")
+				setWriteSource(cmd, "web")
 				return b.finalizeAndWrite(cmd, e, "article", artKeywords)
 			}
-			h := hintsArticle(artTitle, artAuthor, artJournal, artDate)
+			h := hintsArticle(artTitle, artAuthor, artJournal, date)
 			if len(h) == 0 {
 				return manualAdd(cmd, b.Commit, "article", parseKeywordsCSV(artKeywords))
 			}
@@ -230,37 +302,142 @@ func (b Builder) Article() *cobra.Command {
 		},
 	}
 	c.Flags().StringVar(&artDOI, "doi", "", "DOI of the article")
+	c.Flags().StringVar(&artArXiv, "arxiv", "", "arXiv identifier of a preprint (e.g., 2101.00001 or arXiv:hep-th/9901001)")
+	c.Flags().StringVar(&artPMID, "pmid", "", "PubMed id of a biomedical article")
 	c.Flags().StringVar(&artURL, "url", "", "URL of an online article to fetch via OpenGraph/JSON-LD")
 	c.Flags().StringVar(&artTitle, "title", "", "Article title")
 	c.Flags().StringVar(&artAuthor, "author", "", "Author (Family, Given)")
 	c.Flags().StringVar(&artJournal, "journal", "", "Journal or publication name")
-	c.Flags().StringVar(&artDate, "date", "", "Publication date YYYY-MM-DD")
-	c.Flags().StringVar(&artKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	c.Flags().StringVar(&artDate, "date", "", msgDateFlag)
+	addKeywordsFlag(c, &artKeywords)
+	c.Flags().IntVar(&artMaxKeywords, "max-keywords", 10, "maximum Crossref subjects added as keywords for DOI lookups (0 disables)")
+	return c
+}
+
+// Dataset returns the "add dataset" subcommand.
+func (b Builder) Dataset() *cobra.Command {
+	return b.dataCite("dataset", "Add a dataset by DataCite DOI or manual entry")
+}
+
+// Software returns the "add software" subcommand.
+func (b Builder) Software() *cobra.Command {
+	return b.dataCite("software", "Add software by DataCite DOI or manual entry")
+}
+
+// dataCite builds an add subcommand for a DataCite-registered type; the entry keeps
+// typ even when DataCite classifies the DOI differently.
+func (b Builder) dataCite(typ, short string) *cobra.Command {
+	var dcDOI, dcKeywords string
+	c := &cobra.Command{
+		Use:   typ,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(dcDOI) == "" {
+				setWriteSource(cmd, "manual")
+				return manualAdd(cmd, b.Commit, typ, parseKeywordsCSV(dcKeywords))
+			}
+			e, err := datacite.FetchByDOI(cmd.Context(), dcDOI)
+			if err != nil {
+				return err
+			}
+			if e.Type != typ {
+				for i, k := range e.Annotation.Keywords {
+					if k == e.Type {
+						e.Annotation.Keywords[i] = typ
+					}
+				}
+				e.Type = typ
+			}
+			setWriteSource(cmd, "datacite")
+			return b.finalizeAndWrite(cmd, e, typ, dcKeywords)
+		},
+	}
+	c.Flags().StringVar(&dcDOI, "doi", "", "DataCite DOI (e.g., 10.5281/zenodo.1234567)")
+	addKeywordsFlag(c, &dcKeywords)
+	return c
+}
+
+// Podcast returns the "add podcast" subcommand.
+func (b Builder) Podcast() *cobra.Command {
+	var podURL, podEpisode, podKeywords string
+	c := &cobra.Command{
+		Use:   "podcast",
+		Short: "Add a podcast episode (Apple Podcasts or RSS feed URL, or manual entry)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(podURL) == "" {
+				setWriteSource(cmd, "manual")
+				return manualAdd(cmd, b.Commit, "podcast", parseKeywordsCSV(podKeywords))
+			}
+			e, err := podcast.FetchEpisode(cmd.Context(), podURL, podEpisode)
+			if err != nil {
+				return err
+			}
+			setWriteSource(cmd, "podcast")
+			return b.finalizeAndWrite(cmd, e, "podcast", podKeywords)
+		},
+	}
+	c.Flags().StringVar(&podURL, "url", "", "Apple Podcasts episode/show URL or RSS feed URL")
+	c.Flags().StringVar(&podEpisode, "episode", "", "Pick the feed episode whose title contains this text (default: the linked or newest episode)")
+	addKeywordsFlag(c, &podKeywords)
 	return c
 }
 
 // Patent returns the "add patent" subcommand.
 func (b Builder) Patent() *cobra.Command {
-	var patURL, patTitle, patInventor, patAssignee, patDate, patKeywords string
+	var patURL, patNumber, patTitle, patInventor, patAssignee, patDate, patKeywords string
 	c := &cobra.Command{
 		Use:   "patent",
 		Short: "Add a patent (flags or manual entry)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			h := hintsPatent(patURL, patTitle, patInventor, patAssignee, patDate)
+			date, err := normalizeDateFlag(patDate)
+			if err != nil {
+				return err
+			}
+			h := hintsPatent(patURL, patNumber, patTitle, patInventor, patAssignee, date)
 			if len(h) == 0 {
-				store.SetWriteSource("manual")
+				setWriteSource(cmd, "manual")
 				return manualAdd(cmd, b.Commit, "patent", parseKeywordsCSV(patKeywords))
 			}
-			store.SetWriteSource("web")
+			setWriteSource(cmd, "web")
 			return doAddWithKeywords(cmd.Context(), b.Commit, "patent", h, parseKeywordsCSV(patKeywords))
 		},
 	}
 	c.Flags().StringVar(&patURL, "url", "", "Patent URL")
+	c.Flags().StringVar(&patNumber, "number", "", "Patent number (e.g., US1234567B2)")
 	c.Flags().StringVar(&patTitle, "title", "", "Patent title")
 	c.Flags().StringVar(&patInventor, "inventor", "", "Inventor name")
 	c.Flags().StringVar(&patAssignee, "assignee", "", "Assignee/owner")
-	c.Flags().StringVar(&patDate, "date", "", "Filing/publication date")
-	c.Flags().StringVar(&patKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	c.Flags().StringVar(&patDate, "date", "", msgDateFlag)
+	addKeywordsFlag(c, &patKeywords)
+	return c
+}
+
+// Standard returns the "add standard" subcommand for technical standards (ISO, IEEE, NIST, ...).
+func (b Builder) Standard() *cobra.Command {
+	var stdDesignation, stdBody, stdTitle, stdDate, stdPublisher, stdURL, stdKeywords string
+	c := &cobra.Command{
+		Use:   "standard",
+		Short: "Add a technical standard by designation and standards body",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(stdDesignation) == "" || strings.TrimSpace(stdBody) == "" {
+				return fmt.Errorf("--designation and --body are required for standards")
+			}
+			date, err := normalizeDateFlag(stdDate)
+			if err != nil {
+				return err
+			}
+			setWriteSource(cmd, "manual")
+			h := hintsStandard(stdDesignation, stdBody, stdTitle, date, stdPublisher, stdURL)
+			return doAddWithKeywords(cmd.Context(), b.Commit, "standard", h, parseKeywordsCSV(stdKeywords))
+		},
+	}
+	c.Flags().StringVar(&stdDesignation, "designation", "", "Standard designation (e.g., \"ISO/IEC 27001:2022\")")
+	c.Flags().StringVar(&stdBody, "body", "", "Standards body (e.g., ISO, IEEE, NIST)")
+	c.Flags().StringVar(&stdTitle, "title", "", "Standard title")
+	c.Flags().StringVar(&stdDate, "date", "", msgDateFlag)
+	c.Flags().StringVar(&stdPublisher, "publisher", "", "Publisher, when different from the standards body")
+	c.Flags().StringVar(&stdURL, "url", "", "Standard URL")
+	addKeywordsFlag(c, &stdKeywords)
 	return c
 }
 
@@ -277,53 +454,261 @@ func (b Builder) RFC() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				store.SetWriteSource("rfc-editor")
+				setWriteSource(cmd, "rfc-editor")
 				return b.finalizeAndWrite(cmd, e, "rfc", rfcKeywords)
 			}
-			store.SetWriteSource("manual")
+			setWriteSource(cmd, "manual")
 			return manualAdd(cmd, b.Commit, "rfc", parseKeywordsCSV(rfcKeywords))
 		},
 	}
-	c.Flags().StringVar(&rfcKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	addKeywordsFlag(c, &rfcKeywords)
 	return c
 }
 
 // Video returns the "add video" subcommand.
 func (b Builder) Video() *cobra.Command {
-	var ytURL, videoKeywords string
+	var videoURL, ytURL, videoKeywords string
 	c := &cobra.Command{
 		Use:   "video",
-		Short: "Add a video (YouTube URL or manual entry)",
+		Short: "Add a video (YouTube or Vimeo URL, or manual entry)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(ytURL) != "" {
-				e, err := youtube.FetchYouTube(cmd.Context(), ytURL)
+			if u := stringsx.FirstNonEmpty(videoURL, ytURL); u != "" {
+				// The host picks the oEmbed provider, whichever flag carried the URL.
+				e, provider, err := youtube.Fetch(cmd.Context(), u)
 				if err != nil {
 					return err
 				}
-				store.SetWriteSource("youtube")
+				setWriteSource(cmd, provider)
 				return b.finalizeAndWrite(cmd, e, "video", videoKeywords)
 			}
-			store.SetWriteSource("manual")
+			setWriteSource(cmd, "manual")
 			return manualAdd(cmd, b.Commit, "video", parseKeywordsCSV(videoKeywords))
 		},
 	}
-	c.Flags().StringVar(&ytURL, "youtube", "", "YouTube video URL to fetch via oEmbed")
-	c.Flags().StringVar(&videoKeywords, "keywords", "", msgCommaDelimitedKeywords)
+	c.Flags().StringVar(&videoURL, "url", "", "YouTube or Vimeo video URL to fetch via oEmbed")
+	c.Flags().StringVar(&ytURL, "youtube", "", "YouTube video URL to fetch via oEmbed (same as --url)")
+	addKeywordsFlag(c, &videoKeywords)
 	return c
 }
 
+// maxAuthorsStored caps authors kept from provider metadata (see SetMaxAuthorsStored).
+var maxAuthorsStored = schema.DefaultMaxAuthorsStored
+
+// SetMaxAuthorsStored sets the provider author cap; n <= 0 stores every author.
+func SetMaxAuthorsStored(n int) { maxAuthorsStored = n }
+
+// MaxAuthorsStoredDefault returns the author cap used when --max-authors-stored is not
+// given: BIB_MAX_AUTHORS_STORED when it is a non-negative integer (0 = no cap),
+// otherwise schema.DefaultMaxAuthorsStored.
+func MaxAuthorsStoredDefault() int {
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("BIB_MAX_AUTHORS_STORED"))); err == nil && n >= 0 {
+		return n
+	}
+	return schema.DefaultMaxAuthorsStored
+}
+
+// autoKeywords enables keyword generation for added entries (see SetAutoKeywords).
+var autoKeywords bool
+
+// SetAutoKeywords toggles merging generated keywords into added entries: OpenAI when
+// OPENAI_API_KEY is set, otherwise (or on failure) local title/summary extraction.
+func SetAutoKeywords(on bool) { autoKeywords = on }
+
+// force lets an add replace an existing entry with the same DOI or ISBN (see SetForce).
+var force bool
+
+// SetForce toggles replacing duplicates: when off, adding a work whose DOI or ISBN is
+// already in the library fails with "duplicate of <id>".
+func SetForce(on bool) { force = on }
+
+// dryRun previews added entries instead of writing them (see SetDryRun).
+var dryRun bool
+
+// SetDryRun toggles preview mode: the full fetch/build pipeline runs, the entry is
+// printed as YAML, and nothing is written to the library or committed.
+func SetDryRun(on bool) { dryRun = on }
+
+// strict fails adds that miss fields their type should have (see SetStrict).
+var strict bool
+
+// SetStrict toggles strict validation: an added entry must meet every type check
+// (schema CheckTypeStrict), warnings included, or the add fails without writing.
+func SetStrict(on bool) { strict = on }
+
+// checkStrict returns the unmet strict type checks of e as one error, or nil when
+// strict mode is off or e passes.
+func checkStrict(e schema.Entry) error {
+	if !strict {
+		return nil
+	}
+	issues := e.CheckTypeStrict()
+	if len(issues) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(issues))
+	for i, is := range issues {
+		msgs[i] = is.Message
+	}
+	return fmt.Errorf("strict: %s", strings.Join(msgs, "; "))
+}
+
+// printDryRun writes e's YAML preview to w.
+func printDryRun(w io.Writer, e schema.Entry) error {
+	_, err := fmt.Fprint(w, schema.PreviewYAML(e))
+	return err
+}
+
+// normalizePages standardizes e's page range (see pages.Normalize) and rejects a
+// numeric range that ends before it starts.
+func normalizePages(e *schema.Entry) error {
+	p, err := pages.Parse(e.APA7.Pages)
+	if err != nil {
+		return err
+	}
+	e.APA7.Pages = p
+	return nil
+}
+
+// previewDryRun handles a finished entry under --dry-run before anything is staged
+// or written, reporting whether it did: a duplicate still fails, and the preview goes
+// to w, or to the batch slot in ctx so the batch prints it in line order.
+func previewDryRun(ctx context.Context, w io.Writer, e schema.Entry) (bool, error) {
+	if !dryRun {
+		return false, nil
+	}
+	if _, err := resolveDuplicate(&e); err != nil {
+		return true, err
+	}
+	if s := batchSlotFrom(ctx); s != nil {
+		s.preview = schema.PreviewYAML(e)
+		return true, nil
+	}
+	return true, printDryRun(w, e)
+}
+
+// commitTemplate overrides the add commit message (see SetCommitTemplate).
+var commitTemplate string
+
+// SetCommitTemplate sets the add commit message template; {id}, {type}, and {title}
+// are expanded per entry. Empty falls back to $BIB_COMMIT_TEMPLATE, then "add citation: <id>".
+func SetCommitTemplate(t string) { commitTemplate = t }
+
+// addCommitMessage renders the commit message for a newly added e.
+func addCommitMessage(e schema.Entry) string {
+	t := strings.TrimSpace(commitTemplate)
+	if t == "" {
+		t = strings.TrimSpace(os.Getenv("BIB_COMMIT_TEMPLATE"))
+	}
+	if t == "" {
+		return fmt.Sprintf(msgAddCitation, e.ID)
+	}
+	return strings.NewReplacer("{id}", e.ID, "{type}", e.Type, "{title}", strings.TrimSpace(e.APA7.Title)).Replace(t)
+}
+
+// resolveDuplicate looks for a stored entry with e's DOI or ISBN. Without force it
+// returns a "duplicate of <id>" error; with force, e takes over the existing id so the
+// write replaces that record, and the returned message is the update commit message.
+func resolveDuplicate(e *schema.Entry) (string, error) {
+	existing, found, err := store.FindByDOI(e.APA7.DOI)
+	if err == nil && !found {
+		existing, found, err = store.FindByISBN(e.APA7.ISBN)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !found || strings.EqualFold(existing.ID, e.ID) {
+		return addCommitMessage(*e), nil
+	}
+	if !force {
+		return "", fmt.Errorf("duplicate of %s (%s); use --force to update it", existing.ID, strings.TrimSpace(existing.APA7.Title))
+	}
+	e.ID = existing.ID
+	return fmt.Sprintf(msgUpdateCitation, e.ID), nil
+}
+
+// keywordsFromTitleAndSummaryFunc is a seam for faking the OpenAI keyword call in tests.
+var keywordsFromTitleAndSummaryFunc = summarize.KeywordsFromTitleAndSummary
+
+func applyAutoKeywords(ctx context.Context, e *schema.Entry) {
+	if !autoKeywords || e == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var ks []string
+	if strings.TrimSpace(os.Getenv("OPENAI_API_KEY")) != "" {
+		if gen, err := keywordsFromTitleAndSummaryFunc(ctx, e.APA7.Title, e.Annotation.Summary); err == nil {
+			ks = gen
+		}
+	}
+	if len(ks) == 0 {
+		ks = summarize.LocalKeywords(e.APA7.Title, e.Annotation.Summary, summarize.DefaultLocalKeywords)
+	}
+	e.Annotation.Keywords = appendKeywords(e.Annotation.Keywords, ks)
+}
+
+// appendKeywords adds each of extra not already in existing (case-insensitive), keeping order.
+func appendKeywords(existing, extra []string) []string {
+	seen := map[string]bool{}
+	for _, k := range existing {
+		seen[strings.ToLower(strings.TrimSpace(k))] = true
+	}
+	for _, k := range extra {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		existing = append(existing, k)
+	}
+	return existing
+}
+
 // --- helpers previously in add.go ---
 
 func (b Builder) writeCommitPrint(cmd *cobra.Command, e schema.Entry) error {
+	// Only provider-built entries reach here; manually entered authors are never truncated.
+	if schema.TruncateAuthors(&e, maxAuthorsStored) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "stored first %d of %d authors\n", len(e.APA7.Authors), e.APA7.AuthorCount)
+	}
+	return finishAdd(cmd.Context(), cmd.OutOrStdout(), b.Commit, e)
+}
+
+// finishAdd is the shared tail of every add path. It applies --auto-keywords, Unicode
+// folding, and page normalization, validates e (including --strict), and then previews
+// it under --dry-run, stages it inside a batch, or resolves duplicates, writes it, and
+// commits it with the regenerated library, reporting the path to w.
+func finishAdd(ctx context.Context, w io.Writer, commit CommitFunc, e schema.Entry) error {
+	applyAutoKeywords(ctx, &e)
+	sanitize.ApplyUnicodeNormalization(&e)
+	if err := normalizePages(&e); err != nil {
+		return err
+	}
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if err := checkStrict(e); err != nil {
+		return err
+	}
+	if done, err := previewDryRun(ctx, w, e); done {
+		return err
+	}
+	if stageEntry(ctx, e) {
+		return nil
+	}
+	msg, err := resolveDuplicate(&e)
+	if err != nil {
+		return err
+	}
 	path, err := store.WriteEntry(e)
 	if err != nil {
 		return err
 	}
-	// Also commit the regenerated BibTeX library.
-	if err := b.Commit([]string{path, store.BibFile}, fmt.Sprintf(msgAddCitation, e.ID)); err != nil {
+	if err := commit([]string{path, store.BibFile}, msg); err != nil {
 		return err
 	}
-	_, err = fmt.Fprintf(cmd.OutOrStdout(), msgWrote, path)
+	_, err = fmt.Fprintf(w, msgWrote, path)
 	return err
 }
 
@@ -348,7 +733,7 @@ func (b Builder) finalizeAndWrite(cmd *cobra.Command, e schema.Entry, typ string
 	return b.writeCommitPrint(cmd, e)
 }
 
-func hintsBook(name, author, isbn string) map[string]string {
+func hintsBook(name, author, isbn, date string) map[string]string {
 	m := map[string]string{}
 	if strings.TrimSpace(name) != "" {
 		m["title"] = name
@@ -359,6 +744,21 @@ func hintsBook(name, author, isbn string) map[string]string {
 	if strings.TrimSpace(isbn) != "" {
 		m["isbn"] = isbn
 	}
+	if strings.TrimSpace(date) != "" {
+		m["date"] = date
+	}
+	return m
+}
+
+// hintsMedium maps a book format and narrators to hints; print is the default and is not stored.
+func hintsMedium(format, narrator string) map[string]string {
+	m := map[string]string{}
+	if f := strings.ToLower(strings.TrimSpace(format)); f != "" && f != "print" {
+		m["medium"] = f
+	}
+	if strings.TrimSpace(narrator) != "" {
+		m["narrator"] = narrator
+	}
 	return m
 }
 
@@ -398,8 +798,11 @@ func hintsArticle(title, author, journal, date string) map[string]string {
 	return m
 }
 
-func hintsPatent(urlStr, title, inventor, assignee, date string) map[string]string {
+func hintsPatent(urlStr, number, title, inventor, assignee, date string) map[string]string {
 	m := map[string]string{}
+	if strings.TrimSpace(number) != "" {
+		m["patent_number"] = strings.TrimSpace(number)
+	}
 	if strings.TrimSpace(title) != "" {
 		m["title"] = title
 	}
@@ -418,6 +821,26 @@ func hintsPatent(urlStr, title, inventor, assignee, date string) map[string]stri
 	return m
 }
 
+func hintsStandard(designation, body, title, date, publisher, urlStr string) map[string]string {
+	m := map[string]string{
+		"designation":    strings.TrimSpace(designation),
+		"standards_body": strings.TrimSpace(body),
+	}
+	if strings.TrimSpace(title) != "" {
+		m["title"] = title
+	}
+	if strings.TrimSpace(date) != "" {
+		m["date"] = date
+	}
+	if strings.TrimSpace(publisher) != "" {
+		m["publisher"] = publisher
+	}
+	if strings.TrimSpace(urlStr) != "" {
+		m["url"] = strings.TrimSpace(urlStr)
+	}
+	return m
+}
+
 func parseKeywordsCSV(s string) []string {
 	if strings.TrimSpace(s) == "" {
 		return nil
@@ -449,23 +872,12 @@ func doAddWithKeywords(ctx context.Context, commit CommitFunc, typ string, hints
 	applyURL(&e, hints)
 	applyAuthorHint(&e, hints)
 	applyIDs(&e, hints)
+	applyMedium(&e, hints)
+	applyStandard(&e, hints)
 	schema.EnsureAccessedIfURL(&e)
 	applyDefaults(&e, typ, extraKeywords)
 	applyManualSummary(&e)
-	if err := e.Validate(); err != nil {
-		return err
-	}
-	path, err := store.WriteEntry(e)
-	if err != nil {
-		return err
-	}
-	if err = commit([]string{path}, fmt.Sprintf(msgAddCitation, e.ID)); err != nil {
-		return err
-	}
-	if _, err = fmt.Fprintf(os.Stdout, msgWrote, path); err != nil {
-		return err
-	}
-	return nil
+	return finishAdd(ctx, os.Stdout, commit, e)
 }
 
 func deriveTitle(typ string, hints map[string]string) (string, error) {
@@ -493,6 +905,9 @@ func applyJournal(e *schema.Entry, hints map[string]string) {
 
 func applyDate(e *schema.Entry, hints map[string]string) {
 	if v := strings.TrimSpace(hints["date"]); v != "" {
+		if d, err := dates.NormalizeToISO(v); err == nil {
+			v = d
+		}
 		e.APA7.Date = v
 		if y := dates.YearFromDate(v); y > 0 {
 			y2 := y
@@ -501,6 +916,29 @@ func applyDate(e *schema.Entry, hints map[string]string) {
 	}
 }
 
+// addKeywordsFlag registers the common --keywords flag with completion from the keyword index.
+func addKeywordsFlag(c *cobra.Command, p *string) {
+	c.Flags().StringVar(p, "keywords", "", msgCommaDelimitedKeywords)
+	_ = c.RegisterFlagCompletionFunc("keywords", complete.Keywords)
+}
+
+// normalizeDateFlag validates a --date value (or a prompted date), returning it as YYYY,
+// YYYY-MM, or YYYY-MM-DD.
+func normalizeDateFlag(v string) (string, error) {
+	d, err := dates.NormalizeToISO(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: %w", v, err)
+	}
+	return d, nil
+}
+
+// fillDate applies a --date value to provider results that came back without a date.
+func fillDate(e *schema.Entry, date string) {
+	if date != "" && strings.TrimSpace(e.APA7.Date) == "" && e.APA7.Year == nil {
+		applyDate(e, map[string]string{"date": date})
+	}
+}
+
 func applyURL(e *schema.Entry, hints map[string]string) {
 	if v := strings.TrimSpace(hints["url"]); v != "" {
 		e.APA7.URL = v
@@ -520,6 +958,9 @@ func applyIDs(e *schema.Entry, hints map[string]string) {
 	if v := strings.TrimSpace(hints["isbn"]); v != "" {
 		e.APA7.ISBN = v
 	}
+	if v := strings.TrimSpace(hints["patent_number"]); v != "" {
+		e.APA7.PatentNumber = v
+	}
 	if v := strings.TrimSpace(hints["doi"]); v != "" {
 		e.APA7.DOI = v
 		if e.APA7.URL == "" {
@@ -528,6 +969,29 @@ func applyIDs(e *schema.Entry, hints map[string]string) {
 	}
 }
 
+func applyMedium(e *schema.Entry, hints map[string]string) {
+	if v := strings.TrimSpace(hints["medium"]); v != "" {
+		e.APA7.Medium = v
+	}
+	for _, n := range strings.Split(hints["narrator"], ";") {
+		if fam, giv := parseAuthor(strings.TrimSpace(n)); fam != "" {
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: giv, Role: schema.RoleNarrator})
+		}
+	}
+}
+
+func applyStandard(e *schema.Entry, hints map[string]string) {
+	if v := strings.TrimSpace(hints["designation"]); v != "" {
+		e.APA7.Designation = v
+	}
+	if v := strings.TrimSpace(hints["standards_body"]); v != "" {
+		e.APA7.StandardsBody = v
+	}
+	if v := strings.TrimSpace(hints["publisher"]); v != "" && e.Type == "standard" {
+		e.APA7.Publisher = v
+	}
+}
+
 func applyDefaults(e *schema.Entry, typ string, extraKeywords []string) {
 	e.ID = schema.NewID()
 	if len(extraKeywords) > 0 {
@@ -562,6 +1026,24 @@ func parseAuthor(s string) (family, given string) {
 	return s, ""
 }
 
+// reRoleMark matches a trailing role marker on a manually entered name, e.g. "(Ed.)".
+var reRoleMark = regexp.MustCompile(`(?i)\s*\((eds?\.?|editor|trans\.?|translator)\)\s*$`)
+
+// parseCreator parses a manually entered name like parseAuthor, reading a trailing
+// "(Ed.)" or "(Trans.)" as the editor or translator role.
+func parseCreator(s string) schema.Author {
+	var role string
+	if m := reRoleMark.FindStringSubmatch(s); m != nil {
+		role = schema.RoleEditor
+		if strings.HasPrefix(strings.ToLower(m[1]), "trans") {
+			role = schema.RoleTranslator
+		}
+		s = s[:len(s)-len(m[0])]
+	}
+	fam, giv := parseAuthor(s)
+	return schema.Author{Family: fam, Given: giv, Role: role}
+}
+
 // manual entry helpers
 type manualFields struct {
 	title     string
@@ -585,15 +1067,7 @@ func manualAdd(cmd *cobra.Command, commit CommitFunc, typ string, extraKeywords
 	if err != nil {
 		return err
 	}
-	path, err := store.WriteEntry(e)
-	if err != nil {
-		return err
-	}
-	if err := commit([]string{path, store.BibFile}, fmt.Sprintf(msgAddCitation, e.ID)); err != nil {
-		return err
-	}
-	_, err = fmt.Fprintf(cmd.OutOrStdout(), msgWrote, path)
-	return err
+	return finishAdd(cmd.Context(), cmd.OutOrStdout(), commit, e)
 }
 
 func collectManualFields(cmd *cobra.Command, typ string, extraKeywords []string) (manualFields, error) {
@@ -604,8 +1078,12 @@ func collectManualFields(cmd *cobra.Command, typ string, extraKeywords []string)
 	if mf.title == "" {
 		return manualFields{}, fmt.Errorf("title is required")
 	}
-	mf.authorsIn = strings.TrimSpace(prompt(cmd, in, out, "Authors (semicolon-separated; use 'Family, Given' or organization name): "))
-	mf.date = strings.TrimSpace(prompt(cmd, in, out, "Date (YYYY-MM-DD; optional): "))
+	mf.authorsIn = strings.TrimSpace(prompt(cmd, in, out, "Authors (semicolon-separated; use 'Family, Given' or organization name; append (Ed.) or (Trans.) for editors and translators): "))
+	date, err := normalizeDateFlag(prompt(cmd, in, out, "Date (e.g. 1999, May 1999, or 1999-05-03; optional): "))
+	if err != nil {
+		return manualFields{}, err
+	}
+	mf.date = date
 	mf.url = strings.TrimSpace(prompt(cmd, in, out, "URL (optional): "))
 	switch typ {
 	case "article":
@@ -619,6 +1097,9 @@ func collectManualFields(cmd *cobra.Command, typ string, extraKeywords []string)
 	case "song":
 		mf.journal = strings.TrimSpace(prompt(cmd, in, out, "Album/Container (optional): "))
 		mf.publisher = strings.TrimSpace(prompt(cmd, in, out, "Label/Publisher (optional): "))
+	case "podcast":
+		mf.journal = strings.TrimSpace(prompt(cmd, in, out, "Show name (optional): "))
+		mf.publisher = strings.TrimSpace(prompt(cmd, in, out, "Network/Publisher (optional): "))
 	case "rfc":
 		mf.publisher = strings.TrimSpace(prompt(cmd, in, out, "Publisher (default IETF; optional): "))
 		if mf.publisher == "" {
@@ -654,14 +1135,19 @@ func buildManualEntry(typ string, mf manualFields) (schema.Entry, error) {
 	e.APA7.Date = mf.date
 	e.APA7.URL = mf.url
 	e.APA7.DOI = mf.doi
-	e.APA7.ISBN = mf.isbn
+	if strings.TrimSpace(mf.isbn) != "" {
+		norm, err := isbn.Parse(mf.isbn)
+		if err != nil {
+			return schema.Entry{}, err
+		}
+		e.APA7.ISBN = norm
+	}
 	if strings.TrimSpace(e.APA7.URL) != "" {
 		e.APA7.Accessed = dates.NowISO()
 	}
 	for _, name := range splitAuthorsBySemi(mf.authorsIn) {
-		fam, giv := parseAuthor(name)
-		if fam != "" {
-			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: giv})
+		if a := parseCreator(name); a.Family != "" {
+			e.APA7.Authors = append(e.APA7.Authors, a)
 		}
 	}
 	e.Annotation.Summary = mf.summary
@@ -708,14 +1194,31 @@ func getMovieEntry(ctx context.Context, title, date string) (schema.Entry, bool)
 	return schema.Entry{}, false
 }
 
-func getSongEntry(ctx context.Context, title, artist, date string) (schema.Entry, bool) {
-	if e, err := songfetch.FetchSong(ctx, title, artist, date); err == nil {
-		return e, true
+// getSongEntry looks the song up via songfetch.LookupSong (printing a "tried:" line
+// per provider and a "source:" line on success), then falls back to OpenAI. The
+// error is only for output failures.
+func getSongEntry(cmd *cobra.Command, title, artist, date string) (schema.Entry, string, bool, error) {
+	out := cmd.OutOrStdout()
+	e, provider, attempts, err := songfetch.LookupSong(cmd.Context(), title, artist, date)
+	for _, a := range attempts {
+		status := "status: found"
+		if !a.Success {
+			status = "status: not found"
+		}
+		if _, perr := fmt.Fprintf(out, "tried: %s: %s\n", a.Provider, status); perr != nil {
+			return schema.Entry{}, "", false, perr
+		}
 	}
-	if e, err := summarize.GenerateSongFromTitleArtistDate(ctx, title, artist, date); err == nil {
-		return e, true
+	if err != nil {
+		if e, err = summarize.GenerateSongFromTitleArtistDate(cmd.Context(), title, artist, date); err != nil {
+			return schema.Entry{}, "", false, nil
+		}
+		provider = "openai"
 	}
-	return schema.Entry{}, false
+	if _, perr := fmt.Fprintf(out, "source: %s\n", provider); perr != nil {
+		return schema.Entry{}, "", false, perr
+	}
+	return e, provider, true, nil
 }
 
 func getArticleByDOI(ctx context.Context, doiStr string) (schema.Entry, error) {
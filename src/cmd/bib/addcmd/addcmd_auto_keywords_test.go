@@ -0,0 +1,52 @@
+package addcmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestApplyAutoKeywords(t *testing.T) {
+	t.Cleanup(func() { SetAutoKeywords(false) })
+	old := keywordsFromTitleAndSummaryFunc
+	t.Cleanup(func() { keywordsFromTitleAndSummaryFunc = old })
+	mk := func() schema.Entry {
+		return schema.Entry{Type: "article", APA7: schema.APA7{Title: "Practical Raft Consensus"}, Annotation: schema.Annotation{Keywords: []string{"article"}}}
+	}
+
+	e := mk()
+	applyAutoKeywords(context.Background(), &e)
+	if len(e.Annotation.Keywords) != 1 {
+		t.Fatalf("auto keywords should be opt-in: %v", e.Annotation.Keywords)
+	}
+
+	SetAutoKeywords(true)
+	t.Setenv("OPENAI_API_KEY", "")
+	e = mk()
+	applyAutoKeywords(context.Background(), &e)
+	if strings.Join(e.Annotation.Keywords, ",") != "article,practical,raft,consensus" {
+		t.Fatalf("local keywords: %v", e.Annotation.Keywords)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "k")
+	keywordsFromTitleAndSummaryFunc = func(context.Context, string, string) ([]string, error) {
+		return []string{"Distributed Systems", "article"}, nil
+	}
+	e = mk()
+	applyAutoKeywords(context.Background(), &e)
+	if strings.Join(e.Annotation.Keywords, ",") != "article,distributed systems" {
+		t.Fatalf("openai keywords should be preferred: %v", e.Annotation.Keywords)
+	}
+
+	keywordsFromTitleAndSummaryFunc = func(context.Context, string, string) ([]string, error) {
+		return nil, errors.New("down")
+	}
+	e = mk()
+	applyAutoKeywords(context.Background(), &e)
+	if !strings.Contains(strings.Join(e.Annotation.Keywords, ","), "raft") {
+		t.Fatalf("should fall back to local keywords: %v", e.Annotation.Keywords)
+	}
+}
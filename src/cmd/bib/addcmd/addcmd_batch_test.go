@@ -0,0 +1,257 @@
+package addcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/doi"
+	"bibliography/src/internal/openlibrary"
+	rfcpkg "bibliography/src/internal/rfc"
+	"bibliography/src/internal/store"
+	"bibliography/src/internal/webfetch"
+)
+
+func TestBatch_DispatchesLinesAndCommitsOnce(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	var commits [][]string
+	b := New(func(paths []string, msg string) error { commits = append(commits, paths); return nil })
+
+	in := strings.Join([]string{
+		"# patents to add",
+		"patent\tUS1234567B2",
+		"",
+		"patent\thttps://patents.google.com/patent/US7654321B1",
+		"painting\tsomething",
+		"book",
+	}, "\n")
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	err := b.Batch(cmd, strings.NewReader(in), 1)
+	if err == nil {
+		t.Fatalf("expected error for failed lines")
+	}
+	s := out.String()
+	for _, want := range []string{"line 2: ok", "line 4: ok", "line 5: error: unsupported type", "line 6: error:", "batch: 2 added, 2 failed"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("missing %q in output:\n%s", want, s)
+		}
+	}
+	if len(commits) != 1 {
+		t.Fatalf("expected a single commit, got %d", len(commits))
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	if list[0].APA7.PatentNumber == "" && list[1].APA7.PatentNumber == "" {
+		t.Fatalf("expected patent number from identifier: %+v", list)
+	}
+}
+
+func TestDetectBatchType(t *testing.T) {
+	cases := []struct{ ref, typ, id string }{
+		{"doi:10.1234/abc", "article", "10.1234/abc"},
+		{"10.1234/abc", "article", "10.1234/abc"},
+		{"https://doi.org/10.1234/abc", "article", "10.1234/abc"},
+		{"isbn:0306406152", "book", "0306406152"},
+		{"ISBN:978-0-306-40615-7", "book", "978-0-306-40615-7"},
+		{"rfc:9999", "rfc", "9999"},
+		{"https://www.rfc-editor.org/rfc/rfc5424.html", "rfc", "rfc5424"},
+		{"https://youtu.be/xyz", "video", "https://youtu.be/xyz"},
+		{"https://podcasts.apple.com/us/podcast/show/id123", "podcast", "https://podcasts.apple.com/us/podcast/show/id123"},
+		{"https://patents.google.com/patent/US1234567B2", "patent", "https://patents.google.com/patent/US1234567B2"},
+		{"https://news.example.com/post", "article", "https://news.example.com/post"},
+	}
+	for _, c := range cases {
+		typ, id, err := detectBatchType(c.ref)
+		if err != nil || typ != c.typ || id != c.id {
+			t.Errorf("detectBatchType(%q) = %q, %q, %v; want %q, %q", c.ref, typ, id, err, c.typ, c.id)
+		}
+	}
+	if _, _, err := detectBatchType("not-a-reference"); err == nil {
+		t.Fatalf("expected error for an undetectable reference")
+	}
+}
+
+func TestBatchFile_MixedReferences(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	doi.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if strings.Contains(req.URL.Host, "doi.org") {
+			return jsonResp(200, map[string]any{
+				"title":           "Batch Article",
+				"container-title": "Journal X",
+				"issued":          map[string]any{"date-parts": [][]int{{2021, 2, 3}}},
+				"author":          []map[string]string{{"family": "Doe", "given": "Jane"}},
+				"DOI":             "10.1234/batch",
+			})
+		}
+		return textResp(404, "")
+	}})
+	openlibrary.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if strings.Contains(req.URL.RawQuery, "jscmd=data") {
+			return jsonResp(200, map[string]any{"ISBN:0306406152": map[string]any{
+				"title":        "Batch Book",
+				"publishers":   []map[string]string{{"name": "Pub"}},
+				"publish_date": "2001",
+				"authors":      []map[string]string{{"name": "Doe, Jane"}},
+			}})
+		}
+		return textResp(404, "")
+	}})
+	rfcpkg.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if strings.Contains(req.URL.Path, "/rfc/rfc9999.xml") {
+			return textResp(200, `<?xml version="1.0"?><rfc><front><title>Batch RFC</title>`+
+				`<author fullname="Foo Bar"><name><surname>Bar</surname><given>Foo</given></name></author>`+
+				`<date year="2020" month="January"/><seriesInfo name="RFC" value="9999"/></front></rfc>`)
+		}
+		return textResp(404, "")
+	}})
+	webfetch.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if req.URL.Host == "news.example.com" {
+			return &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"text/html"}}, Body: io.NopCloser(strings.NewReader(
+				`<html><head><meta property="og:title" content="Batch Page"><meta name="author" content="Jane Doe"></head></html>`))}
+		}
+		return textResp(404, "")
+	}})
+
+	refs := filepath.Join(dir, "refs.txt")
+	in := strings.Join([]string{
+		"doi:10.1234/batch",
+		"isbn:0306406152",
+		"rfc:9999",
+		"https://news.example.com/post",
+		"isbn:0306406153",
+		"what-is-this",
+	}, "\n")
+	if err := os.WriteFile(refs, []byte(in), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var commits []string
+	b := New(func(_ []string, msg string) error { commits = append(commits, msg); return nil })
+	c := b.BatchFile()
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetErr(io.Discard)
+	c.SetArgs([]string{"--file", refs})
+	if err := c.Execute(); err == nil || !strings.Contains(err.Error(), "2 batch line(s) failed") {
+		t.Fatalf("expected 2 failed lines, got %v", err)
+	}
+	s := out.String()
+	for _, want := range []string{"line 1: ok", "line 2: ok", "line 3: ok", "line 4: ok", "line 5: error: invalid ISBN", "line 6: error: cannot detect type", "batch: 4 added, 2 failed"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("missing %q in output:\n%s", want, s)
+		}
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 4 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	types := map[string]int{}
+	for _, e := range list {
+		types[e.Type]++
+	}
+	if types["article"] != 2 || types["book"] != 1 || types["rfc"] != 1 {
+		t.Fatalf("unexpected types: %v", types)
+	}
+	if len(commits) != 1 || strings.Count(commits[0], store.BibFile+"::") != 4 {
+		t.Fatalf("expected one commit listing 4 paths, got %q", commits)
+	}
+}
+
+func TestBatch_ConcurrentFetchesKeepLineOrder(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	// Earlier DOIs answer later so fetches complete out of order.
+	doi.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		n, _ := strconv.Atoi(path.Base(req.URL.Path))
+		time.Sleep(time.Duration(10-n) * 3 * time.Millisecond)
+		return jsonResp(200, map[string]any{
+			"title":           fmt.Sprintf("Concurrent %d", n),
+			"container-title": "Journal X",
+			"issued":          map[string]any{"date-parts": [][]int{{2020}}},
+			"author":          []map[string]string{{"family": "Doe", "given": "Jane"}},
+			"DOI":             fmt.Sprintf("10.5555/%d", n),
+		})
+	}})
+	var lines []string
+	for i := 0; i < 10; i++ {
+		lines = append(lines, fmt.Sprintf("doi:10.5555/%d", i))
+	}
+	commits := 0
+	b := New(func([]string, string) error { commits++; return nil })
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := b.Batch(cmd, strings.NewReader(strings.Join(lines, "\n")), 4); err != nil {
+		t.Fatalf("batch: %v\n%s", err, out.String())
+	}
+	var want strings.Builder
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&want, "line %d: ok\n", i)
+	}
+	want.WriteString("batch: 10 added, 0 failed\n")
+	if out.String() != want.String() {
+		t.Fatalf("output out of order:\n%s", out.String())
+	}
+	if commits != 1 {
+		t.Fatalf("expected a single commit, got %d", commits)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 10 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	titles := map[string]bool{}
+	for _, e := range list {
+		titles[e.APA7.Title] = true
+	}
+	for i := 0; i < 10; i++ {
+		if !titles[fmt.Sprintf("Concurrent %d", i)] {
+			t.Fatalf("missing entry %d: %v", i, titles)
+		}
+	}
+}
+
+func TestBatch_CancelledWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	commits := 0
+	b := New(func([]string, string) error { commits++; return nil })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	cmd.SetOut(io.Discard)
+	err := b.Batch(cmd, strings.NewReader("site\thttps://example.com/a\nsite\thttps://example.com/b"), 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, serr := os.Stat(store.BibFile); !os.IsNotExist(serr) || commits != 0 {
+		t.Fatalf("cancelled batch should write and commit nothing (stat %v, commits %d)", serr, commits)
+	}
+}
@@ -0,0 +1,41 @@
+package addcmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestBook_AudiobookFormatAndNarrator(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	b := New(func(paths []string, msg string) error { return nil })
+
+	book := b.Book()
+	book.SetArgs([]string{"--name", "Heard", "--author", "Doe, J.", "--audiobook", "--narrator", "Reader, A."})
+	book.SetOut(new(bytes.Buffer))
+	if err := book.Execute(); err != nil {
+		t.Fatalf("book: %v", err)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	got := list[0].APA7
+	if n := got.Authors.WithRole(schema.RoleNarrator); got.Medium != "audiobook" || len(n) != 1 || n[0].Family != "Reader" {
+		t.Fatalf("unexpected medium/narrator: %+v", got)
+	}
+
+	bad := b.Book()
+	bad.SetArgs([]string{"--name", "X", "--format", "vinyl"})
+	bad.SetOut(new(bytes.Buffer))
+	bad.SilenceUsage, bad.SilenceErrors = true, true
+	if err := bad.Execute(); err == nil {
+		t.Fatalf("expected error for invalid --format")
+	}
+}
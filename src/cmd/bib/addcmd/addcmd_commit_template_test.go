@@ -0,0 +1,52 @@
+package addcmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"bibliography/src/internal/store"
+)
+
+func TestAdd_CommitTemplate(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	t.Cleanup(func() { SetCommitTemplate("") })
+
+	var msgs []string
+	commit := func(_ []string, msg string) error { msgs = append(msgs, msg); return nil }
+	add := func(title string) string {
+		t.Helper()
+		if err := AddWithKeywords(context.Background(), commit, "article", map[string]string{"title": title, "journal": "J"}, nil); err != nil {
+			t.Fatalf("add %q: %v", title, err)
+		}
+		return msgs[len(msgs)-1]
+	}
+	idOf := func(title string) string {
+		t.Helper()
+		entries, _ := store.ReadAll()
+		for _, e := range entries {
+			if e.APA7.Title == title {
+				return e.ID
+			}
+		}
+		t.Fatalf("no entry titled %q", title)
+		return ""
+	}
+
+	if got := add("Default"); got != "add citation: "+idOf("Default") {
+		t.Fatalf("default message = %q", got)
+	}
+
+	t.Setenv("BIB_COMMIT_TEMPLATE", "docs(bib): add {type} {id}")
+	if got, want := add("From Env"), "docs(bib): add article "+idOf("From Env"); got != want {
+		t.Fatalf("env template = %q, want %q", got, want)
+	}
+
+	SetCommitTemplate("cite: {title} [{id}]")
+	if got, want := add("From Flag"), "cite: From Flag ["+idOf("From Flag")+"]"; got != want {
+		t.Fatalf("flag template = %q, want %q", got, want)
+	}
+}
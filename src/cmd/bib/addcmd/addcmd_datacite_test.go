@@ -0,0 +1,59 @@
+package addcmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/datacite"
+	"bibliography/src/internal/store"
+)
+
+func TestAddSoftwareAndDatasetByDOI(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	datacite.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if req.URL.Host != "api.datacite.org" {
+			return textResp(404, "not found")
+		}
+		return jsonResp(200, map[string]any{"data": map[string]any{"attributes": map[string]any{
+			"titles":          []map[string]string{{"title": "Sim Output"}},
+			"creators":        []map[string]string{{"name": "Roe, Ann", "familyName": "Roe", "givenName": "Ann"}},
+			"publisher":       "Zenodo",
+			"publicationYear": 2023,
+			"types":           map[string]string{"resourceTypeGeneral": "Dataset"},
+		}}})
+	}})
+	t.Cleanup(func() { datacite.SetHTTPClient(&http.Client{}) })
+
+	var msgs []string
+	b := New(func(_ []string, m string) error { msgs = append(msgs, m); return nil })
+	ds := b.Dataset()
+	ds.SetArgs([]string{"--doi", "10.5281/zenodo.1"})
+	ds.SetOut(new(bytes.Buffer))
+	if err := ds.Execute(); err != nil {
+		t.Fatalf("add dataset: %v", err)
+	}
+	// The subcommand's type wins over DataCite's classification.
+	sw := b.Software()
+	sw.SetArgs([]string{"--doi", "10.5281/zenodo.2", "--keywords", "simulation"})
+	sw.SetOut(new(bytes.Buffer))
+	if err := sw.Execute(); err != nil {
+		t.Fatalf("add software: %v", err)
+	}
+	entries, err := store.ReadAll()
+	if err != nil || len(entries) != 2 || len(msgs) != 2 {
+		t.Fatalf("entries %d commits %d err %v", len(entries), len(msgs), err)
+	}
+	types := map[string]string{}
+	for _, e := range entries {
+		types[e.APA7.DOI] = e.Type + ":" + strings.Join(e.Annotation.Keywords, ",")
+	}
+	if types["10.5281/zenodo.1"] != "dataset:dataset" || !strings.HasPrefix(types["10.5281/zenodo.2"], "software:") {
+		t.Fatalf("unexpected entries: %v", types)
+	}
+}
@@ -0,0 +1,119 @@
+package addcmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/spf13/cobra"
+
+	songfetch "bibliography/src/internal/song"
+	"bibliography/src/internal/store"
+)
+
+func TestAdd_DateFlagGranularities(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	// Keep movie/song lookups offline so the --date hint path is exercised.
+	t.Setenv("OMDB_API_KEY", "")
+	t.Setenv("TMDB_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	songfetch.SetHTTPClient(fakeDoer{handler: func(*http.Request) *http.Response { return textResp(404, "") }})
+	t.Cleanup(func() { songfetch.SetHTTPClient(&http.Client{}) })
+
+	b := New(func([]string, string) error { return nil })
+	commands := map[string]func(date string) *cobra.Command{
+		"movie": func(date string) *cobra.Command {
+			c := b.Movie()
+			c.SetArgs([]string{"Some Film", "--date", date})
+			return c
+		},
+		"song": func(date string) *cobra.Command {
+			c := b.Song()
+			c.SetArgs([]string{"Some Song", "--artist", "Doe, Jane", "--date", date})
+			return c
+		},
+		"article": func(date string) *cobra.Command {
+			c := b.Article()
+			c.SetArgs([]string{"--title", "Some Article", "--author", "Doe, Jane", "--date", date})
+			return c
+		},
+		"book": func(date string) *cobra.Command {
+			c := b.Book()
+			c.SetArgs([]string{"--name", "Some Book", "--author", "Doe, Jane", "--date", date})
+			return c
+		},
+		"patent": func(date string) *cobra.Command {
+			c := b.Patent()
+			c.SetArgs([]string{"--title", "Some Patent", "--inventor", "Doe, Jane", "--date", date})
+			return c
+		},
+	}
+	granularities := map[string]string{
+		"1999":        "1999",
+		"1999-5":      "1999-05",
+		"May 1999":    "1999-05",
+		"1999/05/03":  "1999-05-03",
+		"May 3, 1999": "1999-05-03",
+	}
+	for typ, mk := range commands {
+		for in, want := range granularities {
+			_ = os.Remove(store.BibFile)
+			c := mk(in)
+			c.SetOut(new(bytes.Buffer))
+			if err := c.Execute(); err != nil {
+				t.Fatalf("%s --date %q: %v", typ, in, err)
+			}
+			list, err := store.ReadAll()
+			if err != nil || len(list) != 1 {
+				t.Fatalf("%s --date %q: readall %v %d", typ, in, err, len(list))
+			}
+			got := list[0].APA7
+			if got.Date != want || got.Year == nil || *got.Year != 1999 {
+				t.Fatalf("%s --date %q: date %q year %v, want %q/1999", typ, in, got.Date, got.Year, want)
+			}
+		}
+	}
+
+	bad := b.Song()
+	bad.SetArgs([]string{"Some Song", "--date", "last spring"})
+	bad.SetOut(new(bytes.Buffer))
+	bad.SilenceUsage, bad.SilenceErrors = true, true
+	if err := bad.Execute(); err == nil {
+		t.Fatalf("expected invalid --date error")
+	}
+}
+
+func TestManualAdd_PromptedDateIsNormalized(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	run := func(date string) error {
+		// Title, Authors, Date, URL, Publisher, ISBN, Summary, Keywords
+		// prompt reads through a fresh bufio.Reader per question, so feed one byte at a time.
+		in := iotest.OneByteReader(strings.NewReader(strings.Join([]string{"Dated " + date, "Doe, Jane", date, "", "Pub", "", "A summary", "book", ""}, "\n")))
+		cmd := &cobra.Command{}
+		cmd.SetIn(in)
+		cmd.SetOut(new(bytes.Buffer))
+		return manualAdd(cmd, func([]string, string) error { return nil }, "book", nil)
+	}
+	if err := run("May 1999"); err != nil {
+		t.Fatalf("manualAdd: %v", err)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	if got := list[0].APA7; got.Date != "1999-05" || got.Year == nil || *got.Year != 1999 {
+		t.Fatalf("prompted date not normalized: date %q year %v", got.Date, got.Year)
+	}
+	if err := run("last spring"); err == nil || !strings.Contains(err.Error(), "invalid date") {
+		t.Fatalf("expected invalid date error, got %v", err)
+	}
+}
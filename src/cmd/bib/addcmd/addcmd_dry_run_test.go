@@ -0,0 +1,113 @@
+package addcmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestAdd_DryRunWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	SetDryRun(true)
+	t.Cleanup(func() { SetDryRun(false) })
+
+	commits := 0
+	commit := func([]string, string) error { commits++; return nil }
+	hints := map[string]string{"title": "Dry Title", "doi": "10.1234/dry", "journal": "J"}
+	if err := AddWithKeywords(context.Background(), commit, "article", hints, nil); err != nil {
+		t.Fatalf("dry-run add: %v", err)
+	}
+
+	b := New(commit)
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Preview Book", Identifiers: schema.Identifiers{ISBN: "9780132350884"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if err := b.writeCommitPrint(cmd, e); err != nil {
+		t.Fatalf("dry-run writeCommitPrint: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "id: "+e.ID) || !strings.Contains(got, `title: "Preview Book"`) || !strings.Contains(got, `isbn: "9780132350884"`) {
+		t.Fatalf("expected YAML preview, got %q", got)
+	}
+
+	if commits != 0 {
+		t.Fatalf("dry run should not commit, got %d commits", commits)
+	}
+	if _, err := os.Stat(store.CitationsDir); !os.IsNotExist(err) {
+		t.Fatalf("dry run should not create %s (stat err %v)", store.CitationsDir, err)
+	}
+	if _, err := os.Stat(store.BibFile); !os.IsNotExist(err) {
+		t.Fatalf("dry run should not write %s (stat err %v)", store.BibFile, err)
+	}
+}
+
+func TestAdd_DryRunBatchPreviewsWithoutStaging(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	SetDryRun(true)
+	t.Cleanup(func() { SetDryRun(false) })
+
+	slot := &batchSlot{}
+	ctx := context.WithValue(context.Background(), batchSlotKey{}, slot)
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Slot Book"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if done, err := previewDryRun(ctx, io.Discard, e); !done || err != nil {
+		t.Fatalf("previewDryRun: %v %v", done, err)
+	}
+	if slot.staged || !strings.Contains(slot.preview, `title: "Slot Book"`) {
+		t.Fatalf("dry run should leave a preview, not a staged entry: %+v", slot)
+	}
+
+	commits := 0
+	b := New(func([]string, string) error { commits++; return nil })
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := b.Batch(cmd, strings.NewReader("patent\tUS1234567B2\npatent\tUS7654321B1"), 2); err != nil {
+		t.Fatalf("dry-run batch: %v", err)
+	}
+	s := out.String()
+	if i, j := strings.Index(s, "US1234567B2"), strings.Index(s, "US7654321B1"); i < 0 || j < i || !strings.Contains(s, "batch: 2 added, 0 failed") {
+		t.Fatalf("expected both previews in line order:\n%s", s)
+	}
+	if _, err := os.Stat(store.BibFile); !os.IsNotExist(err) || commits != 0 {
+		t.Fatalf("dry-run batch should write and commit nothing (stat %v, commits %d)", err, commits)
+	}
+}
+
+func TestAdd_DryRunValidatesBeforePreview(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	SetDryRun(true)
+	t.Cleanup(func() { SetDryRun(false) })
+
+	// Provider-built entries take the same validation as manual ones: no preview of
+	// an entry that could not be written.
+	b := New(func([]string, string) error { return nil })
+	cmd := &cobra.Command{}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetContext(context.Background())
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "No Summary"}, Annotation: schema.Annotation{Keywords: []string{"book"}}}
+	if err := b.writeCommitPrint(cmd, e); err == nil || !strings.Contains(err.Error(), "summary") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("invalid entry was previewed:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,80 @@
+package addcmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestAdd_DuplicateDOIAndISBN(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	t.Cleanup(func() { SetForce(false) })
+
+	var msgs []string
+	commit := func(_ []string, msg string) error { msgs = append(msgs, msg); return nil }
+	hints := map[string]string{"title": "First Title", "doi": "10.1234/ABC", "journal": "J"}
+	if err := AddWithKeywords(context.Background(), commit, "article", hints, nil); err != nil {
+		t.Fatalf("first add: %v", err)
+	}
+	entries, _ := store.ReadAll()
+	if len(entries) != 1 {
+		t.Fatalf("want 1 entry, got %d", len(entries))
+	}
+	id := entries[0].ID
+
+	// Same DOI in another form is refused.
+	dup := map[string]string{"title": "Second Title", "doi": "https://doi.org/10.1234/abc", "journal": "J"}
+	err := AddWithKeywords(context.Background(), commit, "article", dup, nil)
+	if err == nil || !strings.Contains(err.Error(), "duplicate of "+id) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	// --force updates the existing record in place.
+	SetForce(true)
+	if err := AddWithKeywords(context.Background(), commit, "article", dup, nil); err != nil {
+		t.Fatalf("forced add: %v", err)
+	}
+	entries, _ = store.ReadAll()
+	if len(entries) != 1 || entries[0].ID != id || entries[0].APA7.Title != "Second Title" {
+		t.Fatalf("forced add should update %s: %+v", id, entries)
+	}
+	if msgs[len(msgs)-1] != "update citation: "+id {
+		t.Fatalf("unexpected commit message %q", msgs[len(msgs)-1])
+	}
+
+	// Provider-built books are checked by ISBN (hyphens ignored).
+	SetForce(false)
+	b := New(commit)
+	book := func(title string) schema.Entry {
+		return schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: title, Identifiers: schema.Identifiers{ISBN: "978-0-13-235088-4"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	}
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := b.writeCommitPrint(cmd, book("Clean Code")); err != nil {
+		t.Fatalf("book add: %v", err)
+	}
+	second := book("Clean Code (again)")
+	second.APA7.ISBN = "9780132350884"
+	if err := b.writeCommitPrint(cmd, second); err == nil || !strings.Contains(err.Error(), "duplicate of") {
+		t.Fatalf("expected ISBN duplicate error, got %v", err)
+	}
+	third := book("Clean Code (ISBN-10)")
+	third.APA7.ISBN = "0-13-235088-2"
+	if err := b.writeCommitPrint(cmd, third); err == nil || !strings.Contains(err.Error(), "duplicate of") {
+		t.Fatalf("expected ISBN-10 duplicate of the ISBN-13 record, got %v", err)
+	}
+	if entries, _ = store.ReadAll(); len(entries) != 2 {
+		t.Fatalf("duplicate book should not be written: %d entries", len(entries))
+	}
+}
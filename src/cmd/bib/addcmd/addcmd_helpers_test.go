@@ -56,6 +56,20 @@ func TestHelperFunctions_ApplyAndDerive(t *testing.T) {
 	}
 }
 
+func TestParseCreatorRoles(t *testing.T) {
+	cases := map[string]schema.Author{
+		"Doe, Jane":             {Family: "Doe", Given: "Jane"},
+		"Doe, Jane (Ed.)":       {Family: "Doe", Given: "Jane", Role: schema.RoleEditor},
+		"Roe, Rick (eds)":       {Family: "Roe", Given: "Rick", Role: schema.RoleEditor},
+		"Poe, Pat (Translator)": {Family: "Poe", Given: "Pat", Role: schema.RoleTranslator},
+	}
+	for in, want := range cases {
+		if got := parseCreator(in); got != want {
+			t.Errorf("parseCreator(%q) = %+v, want %+v", in, got, want)
+		}
+	}
+}
+
 func TestParseAuthorAndSplitAuthorsBySemi(t *testing.T) {
 	fam, giv := parseAuthor("Doe, Jane")
 	if fam != "Doe" || giv == "" {
@@ -107,7 +121,7 @@ func TestManualAdd_BookFlow(t *testing.T) {
 		"2020-01-01",      // date
 		"https://example", // url
 		"Pub",             // publisher
-		"1234567890",      // isbn
+		"0-306-40615-2",   // isbn
 		"A summary",       // summary
 		"book, test",      // keywords
 		"",
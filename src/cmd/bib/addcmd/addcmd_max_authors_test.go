@@ -0,0 +1,48 @@
+package addcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestWriteCommitPrint_TruncatesProviderAuthors(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	t.Cleanup(func() { SetMaxAuthorsStored(schema.DefaultMaxAuthorsStored) })
+	SetMaxAuthorsStored(2)
+
+	e := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Many Hands",
+		Authors: schema.Authors{{Family: "A"}, {Family: "B"}, {Family: "C"}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"article"}}}
+	cmd := &cobra.Command{}
+	var errBuf bytes.Buffer
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(&errBuf)
+	if err := New(func([]string, string) error { return nil }).writeCommitPrint(cmd, e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !strings.Contains(errBuf.String(), "stored first 2 of 3 authors") {
+		t.Fatalf("missing notice: %q", errBuf.String())
+	}
+	list, _ := store.ReadAll()
+	if got := list[0].APA7; len(got.Authors) != 2 || got.AuthorCount != 3 {
+		t.Fatalf("unexpected authors: %+v", got)
+	}
+}
+
+func TestMaxAuthorsStoredDefault_FromEnv(t *testing.T) {
+	for v, want := range map[string]int{"": schema.DefaultMaxAuthorsStored, "10": 10, " 0 ": 0, "-3": schema.DefaultMaxAuthorsStored, "many": schema.DefaultMaxAuthorsStored} {
+		t.Setenv("BIB_MAX_AUTHORS_STORED", v)
+		if got := MaxAuthorsStoredDefault(); got != want {
+			t.Errorf("MaxAuthorsStoredDefault with %q = %d, want %d", v, got, want)
+		}
+	}
+}
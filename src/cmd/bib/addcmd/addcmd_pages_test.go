@@ -0,0 +1,43 @@
+package addcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestAdd_NormalizesAndValidatesPages(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	commits := 0
+	b := New(func([]string, string) error { commits++; return nil })
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	article := func(title, pages string) schema.Entry {
+		return schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: title, Journal: "J", Pages: pages}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"article"}}}
+	}
+	if err := b.writeCommitPrint(cmd, article("Backwards", "60-45")); err == nil || !strings.Contains(err.Error(), "invalid page range") {
+		t.Fatalf("expected invalid page range error, got %v", err)
+	}
+	if commits != 0 {
+		t.Fatalf("a rejected entry should not be committed")
+	}
+	ok := article("Forwards", "pp. 45 – 60")
+	if err := b.writeCommitPrint(cmd, ok); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 1 || list[0].APA7.Pages != "45-60" {
+		t.Fatalf("expected one entry with pages 45-60: %v %+v", err, list)
+	}
+}
@@ -180,7 +180,7 @@ func TestAdd_Providers_DOI_YouTube_OMDb_TMDb_RFC_ISBN(t *testing.T) {
 	// 6) Book by ISBN via OpenLibrary (jscmd=data)
 	openlibrary.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
 		if strings.Contains(req.URL.Host, "openlibrary.org") && strings.Contains(req.URL.RawQuery, "jscmd=data") {
-			key := "ISBN:1234567890"
+			key := "ISBN:0306406152"
 			payload := map[string]any{
 				key: map[string]any{
 					"title":        "OL Book",
@@ -195,7 +195,7 @@ func TestAdd_Providers_DOI_YouTube_OMDb_TMDb_RFC_ISBN(t *testing.T) {
 		return textResp(404, "")
 	}})
 	book := b.Book()
-	book.SetArgs([]string{"--isbn", "1234567890"})
+	book.SetArgs([]string{"--isbn", "0306406152"})
 	book.SetOut(new(bytes.Buffer))
 	if err := book.Execute(); err != nil {
 		t.Fatalf("book isbn: %v", err)
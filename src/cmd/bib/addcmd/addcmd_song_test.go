@@ -0,0 +1,43 @@
+package addcmd
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	songfetch "bibliography/src/internal/song"
+	"bibliography/src/internal/store"
+)
+
+func TestAdd_SongPrintsProviderAttempts(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	// iTunes has nothing; MusicBrainz knows the recording.
+	songfetch.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if strings.Contains(req.URL.Host, "musicbrainz.org") {
+			return textResp(200, `{"recordings":[{"title":"Blue","artist-credit":[{"name":"Doe"}],"releases":[{"title":"Colours","date":"2020-01-01"}]}]}`)
+		}
+		return textResp(200, `{"resultCount":0,"results":[]}`)
+	}})
+	t.Cleanup(func() { songfetch.SetHTTPClient(&http.Client{}) })
+
+	c := New(func([]string, string) error { return nil }).Song()
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetArgs([]string{"Blue", "--artist", "Doe"})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("song: %v", err)
+	}
+	want := "tried: itunes: status: not found\ntried: musicbrainz: status: found\nsource: musicbrainz\n"
+	if !strings.HasPrefix(out.String(), want) {
+		t.Fatalf("output:\n%s\nwant prefix:\n%s", out.String(), want)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 1 || list[0].APA7.Title != "Blue" {
+		t.Fatalf("stored: %+v %v", list, err)
+	}
+}
@@ -0,0 +1,55 @@
+package addcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/cmd/bib/citecmd"
+	"bibliography/src/internal/store"
+)
+
+func TestStandard_AddRoundTripAndCite(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	b := New(func(paths []string, msg string) error { return nil })
+
+	missing := b.Standard()
+	missing.SetArgs([]string{"--title", "No Body", "--designation", "X 1"})
+	missing.SetOut(new(bytes.Buffer))
+	missing.SilenceUsage, missing.SilenceErrors = true, true
+	if err := missing.Execute(); err == nil {
+		t.Fatalf("expected error without --body")
+	}
+
+	std := b.Standard()
+	std.SetArgs([]string{"--designation", "ISO/IEC 27001:2022", "--body", "International Organization for Standardization", "--title", "Information security management systems", "--date", "2022-10-25"})
+	std.SetOut(new(bytes.Buffer))
+	if err := std.Execute(); err != nil {
+		t.Fatalf("standard: %v", err)
+	}
+	lib, err := os.ReadFile(store.BibFile)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	for _, want := range []string{"@techreport{", "institution = {International Organization for Standardization}", "number = {ISO/IEC 27001:2022}"} {
+		if !strings.Contains(string(lib), want) {
+			t.Fatalf("library missing %q:\n%s", want, lib)
+		}
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	got := list[0]
+	if got.Type != "standard" || got.APA7.Designation != "ISO/IEC 27001:2022" || got.APA7.Issue != "" {
+		t.Fatalf("unexpected round trip: %+v", got.APA7)
+	}
+	want := "International Organization for Standardization. (2022). Information security management systems (ISO/IEC 27001:2022)."
+	if c := citecmd.APACitation(got); c != want {
+		t.Fatalf("citation:\n got %q\nwant %q", c, want)
+	}
+}
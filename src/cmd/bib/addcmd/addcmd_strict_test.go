@@ -0,0 +1,41 @@
+package addcmd
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/store"
+)
+
+func TestAdd_StrictRejectsStandardWithoutBody(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	SetStrict(true)
+	t.Cleanup(func() { SetStrict(false) })
+
+	commits := 0
+	commit := func([]string, string) error { commits++; return nil }
+	hints := map[string]string{"title": "Security and Privacy Controls", "designation": "SP 800-53 Rev. 5", "publisher": "NIST"}
+	err := AddWithKeywords(context.Background(), commit, "standard", hints, nil)
+	if err == nil || !strings.Contains(err.Error(), "missing standards body") {
+		t.Fatalf("expected strict standards body error, got %v", err)
+	}
+	if commits != 0 {
+		t.Fatalf("strict failure committed %d times", commits)
+	}
+	if _, err := os.Stat(store.BibFile); !os.IsNotExist(err) {
+		t.Fatalf("strict failure wrote the library: %v", err)
+	}
+
+	hints["standards_body"] = "National Institute of Standards and Technology"
+	if err := AddWithKeywords(context.Background(), commit, "standard", hints, nil); err != nil {
+		t.Fatalf("strict add with body: %v", err)
+	}
+	if commits != 1 {
+		t.Fatalf("commits = %d; want 1", commits)
+	}
+}
@@ -0,0 +1,332 @@
+package addcmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+	youtube "bibliography/src/internal/video"
+)
+
+// BatchFile returns the "add batch" subcommand, which runs Batch over --file.
+func (b Builder) BatchFile() *cobra.Command {
+	var file string
+	var workers int
+	c := &cobra.Command{
+		Use:   "batch --file refs.txt",
+		Short: "Add one reference per line (URL, doi:, isbn:, rfc:, or type<TAB>identifier), committing once",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			return b.Batch(cmd, f, workers)
+		},
+	}
+	c.Flags().StringVar(&file, "file", "", "file of references, one per line")
+	AddConcurrencyFlag(c, &workers)
+	_ = c.MarkFlagRequired("file")
+	return c
+}
+
+// AddConcurrencyFlag registers --concurrency, the number of batch lines fetched at once.
+func AddConcurrencyFlag(c *cobra.Command, workers *int) {
+	c.Flags().IntVar(workers, "concurrency", 1, "fetch up to N batch lines at once (results are still written in line order)")
+}
+
+// batchSlot receives the finished entry of one batch line in place of a write: when a
+// subcommand runs with a slot in its context, setWriteSource and the write helpers record
+// into it so lines can be fetched concurrently and written in order afterwards.
+type batchSlot struct {
+	source  string
+	entry   schema.Entry
+	staged  bool
+	preview string // the --dry-run YAML preview, printed in place of a write
+}
+
+type batchSlotKey struct{}
+
+func batchSlotFrom(ctx context.Context) *batchSlot {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(batchSlotKey{}).(*batchSlot)
+	return s
+}
+
+// stageEntry hands e to the batch slot in ctx, reporting whether there was one.
+func stageEntry(ctx context.Context, e schema.Entry) bool {
+	s := batchSlotFrom(ctx)
+	if s == nil {
+		return false
+	}
+	s.entry, s.staged = e, true
+	return true
+}
+
+// setWriteSource records the provenance label for cmd's entry: on its batch slot inside
+// a batch, otherwise on the store.
+func setWriteSource(cmd *cobra.Command, src string) {
+	if s := batchSlotFrom(cmd.Context()); s != nil {
+		s.source = src
+		return
+	}
+	store.SetWriteSource(src)
+}
+
+type batchLine struct {
+	n    int
+	text string
+}
+
+type batchResult struct {
+	slot *batchSlot
+	err  error
+}
+
+// Batch adds one item per line read from r, dispatching each to the matching
+// subcommand. A line is either "type<TAB>identifier-or-url" or a bare reference whose
+// type is detected (see detectBatchType). Blank lines and '#' comments are skipped.
+// Up to workers lines are fetched at once; entries are then written in line order,
+// results are reported per line number, and all written files are committed once at the
+// end. Cancelling the context before the fetches finish aborts without writing anything.
+func (b Builder) Batch(cmd *cobra.Command, r io.Reader, workers int) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	out := cmd.OutOrStdout()
+	var lines []batchLine
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		lines = append(lines, batchLine{n: n, text: text})
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	results := b.fetchBatch(ctx, lines, workers)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("batch aborted, nothing written: %w", err)
+	}
+	var paths []string
+	added, failed := 0, 0
+	for i, l := range lines {
+		p, err := "", results[i].err
+		if err == nil {
+			p, err = writeStaged(out, results[i].slot)
+		}
+		if err != nil {
+			failed++
+			if _, perr := fmt.Fprintf(out, "line %d: error: %v\n", l.n, err); perr != nil {
+				return perr
+			}
+			continue
+		}
+		added++
+		if p != "" {
+			paths = append(paths, p)
+		}
+		if _, perr := fmt.Fprintf(out, "line %d: ok\n", l.n); perr != nil {
+			return perr
+		}
+	}
+	if len(paths) > 0 {
+		msg := fmt.Sprintf("add citations: batch of %d\n\n%s", added, strings.Join(paths, "\n"))
+		if err := b.Commit(append(paths, store.BibFile), msg); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(out, "batch: %d added, %d failed\n", added, failed); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d batch line(s) failed", failed)
+	}
+	return nil
+}
+
+// fetchBatch runs each line's subcommand on a pool of workers, staging entries rather
+// than writing them; results are indexed like lines. Lines not started before ctx is
+// cancelled report ctx.Err().
+func (b Builder) fetchBatch(ctx context.Context, lines []batchLine, workers int) []batchResult {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]batchResult, len(lines))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i].err = err
+					continue
+				}
+				results[i] = b.stageLine(ctx, lines[i].text)
+			}
+		}()
+	}
+feed:
+	for i := range lines {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// stageLine runs the subcommand for one batch line with a fresh slot in its context.
+func (b Builder) stageLine(ctx context.Context, text string) batchResult {
+	sub, err := b.batchCommand(text)
+	if err != nil {
+		return batchResult{err: err}
+	}
+	slot := &batchSlot{source: "manual"}
+	sub.SetContext(context.WithValue(ctx, batchSlotKey{}, slot))
+	sub.SetOut(io.Discard)
+	sub.SetErr(io.Discard)
+	sub.SilenceUsage, sub.SilenceErrors = true, true
+	if err := sub.Execute(); err != nil {
+		return batchResult{err: err}
+	}
+	return batchResult{slot: slot}
+}
+
+// writeStaged writes a staged batch entry (or prints its --dry-run preview) and
+// returns its path; a line that staged nothing writes nothing.
+func writeStaged(out io.Writer, s *batchSlot) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+	if s.preview != "" {
+		_, err := fmt.Fprint(out, s.preview)
+		return "", err
+	}
+	if !s.staged {
+		return "", nil
+	}
+	e := s.entry
+	if _, err := resolveDuplicate(&e); err != nil {
+		return "", err
+	}
+	store.SetWriteSource(s.source)
+	return store.WriteEntry(e)
+}
+
+// batchCommand maps a "type<TAB>identifier" line to a configured add subcommand.
+func (b Builder) batchCommand(line string) (*cobra.Command, error) {
+	typ, id, ok := strings.Cut(line, "\t")
+	if !ok {
+		f := strings.Fields(line)
+		switch len(f) {
+		case 1:
+			var err error
+			if typ, id, err = detectBatchType(f[0]); err != nil {
+				return nil, err
+			}
+		case 2:
+			typ, id = f[0], f[1]
+		default:
+			return nil, fmt.Errorf("expected type<TAB>identifier, got %q", line)
+		}
+	}
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("missing identifier for %s", typ)
+	}
+	isURL := strings.HasPrefix(id, "http://") || strings.HasPrefix(id, "https://")
+	var c *cobra.Command
+	var args []string
+	switch typ {
+	case "article":
+		c = b.Article()
+		if isURL {
+			args = []string{"--url", id}
+		} else {
+			args = []string{"--doi", strings.TrimPrefix(strings.TrimPrefix(id, "doi:"), "DOI:")}
+		}
+	case "book":
+		c = b.Book()
+		args = []string{"--isbn", strings.TrimPrefix(strings.TrimPrefix(id, "isbn:"), "ISBN:")}
+	case "rfc":
+		c, args = b.RFC(), []string{id}
+	case "site", "website":
+		c, args = b.Site(), []string{id}
+	case "video":
+		c, args = b.Video(), []string{"--url", id}
+	case "patent":
+		c = b.Patent()
+		if isURL {
+			args = []string{"--url", id}
+		} else {
+			args = []string{"--number", id, "--url", "https://patents.google.com/patent/" + id}
+		}
+	case "movie":
+		c, args = b.Movie(), []string{id}
+	case "song":
+		c, args = b.Song(), []string{id}
+	case "podcast":
+		c, args = b.Podcast(), []string{"--url", id}
+	default:
+		return nil, fmt.Errorf("unsupported type %q", typ)
+	}
+	c.SetArgs(args)
+	return c, nil
+}
+
+// detectBatchType infers the add type for a bare batch reference: "doi:", "isbn:", and
+// "rfc:" prefixes (or a bare 10.x DOI), doi.org, video, podcast, patent, and RFC Editor
+// URLs, and any other URL as an article page.
+func detectBatchType(ref string) (typ, id string, err error) {
+	prefix, rest, _ := strings.Cut(ref, ":")
+	switch strings.ToLower(prefix) {
+	case "doi":
+		return "article", rest, nil
+	case "isbn":
+		return "book", rest, nil
+	case "rfc":
+		return "rfc", rest, nil
+	}
+	if strings.HasPrefix(ref, "10.") && strings.Contains(ref, "/") {
+		return "article", ref, nil
+	}
+	u, perr := url.Parse(ref)
+	if perr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("cannot detect type of %q (want a URL, doi:, isbn:, or rfc: reference)", ref)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "doi.org" || host == "dx.doi.org":
+		return "article", strings.TrimPrefix(u.Path, "/"), nil
+	case youtube.ProviderFor(ref) != "":
+		return "video", ref, nil
+	case host == "podcasts.apple.com":
+		return "podcast", ref, nil
+	case host == "patents.google.com":
+		return "patent", ref, nil
+	case host == "rfc-editor.org" && strings.HasPrefix(u.Path, "/rfc/"):
+		return "rfc", strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(u.Path, "/rfc/"), ".html"), ".txt"), nil
+	}
+	return "article", ref, nil
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/archivecmd"
+	"github.com/spf13/cobra"
+)
+
+// newArchiveCmd creates the "archive" command to snapshot entry URLs in the Wayback Machine.
+func newArchiveCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(archivecmd.New(optionalCommit(&noCommit)), &noCommit)
+}
@@ -0,0 +1,100 @@
+package archivecmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+	"bibliography/src/internal/wayback"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the archive command, which stores Wayback Machine snapshots of entry URLs.
+func New(commit CommitFunc) *cobra.Command {
+	var id string
+	var all, force bool
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Save entry URLs to the Wayback Machine and record the snapshot URL",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id = strings.TrimSpace(id)
+			if (id == "") == !all {
+				return fmt.Errorf("specify exactly one of --id or --all")
+			}
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			targets, err := selectTargets(entries, id, force)
+			if err != nil {
+				return err
+			}
+			archived, failed := 0, 0
+			for _, e := range targets {
+				snap, err := wayback.Save(cmd.Context(), e.APA7.URL)
+				if err != nil {
+					if !all {
+						return err
+					}
+					failed++
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", e.ID, err)
+					continue
+				}
+				e.APA7.ArchivedURL = snap
+				if _, err := store.WriteEntry(e); err != nil {
+					return err
+				}
+				archived++
+				fmt.Fprintf(cmd.OutOrStdout(), "archived %s: %s\n", e.ID, snap)
+			}
+			if archived > 0 {
+				msg := fmt.Sprintf("archive citation: %s", id)
+				if all {
+					msg = fmt.Sprintf("archive %d citation(s)", archived)
+				}
+				if err := commit([]string{store.BibFile}, msg); err != nil {
+					return err
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("archive: %d of %d URL(s) failed", failed, len(targets))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&id, "id", "", "Entry ID (uuid)")
+	_ = cmd.RegisterFlagCompletionFunc("id", complete.IDs)
+	cmd.Flags().BoolVar(&all, "all", false, "Archive every entry with a URL and no snapshot yet")
+	cmd.Flags().BoolVar(&force, "force", false, "With --all, also re-archive entries that already have a snapshot")
+	return cmd
+}
+
+// selectTargets returns the entry with id (which must have a URL), or, when id is
+// empty, every entry with a URL that is not yet archived (all of them with force).
+func selectTargets(entries []schema.Entry, id string, force bool) ([]schema.Entry, error) {
+	if id != "" {
+		for _, e := range entries {
+			if strings.EqualFold(e.ID, id) {
+				if strings.TrimSpace(e.APA7.URL) == "" {
+					return nil, fmt.Errorf("%s has no url to archive", e.ID)
+				}
+				return []schema.Entry{e}, nil
+			}
+		}
+		return nil, fmt.Errorf("no citation found for id %s", id)
+	}
+	var out []schema.Entry
+	for _, e := range entries {
+		if strings.TrimSpace(e.APA7.URL) != "" && (force || strings.TrimSpace(e.APA7.ArchivedURL) == "") {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,138 @@
+package archivecmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/cmd/bib/citecmd"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+	"bibliography/src/internal/wayback"
+)
+
+type fakeDoer func(*http.Request) *http.Response
+
+func (f fakeDoer) Do(req *http.Request) (*http.Response, error) { return f(req), nil }
+
+const (
+	siteID  = "00000000-0000-4000-8000-0000000000e1"
+	doneID  = "00000000-0000-4000-8000-0000000000e2"
+	bookID  = "00000000-0000-4000-8000-0000000000e3"
+	snapURL = "https://web.archive.org/web/20240102030405/https://example.com/page"
+)
+
+func setup(t *testing.T) *[]string {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	for _, e := range []schema.Entry{
+		{ID: siteID, Type: "website", APA7: schema.APA7{Title: "Page", URL: "https://example.com/page", Accessed: "2024-01-01"}},
+		{ID: doneID, Type: "website", APA7: schema.APA7{Title: "Done", URL: "https://example.com/done", Accessed: "2024-01-01", ArchivedURL: "https://web.archive.org/web/2023/https://example.com/done"}},
+		{ID: bookID, Type: "book", APA7: schema.APA7{Title: "Book"}},
+	} {
+		e.Annotation = schema.Annotation{Summary: "s", Keywords: []string{"k"}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	var saved []string
+	wayback.SetHTTPClient(fakeDoer(func(req *http.Request) *http.Response {
+		saved = append(saved, req.URL.String())
+		target := strings.TrimPrefix(req.URL.String(), "https://web.archive.org/save/")
+		h := http.Header{"Content-Location": {"/web/20240102030405/" + target}}
+		return &http.Response{StatusCode: http.StatusOK, Header: h, Body: io.NopCloser(strings.NewReader(""))}
+	}))
+	t.Cleanup(func() { wayback.SetHTTPClient(httpx.DefaultClient()) })
+	return &saved
+}
+
+func run(t *testing.T, commits *[]string, args ...string) (string, error) {
+	t.Helper()
+	cmd := New(func(paths []string, msg string) error { *commits = append(*commits, msg); return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func entryByID(t *testing.T, id string) schema.Entry {
+	t.Helper()
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e
+		}
+	}
+	t.Fatalf("entry %s not found", id)
+	return schema.Entry{}
+}
+
+func TestArchiveByID(t *testing.T) {
+	saved := setup(t)
+	var commits []string
+	out, err := run(t, &commits, "--id", siteID)
+	if err != nil {
+		t.Fatalf("archive: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, snapURL) || len(commits) != 1 || commits[0] != "archive citation: "+siteID {
+		t.Fatalf("out=%q commits=%v", out, commits)
+	}
+	if len(*saved) != 1 || (*saved)[0] != "https://web.archive.org/save/https://example.com/page" {
+		t.Fatalf("save requests = %v", *saved)
+	}
+	e := entryByID(t, siteID)
+	if e.APA7.ArchivedURL != snapURL {
+		t.Fatalf("archived url = %q", e.APA7.ArchivedURL)
+	}
+	bib, err := os.ReadFile(store.BibFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bib), "note = {Archived at "+snapURL+"}") {
+		t.Fatalf("bibtex should carry the snapshot as a note:\n%s", bib)
+	}
+	if c := citecmd.APACitation(e); !strings.HasSuffix(c, "https://example.com/page. Archived at "+snapURL+".") {
+		t.Fatalf("citation = %q", c)
+	}
+
+	if _, err := run(t, &commits, "--id", bookID); err == nil || !strings.Contains(err.Error(), "no url") {
+		t.Fatalf("expected no url error, got %v", err)
+	}
+	if _, err := run(t, &commits, "--id", siteID, "--all"); err == nil {
+		t.Fatalf("--id with --all should error")
+	}
+}
+
+func TestArchiveAll(t *testing.T) {
+	saved := setup(t)
+	var commits []string
+	if out, err := run(t, &commits, "--all"); err != nil {
+		t.Fatalf("archive --all: %v\n%s", err, out)
+	}
+	if len(*saved) != 1 || len(commits) != 1 || commits[0] != "archive 1 citation(s)" {
+		t.Fatalf("only the unarchived entry should be saved: saved=%v commits=%v", *saved, commits)
+	}
+	*saved, commits = nil, nil
+	if _, err := run(t, &commits, "--all", "--force"); err != nil {
+		t.Fatal(err)
+	}
+	if len(*saved) != 2 || commits[0] != "archive 2 citation(s)" {
+		t.Fatalf("--force should re-archive: saved=%v commits=%v", *saved, commits)
+	}
+	if e := entryByID(t, doneID); e.APA7.ArchivedURL != "https://web.archive.org/web/20240102030405/https://example.com/done" {
+		t.Fatalf("re-archived url = %q", e.APA7.ArchivedURL)
+	}
+}
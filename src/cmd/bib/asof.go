@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bibliography/src/internal/dates"
+)
+
+var asOf string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&asOf, "as-of", "", "Pin today's date (YYYY-MM-DD) for accessed dates and timestamps (reproducible runs)")
+}
+
+// applyAsOf pins the dates clock to the given day (UTC midnight) when set.
+func applyAsOf(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return fmt.Errorf("invalid --as-of %q (want YYYY-MM-DD)", s)
+	}
+	dates.SetClock(func() time.Time { return t })
+	return nil
+}
@@ -0,0 +1,12 @@
+package main
+
+import "bibliography/src/internal/httpx"
+
+var noCache bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Bypass the provider response cache in data/.cache (enabled by BIB_CACHE_TTL, e.g. 24h)")
+}
+
+// applyNoCache turns the provider response cache off for this run when --no-cache is set.
+func applyNoCache(disabled bool) { httpx.SetCacheDisabled(disabled) }
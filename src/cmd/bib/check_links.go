@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/checklinkscmd"
+	"github.com/spf13/cobra"
+)
+
+// newCheckLinksCmd creates the "check-links" command to find link rot.
+func newCheckLinksCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(checklinkscmd.New(optionalCommit(&noCommit)), &noCommit)
+}
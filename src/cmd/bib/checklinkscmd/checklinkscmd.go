@@ -0,0 +1,160 @@
+package checklinkscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/linkcheck"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// LinkResult is one probed URL; Fixed is the doi.org URL that replaced a dead article link.
+type LinkResult struct {
+	linkcheck.Result
+	Fixed string `json:"fixed,omitempty"`
+}
+
+// Report is the --json document.
+type Report struct {
+	Checked    int          `json:"checked"`
+	Broken     int          `json:"broken"`
+	Redirected int          `json:"redirected"`
+	Fixed      int          `json:"fixed"`
+	Results    []LinkResult `json:"results"`
+}
+
+// New returns the check-links command, which probes every entry URL for link rot.
+func New(commit CommitFunc) *cobra.Command {
+	var fix, asJSON bool
+	var workers int
+	cmd := &cobra.Command{
+		Use:   "check-links",
+		Short: "Probe every entry URL (HEAD, then GET) and flag 4xx/5xx responses and redirects",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			rep, err := checkLinks(cmd, entries, workers, fix)
+			if err != nil {
+				return err
+			}
+			if rep.Fixed > 0 {
+				if err := commit([]string{store.BibFile}, fmt.Sprintf("check-links: fix %d dead article URL(s)", rep.Fixed)); err != nil {
+					return err
+				}
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(rep); err != nil {
+					return err
+				}
+			} else if err := render(cmd, rep); err != nil {
+				return err
+			}
+			if rep.Broken > 0 {
+				return fmt.Errorf("check-links found %d broken link(s)", rep.Broken)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&fix, "fix", false, "For articles with a dead URL, switch to the doi.org link when the DOI resolves, and commit")
+	cmd.Flags().IntVar(&workers, "concurrency", 8, "Number of concurrent requests")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
+
+// checkLinks probes the URL of each entry. With fix, a dead article URL is replaced by
+// its doi.org link (store.NormalizeArticleDOI) when that DOI resolves; fixed links no
+// longer count as broken.
+func checkLinks(cmd *cobra.Command, entries []schema.Entry, workers int, fix bool) (Report, error) {
+	byID := map[string]schema.Entry{}
+	var targets []linkcheck.Target
+	for _, e := range entries {
+		if u := strings.TrimSpace(e.APA7.URL); u != "" {
+			byID[e.ID] = e
+			targets = append(targets, linkcheck.Target{ID: e.ID, Kind: linkcheck.KindURL, Value: u})
+		}
+	}
+	rep := Report{Checked: len(targets), Results: make([]LinkResult, 0, len(targets))}
+	for _, r := range linkcheck.CheckAll(cmd.Context(), targets, workers) {
+		lr := LinkResult{Result: r}
+		switch {
+		case r.Status == linkcheck.Redirected:
+			rep.Redirected++
+		case !r.Status.Reachable():
+			if fix {
+				fixed, err := fixArticleURL(cmd, byID[r.ID])
+				if err != nil {
+					return Report{}, err
+				}
+				lr.Fixed = fixed
+			}
+			if lr.Fixed != "" {
+				rep.Fixed++
+			} else {
+				rep.Broken++
+			}
+		}
+		rep.Results = append(rep.Results, lr)
+	}
+	return rep, nil
+}
+
+// fixArticleURL points a dead article URL at its doi.org link and writes the entry when
+// the DOI resolves. It returns the new URL, or "" when nothing was changed.
+func fixArticleURL(cmd *cobra.Command, e schema.Entry) (string, error) {
+	if !store.NormalizeArticleDOI(&e) {
+		return "", nil
+	}
+	if r := linkcheck.Check(cmd.Context(), linkcheck.Target{ID: e.ID, Kind: linkcheck.KindDOI, Value: e.APA7.DOI}); r.Status != linkcheck.OK {
+		return "", nil
+	}
+	if _, err := store.WriteEntry(e); err != nil {
+		return "", err
+	}
+	return e.APA7.URL, nil
+}
+
+// render prints every URL with its status code, marking broken links with "!" and
+// showing redirect targets, followed by a one-line summary.
+func render(cmd *cobra.Command, rep Report) error {
+	out := cmd.OutOrStdout()
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "\tSTATUS\tCODE\tID\tURL\tDETAIL"); err != nil {
+		return err
+	}
+	for _, r := range rep.Results {
+		flag, detail := "", r.Detail
+		switch {
+		case r.Fixed != "":
+			detail = "fixed -> " + r.Fixed
+		case r.Status == linkcheck.Redirected:
+			flag, detail = "~", "-> "+r.Location
+		case !r.Status.Reachable():
+			flag = "!"
+		}
+		code := "-"
+		if r.Code != 0 {
+			code = fmt.Sprint(r.Code)
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", flag, r.Status, code, r.ID, r.Value, detail); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "checked %d: %d broken, %d redirected, %d fixed\n", rep.Checked, rep.Broken, rep.Redirected, rep.Fixed)
+	return err
+}
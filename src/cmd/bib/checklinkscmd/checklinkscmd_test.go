@@ -0,0 +1,173 @@
+package checklinkscmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/linkcheck"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// doiToServer sends doi.org probes to the test server under /doi/ and does not follow redirects.
+type doiToServer struct {
+	srv    *httptest.Server
+	client *http.Client
+}
+
+func (d doiToServer) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "doi.org" {
+		r2 := req.Clone(req.Context())
+		u := *req.URL
+		u.Scheme, u.Host, u.Path = "http", strings.TrimPrefix(d.srv.URL, "http://"), "/doi"+req.URL.Path
+		r2.URL, r2.Host = &u, ""
+		req = r2
+	}
+	return d.client.Do(req)
+}
+
+func setup(t *testing.T) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/head-rejected":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/boom":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/doi/10.1/live":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	linkcheck.SetHTTPClient(doiToServer{srv: srv, client: &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}})
+	t.Cleanup(func() { linkcheck.SetHTTPClient(&http.Client{}) })
+	return srv
+}
+
+func writeEntries(t *testing.T, es ...schema.Entry) {
+	t.Helper()
+	for _, e := range es {
+		e.APA7.Accessed = "2024-01-01"
+		e.Annotation = schema.Annotation{Summary: "s", Keywords: []string{"k"}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func run(t *testing.T, commit CommitFunc, args ...string) (string, error) {
+	t.Helper()
+	cmd := New(commit)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+const (
+	okID      = "00000000-0000-4000-8000-0000000000c1"
+	headID    = "00000000-0000-4000-8000-0000000000c2"
+	movedID   = "00000000-0000-4000-8000-0000000000c3"
+	boomID    = "00000000-0000-4000-8000-0000000000c4"
+	goneID    = "00000000-0000-4000-8000-0000000000c5"
+	noURLID   = "00000000-0000-4000-8000-0000000000c6"
+	articleID = "00000000-0000-4000-8000-0000000000c7"
+)
+
+func TestCheckLinksMixedStatuses(t *testing.T) {
+	srv := setup(t)
+	writeEntries(t,
+		schema.Entry{ID: okID, Type: "website", APA7: schema.APA7{Title: "OK", URL: srv.URL + "/ok"}},
+		schema.Entry{ID: headID, Type: "website", APA7: schema.APA7{Title: "Head", URL: srv.URL + "/head-rejected"}},
+		schema.Entry{ID: movedID, Type: "website", APA7: schema.APA7{Title: "Moved", URL: srv.URL + "/moved"}},
+		schema.Entry{ID: boomID, Type: "website", APA7: schema.APA7{Title: "Boom", URL: srv.URL + "/boom"}},
+		schema.Entry{ID: goneID, Type: "website", APA7: schema.APA7{Title: "Gone", URL: srv.URL + "/gone"}},
+		schema.Entry{ID: noURLID, Type: "book", APA7: schema.APA7{Title: "No URL"}},
+	)
+	noCommit := func([]string, string) error { t.Fatalf("check-links without --fix should not commit"); return nil }
+
+	out, err := run(t, noCommit, "--concurrency", "3")
+	if err == nil || !strings.Contains(err.Error(), "2 broken link(s)") {
+		t.Fatalf("expected 2 broken links, got %v\n%s", err, out)
+	}
+	for _, want := range []string{"500", "404", "301", "-> /ok", "checked 5: 2 broken, 1 redirected, 0 fixed"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in %q", want, out)
+		}
+	}
+	if strings.Contains(out, noURLID) {
+		t.Fatalf("entries without a URL should be skipped: %q", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, goneID) && !strings.HasPrefix(line, "!") {
+			t.Fatalf("404 should be flagged: %q", line)
+		}
+		if strings.Contains(line, movedID) && !strings.HasPrefix(line, "~") {
+			t.Fatalf("redirect should be flagged: %q", line)
+		}
+		if strings.Contains(line, headID) && (!strings.Contains(line, "200") || strings.HasPrefix(line, "!")) {
+			t.Fatalf("HEAD-rejecting URL should fall back to GET: %q", line)
+		}
+	}
+
+	out, _ = run(t, noCommit, "--json")
+	var rep Report
+	if err := json.Unmarshal([]byte(out), &rep); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if rep.Checked != 5 || rep.Broken != 2 || rep.Redirected != 1 || len(rep.Results) != 5 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+	for _, r := range rep.Results {
+		if r.ID == boomID && (r.Code != 500 || r.Status != linkcheck.Failed) {
+			t.Fatalf("unexpected result: %+v", r)
+		}
+	}
+}
+
+func TestCheckLinksFixArticleDOI(t *testing.T) {
+	srv := setup(t)
+	writeEntries(t,
+		schema.Entry{ID: articleID, Type: "article", APA7: schema.APA7{Title: "Article", URL: srv.URL + "/gone", Identifiers: schema.Identifiers{DOI: "10.1/live"}}},
+		schema.Entry{ID: goneID, Type: "website", APA7: schema.APA7{Title: "Gone", URL: srv.URL + "/gone"}},
+	)
+	var commits []string
+	out, err := run(t, func(paths []string, msg string) error { commits = append(commits, msg); return nil }, "--fix")
+	if err == nil || !strings.Contains(err.Error(), "1 broken link(s)") {
+		t.Fatalf("the website link cannot be fixed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "fixed -> https://doi.org/10.1/live") || !strings.Contains(out, "1 broken, 0 redirected, 1 fixed") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if len(commits) != 1 || commits[0] != "check-links: fix 1 dead article URL(s)" {
+		t.Fatalf("commits = %v", commits)
+	}
+	e, ok, err := store.FindByDOI("10.1/live")
+	if err != nil || !ok {
+		t.Fatalf("find: %v %v", ok, err)
+	}
+	if e.APA7.URL != "https://doi.org/10.1/live" {
+		t.Fatalf("article URL not fixed: %q", e.APA7.URL)
+	}
+}
@@ -2,28 +2,67 @@ package citecmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/lang"
 	"bibliography/src/internal/names"
 	"bibliography/src/internal/schema"
 	"bibliography/src/internal/store"
 	"bibliography/src/internal/stringsx"
+	"bibliography/src/internal/titlecase"
 )
 
-// New returns the cite command which prints APA7 and in‑text citations for an id.
+// New returns the cite command which prints a reference (APA7 unless --style says
+// otherwise) and in‑text citation for an id, or (with --all) the whole library as a
+// sorted reference list.
 func New() *cobra.Command {
+	var wrap int
+	var id string
+	var all, intext, narrative bool
+	var opts Options
 	cmd := &cobra.Command{
-		Use:   "cite <id>",
-		Short: "Print APA7 citation and in-text citation for a work",
-		Args:  cobra.ExactArgs(1),
+		Use:   "cite [<id> | --id <id> | --all]",
+		Short: "Print an APA7 (or --style mla|chicago) citation and in-text citation for a work (or --all as a reference list)",
+		Args:  cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return complete.IDs(cmd, args, toComplete)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			id := strings.TrimSpace(args[0])
+			if len(args) == 1 {
+				if strings.TrimSpace(id) != "" {
+					return fmt.Errorf("give the id as an argument or with --id, not both")
+				}
+				id = args[0]
+			}
+			id = strings.TrimSpace(id)
+			if all == (id != "") {
+				return fmt.Errorf("provide an id (or --id) or --all")
+			}
+			if (intext || narrative) && all {
+				return fmt.Errorf("--intext and --narrative apply to a single id, not --all")
+			}
+			if !IsValidStyle(opts.Style) {
+				return fmt.Errorf("invalid --style %q (want one of %s)", opts.Style, strings.Join(Styles, ", "))
+			}
 			entries, err := store.ReadAll()
 			if err != nil {
 				return err
 			}
+			if all {
+				for _, ref := range ReferenceList(entries, opts) {
+					if _, err := fmt.Fprintln(cmd.OutOrStdout(), Wrap(ref, wrap)); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
 			var found *schema.Entry
 			for i := range entries {
 				if strings.EqualFold(entries[i].ID, id) {
@@ -34,39 +73,144 @@ func New() *cobra.Command {
 			if found == nil {
 				return fmt.Errorf("no citation found for id %s", id)
 			}
-			citation := APACitation(*found)
-			inline := toInTextCitation(*found)
+			if narrative {
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), NarrativeCitation(*found))
+				return err
+			}
+			if intext {
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), InTextCitationFor(*found, opts.Style))
+				return err
+			}
+			citation := Wrap(Citation(*found, opts), wrap)
+			inline := InTextCitationFor(*found, opts.Style)
 			_, err = fmt.Fprintf(cmd.OutOrStdout(), "\ncitation:\n%s\n\nin text:\n%s\n\n", citation, inline)
 			return err
 		},
 	}
+	cmd.Flags().IntVar(&wrap, "wrap", 0, "Wrap the reference to N columns with a hanging indent (0 = no wrapping)")
+	cmd.Flags().StringVar(&id, "id", "", "ID of the work to cite (same as the positional argument)")
+	_ = cmd.RegisterFlagCompletionFunc("id", complete.IDs)
+	cmd.Flags().BoolVar(&all, "all", false, "Print every entry as an alphabetical reference list")
+	cmd.Flags().BoolVar(&intext, "intext", false, "Print only the parenthetical in-text citation, e.g. (Smith & Doe, 2020)")
+	cmd.Flags().BoolVar(&narrative, "narrative", false, "Print only the APA7 narrative in-text citation, e.g. Smith and Doe (2020)")
+	cmd.Flags().StringVar(&opts.Style, "style", StyleAPA7, "Citation style: "+strings.Join(Styles, ", "))
+	cmd.Flags().BoolVar(&opts.SentenceCase, "sentence-case", false, "Convert titles to sentence case (proper nouns are lowercased too; review the output)")
+	cmd.Flags().BoolVar(&opts.TitleCase, "title-case", false, "Convert journal and container names to title case")
+	cmd.Flags().BoolVar(&opts.Italics, "italics", false, "Mark italic parts (journal and volume, or the title) with *asterisks*")
 	return cmd
 }
 
-func APACitation(e schema.Entry) string {
-	authors := formatAuthors(e.APA7.Authors)
-	year := apaYear(e)
+// ReferenceList renders each entry's reference in opts.Style and sorts them
+// alphabetically, as in an APA reference list or MLA works-cited list.
+func ReferenceList(entries []schema.Entry, opts Options) []string {
+	refs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		refs = append(refs, Citation(e, opts))
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// hangingIndent prefixes continuation lines of a wrapped reference.
+const hangingIndent = "    "
+
+// Wrap breaks s at spaces so lines fit within width columns, indenting continuation
+// lines with a hanging indent. Tokens are never split, so a long URL may overflow.
+// A width <= 0 returns s unchanged.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return s
+	}
+	var b strings.Builder
+	line := words[0]
+	for _, w := range words[1:] {
+		if len(line)+1+len(w) > width {
+			b.WriteString(line)
+			b.WriteString("\n")
+			line = hangingIndent + w
+			continue
+		}
+		line += " " + w
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// Options tune how a reference is rendered beyond the stored fields.
+type Options struct {
+	// Style is the citation style (one of Styles); empty means APA7.
+	Style string
+	// SentenceCase lowercases title-cased words in the title (see SentenceCase; APA7 only).
+	SentenceCase bool
+	// TitleCase converts journal and container names to APA title case (and, for MLA
+	// and Chicago, the title too).
+	TitleCase bool
+	// Italics wraps the italic parts of a reference in *asterisks* (Markdown): the
+	// journal and volume of an article, the album of a song or show of a podcast,
+	// otherwise the title.
+	Italics bool
+}
+
+// APACitation renders the APA7 reference for e as stored (no case or italics changes).
+func APACitation(e schema.Entry) string { return APACitationWith(e, Options{}) }
+
+// APACitationWith renders the APA7 reference for e; works without a year cite "n.d.".
+func APACitationWith(e schema.Entry, opts Options) string {
+	authors := APAAuthors(e)
+	year := stringsx.FirstNonEmpty(apaYear(e), "n.d.")
 	title := strings.TrimSpace(e.APA7.Title)
+	if opts.SentenceCase {
+		title = SentenceCase(title)
+	}
 	cont := strings.TrimSpace(stringsx.FirstNonEmpty(e.APA7.Journal, e.APA7.ContainerTitle))
+	if opts.TitleCase {
+		cont = titlecase.TitleCase(cont)
+	}
 	vol := strings.TrimSpace(e.APA7.Volume)
 	iss := strings.TrimSpace(e.APA7.Issue)
 	pgs := strings.TrimSpace(e.APA7.Pages)
 	pub := strings.TrimSpace(e.APA7.Publisher)
 	doi := strings.TrimSpace(e.APA7.DOI)
 	url := strings.TrimSpace(e.APA7.URL)
+	// Standards are authored by their body, which APA omits as publisher when repeated.
+	if body := strings.TrimSpace(e.APA7.StandardsBody); body != "" && authors == "" {
+		authors = body
+		if !strings.HasSuffix(authors, ".") {
+			authors += "."
+		}
+		if strings.EqualFold(pub, body) {
+			pub = ""
+		}
+	}
 
 	var b strings.Builder
 	if authors != "" {
 		b.WriteString(authors)
 		b.WriteString(" ")
 	}
-	if year != "" {
-		b.WriteString("(")
-		b.WriteString(year)
-		b.WriteString("). ")
+	b.WriteString("(")
+	b.WriteString(year)
+	b.WriteString("). ")
+	if opts.Italics {
+		switch strings.ToLower(e.Type) {
+		case "article":
+			cont, vol = italic(cont), italic(vol)
+		case "song", "podcast":
+			cont = italic(cont)
+		default:
+			title = italic(title)
+		}
 	}
 	if title != "" {
 		b.WriteString(title)
+		b.WriteString(titleSuffix(e))
+		if d := strings.TrimSpace(e.APA7.Designation); d != "" {
+			fmt.Fprintf(&b, " (%s)", d)
+		}
 		b.WriteString(". ")
 	}
 	b.WriteString(typeDetails(strings.ToLower(e.Type), cont, vol, iss, pgs, pub))
@@ -78,6 +222,11 @@ func APACitation(e schema.Entry) string {
 		b.WriteString(url)
 		b.WriteString(". ")
 	}
+	if archived := strings.TrimSpace(e.APA7.ArchivedURL); archived != "" {
+		b.WriteString("Archived at ")
+		b.WriteString(archived)
+		b.WriteString(". ")
+	}
 	out := strings.TrimSpace(b.String())
 	if !strings.HasSuffix(out, ".") {
 		out += "."
@@ -85,6 +234,69 @@ func APACitation(e schema.Entry) string {
 	return out
 }
 
+// italic marks s as italic (Markdown); empty strings stay empty.
+func italic(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "*" + s + "*"
+}
+
+// SentenceCase converts a title-cased title to APA sentence case (see
+// titlecase.SentenceCase).
+func SentenceCase(title string) string { return titlecase.SentenceCase(title) }
+
+// titleSuffix renders the credits, medium, and language that follow a title, e.g.
+// " (J. Editor, Ed.; A. Narrator, Narr.) [Audiobook]" or " [In German]" for a work not
+// in English. Editors are credited here only when the work also has authors;
+// otherwise they lead the reference (see APAAuthors).
+func titleSuffix(e schema.Entry) string {
+	var credits []string
+	if len(e.APA7.Authors.WithRole(schema.RoleAuthor)) > 0 {
+		credits = appendCredit(credits, e.APA7.Authors.WithRole(schema.RoleEditor), "Ed.", "Eds.")
+	}
+	credits = appendCredit(credits, e.APA7.Authors.WithRole(schema.RoleTranslator), "Trans.", "Trans.")
+	isBook := strings.EqualFold(e.Type, "book")
+	if isBook {
+		credits = appendCredit(credits, e.APA7.Authors.WithRole(schema.RoleNarrator), "Narr.", "Narrs.")
+	}
+	var b strings.Builder
+	if len(credits) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(credits, "; "))
+	}
+	if isBook && strings.EqualFold(strings.TrimSpace(e.APA7.Medium), "audiobook") {
+		b.WriteString(" [Audiobook]")
+	}
+	if !lang.IsEnglish(e.APA7.Language) {
+		fmt.Fprintf(&b, " [In %s]", lang.Name(e.APA7.Language))
+	}
+	return b.String()
+}
+
+// appendCredit appends "A. Name, Ed." (or "A. Name & B. Name, Eds.") for people to
+// credits; one and many are the singular and plural role abbreviations.
+func appendCredit(credits []string, people schema.Authors, one, many string) []string {
+	var ns []string
+	for _, p := range people {
+		n := strings.TrimSpace(p.Family)
+		if gi := names.Initials(p.Given); gi != "" && n != "" {
+			n = gi + " " + n
+		}
+		if n != "" {
+			ns = append(ns, n)
+		}
+	}
+	switch len(ns) {
+	case 0:
+		return credits
+	case 1:
+		return append(credits, ns[0]+", "+one)
+	default:
+		last := len(ns) - 1
+		return append(credits, fmt.Sprintf("%s & %s, %s", strings.Join(ns[:last], ", "), ns[last], many))
+	}
+}
+
 func typeDetails(typ, cont, vol, iss, pgs, pub string) string {
 	if f, ok := detailFormatters[typ]; ok {
 		return joinDetails(f(cont, vol, iss, pgs, pub))
@@ -93,14 +305,16 @@ func typeDetails(typ, cont, vol, iss, pgs, pub string) string {
 }
 
 var detailFormatters = map[string]func(cont, vol, iss, pgs, pub string) []string{
-	"article": detailsArticle,
-	"book":    detailsBook,
-	"website": detailsWebsite,
-	"movie":   detailsMovie,
-	"video":   detailsVideo,
-	"song":    detailsSong,
-	"patent":  detailsPatent,
-	"rfc":     detailsRFC,
+	"article":  detailsArticle,
+	"book":     detailsBook,
+	"website":  detailsWebsite,
+	"movie":    detailsMovie,
+	"video":    detailsVideo,
+	"song":     detailsSong,
+	"podcast":  detailsPodcast,
+	"patent":   detailsPatent,
+	"rfc":      detailsRFC,
+	"standard": detailsDefault,
 }
 
 func detailsArticle(cont, vol, iss, pgs, _ string) []string {
@@ -120,6 +334,16 @@ func detailsVideo(cont, _, _, _, _ string) []string {
 	return compact("[Video]", cont)
 }
 func detailsSong(cont, _, _, _, pub string) []string { return compact("[Song]", cont, pub) }
+func detailsPodcast(cont, _, iss, _, pub string) []string {
+	label := "[Audio podcast episode]"
+	if strings.TrimSpace(iss) != "" {
+		label = "(No. " + strings.TrimSpace(iss) + ") " + label
+	}
+	if strings.TrimSpace(cont) != "" {
+		cont = "In " + cont
+	}
+	return compact(label, cont, pub)
+}
 func detailsPatent(cont, _, _, _, pub string) []string {
 	if strings.TrimSpace(cont) != "" {
 		cont = "Patent office: " + cont
@@ -166,38 +390,81 @@ func joinDetails(parts []string) string {
 	return strings.Join(parts, ". ") + ". "
 }
 
+// APAAuthors formats an entry's authors as in an APA reference list, ending in
+// "et al." when the stored list was truncated. An edited volume without authors is
+// led by its editors, marked "(Ed.)." or "(Eds.).".
+func APAAuthors(e schema.Entry) string {
+	lead, edited := leadAuthors(e)
+	authors := formatAuthors(lead)
+	if e.APA7.AuthorsTruncated() && authors != "" {
+		authors = truncatedAuthors(lead)
+	}
+	if edited && authors != "" {
+		if len(lead) == 1 {
+			authors += " (Ed.)."
+		} else {
+			authors += " (Eds.)."
+		}
+	}
+	return authors
+}
+
+// leadAuthors returns the people in the author position of a reference: the authors,
+// or the editors (edited reports true) when there are none.
+func leadAuthors(e schema.Entry) (lead schema.Authors, edited bool) {
+	if as := e.APA7.Authors.WithRole(schema.RoleAuthor); len(as) > 0 {
+		return as, false
+	}
+	eds := e.APA7.Authors.WithRole(schema.RoleEditor)
+	return eds, len(eds) > 0
+}
+
+// APAYear returns the year cited for an entry, or "" when unknown.
+func APAYear(e schema.Entry) string { return apaYear(e) }
+
+// InTextCitation returns the parenthetical in-text citation, e.g. "(Doe & Roe, 2020)".
+func InTextCitation(e schema.Entry) string { return toInTextCitation(e) }
+
+// NarrativeCitation returns the narrative in-text citation, which spells out "and"
+// between two authors, e.g. "Doe and Roe (2020)".
+func NarrativeCitation(e schema.Entry) string {
+	return fmt.Sprintf("%s (%s)", inTextAuthor(e, "and"), stringsx.FirstNonEmpty(apaYear(e), "n.d."))
+}
+
 func toInTextCitation(e schema.Entry) string {
-	year := apaYear(e)
-	if len(e.APA7.Authors) == 0 {
-		name := strings.TrimSpace(stringsx.FirstNonEmpty(e.APA7.Publisher, e.APA7.ContainerTitle, e.APA7.Journal, e.APA7.Title))
-		if name == "" {
-			name = "Anon"
+	return fmt.Sprintf("(%s, %s)", inTextAuthor(e, "&"), stringsx.FirstNonEmpty(apaYear(e), "n.d."))
+}
+
+// inTextAuthor names the lead authors for an APA in-text citation: one family name,
+// two joined by conj ("&" in parentheses, "and" in prose), or the first followed by
+// "et al." for three or more (or a truncated list). Without authors the standards body
+// stands in as group author, else the quoted title.
+func inTextAuthor(e schema.Entry, conj string) string {
+	lead, _ := leadAuthors(e)
+	if len(lead) == 0 {
+		if body := strings.TrimSpace(e.APA7.StandardsBody); body != "" {
+			return body
 		}
-		if year == "" {
-			year = "n.d."
+		if title := strings.TrimSuffix(strings.TrimSpace(e.APA7.Title), "."); title != "" {
+			return `"` + title + `"`
 		}
-		return fmt.Sprintf("(%s, %s)", name, year)
+		return "Anon"
 	}
-	fams := make([]string, 0, len(e.APA7.Authors))
-	for _, a := range e.APA7.Authors {
-		f := strings.TrimSpace(a.Family)
-		if f != "" {
+	fams := make([]string, 0, len(lead))
+	for _, a := range lead {
+		if f := strings.TrimSpace(a.Family); f != "" {
 			fams = append(fams, f)
 		}
 	}
-	if len(fams) == 0 {
-		fams = []string{"Anon"}
-	}
-	if year == "" {
-		year = "n.d."
+	switch {
+	case len(fams) == 0:
+		return "Anon"
+	case len(fams) >= 3 || e.APA7.AuthorsTruncated():
+		return fams[0] + " et al."
+	case len(fams) == 2:
+		return fams[0] + " " + conj + " " + fams[1]
 	}
-	if len(fams) == 1 {
-		return fmt.Sprintf("(%s, %s)", fams[0], year)
-	}
-	if len(fams) == 2 {
-		return fmt.Sprintf("(%s & %s, %s)", fams[0], fams[1], year)
-	}
-	return fmt.Sprintf("(%s et al., %s)", fams[0], year)
+	return fams[0]
 }
 
 func apaYear(e schema.Entry) string {
@@ -224,6 +491,18 @@ func formatAuthors(authors schema.Authors) string {
 	return joinOxfordAmp(parts)
 }
 
+// truncatedAuthors lists the stored authors of a truncated list followed by "et al."
+// since the remaining names are unknown.
+func truncatedAuthors(authors schema.Authors) string {
+	parts := make([]string, 0, len(authors))
+	for _, a := range authors {
+		if s := formatAuthor(a); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ") + ", et al."
+}
+
 func formatAuthor(a schema.Author) string {
 	fam := strings.TrimSpace(a.Family)
 	giv := strings.TrimSpace(a.Given)
@@ -0,0 +1,79 @@
+package citecmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestCiteAllPrintsSortedReferenceList(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	y := 2020
+	for _, e := range []schema.Entry{
+		{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Zebra Book", Authors: schema.Authors{{Family: "Young", Given: "Z."}}, Publisher: "Pub"}},
+		{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "An Article", Authors: schema.Authors{{Family: "Adams", Given: "A."}}, Year: &y, Journal: "Journal", Volume: "4", Issue: "2"}},
+	} {
+		e.Annotation = schema.Annotation{Summary: "s", Keywords: []string{"k"}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--all", "--italics"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cite --all: %v", err)
+	}
+	want := "Adams, A. (2020). An Article. *Journal*. *4*(2).\n" +
+		"Young, Z. (n.d.). *Zebra Book*. Pub.\n"
+	if buf.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	for _, args := range [][]string{{}, {"--all", "--id", "x"}, {"a", "--id", "b"}} {
+		c := New()
+		c.SetOut(&bytes.Buffer{})
+		c.SilenceUsage, c.SilenceErrors = true, true
+		c.SetArgs(args)
+		if err := c.Execute(); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestSentenceCase(t *testing.T) {
+	cases := map[string]string{
+		"The Design Of Everyday Things":       "The design of everyday things",
+		"Learning APA Style: A Guide For iOS": "Learning APA style: A guide for iOS",
+		"Why Does It Matter? An Answer":       "Why does it matter? An answer",
+		"already sentence case":               "Already sentence case",
+	}
+	for in, want := range cases {
+		if got := SentenceCase(in); got != want {
+			t.Fatalf("SentenceCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+	e := schema.Entry{Type: "article", APA7: schema.APA7{Title: "Big Data In Practice"}}
+	if s := APACitationWith(e, Options{SentenceCase: true}); !strings.Contains(s, "Big data in practice.") {
+		t.Fatalf("sentence case not applied: %q", s)
+	}
+}
+
+func TestTitleCaseOption(t *testing.T) {
+	e := schema.Entry{Type: "article", APA7: schema.APA7{Title: "A tale: Two cities", Journal: "journal of the american society", Volume: "3"}}
+	if s := APACitationWith(e, Options{}); !strings.Contains(s, "journal of the american society") {
+		t.Fatalf("journal recased without the option: %q", s)
+	}
+	if s := APACitationWith(e, Options{TitleCase: true}); !strings.Contains(s, "Journal of the American Society.") {
+		t.Fatalf("title case not applied: %q", s)
+	}
+}
@@ -0,0 +1,39 @@
+package citecmd
+
+import (
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestAPACitation_Editors(t *testing.T) {
+	y := 2019
+	single := schema.Entry{Type: "book", APA7: schema.APA7{Title: "Handbook of Things", Year: &y, Publisher: "Acme Press",
+		Authors: schema.Authors{{Family: "Doe", Given: "Jane", Role: schema.RoleEditor}}}}
+	if got, want := APACitation(single), "Doe, J. (Ed.). (2019). Handbook of Things. Acme Press."; got != want {
+		t.Fatalf("single editor:\n got %q\nwant %q", got, want)
+	}
+	if got := toInTextCitation(single); got != "(Doe, 2019)" {
+		t.Fatalf("in-text: %q", got)
+	}
+	multi := single
+	multi.APA7.Authors = schema.Authors{{Family: "Doe", Given: "Jane", Role: schema.RoleEditor}, {Family: "Roe", Given: "Rick", Role: schema.RoleEditor}}
+	if got, want := APACitation(multi), "Doe, J., & Roe, R. (Eds.). (2019). Handbook of Things. Acme Press."; got != want {
+		t.Fatalf("multiple editors:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestAPACitation_EditorsAndTranslatorsAfterTitle(t *testing.T) {
+	y := 2004
+	e := schema.Entry{Type: "book", APA7: schema.APA7{Title: "Collected Works", Year: &y, Publisher: "Acme Press",
+		Authors: schema.Authors{
+			{Family: "Author", Given: "Ann"},
+			{Family: "Doe", Given: "Jane", Role: schema.RoleEditor},
+			{Family: "Roe", Given: "Rick", Role: schema.RoleEditor},
+			{Family: "Poe", Given: "Pat", Role: schema.RoleTranslator},
+		}}}
+	want := "Author, A. (2004). Collected Works (J. Doe & R. Roe, Eds.; P. Poe, Trans.). Acme Press."
+	if got := APACitation(e); got != want {
+		t.Fatalf("credits:\n got %q\nwant %q", got, want)
+	}
+}
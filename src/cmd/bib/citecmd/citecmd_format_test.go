@@ -22,6 +22,7 @@ func TestAPACitation_TypeDetails(t *testing.T) {
 		{"song", schema.Entry{Type: "song", APA7: schema.APA7{Title: "S", Year: &y, ContainerTitle: "Album", Publisher: "Label"}}, []string{"[Song]", "Album", "Label"}},
 		{"patent", schema.Entry{Type: "patent", APA7: schema.APA7{Title: "P", Year: &y, ContainerTitle: "USPTO", Publisher: "Assignee"}}, []string{"Patent office: USPTO", "Assignee"}},
 		{"rfc", schema.Entry{Type: "rfc", APA7: schema.APA7{Title: "R", Year: &y}}, []string{"RFC"}},
+		{"podcast", schema.Entry{Type: "podcast", APA7: schema.APA7{Title: "E", Year: &y, ContainerTitle: "Show", Publisher: "Network", Issue: "42"}}, []string{"E. (No. 42) [Audio podcast episode]. In Show. Network."}},
 	}
 	for _, tc := range cases {
 		got := APACitation(tc.e)
@@ -32,3 +33,43 @@ func TestAPACitation_TypeDetails(t *testing.T) {
 		}
 	}
 }
+
+func TestAPACitation_Audiobook(t *testing.T) {
+	y := 2019
+	e := schema.Entry{Type: "book", APA7: schema.APA7{
+		Title: "Becoming", Year: &y, Publisher: "Random House Audio", Medium: "audiobook",
+		Authors: schema.Authors{{Family: "Obama", Given: "Michelle"}, {Family: "Obama", Given: "Michelle", Role: schema.RoleNarrator}},
+	}}
+	want := "Obama, M. (2019). Becoming (M. Obama, Narr.) [Audiobook]. Random House Audio."
+	if got := APACitation(e); got != want {
+		t.Fatalf("audiobook citation: got %q want %q", got, want)
+	}
+	e.APA7.Medium = "ebook"
+	e.APA7.Authors = e.APA7.Authors[:1]
+	if got := APACitation(e); strings.Contains(got, "[") {
+		t.Fatalf("ebook should not carry a descriptor: %q", got)
+	}
+}
+
+func TestWrap_HangingIndentKeepsURLsWhole(t *testing.T) {
+	ref := "Doe, J. (2020). A fairly long title for wrapping. Publisher. https://example.com/a/very/long/path/that/should/not/break."
+	got := Wrap(ref, 30)
+	lines := strings.Split(got, "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected several lines, got %q", got)
+	}
+	for i, l := range lines {
+		if i > 0 && !strings.HasPrefix(l, "    ") {
+			t.Fatalf("line %d missing hanging indent: %q", i, l)
+		}
+		if len(l) > 30 && !strings.Contains(l, "https://") {
+			t.Fatalf("line %d exceeds width: %q", i, l)
+		}
+	}
+	if !strings.Contains(got, "https://example.com/a/very/long/path/that/should/not/break.") {
+		t.Fatalf("url was split: %q", got)
+	}
+	if Wrap(ref, 0) != ref {
+		t.Fatalf("width 0 should not wrap")
+	}
+}
@@ -1,8 +1,12 @@
 package citecmd
 
 import (
-	"bibliography/src/internal/schema"
+	"bytes"
+	"os"
 	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
 )
 
 func TestToInTextCitation(t *testing.T) {
@@ -27,3 +31,56 @@ func TestToInTextCitation(t *testing.T) {
 		t.Fatalf("expected fallback in-text")
 	}
 }
+
+func TestInTextCitationAuthorCounts(t *testing.T) {
+	y2009, y2020, y2021 := 2009, 2020, 2021
+	cases := []struct {
+		name            string
+		e               schema.Entry
+		paren, narrated string
+	}{
+		{"one", schema.Entry{Type: "rfc", APA7: schema.APA7{Title: "The Syslog Protocol", Year: &y2009, Authors: schema.Authors{{Family: "Gerhards", Given: "R."}}}}, "(Gerhards, 2009)", "Gerhards (2009)"},
+		{"two", schema.Entry{Type: "article", APA7: schema.APA7{Title: "T", Year: &y2020, Authors: schema.Authors{{Family: "Smith"}, {Family: "Doe"}}}}, "(Smith & Doe, 2020)", "Smith and Doe (2020)"},
+		{"three", schema.Entry{Type: "article", APA7: schema.APA7{Title: "T", Year: &y2021, Authors: schema.Authors{{Family: "Smith"}, {Family: "Doe"}, {Family: "Roe"}}}}, "(Smith et al., 2021)", "Smith et al. (2021)"},
+		{"zero", schema.Entry{Type: "website", APA7: schema.APA7{Title: "Title"}}, `("Title", n.d.)`, `"Title" (n.d.)`},
+		{"group author", schema.Entry{Type: "standard", APA7: schema.APA7{Title: "Dates", StandardsBody: "ISO", Year: &y2020}}, "(ISO, 2020)", "ISO (2020)"},
+	}
+	for _, c := range cases {
+		if got := InTextCitation(c.e); got != c.paren {
+			t.Errorf("%s: parenthetical %q, want %q", c.name, got, c.paren)
+		}
+		if got := NarrativeCitation(c.e); got != c.narrated {
+			t.Errorf("%s: narrative %q, want %q", c.name, got, c.narrated)
+		}
+	}
+}
+
+func TestCiteIntextFlag(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	y := 2020
+	e := schema.Entry{ID: "00000000-0000-4000-8000-0000000000b2", Type: "article", APA7: schema.APA7{Title: "T", Year: &y, Journal: "J", Authors: schema.Authors{{Family: "Smith"}, {Family: "Doe"}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	for flag, want := range map[string]string{"--intext": "(Smith & Doe, 2020)\n", "--narrative": "Smith and Doe (2020)\n"} {
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs([]string{flag, "--id", e.ID})
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s output %q, want %q", flag, buf.String(), want)
+		}
+	}
+	cmd := New()
+	cmd.SetArgs([]string{"--intext", "--all"})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("--intext with --all should fail")
+	}
+}
@@ -0,0 +1,48 @@
+package citecmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/doi"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/schema"
+)
+
+type crossrefOnly struct{ body string }
+
+func (c crossrefOnly) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != "api.crossref.org" {
+		return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("not found")), Header: make(http.Header)}, nil
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(c.body)), Header: make(http.Header)}, nil
+}
+
+func TestAPACitation_CrossrefLanguageNote(t *testing.T) {
+	doi.SetHTTPClient(crossrefOnly{`{"status":"ok","message":{"title":["Über Dinge"],
+		"author":[{"family":"Müller","given":"Karl"}],"container-title":["Zeitschrift für Dinge"],
+		"issued":{"date-parts":[[1999]]},"volume":"3","page":"1-9","DOI":"10.1234/de","language":"de"}}`})
+	t.Cleanup(func() { doi.SetHTTPClient(httpx.ProviderClient()) })
+
+	e, err := doi.FetchArticleByDOI(context.Background(), "10.1234/de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := APACitation(e); !strings.HasPrefix(got, "Müller, K. (1999). Über Dinge [In German]. Zeitschrift für Dinge") {
+		t.Fatalf("APACitation = %q", got)
+	}
+}
+
+func TestAPACitation_EnglishHasNoLanguageNote(t *testing.T) {
+	y := 2001
+	for _, l := range []string{"", "en", "en-GB"} {
+		e := schema.Entry{Type: "book", APA7: schema.APA7{Title: "Things", Year: &y, Publisher: "Acme", Language: l,
+			Authors: schema.Authors{{Family: "Doe", Given: "J."}}}}
+		if got := APACitation(e); strings.Contains(got, "[In") {
+			t.Fatalf("language %q: %q", l, got)
+		}
+	}
+}
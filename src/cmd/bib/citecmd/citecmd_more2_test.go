@@ -8,7 +8,7 @@ import (
 )
 
 func TestAPACitation_DOIvsURL(t *testing.T) {
-	e := schema.Entry{Type: "article", APA7: schema.APA7{Title: "T", DOI: "10.1/x"}}
+	e := schema.Entry{Type: "article", APA7: schema.APA7{Title: "T", Identifiers: schema.Identifiers{DOI: "10.1/x"}}}
 	if s := APACitation(e); !strings.Contains(s, "https://doi.org/10.1/x") {
 		t.Fatalf("expected doi link in %q", s)
 	}
@@ -0,0 +1,19 @@
+package citecmd
+
+import (
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestAPACitation_TruncatedAuthors(t *testing.T) {
+	y := 2012
+	e := schema.Entry{Type: "article", APA7: schema.APA7{Title: "Observation of a new boson", Year: &y,
+		Authors: schema.Authors{{Family: "Aad", Given: "Georges"}, {Family: "Abajyan", Given: "Tatevik"}}, AuthorCount: 2932}}
+	if got, want := APACitation(e), "Aad, G., Abajyan, T., et al. (2012). Observation of a new boson."; got != want {
+		t.Fatalf("citation:\n got %q\nwant %q", got, want)
+	}
+	if got := toInTextCitation(e); got != "(Aad et al., 2012)" {
+		t.Fatalf("in-text: %q", got)
+	}
+}
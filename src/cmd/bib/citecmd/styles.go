@@ -0,0 +1,276 @@
+package citecmd
+
+import (
+	"fmt"
+	"strings"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/stringsx"
+	"bibliography/src/internal/titlecase"
+)
+
+// Citation styles accepted by --style.
+const (
+	StyleAPA7    = "apa7"
+	StyleMLA     = "mla"
+	StyleChicago = "chicago"
+)
+
+// Styles lists the citation styles, the default first.
+var Styles = []string{StyleAPA7, StyleMLA, StyleChicago}
+
+// IsValidStyle reports whether s is empty (APA7) or one of Styles (case-insensitive).
+func IsValidStyle(s string) bool {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return true
+	}
+	for _, v := range Styles {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Citation renders the reference for e in opts.Style (APA7 when empty).
+func Citation(e schema.Entry, opts Options) string {
+	switch strings.ToLower(strings.TrimSpace(opts.Style)) {
+	case StyleMLA:
+		return MLACitation(e, opts)
+	case StyleChicago:
+		return ChicagoCitation(e, opts)
+	default:
+		return APACitationWith(e, opts)
+	}
+}
+
+// InTextCitationFor returns the parenthetical citation in style: APA "(Doe & Roe,
+// 2020)", MLA "(Doe and Roe)", or Chicago author-date "(Doe and Roe 2020)".
+func InTextCitationFor(e schema.Entry, style string) string {
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case StyleMLA:
+		return "(" + shortAuthors(e) + ")"
+	case StyleChicago:
+		return fmt.Sprintf("(%s %s)", shortAuthors(e), stringsx.FirstNonEmpty(apaYear(e), "n.d."))
+	default:
+		return toInTextCitation(e)
+	}
+}
+
+// shortAuthors names the lead authors for an MLA or Chicago in-text citation: one or
+// two family names joined by "and", or the first with "et al.".
+func shortAuthors(e schema.Entry) string {
+	lead, _ := leadAuthors(e)
+	var fams []string
+	for _, a := range lead {
+		if f := strings.TrimSpace(a.Family); f != "" {
+			fams = append(fams, f)
+		}
+	}
+	switch {
+	case len(fams) == 0:
+		return strings.TrimSpace(stringsx.FirstNonEmpty(e.APA7.StandardsBody, e.APA7.Publisher, e.APA7.Title, "Anon"))
+	case len(fams) > 2 || e.APA7.AuthorsTruncated():
+		return fams[0] + " et al."
+	case len(fams) == 2:
+		return fams[0] + " and " + fams[1]
+	}
+	return fams[0]
+}
+
+// MLACitation renders e in MLA (9th edition) works-cited form: the first author
+// inverted, titles of parts in quotes and their containers after them, then volume,
+// issue, date, and pages, e.g. `Doe, Jane, and Richard Roe. "Title." Journal, vol. 12,
+// no. 3, 2020, pp. 45-60. https://doi.org/...`.
+func MLACitation(e schema.Entry, opts Options) string {
+	a := e.APA7
+	title, cont := styledTitles(e, opts)
+	var b strings.Builder
+	writeSentence(&b, nameList(e, mlaJoin, "editor", "editors"))
+	writeSentence(&b, title)
+	var details []string
+	add(&details, cont)
+	if v := strings.TrimSpace(a.Volume); v != "" {
+		details = append(details, "vol. "+v)
+	}
+	if i := strings.TrimSpace(a.Issue); i != "" {
+		details = append(details, "no. "+i)
+	}
+	if !isPart(e.Type) || cont == "" {
+		add(&details, a.Publisher)
+	}
+	add(&details, apaYear(e))
+	if p := strings.TrimSpace(a.Pages); p != "" {
+		prefix := "p. "
+		if strings.Contains(p, "-") {
+			prefix = "pp. "
+		}
+		details = append(details, prefix+p)
+	}
+	writeSentence(&b, strings.Join(details, ", "))
+	writeSentence(&b, link(a))
+	if strings.EqualFold(e.Type, "website") {
+		if acc := strings.TrimSpace(a.Accessed); acc != "" {
+			writeSentence(&b, "Accessed "+acc)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// ChicagoCitation renders e as a Chicago (17th edition, notes and bibliography)
+// bibliography entry: authors as in MLA, then for an article the journal with
+// volume, "no." issue, the year in parentheses, and pages after a colon, e.g.
+// `Doe, Jane, and Richard Roe. "Title." Journal 12, no. 3 (2020): 45–60. https://...`;
+// other works end with "Place: Publisher, Year.".
+func ChicagoCitation(e schema.Entry, opts Options) string {
+	a := e.APA7
+	title, cont := styledTitles(e, opts)
+	year := apaYear(e)
+	var b strings.Builder
+	writeSentence(&b, nameList(e, chicagoJoin, "ed.", "eds."))
+	writeSentence(&b, title)
+	if strings.EqualFold(e.Type, "article") {
+		s := cont
+		if v := strings.TrimSpace(a.Volume); v != "" {
+			s = strings.TrimSpace(s + " " + v)
+		}
+		if i := strings.TrimSpace(a.Issue); i != "" {
+			s += ", no. " + i
+		}
+		if year != "" {
+			s = strings.TrimSpace(s + " (" + year + ")")
+		}
+		if p := strings.TrimSpace(a.Pages); p != "" {
+			s += ": " + strings.ReplaceAll(p, "-", "–")
+		}
+		writeSentence(&b, s)
+	} else {
+		writeSentence(&b, cont)
+		pub := strings.TrimSpace(a.Publisher)
+		if loc := strings.TrimSpace(a.PublisherLocation); loc != "" && pub != "" {
+			pub = loc + ": " + pub
+		}
+		writeSentence(&b, strings.Join(compact(pub, year), ", "))
+	}
+	writeSentence(&b, link(a))
+	return strings.TrimSpace(b.String())
+}
+
+// styledTitles returns the title (quoted when the work is part of a container,
+// otherwise italic with opts.Italics) and the container name (italic with
+// opts.Italics). With opts.TitleCase both are converted to title case, which MLA and
+// Chicago use for every title.
+func styledTitles(e schema.Entry, opts Options) (title, cont string) {
+	title = strings.TrimSpace(e.APA7.Title)
+	cont = strings.TrimSpace(stringsx.FirstNonEmpty(e.APA7.Journal, e.APA7.ContainerTitle))
+	if opts.TitleCase {
+		title, cont = titlecase.TitleCase(title), titlecase.TitleCase(cont)
+	}
+	if opts.Italics {
+		cont = italic(cont)
+	}
+	switch {
+	case title == "":
+	case isPart(e.Type):
+		// The closing period goes inside the quotes.
+		title = `"` + strings.TrimSuffix(title, ".") + `."`
+	case opts.Italics:
+		title = italic(title)
+	}
+	return title, cont
+}
+
+// isPart reports whether works of typ are usually part of a larger container (an
+// article in a journal, an episode in a show), so their titles are quoted.
+func isPart(typ string) bool {
+	switch strings.ToLower(typ) {
+	case "article", "website", "podcast", "song", "video":
+		return true
+	}
+	return false
+}
+
+// link returns the DOI as a URL, else the URL.
+func link(a schema.APA7) string {
+	if doi := strings.TrimSpace(a.DOI); doi != "" {
+		return "https://doi.org/" + doi
+	}
+	return strings.TrimSpace(a.URL)
+}
+
+// writeSentence appends s as a sentence ending in a period; empty s is skipped.
+func writeSentence(b *strings.Builder, s string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	b.WriteString(s)
+	if !strings.HasSuffix(s, ".") && !strings.HasSuffix(s, `."`) {
+		b.WriteString(".")
+	}
+}
+
+// nameList formats the lead authors with the first inverted ("Doe, Jane") and the rest
+// in natural order, joined by join; lead editors get the one/many role label.
+func nameList(e schema.Entry, join func([]string) string, one, many string) string {
+	lead, edited := leadAuthors(e)
+	var names []string
+	for i, p := range lead {
+		fam, giv := strings.TrimSpace(p.Family), strings.TrimSpace(p.Given)
+		switch {
+		case fam == "":
+			names = append(names, giv)
+		case giv == "":
+			names = append(names, fam)
+		case i == 0:
+			names = append(names, fam+", "+giv)
+		default:
+			names = append(names, giv+" "+fam)
+		}
+	}
+	s := join(names)
+	if s != "" && e.APA7.AuthorsTruncated() && !strings.HasSuffix(s, "et al.") {
+		s += ", et al."
+	}
+	if s != "" && edited {
+		if len(lead) == 1 {
+			s += ", " + one
+		} else {
+			s += ", " + many
+		}
+	}
+	return s
+}
+
+// mlaJoin lists one or two names, or the first followed by "et al." for three or more.
+func mlaJoin(ns []string) string {
+	switch len(ns) {
+	case 0:
+		return ""
+	case 1:
+		return ns[0]
+	case 2:
+		return ns[0] + ", and " + ns[1]
+	}
+	return ns[0] + ", et al."
+}
+
+// chicagoJoin lists up to ten names with a serial "and"; longer lists keep the first
+// seven followed by "et al.".
+func chicagoJoin(ns []string) string {
+	switch {
+	case len(ns) == 0:
+		return ""
+	case len(ns) == 1:
+		return ns[0]
+	case len(ns) == 2:
+		return ns[0] + ", and " + ns[1]
+	case len(ns) > 10:
+		return strings.Join(ns[:7], ", ") + ", et al."
+	}
+	return strings.Join(ns[:len(ns)-1], ", ") + ", and " + ns[len(ns)-1]
+}
@@ -0,0 +1,93 @@
+package citecmd
+
+import (
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func styleArticle() schema.Entry {
+	y := 2020
+	return schema.Entry{ID: "00000000-0000-4000-8000-0000000000b1", Type: "article", APA7: schema.APA7{
+		Authors: schema.Authors{{Family: "Doe", Given: "Jane"}, {Family: "Roe", Given: "Richard"}},
+		Year:    &y, Title: "Logs at scale", Journal: "Journal of Systems", Volume: "12", Issue: "3", Pages: "45-60",
+		Identifiers: schema.Identifiers{DOI: "10.1234/abc"},
+	}}
+}
+
+func TestCitationStylesForArticle(t *testing.T) {
+	e := styleArticle()
+	cases := map[string]string{
+		StyleAPA7:    "Doe, J., & Roe, R. (2020). Logs at scale. Journal of Systems. 12(3). 45-60. https://doi.org/10.1234/abc.",
+		StyleMLA:     `Doe, Jane, and Richard Roe. "Logs at scale." Journal of Systems, vol. 12, no. 3, 2020, pp. 45-60. https://doi.org/10.1234/abc.`,
+		StyleChicago: `Doe, Jane, and Richard Roe. "Logs at scale." Journal of Systems 12, no. 3 (2020): 45–60. https://doi.org/10.1234/abc.`,
+	}
+	seen := map[string]bool{}
+	for style, want := range cases {
+		got := Citation(e, Options{Style: style})
+		if got != want {
+			t.Errorf("%s:\n got %s\nwant %s", style, got, want)
+		}
+		if seen[got] {
+			t.Errorf("%s output is not distinct: %s", style, got)
+		}
+		seen[got] = true
+	}
+	if got := Citation(e, Options{}); got != cases[StyleAPA7] {
+		t.Errorf("default style should be APA7: %s", got)
+	}
+}
+
+func TestInTextCitationStyles(t *testing.T) {
+	e := styleArticle()
+	for style, want := range map[string]string{
+		StyleAPA7:    "(Doe & Roe, 2020)",
+		StyleMLA:     "(Doe and Roe)",
+		StyleChicago: "(Doe and Roe 2020)",
+	} {
+		if got := InTextCitationFor(e, style); got != want {
+			t.Errorf("%s in-text = %q, want %q", style, got, want)
+		}
+	}
+}
+
+func TestMLAAndChicagoBooks(t *testing.T) {
+	y := 2011
+	e := schema.Entry{Type: "book", APA7: schema.APA7{
+		Authors: schema.Authors{{Family: "Knuth", Given: "Donald E."}, {Family: "Roe", Given: "R."}, {Family: "Poe", Given: "E. A."}},
+		Year:    &y, Title: "The art of computer programming", Publisher: "Addison-Wesley", PublisherLocation: "Boston",
+	}}
+	if got, want := MLACitation(e, Options{Italics: true, TitleCase: true}), "Knuth, Donald E., et al. *The Art of Computer Programming*. Addison-Wesley, 2011."; got != want {
+		t.Errorf("MLA book:\n got %s\nwant %s", got, want)
+	}
+	if got, want := ChicagoCitation(e, Options{}), "Knuth, Donald E., R. Roe, and E. A. Poe. The art of computer programming. Boston: Addison-Wesley, 2011."; got != want {
+		t.Errorf("Chicago book:\n got %s\nwant %s", got, want)
+	}
+	ed := schema.Entry{Type: "book", APA7: schema.APA7{Authors: schema.Authors{{Family: "Doe", Given: "Jane", Role: schema.RoleEditor}}, Title: "Readings", Publisher: "P"}}
+	if got, want := MLACitation(ed, Options{}), "Doe, Jane, editor. Readings. P."; got != want {
+		t.Errorf("MLA edited book:\n got %s\nwant %s", got, want)
+	}
+	if got, want := ChicagoCitation(ed, Options{}), "Doe, Jane, ed. Readings. P."; got != want {
+		t.Errorf("Chicago edited book:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestIsValidStyle(t *testing.T) {
+	for _, s := range []string{"", "apa7", "MLA", "chicago"} {
+		if !IsValidStyle(s) {
+			t.Errorf("IsValidStyle(%q) = false", s)
+		}
+	}
+	if IsValidStyle("harvard") {
+		t.Errorf("harvard should be rejected")
+	}
+}
+
+func TestCiteRejectsUnknownStyle(t *testing.T) {
+	cmd := New()
+	cmd.SetArgs([]string{"--all", "--style", "harvard"})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	if err := cmd.Execute(); err == nil || err.Error() != `invalid --style "harvard" (want one of apa7, mla, chicago)` {
+		t.Fatalf("unknown style: %v", err)
+	}
+}
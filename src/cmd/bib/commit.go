@@ -1,6 +1,40 @@
 package main
 
-import "bibliography/src/internal/gitutil"
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/gitutil"
+)
 
 // indirection for testability across commands
 var commitAndPush = gitutil.CommitAndPush
+
+const noCommitHelp = "Write changes to the library but skip git add/commit/push (also BIB_NO_COMMIT=1)"
+
+// optionalCommit returns a commit func that is a no-op when *skip is set or
+// BIB_NO_COMMIT is true, and otherwise calls commitAndPush.
+func optionalCommit(skip *bool) func(paths []string, message string) error {
+	return func(paths []string, message string) error {
+		if *skip || noCommitEnv() {
+			return nil
+		}
+		return commitAndPush(paths, message)
+	}
+}
+
+// addNoCommitFlag adds --no-commit, bound to skip, to cmd and returns cmd; pair it
+// with optionalCommit(skip) as the commit func the command was built with.
+func addNoCommitFlag(cmd *cobra.Command, skip *bool) *cobra.Command {
+	cmd.Flags().BoolVar(skip, "no-commit", false, noCommitHelp)
+	return cmd
+}
+
+// noCommitEnv reports whether BIB_NO_COMMIT is set to a true value (1, true, ...).
+func noCommitEnv() bool {
+	on, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("BIB_NO_COMMIT")))
+	return err == nil && on
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bibliography/src/internal/gitutil"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/store"
+)
+
+var dataDir, repoRoot string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", "", "Directory holding library.bib, citations/, and metadata/ (default ./data; also BIB_DATA_DIR)")
+	rootCmd.PersistentFlags().StringVar(&repoRoot, "repo", "", "Repository root containing data/ (default: the nearest ancestor of the working directory that has one)")
+}
+
+// applyDataDir points the store at the --data-dir flag, else BIB_DATA_DIR, else the
+// data/ directory of the --repo root or, without one, of the nearest ancestor that has
+// one (store.FindRepoRoot), falling back to ./data. A chosen directory is also where git
+// commits run, so its library can live in another repository, and its .cache/http holds
+// the provider response cache.
+func applyDataDir(flag, repo string) {
+	dir := strings.TrimSpace(flag)
+	if dir == "" {
+		dir = strings.TrimSpace(os.Getenv("BIB_DATA_DIR"))
+	}
+	if dir == "" {
+		root := strings.TrimSpace(repo)
+		if root == "" {
+			if found, ok := store.FindRepoRoot(); ok {
+				if wd, err := os.Getwd(); err != nil || found != wd {
+					root = found
+				}
+			}
+		}
+		if root != "" {
+			dir = filepath.Join(root, store.DefaultDataDir)
+		}
+	}
+	store.SetDataDir(dir)
+	gitutil.SetDir(dir)
+	httpx.SetCacheDir(filepath.Join(store.DataDir, ".cache", "http"))
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/dedupecmd"
+	"github.com/spf13/cobra"
+)
+
+// newDedupeCmd creates the "dedupe" command to find and merge duplicate citations.
+func newDedupeCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(dedupecmd.New(optionalCommit(&noCommit)), &noCommit)
+}
@@ -0,0 +1,247 @@
+package dedupecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// Cluster is a group of entries of the same type that share a DOI, ISBN, or
+// title+year fingerprint. Keep is the most complete record; Drop are the rest.
+type Cluster struct {
+	Key  string
+	Keep schema.Entry
+	Drop []schema.Entry
+}
+
+// New returns the dedupe command which reports (and with --apply merges) duplicates.
+func New(commit CommitFunc) *cobra.Command {
+	var apply, dryRun bool
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Find duplicate citations (by DOI, ISBN, or title+year) and optionally merge them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if apply && dryRun && cmd.Flags().Changed("dry-run") {
+				return fmt.Errorf("--apply and --dry-run are mutually exclusive")
+			}
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			clusters := FindClusters(entries)
+			out := cmd.OutOrStdout()
+			if len(clusters) == 0 {
+				_, err := fmt.Fprintln(out, "no duplicates found")
+				return err
+			}
+			dropped := 0
+			for _, c := range clusters {
+				if _, err := fmt.Fprintf(out, "== %s\n  keep %s  %s\n", c.Key, c.Keep.ID, c.Keep.APA7.Title); err != nil {
+					return err
+				}
+				for _, d := range c.Drop {
+					if _, err := fmt.Fprintf(out, "  drop %s  %s\n", d.ID, d.APA7.Title); err != nil {
+						return err
+					}
+				}
+				dropped += len(c.Drop)
+			}
+			if !apply {
+				_, err := fmt.Fprintf(out, "%d cluster(s), %d duplicate(s); re-run with --apply to merge\n", len(clusters), dropped)
+				return err
+			}
+			paths := []string{store.BibFile}
+			legacy := false
+			for _, c := range clusters {
+				ids := make([]string, 0, len(c.Drop))
+				for _, d := range c.Drop {
+					ids = append(ids, d.ID)
+				}
+				removed, err := store.MergeDuplicates(c.Keep.ID, c.Keep.Annotation.Keywords, ids)
+				if err != nil {
+					return err
+				}
+				legacy = legacy || removed
+			}
+			if legacy {
+				paths = append(paths, store.CitationsDir)
+			}
+			if err := commit(paths, fmt.Sprintf("dedupe: merge %d duplicate(s)", dropped)); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(out, "merged %d duplicate(s) in %d cluster(s)\n", dropped, len(clusters))
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&apply, "apply", false, "Merge each cluster into its most complete record and remove the rest")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", true, "Only report clusters (default)")
+	return cmd
+}
+
+// FindClusters groups entries that share a normalized DOI, ISBN, or title+year
+// fingerprint. Entries of different types are never grouped. Each cluster's keeper
+// carries the union of the cluster's keywords.
+func FindClusters(entries []schema.Entry) []Cluster {
+	parent := make([]int, len(entries))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	first := map[string]int{}
+	reason := map[int]string{} // entry index -> shared key that linked it
+	for i, e := range entries {
+		for _, k := range keysOf(e) {
+			j, ok := first[strings.ToLower(e.Type)+"|"+k]
+			if !ok {
+				first[strings.ToLower(e.Type)+"|"+k] = i
+				continue
+			}
+			if ri, rj := find(i), find(j); ri != rj {
+				parent[ri] = rj
+			}
+			if _, ok := reason[i]; !ok {
+				reason[i] = k
+			}
+		}
+	}
+	groups := map[int][]int{}
+	for i := range entries {
+		r := find(i)
+		groups[r] = append(groups[r], i)
+	}
+	var out []Cluster
+	for _, idx := range groups {
+		if len(idx) < 2 {
+			continue
+		}
+		var key string
+		members := make([]schema.Entry, 0, len(idx))
+		for _, i := range idx {
+			members = append(members, entries[i])
+			if k, ok := reason[i]; ok && (key == "" || k < key) {
+				key = k
+			}
+		}
+		sort.SliceStable(members, func(a, b int) bool { return better(members[a], members[b]) })
+		keep := members[0]
+		keep.Annotation.Keywords = unionKeywords(members)
+		out = append(out, Cluster{Key: key, Keep: keep, Drop: members[1:]})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Key != out[j].Key {
+			return out[i].Key < out[j].Key
+		}
+		return out[i].Keep.ID < out[j].Keep.ID
+	})
+	return out
+}
+
+// keysOf returns the duplicate keys of an entry: doi:, isbn:, and title:+year.
+func keysOf(e schema.Entry) []string {
+	var keys []string
+	if d := store.NormalizeDOI(e.APA7.DOI); d != "" {
+		keys = append(keys, "doi:"+d)
+	}
+	if n := store.NormalizeISBN(e.APA7.ISBN); n != "" {
+		keys = append(keys, "isbn:"+n)
+	}
+	if fp := fingerprint(e.APA7.Title); fp != "" && e.APA7.Year != nil {
+		keys = append(keys, fmt.Sprintf("title:%s %d", fp, *e.APA7.Year))
+	}
+	return keys
+}
+
+// fingerprint lowercases a title and keeps only letters and digits.
+func fingerprint(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// better reports whether a should be kept over b: more non-empty APA7 fields wins,
+// then verified over unverified, then the lower id for stability.
+func better(a, b schema.Entry) bool {
+	ca, cb := completeness(a.APA7), completeness(b.APA7)
+	if ca != cb {
+		return ca > cb
+	}
+	if (a.Verification != nil) != (b.Verification != nil) {
+		return a.Verification != nil
+	}
+	return strings.ToLower(a.ID) < strings.ToLower(b.ID)
+}
+
+// completeness counts the non-empty APA7 fields.
+func completeness(a schema.APA7) int {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return 0
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return 0
+	}
+	n := 0
+	for _, v := range m {
+		switch x := v.(type) {
+		case nil:
+		case string:
+			if strings.TrimSpace(x) != "" {
+				n++
+			}
+		case []any:
+			if len(x) > 0 {
+				n++
+			}
+		case map[string]any:
+			if len(x) > 0 {
+				n++
+			}
+		case float64:
+			if x != 0 {
+				n++
+			}
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// unionKeywords merges keywords across entries, keeping first-seen order.
+func unionKeywords(entries []schema.Entry) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, e := range entries {
+		for _, k := range e.Annotation.Keywords {
+			lk := strings.ToLower(strings.TrimSpace(k))
+			if lk == "" || seen[lk] {
+				continue
+			}
+			seen[lk] = true
+			out = append(out, k)
+		}
+	}
+	return out
+}
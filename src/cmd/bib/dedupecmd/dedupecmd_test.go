@@ -0,0 +1,100 @@
+package dedupecmd
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func article(id, title, doi string, year int, kw ...string) schema.Entry {
+	return schema.Entry{ID: id, Type: "article", APA7: schema.APA7{Title: title, Identifiers: schema.Identifiers{DOI: doi}, Year: &year, Journal: "J"}, Annotation: schema.Annotation{Summary: "s", Keywords: kw}}
+}
+
+func TestFindClusters(t *testing.T) {
+	a := article("00000000-0000-4000-8000-0000000000d1", "Go Concurrency", "10.1234/GO", 2020, "article", "go")
+	b := article("00000000-0000-4000-8000-0000000000d2", "Go concurrency!", "", 2020, "article", "concurrency")
+	b.APA7.Journal = ""
+	c := article("00000000-0000-4000-8000-0000000000d3", "Unrelated", "https://doi.org/10.1234/go", 2021, "misc")
+	c.APA7.Pages = "1-2"
+	d := schema.Entry{ID: "00000000-0000-4000-8000-0000000000d4", Type: "book", APA7: schema.APA7{Title: "Go Concurrency", Year: a.APA7.Year}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	got := FindClusters([]schema.Entry{a, b, c, d})
+	if len(got) != 1 {
+		t.Fatalf("want 1 cluster, got %+v", got)
+	}
+	cl := got[0]
+	if cl.Keep.ID != c.ID || len(cl.Drop) != 2 {
+		t.Fatalf("unexpected keeper/drops: %+v", cl)
+	}
+	if !reflect.DeepEqual(cl.Keep.Annotation.Keywords, []string{"misc", "article", "go", "concurrency"}) {
+		t.Fatalf("keywords not merged: %v", cl.Keep.Annotation.Keywords)
+	}
+}
+
+func TestBetterPrefersVerifiedOnTie(t *testing.T) {
+	a := article("00000000-0000-4000-8000-0000000000d1", "T", "", 2020)
+	b := article("00000000-0000-4000-8000-0000000000d2", "T", "", 2020)
+	b.Verification = &schema.Verification{By: "x"}
+	if !better(b, a) || better(a, b) {
+		t.Fatal("verified record should win a tie")
+	}
+}
+
+func TestDedupeReportAndApply(t *testing.T) {
+	chdirTemp(t)
+	a := article("00000000-0000-4000-8000-0000000000e1", "Paper", "10.1234/abc", 2020, "article", "x")
+	a.APA7.Volume = "3"
+	b := article("00000000-0000-4000-8000-0000000000e2", "Paper (copy)", "10.1234/ABC", 2020, "article", "y")
+	for _, e := range []schema.Entry{a, b} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	committed := false
+	cmd := New(func([]string, string) error { committed = true; return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if committed || !strings.Contains(buf.String(), "== doi:10.1234/abc") || !strings.Contains(buf.String(), "drop "+b.ID) {
+		t.Fatalf("unexpected report %q (committed=%v)", buf.String(), committed)
+	}
+	if all, _ := store.ReadAll(); len(all) != 2 {
+		t.Fatalf("dry run must not modify the library: %d entries", len(all))
+	}
+
+	var msg string
+	cmd = New(func(_ []string, m string) error { msg = m; return nil })
+	buf.Reset()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--apply"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if msg != "dedupe: merge 1 duplicate(s)" {
+		t.Fatalf("unexpected commit %q", msg)
+	}
+	all, err := store.ReadAll()
+	if err != nil || len(all) != 1 || all[0].ID != a.ID {
+		t.Fatalf("unexpected library %+v (%v)", all, err)
+	}
+	if !reflect.DeepEqual(all[0].Annotation.Keywords, []string{"article", "x", "y"}) {
+		t.Fatalf("keywords: %v", all[0].Annotation.Keywords)
+	}
+}
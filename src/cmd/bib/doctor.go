@@ -0,0 +1,11 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/doctorcmd"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(doctorcmd.New(optionalCommit(&noCommit)), &noCommit)
+}
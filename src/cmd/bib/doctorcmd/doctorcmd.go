@@ -0,0 +1,426 @@
+package doctorcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// earliestAccessed is the lower bound for plausible accessed dates (the web did not exist before 1991).
+var earliestAccessed = time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the doctor command which checks the store for consistency problems.
+func New(commit CommitFunc) *cobra.Command {
+	var checkFiles, checkAccessed, checkSchema, checkTypes, checkVerified, fix, preferFilename, clearAccessed bool
+	var reachability, asJSON, strict bool
+	var workers int
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the citation store for consistency problems (optionally --fix)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if asJSON && !reachability {
+				return fmt.Errorf("--json applies to --reachability")
+			}
+			// With no rule selected, run every offline rule (--reachability is opt-in).
+			all := !checkFiles && !checkAccessed && !checkSchema && !checkTypes && !checkVerified && !reachability
+			problems, fixed := 0, 0
+			var commitPaths []string
+			if all || checkFiles {
+				n, f, paths, err := runCheckFiles(cmd, fix, preferFilename)
+				if err != nil {
+					return err
+				}
+				problems += n
+				fixed += f
+				if len(paths) > 0 {
+					commitPaths = append(commitPaths, store.CitationsDir)
+				}
+			}
+			if all || checkAccessed {
+				n, f, err := runCheckAccessed(cmd, fix, clearAccessed)
+				if err != nil {
+					return err
+				}
+				problems += n
+				fixed += f
+				if f > 0 {
+					commitPaths = append(commitPaths, store.BibFile)
+				}
+			}
+			if all || checkSchema {
+				n, err := runCheckSchema(cmd)
+				if err != nil {
+					return err
+				}
+				problems += n
+			}
+			if all || checkTypes {
+				n, err := runCheckTypes(cmd, strict)
+				if err != nil {
+					return err
+				}
+				problems += n
+			}
+			if all || checkVerified {
+				n, err := runCheckVerified(cmd)
+				if err != nil {
+					return err
+				}
+				problems += n
+			}
+			if reachability {
+				n, err := runReachability(cmd, workers, asJSON)
+				if err != nil {
+					return err
+				}
+				problems += n
+			}
+			if fixed > 0 {
+				if err := commit(commitPaths, fmt.Sprintf("doctor: fix %d problem(s)", fixed)); err != nil {
+					return err
+				}
+			}
+			if problems > fixed {
+				return fmt.Errorf("doctor found %d problem(s)", problems-fixed)
+			}
+			if problems == 0 && !asJSON {
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), "no problems found")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&checkFiles, "check-files", false, "Check that each YAML filename matches the id inside (<id>.yaml) and sits in its type's directory")
+	cmd.Flags().BoolVar(&checkAccessed, "check-accessed", false, "Check for accessed dates in the future, before 1991, missing for a url, or set without a url")
+	cmd.Flags().BoolVar(&checkSchema, "check-schema", false, "Check for entries that fail validation, duplicate ids, and boilerplate (\"manually constructed\") summaries")
+	cmd.Flags().BoolVar(&checkTypes, "check-types", false, "Check each entry for the fields its type needs (errors) or should have (warnings, not counted as problems)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "With --check-types, apply the strict type checks of add --strict and count every finding as a problem")
+	cmd.Flags().BoolVar(&checkVerified, "check-verified", false, "Check that verified entries record who verified them, when, and (for consensus) which providers")
+	cmd.Flags().BoolVar(&reachability, "reachability", false, "Probe every URL and DOI (network) and summarize: ok, redirected, 404, dns error, timeout")
+	cmd.Flags().IntVar(&workers, "workers", 8, "With --reachability, number of concurrent requests")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "With --reachability, print the full report as JSON")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Repair problems: rename or move files to match their id and type, set missing and clamp implausible accessed dates to today")
+	cmd.Flags().BoolVar(&clearAccessed, "clear-accessed", false, "With --fix, clear accessed dates on entries that have no url")
+	cmd.Flags().BoolVar(&preferFilename, "prefer-filename", false, "With --fix, update the internal id to match the filename instead of renaming")
+	return cmd
+}
+
+// runCheckFiles reports YAML files whose basename differs from their internal id or
+// that sit outside their type's segment directory and, with fix, moves them to their
+// id-based path (store.RepairFilenames), first rewriting the id from the filename when
+// preferFilename. Files that are unreadable or invalid are left to the schema check. It
+// returns the number of problems found, the number repaired, and the paths written.
+func runCheckFiles(cmd *cobra.Command, fix, preferFilename bool) (int, int, []string, error) {
+	files, err := store.ScanYAMLFiles()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	out := cmd.OutOrStdout()
+	problems := 0
+	var repaired []string
+	for _, f := range files {
+		if f.Err != nil {
+			continue
+		}
+		fileID := strings.TrimSuffix(path.Base(f.Path), ".yaml")
+		wantDir := path.Join(filepath.ToSlash(store.CitationsDir), store.SegmentForType(f.Entry.Type))
+		if fileID != f.Entry.ID {
+			problems++
+			if _, err := fmt.Fprintf(out, "file/id mismatch: %s (filename id: %s, internal id: %s)\n", f.Path, fileID, f.Entry.ID); err != nil {
+				return 0, 0, nil, err
+			}
+			if fix && preferFilename {
+				e := f.Entry
+				e.ID = fileID
+				if err := e.Validate(); err != nil {
+					return 0, 0, nil, fmt.Errorf("%s: cannot use filename id: %w", f.Path, err)
+				}
+				b, err := json.MarshalIndent(e, "", "  ")
+				if err != nil {
+					return 0, 0, nil, err
+				}
+				if err := os.WriteFile(filepath.FromSlash(f.Path), append(b, '\n'), 0o644); err != nil {
+					return 0, 0, nil, err
+				}
+				repaired = append(repaired, f.Path)
+				if _, err := fmt.Fprintf(out, "  updated id in %s -> %s\n", f.Path, fileID); err != nil {
+					return 0, 0, nil, err
+				}
+			}
+		}
+		if path.Dir(f.Path) != wantDir {
+			problems++
+			if _, err := fmt.Fprintf(out, "misfiled: %s (type %s belongs in %s)\n", f.Path, f.Entry.Type, wantDir); err != nil {
+				return 0, 0, nil, err
+			}
+		}
+	}
+	if !fix || problems == 0 {
+		return problems, 0, nil, nil
+	}
+	moved, err := store.RepairFilenames()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	for _, m := range moved {
+		repaired = append(repaired, m.To)
+		if _, err := fmt.Fprintf(out, "  renamed %s -> %s\n", m.From, m.To); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	return problems, problems, repaired, nil
+}
+
+// runCheckAccessed reports entries whose accessed date is unparseable, in the future, or
+// before 1991, entries with a URL but no accessed date, and entries with an accessed date
+// but no URL. With fix, implausible dates are clamped to today, a missing date is set to
+// today, and (with clearOrphans) accessed is cleared where there is no URL.
+// It returns the number of problems found and the number repaired.
+func runCheckAccessed(cmd *cobra.Command, fix, clearOrphans bool) (int, int, error) {
+	entries, err := store.ReadAll()
+	if err != nil {
+		return 0, 0, err
+	}
+	out := cmd.OutOrStdout()
+	now := dates.Now().UTC()
+	today := dates.NowISO()
+	problems := 0
+	fixed := 0
+	for _, e := range entries {
+		accessed := strings.TrimSpace(e.APA7.Accessed)
+		if accessed == "" {
+			if strings.TrimSpace(e.APA7.URL) == "" {
+				continue
+			}
+			problems++
+			if _, err := fmt.Fprintf(out, "url without accessed: %s\n", e.ID); err != nil {
+				return 0, 0, err
+			}
+			if !fix {
+				continue
+			}
+			e.APA7.Accessed = today
+			if e.Validate() != nil {
+				// Left for the schema check to report; WriteEntry would reject it.
+				continue
+			}
+			if _, err := store.WriteEntry(e); err != nil {
+				return 0, 0, err
+			}
+			fixed++
+			if _, err := fmt.Fprintf(out, "  set accessed to %s\n", today); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+		changed := false
+		if t, err := time.Parse("2006-01-02", accessed); err != nil || t.After(now) || t.Before(earliestAccessed) {
+			problems++
+			if _, err := fmt.Fprintf(out, "implausible accessed date: %s (%s)\n", e.ID, accessed); err != nil {
+				return 0, 0, err
+			}
+			if fix {
+				e.APA7.Accessed = today
+				changed = true
+				fixed++
+				if _, err := fmt.Fprintf(out, "  clamped accessed to %s\n", today); err != nil {
+					return 0, 0, err
+				}
+			}
+		}
+		if strings.TrimSpace(e.APA7.URL) == "" {
+			problems++
+			if _, err := fmt.Fprintf(out, "accessed without url: %s\n", e.ID); err != nil {
+				return 0, 0, err
+			}
+			if fix && clearOrphans {
+				e.APA7.Accessed = ""
+				changed = true
+				fixed++
+				if _, err := fmt.Fprintln(out, "  cleared accessed"); err != nil {
+					return 0, 0, err
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+		if _, err := store.WriteEntry(e); err != nil {
+			return 0, 0, err
+		}
+	}
+	return problems, fixed, nil
+}
+
+// boilerplateSummaries are placeholder phrases written when no real summary was
+// available; entries carrying them still need an annotation.
+var boilerplateSummaries = []string{"manually constructed"}
+
+// runCheckSchema reports legacy YAML files that cannot be read or fail validation,
+// library entries that fail validation, ids used by more than one entry or file, and
+// boilerplate summaries. A missing accessed date is left to the accessed check. None of
+// these are repaired automatically. It returns the number of problems found.
+func runCheckSchema(cmd *cobra.Command) (int, error) {
+	files, err := store.ScanYAMLFiles()
+	if err != nil {
+		return 0, err
+	}
+	out := cmd.OutOrStdout()
+	problems := 0
+	report := func(format string, args ...any) error {
+		problems++
+		_, err := fmt.Fprintf(out, format, args...)
+		return err
+	}
+	fileIDs := map[string][]string{}
+	for _, f := range files {
+		if f.Err != nil {
+			if err := report("invalid entry: %v\n", f.Err); err != nil {
+				return 0, err
+			}
+		}
+		if id := strings.ToLower(f.Entry.ID); id != "" {
+			fileIDs[id] = append(fileIDs[id], f.Path)
+		}
+	}
+	// Without a BibTeX library, the entries are the YAML files already scanned: reading
+	// them again with store.ReadAll would stop at the first bad file reported above.
+	fi, statErr := os.Stat(store.BibFile)
+	fromBib := statErr == nil && fi.Size() > 0
+	var entries []schema.Entry
+	if fromBib {
+		if entries, err = store.ReadAll(); err != nil {
+			return 0, err
+		}
+	} else {
+		for _, f := range files {
+			if f.Entry.ID != "" {
+				entries = append(entries, f.Entry)
+			}
+		}
+	}
+	libraryIDs := map[string]int{}
+	for _, e := range entries {
+		libraryIDs[strings.ToLower(e.ID)]++
+		c := e
+		schema.EnsureAccessedIfURL(&c)
+		if err := c.Validate(); err != nil && fromBib {
+			if err := report("invalid entry: %s: %v\n", e.ID, err); err != nil {
+				return 0, err
+			}
+		}
+		for _, phrase := range boilerplateSummaries {
+			if strings.Contains(strings.ToLower(e.Annotation.Summary), phrase) {
+				if err := report("boilerplate summary: %s (%q)\n", e.ID, e.Annotation.Summary); err != nil {
+					return 0, err
+				}
+				break
+			}
+		}
+	}
+	for _, id := range sortedKeys(fileIDs) {
+		if paths := fileIDs[id]; len(paths) > 1 {
+			if err := report("duplicate id: %s (%s)\n", id, strings.Join(paths, ", ")); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if fromBib {
+		for _, id := range sortedKeys(libraryIDs) {
+			if n := libraryIDs[id]; n > 1 {
+				if err := report("duplicate id: %s (%d entries in %s)\n", id, n, filepath.ToSlash(store.BibFile)); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// runCheckTypes reports the type-specific findings of each entry (schema CheckType).
+// Errors count as problems; warnings are printed but do not fail the run. With strict,
+// the findings are those of CheckTypeStrict, all errors. It returns the number of errors.
+func runCheckTypes(cmd *cobra.Command, strict bool) (int, error) {
+	entries, err := store.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+	out := cmd.OutOrStdout()
+	problems := 0
+	for _, e := range entries {
+		// A missing accessed date is left to the accessed check, which can fix it.
+		schema.EnsureAccessedIfURL(&e)
+		issues := e.CheckType()
+		if strict {
+			issues = e.CheckTypeStrict()
+		}
+		for _, is := range issues {
+			if is.Severity == schema.SeverityError {
+				problems++
+			}
+			if _, err := fmt.Fprintf(out, "type check %s: %s\n", is.Severity, is.Message); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return problems, nil
+}
+
+// runCheckVerified reports verified entries whose audit details are incomplete: no
+// verifier, no timestamp, or a consensus verification that names no providers. These
+// cannot be repaired automatically; re-run bib verify on the entry instead.
+func runCheckVerified(cmd *cobra.Command) (int, error) {
+	entries, err := store.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+	out := cmd.OutOrStdout()
+	problems := 0
+	for _, e := range entries {
+		v := e.Verification
+		if v == nil {
+			continue
+		}
+		var missing []string
+		if v.By == "" {
+			missing = append(missing, "by")
+		}
+		if v.At == "" {
+			missing = append(missing, "at")
+		}
+		if v.Method == schema.VerifyConsensus && len(v.Providers) == 0 {
+			missing = append(missing, "providers")
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		problems++
+		if _, err := fmt.Fprintf(out, "incomplete verification: %s (missing %s)\n", e.ID, strings.Join(missing, ", ")); err != nil {
+			return 0, err
+		}
+	}
+	return problems, nil
+}
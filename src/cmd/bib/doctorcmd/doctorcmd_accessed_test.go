@@ -0,0 +1,155 @@
+package doctorcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestDoctorCheckAccessed_ReportsAndFixes(t *testing.T) {
+	chdirTemp(t)
+	dates.SetClock(func() time.Time { return time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC) })
+	t.Cleanup(func() { dates.SetClock(nil) })
+
+	const (
+		future  = "00000000-0000-4000-8000-000000000011"
+		ancient = "00000000-0000-4000-8000-000000000012"
+		fine    = "00000000-0000-4000-8000-000000000014"
+	)
+	mk := func(id, accessed, url string) schema.Entry {
+		return schema.Entry{ID: id, Type: "website", APA7: schema.APA7{Title: "T", URL: url, Accessed: accessed}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"web"}}}
+	}
+	for _, e := range []schema.Entry{
+		mk(future, "2031-01-01", "https://example.com/a"),
+		mk(ancient, "1985-03-04", "https://example.com/b"),
+		mk(fine, "2023-02-03", "https://example.com/c"),
+	} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	report := New(func([]string, string) error { t.Fatalf("report-only run should not commit"); return nil })
+	var buf bytes.Buffer
+	report.SetOut(&buf)
+	report.SilenceUsage, report.SilenceErrors = true, true
+	report.SetArgs([]string{"--check-accessed"})
+	if err := report.Execute(); err == nil || !strings.Contains(err.Error(), "2 problem(s)") {
+		t.Fatalf("expected 2 problems, got %v", err)
+	}
+	for _, want := range []string{"implausible accessed date: " + future + " (2031-01-01)", "implausible accessed date: " + ancient + " (1985-03-04)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("missing %q in %q", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), fine) {
+		t.Fatalf("plausible entry should not be reported: %q", buf.String())
+	}
+
+	var committed []string
+	fix := New(func(paths []string, msg string) error { committed = paths; return nil })
+	fix.SetOut(&bytes.Buffer{})
+	fix.SetArgs([]string{"--check-accessed", "--fix"})
+	if err := fix.Execute(); err != nil {
+		t.Fatalf("fix: %v", err)
+	}
+	if len(committed) != 1 || committed[0] != store.BibFile {
+		t.Fatalf("unexpected commit paths: %v", committed)
+	}
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	got := map[string]string{}
+	for _, e := range entries {
+		got[e.ID] = e.APA7.Accessed
+	}
+	if got[future] != "2024-05-06" || got[ancient] != "2024-05-06" || got[fine] != "2023-02-03" {
+		t.Fatalf("unexpected accessed dates after fix: %v", got)
+	}
+}
+
+func TestDoctorCheckAccessed_ClearsAccessedWithoutURL(t *testing.T) {
+	chdirTemp(t)
+	// The library.bib only stores urldate alongside a url, so orphans come from legacy YAML.
+	p := filepath.Join("data", "citations", "site", internalID+".yaml")
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	e := schema.Entry{ID: internalID, Type: "website", APA7: schema.APA7{Title: "T", Accessed: "2020-01-01"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"web"}}}
+	b, _ := json.Marshal(e)
+	if err := os.WriteFile(p, b, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	keep := New(func([]string, string) error { return nil })
+	var buf bytes.Buffer
+	keep.SetOut(&buf)
+	keep.SilenceUsage, keep.SilenceErrors = true, true
+	keep.SetArgs([]string{"--check-accessed", "--fix"})
+	if err := keep.Execute(); err == nil {
+		t.Fatalf("expected error when accessed is kept without --clear-accessed")
+	}
+	if !strings.Contains(buf.String(), "accessed without url: "+internalID) {
+		t.Fatalf("unexpected report: %q", buf.String())
+	}
+
+	commits := 0
+	clear := New(func([]string, string) error { commits++; return nil })
+	clear.SetOut(&bytes.Buffer{})
+	clear.SetArgs([]string{"--check-accessed", "--fix", "--clear-accessed"})
+	if err := clear.Execute(); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if commits != 1 {
+		t.Fatalf("expected one commit, got %d", commits)
+	}
+	entries, err := store.ReadAll()
+	if err != nil || len(entries) != 1 || entries[0].APA7.Accessed != "" {
+		t.Fatalf("accessed should be cleared: %v %+v", err, entries)
+	}
+}
+
+func TestDoctorCheckVerified_ReportsIncompleteAudit(t *testing.T) {
+	chdirTemp(t)
+	const (
+		complete   = "00000000-0000-4000-8000-000000000031"
+		noProvider = "00000000-0000-4000-8000-000000000032"
+		unverified = "00000000-0000-4000-8000-000000000033"
+	)
+	for _, id := range []string{complete, noProvider, unverified} {
+		e := schema.Entry{ID: id, Type: "website", APA7: schema.APA7{Title: "T", URL: "https://example.com/" + id, Accessed: "2023-02-03"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"web"}}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := store.VerifyByIDWithProviders(complete, "tester", schema.VerifyConsensus, []string{"web"}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := store.VerifyByIDWithProviders(noProvider, "tester", schema.VerifyConsensus, nil); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	cmd := New(func([]string, string) error { t.Fatalf("report-only rule should not commit"); return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"--check-verified"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "1 problem(s)") {
+		t.Fatalf("expected 1 problem, got %v", err)
+	}
+	if want := "incomplete verification: " + noProvider + " (missing providers)"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("missing %q in %q", want, buf.String())
+	}
+	if strings.Contains(buf.String(), complete) || strings.Contains(buf.String(), unverified) {
+		t.Fatalf("only the incomplete entry should be reported: %q", buf.String())
+	}
+}
@@ -0,0 +1,236 @@
+package doctorcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// integrityBib seeds one library entry per problem class: no title (invalid), a url
+// without an accessed date, a boilerplate summary, and an id used twice.
+const integrityBib = `@misc{invalid,
+  abstract = {s},
+  keywords = {x},
+  _id = {00000000-0000-4000-8000-000000000021},
+  _type = {book},
+}
+
+@misc{noaccessed,
+  title = {No Accessed},
+  url = {https://example.com/a},
+  abstract = {s},
+  keywords = {web},
+  _id = {00000000-0000-4000-8000-000000000022},
+  _type = {website},
+}
+
+@misc{boiler,
+  title = {Boiler},
+  abstract = {Bibliographic record for Boiler (manually constructed).},
+  keywords = {book},
+  _id = {00000000-0000-4000-8000-000000000023},
+  _type = {book},
+}
+
+@misc{dup1,
+  title = {Dup One},
+  abstract = {s},
+  keywords = {book},
+  _id = {00000000-0000-4000-8000-000000000024},
+  _type = {book},
+}
+
+@misc{dup2,
+  title = {Dup Two},
+  abstract = {s},
+  keywords = {book},
+  _id = {00000000-0000-4000-8000-000000000024},
+  _type = {book},
+}
+`
+
+const misfiledID = "00000000-0000-4000-8000-000000000025"
+
+func seedIntegrity(t *testing.T) {
+	t.Helper()
+	chdirTemp(t)
+	dates.SetClock(func() time.Time { return time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC) })
+	t.Cleanup(func() { dates.SetClock(nil) })
+	_ = os.MkdirAll(filepath.Dir(store.BibFile), 0o755)
+	if err := os.WriteFile(store.BibFile, []byte(integrityBib), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e := schema.Entry{ID: misfiledID, Type: "article", APA7: schema.APA7{Title: "T"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"article"}}}
+	b, _ := json.Marshal(e)
+	p := filepath.Join("data", "citations", "books", misfiledID+".yaml")
+	_ = os.MkdirAll(filepath.Dir(p), 0o755)
+	if err := os.WriteFile(p, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bad := filepath.Join("data", "citations", "books", "broken.yaml")
+	if err := os.WriteFile(bad, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDoctorReportsEachProblemClass(t *testing.T) {
+	seedIntegrity(t)
+	cmd := New(func([]string, string) error { t.Fatalf("report-only run should not commit"); return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"--check-files", "--check-accessed", "--check-schema"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "doctor found 6 problem(s)") {
+		t.Fatalf("expected 6 problems, got %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{
+		"misfiled: data/citations/books/" + misfiledID + ".yaml (type article belongs in data/citations/article)",
+		"url without accessed: 00000000-0000-4000-8000-000000000022",
+		"invalid entry: invalid YAML in data/citations/books/broken.yaml",
+		"invalid entry: 00000000-0000-4000-8000-000000000021: apa7.title is required",
+		"boilerplate summary: 00000000-0000-4000-8000-000000000023",
+		"duplicate id: 00000000-0000-4000-8000-000000000024 (2 entries in data/library.bib)",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "invalid entry: 00000000-0000-4000-8000-000000000022") {
+		t.Errorf("missing accessed should only be reported by the accessed check:\n%s", buf.String())
+	}
+}
+
+func TestDoctorFixMovesMisfiledAndSetsAccessed(t *testing.T) {
+	seedIntegrity(t)
+	var paths []string
+	cmd := New(func(p []string, msg string) error { paths = p; return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"--check-files", "--check-accessed", "--fix"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("fix: %v\n%s", err, buf.String())
+	}
+	if _, err := os.Stat(filepath.Join("data", "citations", "article", misfiledID+".yaml")); err != nil {
+		t.Fatalf("misfiled entry not moved: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("commit paths: %v", paths)
+	}
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.ID == "00000000-0000-4000-8000-000000000022" && e.APA7.Accessed != "2024-05-06" {
+			t.Fatalf("accessed not set: %q", e.APA7.Accessed)
+		}
+	}
+}
+
+func TestDoctorCheckTypesErrorsAndWarnings(t *testing.T) {
+	chdirTemp(t)
+	year := 2020
+	for _, e := range []schema.Entry{
+		{ID: "00000000-0000-4000-8000-000000000031", Type: "book", APA7: schema.APA7{Title: "No Publisher", Year: &year}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}},
+		{ID: "00000000-0000-4000-8000-000000000032", Type: "article", APA7: schema.APA7{Title: "No Journal", Year: &year, Identifiers: schema.Identifiers{DOI: "10.1/x"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"article"}}},
+	} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func() (string, error) {
+		cmd := New(func([]string, string) error { return nil })
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		cmd.SetArgs([]string{"--check-types"})
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+	out, err := run()
+	if err == nil || !strings.Contains(err.Error(), "doctor found 1 problem(s)") {
+		t.Fatalf("only the book error should count: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"type check error: book entry 00000000-0000-4000-8000-000000000031 missing publisher and ISBN",
+		"type check warning: article entry 00000000-0000-4000-8000-000000000032 missing journal and container title",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report missing %q:\n%s", want, out)
+		}
+	}
+
+	// Warnings alone do not fail the run.
+	entries, _ := store.ReadAll()
+	for _, e := range entries {
+		if e.Type == "book" {
+			e.APA7.Publisher = "P"
+			if _, err := store.WriteEntry(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if out, err := run(); err != nil || !strings.Contains(out, "type check warning") {
+		t.Fatalf("warnings should not fail the run: %v\n%s", err, out)
+	}
+
+	// Under --strict they do.
+	cmd := New(func([]string, string) error { return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"--check-types", "--strict"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "doctor found 1 problem(s)") {
+		t.Fatalf("strict should count the article warning: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "type check error: article entry 00000000-0000-4000-8000-000000000032 missing journal and container title") {
+		t.Fatalf("strict report:\n%s", buf.String())
+	}
+}
+
+func TestDoctorCheckSchema_YAMLOnlyReportsEveryFile(t *testing.T) {
+	chdirTemp(t)
+	dir := filepath.Join("data", "citations", "books")
+	_ = os.MkdirAll(dir, 0o755)
+	write := func(name string, b []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a-broken.yaml", []byte("{not json"))
+	write("b-broken.yaml", []byte("["))
+	untitled, _ := json.Marshal(schema.Entry{ID: "00000000-0000-4000-8000-000000000031", Type: "book", Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}})
+	write("00000000-0000-4000-8000-000000000031.yaml", untitled)
+	boiler, _ := json.Marshal(schema.Entry{ID: "00000000-0000-4000-8000-000000000032", Type: "book", APA7: schema.APA7{Title: "Boiler"}, Annotation: schema.Annotation{Summary: "Bibliographic record for Boiler (manually constructed).", Keywords: []string{"book"}}})
+	write("00000000-0000-4000-8000-000000000032.yaml", boiler)
+
+	cmd := New(func([]string, string) error { return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"--check-schema"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "doctor found 4 problem(s)") {
+		t.Fatalf("expected 4 problems, got %v\n%s", err, buf.String())
+	}
+	for _, want := range []string{
+		"invalid YAML in data/citations/books/a-broken.yaml",
+		"invalid YAML in data/citations/books/b-broken.yaml",
+		"invalid entry in data/citations/books/00000000-0000-4000-8000-000000000031.yaml",
+		"boilerplate summary: 00000000-0000-4000-8000-000000000032",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
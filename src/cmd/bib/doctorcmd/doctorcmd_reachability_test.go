@@ -0,0 +1,81 @@
+package doctorcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/linkcheck"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+type statusHTTP map[string]int
+
+func (f statusHTTP) Do(req *http.Request) (*http.Response, error) {
+	code, ok := f[req.URL.String()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+}
+
+func TestDoctorReachability(t *testing.T) {
+	chdirTemp(t)
+	const (
+		site    = "00000000-0000-4000-8000-000000000051"
+		dead    = "00000000-0000-4000-8000-000000000052"
+		article = "00000000-0000-4000-8000-000000000053"
+	)
+	for _, e := range []schema.Entry{
+		{ID: site, Type: "website", APA7: schema.APA7{Title: "Site", URL: "https://example.com/ok", Accessed: "2024-01-01"}},
+		{ID: dead, Type: "website", APA7: schema.APA7{Title: "Dead", URL: "https://example.com/gone", Accessed: "2024-01-01"}},
+		{ID: article, Type: "article", APA7: schema.APA7{Title: "Article", URL: "https://doi.org/10.1/x", Accessed: "2024-01-01", Identifiers: schema.Identifiers{DOI: "10.1/x"}}},
+	} {
+		e.Annotation = schema.Annotation{Summary: "s", Keywords: []string{"k"}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	linkcheck.SetHTTPClient(statusHTTP{"https://example.com/ok": 200, "https://example.com/gone": 404, "https://doi.org/10.1/x": 302})
+	t.Cleanup(func() { linkcheck.SetHTTPClient(&http.Client{}) })
+
+	run := func(args ...string) (string, error) {
+		cmd := New(func([]string, string) error { t.Fatalf("reachability should not commit"); return nil })
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("--reachability")
+	if err == nil || !strings.Contains(err.Error(), "1 problem(s)") {
+		t.Fatalf("expected 1 problem, got %v", err)
+	}
+	for _, want := range []string{"404", dead, "https://example.com/gone", "ok: 2", "404: 1", "reachable: 2/3 (66.7%)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in %q", want, out)
+		}
+	}
+	if strings.Contains(out, "file/id mismatch") || strings.Contains(out, site) {
+		t.Fatalf("only the unreachable target should be listed: %q", out)
+	}
+
+	out, _ = run("--reachability", "--json")
+	var rep ReachabilityReport
+	if err := json.Unmarshal([]byte(out), &rep); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if rep.Checked != 3 || rep.Reachable != 2 || rep.Summary[linkcheck.NotFound] != 1 || len(rep.Results) != 3 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+
+	if _, err := run("--json"); err == nil {
+		t.Fatalf("--json without --reachability should error")
+	}
+}
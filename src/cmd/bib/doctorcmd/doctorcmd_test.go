@@ -0,0 +1,92 @@
+package doctorcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+const (
+	internalID = "00000000-0000-4000-8000-000000000001"
+	fileID     = "00000000-0000-4000-8000-000000000002"
+)
+
+func writeMismatched(t *testing.T) string {
+	t.Helper()
+	p := filepath.Join("data", "citations", "books", fileID+".yaml")
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	e := schema.Entry{ID: internalID, Type: "book", APA7: schema.APA7{Title: "T"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	b, _ := json.Marshal(e)
+	if err := os.WriteFile(p, b, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return p
+}
+
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+}
+
+func TestDoctorCheckFiles_ReportsAndRenames(t *testing.T) {
+	chdirTemp(t)
+	p := writeMismatched(t)
+
+	report := New(func([]string, string) error { t.Fatalf("report-only run should not commit"); return nil })
+	var buf bytes.Buffer
+	report.SetOut(&buf)
+	report.SilenceUsage, report.SilenceErrors = true, true
+	report.SetArgs([]string{"--check-files"})
+	if err := report.Execute(); err == nil {
+		t.Fatalf("expected error when mismatches remain")
+	}
+	if !strings.Contains(buf.String(), "filename id: "+fileID+", internal id: "+internalID) {
+		t.Fatalf("unexpected report: %q", buf.String())
+	}
+
+	commits := 0
+	fix := New(func(paths []string, msg string) error { commits++; return nil })
+	fix.SetOut(&bytes.Buffer{})
+	fix.SetArgs([]string{"--check-files", "--fix"})
+	if err := fix.Execute(); err != nil {
+		t.Fatalf("fix: %v", err)
+	}
+	if _, err := os.Stat(p); err == nil {
+		t.Fatalf("old file should be renamed")
+	}
+	if _, err := os.Stat(filepath.Join("data", "citations", "books", internalID+".yaml")); err != nil {
+		t.Fatalf("renamed file missing: %v", err)
+	}
+	if commits != 1 {
+		t.Fatalf("expected one commit, got %d", commits)
+	}
+}
+
+func TestDoctorCheckFiles_PreferFilename(t *testing.T) {
+	chdirTemp(t)
+	p := writeMismatched(t)
+	cmd := New(func([]string, string) error { return nil })
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--check-files", "--fix", "--prefer-filename"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("fix: %v", err)
+	}
+	files, err := store.ReadAllYAMLFiles()
+	if err != nil || len(files) != 1 {
+		t.Fatalf("read: %v %d", err, len(files))
+	}
+	if files[0].Entry.ID != fileID || files[0].Path != filepath.ToSlash(p) {
+		t.Fatalf("id not updated: %+v", files[0])
+	}
+}
@@ -0,0 +1,105 @@
+package doctorcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/linkcheck"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// ReachabilityReport is the --reachability --json document.
+type ReachabilityReport struct {
+	Checked   int                      `json:"checked"`
+	Reachable int                      `json:"reachable"`
+	Percent   float64                  `json:"percent_reachable"`
+	Summary   map[linkcheck.Status]int `json:"summary"`
+	Results   []linkcheck.Result       `json:"results"`
+}
+
+// runReachability probes every entry's URL and DOI concurrently and prints a table of
+// unreachable or redirected targets followed by per-category counts (or, with asJSON,
+// the full report). It returns the number of unreachable targets.
+func runReachability(cmd *cobra.Command, workers int, asJSON bool) (int, error) {
+	entries, err := store.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+	results := linkcheck.CheckAll(cmd.Context(), reachabilityTargets(entries), workers)
+	rep := ReachabilityReport{Checked: len(results), Summary: map[linkcheck.Status]int{}, Results: results}
+	for _, r := range results {
+		rep.Summary[r.Status]++
+		if r.Status.Reachable() {
+			rep.Reachable++
+		}
+	}
+	if rep.Checked > 0 {
+		rep.Percent = float64(rep.Reachable) * 100 / float64(rep.Checked)
+	}
+	unreachable := rep.Checked - rep.Reachable
+	out := cmd.OutOrStdout()
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return unreachable, enc.Encode(rep)
+	}
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if unreachable > 0 || rep.Summary[linkcheck.Redirected] > 0 {
+		if _, err := fmt.Fprintln(tw, "STATUS\tID\tKIND\tTARGET\tDETAIL"); err != nil {
+			return 0, err
+		}
+	}
+	for _, r := range results {
+		if r.Status == linkcheck.OK {
+			continue
+		}
+		detail := r.Detail
+		if r.Status == linkcheck.Redirected {
+			detail = "-> " + r.Location
+		} else if r.Code != 0 {
+			detail = fmt.Sprintf("http %d", r.Code)
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Status, r.ID, r.Kind, r.Value, detail); err != nil {
+			return 0, err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return 0, err
+	}
+	for _, s := range linkcheck.Statuses {
+		if n := rep.Summary[s]; n > 0 {
+			if _, err := fmt.Fprintf(out, "%s: %d\n", s, n); err != nil {
+				return 0, err
+			}
+		}
+	}
+	_, err = fmt.Fprintf(out, "reachable: %d/%d (%.1f%%)\n", rep.Reachable, rep.Checked, rep.Percent)
+	return unreachable, err
+}
+
+// reachabilityTargets lists the DOI and URL of each entry. A URL that is just the
+// doi.org link for the entry's DOI is not probed twice.
+func reachabilityTargets(entries []schema.Entry) []linkcheck.Target {
+	var targets []linkcheck.Target
+	for _, e := range entries {
+		doi := strings.TrimSpace(e.APA7.DOI)
+		if doi != "" {
+			targets = append(targets, linkcheck.Target{ID: e.ID, Kind: linkcheck.KindDOI, Value: doi})
+		}
+		raw := strings.TrimSpace(e.APA7.URL)
+		if raw == "" {
+			continue
+		}
+		if u, err := url.Parse(raw); err == nil && doi != "" && strings.HasSuffix(u.Hostname(), "doi.org") {
+			continue
+		}
+		targets = append(targets, linkcheck.Target{ID: e.ID, Kind: linkcheck.KindURL, Value: raw})
+	}
+	return targets
+}
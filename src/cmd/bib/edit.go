@@ -0,0 +1,11 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/editcmd"
+	"github.com/spf13/cobra"
+)
+
+func newEditCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(editcmd.New(optionalCommit(&noCommit)), &noCommit)
+}
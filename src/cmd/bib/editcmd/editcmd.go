@@ -0,0 +1,82 @@
+package editcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the edit command for targeted maintenance of a single entry.
+func New(commit CommitFunc) *cobra.Command {
+	var id string
+	var touch, show bool
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit an entry in place (e.g., --touch to refresh the accessed date)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(id) == "" {
+				return fmt.Errorf("--id is required")
+			}
+			if !touch && !show {
+				return fmt.Errorf("nothing to edit: provide --touch (or --show to print the entry)")
+			}
+			e, err := findByID(id)
+			if err != nil {
+				return err
+			}
+			if show {
+				b, err := json.MarshalIndent(e, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), string(b))
+				return err
+			}
+			if strings.TrimSpace(e.APA7.URL) == "" {
+				return fmt.Errorf("entry %s has no url; accessed date only applies to online sources", e.ID)
+			}
+			before := e.APA7.Accessed
+			e.APA7.Accessed = dates.NowISO()
+			if _, err := store.WriteEntry(e); err != nil {
+				return err
+			}
+			if err := commit([]string{store.BibFile}, fmt.Sprintf("touch accessed: %s", e.ID)); err != nil {
+				return err
+			}
+			if strings.TrimSpace(before) == "" {
+				before = "(none)"
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "accessed: %s -> %s\n", before, e.APA7.Accessed)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&id, "id", "", "ID of the entry to edit")
+	_ = cmd.RegisterFlagCompletionFunc("id", complete.IDs)
+	cmd.Flags().BoolVar(&touch, "touch", false, "Set the accessed date to today (entries with a URL)")
+	cmd.Flags().BoolVar(&show, "show", false, "Print the entry, including verification details, without changing it")
+	return cmd
+}
+
+// findByID returns the entry with the given id (case-insensitive).
+func findByID(id string) (schema.Entry, error) {
+	entries, err := store.ReadAll()
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	for _, e := range entries {
+		if strings.EqualFold(e.ID, strings.TrimSpace(id)) {
+			return e, nil
+		}
+	}
+	return schema.Entry{}, fmt.Errorf("no citation found for id %s", id)
+}
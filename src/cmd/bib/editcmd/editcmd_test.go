@@ -0,0 +1,87 @@
+package editcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestEditTouchRefreshesAccessed(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	dates.SetClock(func() time.Time { return time.Date(2025, 6, 7, 12, 0, 0, 0, time.UTC) })
+	t.Cleanup(func() { dates.SetClock(nil) })
+
+	site := schema.Entry{ID: schema.NewID(), Type: "website", APA7: schema.APA7{Title: "Site", URL: "https://example.com", Accessed: "2020-01-01"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	book := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Book"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	for _, e := range []schema.Entry{site, book} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	commits := 0
+	cmd := New(func(paths []string, msg string) error { commits++; return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--id", site.ID, "--touch"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(buf.String(), "accessed: 2020-01-01 -> 2025-06-07") || commits != 1 {
+		t.Fatalf("unexpected output %q (commits=%d)", buf.String(), commits)
+	}
+	got, err := findByID(site.ID)
+	if err != nil || got.APA7.Accessed != "2025-06-07" {
+		t.Fatalf("accessed not updated: %v %q", err, got.APA7.Accessed)
+	}
+
+	for _, args := range [][]string{
+		{"--id", book.ID, "--touch"},
+		{"--id", site.ID},
+		{"--touch"},
+		{"--id", "missing", "--touch"},
+	} {
+		c := New(func(paths []string, msg string) error { return nil })
+		c.SetOut(&bytes.Buffer{})
+		c.SilenceUsage, c.SilenceErrors = true, true
+		c.SetArgs(args)
+		if err := c.Execute(); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
+
+func TestEditShowPrintsVerification(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: schema.NewID(), Type: "website", APA7: schema.APA7{Title: "Site", URL: "https://example.com", Accessed: "2020-01-01"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := store.VerifyByIDWithProviders(e.ID, "tester", schema.VerifyConsensus, []string{"web"}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	cmd := New(func(paths []string, msg string) error { t.Fatalf("--show should not commit"); return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--id", e.ID, "--show"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	for _, want := range []string{`"verification"`, `"by": "tester"`, `"method": "consensus"`, `"web"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("missing %s in %q", want, buf.String())
+		}
+	}
+}
@@ -4,41 +4,323 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"bibliography/src/cmd/bib/citecmd"
+	"bibliography/src/internal/gitutil"
+	"bibliography/src/internal/schema"
 	"bibliography/src/internal/store"
 )
 
-// New returns an export command to migrate YAML citations to a consolidated BibTeX file.
+// firstCommitTimes resolves path -> first commit time; replaceable in tests.
+var firstCommitTimes = gitutil.FirstCommitTimes
+
+// New returns the export-bib command. With no filter and the default BibTeX format it
+// migrates the legacy YAML citations into data/library.bib (or --output); otherwise it
+// writes the library, or a selection of it, as BibTeX, RIS, CSL-JSON, APA, or a user
+// template to --output or stdout.
 func New() *cobra.Command {
 	var out string
 	var deleteYAML bool
+	var since string
+	var format string
+	var splitByType bool
+	var outDir string
+	var wrap int
+	var tmplPath string
+	var typeFilter string
 	cmd := &cobra.Command{
 		Use:   "export-bib",
-		Short: "Export all YAML citations to a consolidated BibTeX file",
+		Short: "Export citations as BibTeX (or RIS, CSL-JSON, APA, or a template) to a file or stdout",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if out == "" {
-				out = filepath.ToSlash(filepath.Join("data", "library.bib"))
-			}
-			if err := store.ExportYAMLToBib(out); err != nil {
-				return err
+			format = strings.ToLower(strings.TrimSpace(format))
+			if _, ok := extensions[format]; !ok {
+				return fmt.Errorf("unsupported --format %q (want bibtex, ris, csl, apa, or template)", format)
 			}
-			_, err := fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\n", out)
+			types, err := parseTypes(typeFilter)
 			if err != nil {
 				return err
 			}
+			opts := renderOptions{format: format, wrap: wrap}
+			if format == "template" {
+				t, err := loadTemplate(tmplPath)
+				if err != nil {
+					return err
+				}
+				opts.tmpl = t
+			}
 			if deleteYAML {
-				// Remove the entire data/citations tree
-				if rmErr := os.RemoveAll(filepath.Join("data", "citations")); rmErr != nil {
-					return rmErr
+				if err := checkDeleteYAML(splitByType, since, types, format); err != nil {
+					return err
+				}
+			}
+			if !splitByType && strings.TrimSpace(since) == "" && len(types) == 0 && format == "bibtex" {
+				if out == "" {
+					out = store.BibFile
+				}
+				n, err := store.ExportYAMLToBib(out)
+				if err != nil {
+					return err
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "removed data/citations\n")
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "wrote %s (%d entries)\n", out, n); err != nil {
+					return err
+				}
+				if deleteYAML {
+					// Remove the entire data/citations tree
+					if rmErr := os.RemoveAll(filepath.FromSlash(store.CitationsDir)); rmErr != nil {
+						return rmErr
+					}
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", store.CitationsDir)
+				}
+				return nil
+			}
+			if !splitByType && isLibrary(out) {
+				return fmt.Errorf("refusing to overwrite %s with a filtered or non-BibTeX export", store.BibFile)
+			}
+			entries, err := selectEntries(since, types)
+			if err != nil {
+				return err
 			}
-			return nil
+			if splitByType {
+				return writeSplit(cmd, entries, opts, outDir)
+			}
+			return writeExport(cmd, entries, opts, out)
 		},
 	}
-	cmd.Flags().StringVarP(&out, "output", "o", "", "Output bib file path (default data/library.bib)")
+	cmd.Flags().StringVarP(&out, "output", "o", "", "Output file path (default data/library.bib; stdout with --since, --type, or a non-bibtex --format)")
 	cmd.Flags().BoolVar(&deleteYAML, "delete-yaml", false, "Delete data/citations after export")
+	cmd.Flags().StringVar(&since, "since", "", "Only export entries added on/after this date (YYYY-MM-DD; by created timestamp, else modified)")
+	cmd.Flags().StringVar(&typeFilter, "type", "", "Only export entries of these types (comma-separated, e.g. article,book)")
+	cmd.Flags().StringVar(&format, "format", "bibtex", "Output format: bibtex, ris, csl (CSL-JSON), apa, or template")
+	cmd.Flags().StringVar(&tmplPath, "template", "", "With --format template, a Go text/template file executed once per entry")
+	cmd.Flags().BoolVar(&splitByType, "split-by-type", false, "Write one file per entry type (e.g., article.bib, book.bib) plus an index into --out-dir")
+	cmd.Flags().StringVar(&outDir, "out-dir", "refs", "Output directory for --split-by-type")
+	cmd.Flags().IntVar(&wrap, "wrap", 0, "With --format apa, wrap references to N columns with a hanging indent (0 = no wrapping)")
 	return cmd
 }
+
+// parseTypes splits a comma-separated --type value into a set of known entry types.
+func parseTypes(s string) (map[string]bool, error) {
+	types := map[string]bool{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if !schema.IsValidType(t) {
+			return nil, fmt.Errorf("invalid --type %q (want one of %s)", t, strings.Join(schema.Types, ", "))
+		}
+		types[t] = true
+	}
+	return types, nil
+}
+
+// selectEntries reads the library and keeps the entries whose type is in types (when
+// non-empty) that were added on or after since (when set; see addedTimes).
+func selectEntries(since string, types map[string]bool) ([]schema.Entry, error) {
+	all, err := store.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	entries := all[:0]
+	for _, e := range all {
+		if len(types) == 0 || types[e.Type] {
+			entries = append(entries, e)
+		}
+	}
+	if strings.TrimSpace(since) == "" {
+		return entries, nil
+	}
+	cutoff, err := time.Parse("2006-01-02", strings.TrimSpace(since))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --since %q (want YYYY-MM-DD)", since)
+	}
+	added, err := addedTimes(entries)
+	if err != nil {
+		return nil, err
+	}
+	var out []schema.Entry
+	for _, e := range entries {
+		if t, ok := added[strings.ToLower(e.ID)]; ok && !t.Before(cutoff) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// addedTimes returns when each entry was added, keyed by lowercase id: the created
+// timestamp of its library record, else its modified timestamp. A legacy YAML entry
+// without either falls back to the first commit of its file (else the file mtime).
+// Entries with no known time are left out.
+func addedTimes(entries []schema.Entry) (map[string]time.Time, error) {
+	times, err := store.ReadRecordTimes()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := store.EntryPaths(entries)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]time.Time{}
+	var legacy []string
+	for _, e := range entries {
+		id := strings.ToLower(e.ID)
+		rt := times[id]
+		switch {
+		case !rt.Created.IsZero():
+			out[id] = rt.Created
+		case !rt.Modified.IsZero():
+			out[id] = rt.Modified
+		case strings.HasSuffix(paths[id], ".yaml"):
+			legacy = append(legacy, paths[id])
+		}
+	}
+	if len(legacy) == 0 {
+		return out, nil
+	}
+	// Prefer git history; when unavailable (e.g., not a repo), fall back to mtimes below.
+	committed, gerr := firstCommitTimes(legacy)
+	if gerr != nil {
+		committed = map[string]time.Time{}
+	}
+	for _, e := range entries {
+		id := strings.ToLower(e.ID)
+		p := paths[id]
+		if _, ok := out[id]; ok || !strings.HasSuffix(p, ".yaml") {
+			continue
+		}
+		t, ok := committed[p]
+		if !ok {
+			fi, serr := os.Stat(filepath.FromSlash(p))
+			if serr != nil {
+				return nil, serr
+			}
+			t = fi.ModTime()
+		}
+		out[id] = t
+	}
+	return out, nil
+}
+
+// writeExport renders entries in the requested format to the output path, or to
+// stdout when no path is given (a filtered export never overwrites the library).
+func writeExport(cmd *cobra.Command, entries []schema.Entry, opts renderOptions, out string) error {
+	data, err := render(entries, opts)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return fmt.Errorf("cannot create output directory for %s: %w", out, err)
+	}
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "wrote %s (%d entries)\n", out, len(entries))
+	return err
+}
+
+// renderOptions selects the output format; wrap applies to apa, tmpl to template.
+type renderOptions struct {
+	format string
+	wrap   int
+	tmpl   *template.Template
+}
+
+// render formats entries as BibTeX, RIS, or CSL-JSON, as an alphabetical APA reference list (optionally
+// wrapped to opts.wrap columns), or through a user template in reference-list order.
+func render(entries []schema.Entry, opts renderOptions) ([]byte, error) {
+	switch opts.format {
+	case "bibtex":
+		return store.EntriesToBibTeX(entries), nil
+	case "ris":
+		return store.EntriesToRIS(entries), nil
+	case "csl":
+		return store.EntriesToCSLJSON(entries)
+	case "template":
+		sorted := append([]schema.Entry(nil), entries...)
+		sort.SliceStable(sorted, func(i, j int) bool { return citecmd.APACitation(sorted[i]) < citecmd.APACitation(sorted[j]) })
+		return renderTemplate(sorted, opts.tmpl)
+	}
+	var b strings.Builder
+	for _, r := range citecmd.ReferenceList(entries, citecmd.Options{}) {
+		b.WriteString(citecmd.Wrap(r, opts.wrap))
+		b.WriteString("\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// extensions maps export formats to the file extension used for split output.
+var extensions = map[string]string{"bibtex": ".bib", "ris": ".ris", "csl": ".json", "apa": ".txt", "template": ".txt"}
+
+// isLibrary reports whether out names the library file itself, which only the full
+// migration may write.
+func isLibrary(out string) bool {
+	if strings.TrimSpace(out) == "" {
+		return false
+	}
+	a, err1 := filepath.Abs(out)
+	b, err2 := filepath.Abs(store.BibFile)
+	return err1 == nil && err2 == nil && a == b
+}
+
+// checkDeleteYAML allows --delete-yaml only for the full BibTeX migration.
+func checkDeleteYAML(splitByType bool, since string, types map[string]bool, format string) error {
+	if splitByType {
+		return fmt.Errorf("--delete-yaml cannot be combined with --split-by-type")
+	}
+	if strings.TrimSpace(since) != "" || len(types) > 0 || format != "bibtex" {
+		return fmt.Errorf("--delete-yaml requires a full bibtex export (no --since or --type)")
+	}
+	return nil
+}
+
+// writeSplit writes one file per entry type into dir, plus an index.txt listing
+// each file and its entry count in type order.
+func writeSplit(cmd *cobra.Command, entries []schema.Entry, opts renderOptions, dir string) error {
+	if strings.TrimSpace(dir) == "" {
+		return fmt.Errorf("--out-dir is required with --split-by-type")
+	}
+	byType := map[string][]schema.Entry{}
+	for _, e := range entries {
+		t := strings.ToLower(strings.TrimSpace(e.Type))
+		if t == "" {
+			t = "misc"
+		}
+		byType[t] = append(byType[t], e)
+	}
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create output directory %s: %w", dir, err)
+	}
+	var index strings.Builder
+	for _, t := range types {
+		name := t + extensions[opts.format]
+		data, err := render(byType[t], opts)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(&index, "%s\t%d\n", name, len(byType[t]))
+	}
+	indexPath := filepath.Join(dir, "index.txt")
+	if err := os.WriteFile(indexPath, []byte(index.String()), 0o644); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(cmd.OutOrStdout(), "wrote %d files to %s (index %s)\n", len(types), dir, filepath.ToSlash(indexPath))
+	return err
+}
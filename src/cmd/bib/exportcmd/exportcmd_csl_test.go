@@ -0,0 +1,51 @@
+package exportcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/importer"
+)
+
+func TestExportCSLRoundTripsThroughImporter(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := entry{ID: "00000000-0000-4000-8000-000000000093", Type: "website", APA7: apa7{Title: "A Site", URL: "https://example.com", Accessed: "2025-01-02", Authors: []author{{Family: "Doe", Given: "J."}}}, Annotation: annot{Summary: "s", Keywords: []string{"web"}}}
+	p := filepath.Join("data", "citations", "site", e.ID+".yaml")
+	_ = os.MkdirAll(filepath.Dir(p), 0o755)
+	b, _ := json.Marshal(e)
+	if err := os.WriteFile(p, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--format", "csl", "-o", "refs.json"})
+	if err := cmd.Execute(); err != nil || !strings.Contains(buf.String(), "wrote refs.json (1 entries)") {
+		t.Fatalf("csl export: %v %q", err, buf.String())
+	}
+	f, err := os.Open("refs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	recs, err := importer.ParseCSLJSON(f)
+	if err != nil || len(recs) != 1 {
+		t.Fatalf("reimport: %v %d", err, len(recs))
+	}
+	got := recs[0].Entry
+	if got.Type != "website" || got.APA7.Title != "A Site" || got.APA7.URL != "https://example.com" || got.APA7.Accessed != "2025-01-02" || len(got.APA7.Authors) != 1 || got.APA7.Authors[0].Family != "Doe" {
+		t.Fatalf("round trip lost data: %+v", got)
+	}
+	if len(recs[0].Unmapped) != 0 {
+		t.Fatalf("exported keys should all be understood by the importer: %v", recs[0].Unmapped)
+	}
+}
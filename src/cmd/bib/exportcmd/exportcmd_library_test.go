@@ -0,0 +1,162 @@
+package exportcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// libraryFixture is a data/library.bib with no YAML tree: an article created in 2023
+// (modified since), a book created in 2024, and a website with only a modified time.
+const libraryFixture = `@article{doe2023,
+  author = {Doe, Jane},
+  title = {Old Article},
+  journal = {Journal of Things},
+  year = {2023},
+  abstract = {s},
+  keywords = {k},
+  _id = {00000000-0000-4000-8000-0000000000c1},
+  _type = {article},
+  created = {2023-05-01T10:00:00Z},
+  modified = {2024-06-01T10:00:00Z},
+}
+
+@book{roe2024,
+  author = {Roe, Rick},
+  title = {New Book},
+  publisher = {Acme},
+  year = {2024},
+  abstract = {s},
+  keywords = {k},
+  _id = {00000000-0000-4000-8000-0000000000c2},
+  _type = {book},
+  created = {2024-03-01T10:00:00Z},
+  modified = {2024-03-01T10:00:00Z},
+}
+
+@misc{site,
+  title = {A Site},
+  howpublished = {Corp},
+  year = {2024},
+  url = {https://example.com},
+  urldate = {2024-02-01},
+  abstract = {s},
+  keywords = {k},
+  _id = {00000000-0000-4000-8000-0000000000c3},
+  _type = {website},
+  modified = {2024-02-01T10:00:00Z},
+}
+`
+
+// chdirLibrary switches to a temp dir holding only libraryFixture as data/library.bib.
+func chdirLibrary(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	if err := os.MkdirAll("data", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("data", "library.bib"), []byte(libraryFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runExport(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export %v: %v", args, err)
+	}
+	return buf.String()
+}
+
+func TestExportSince_LibraryTimestamps(t *testing.T) {
+	chdirLibrary(t)
+	prev := firstCommitTimes
+	t.Cleanup(func() { firstCommitTimes = prev })
+	firstCommitTimes = func([]string) (map[string]time.Time, error) {
+		t.Fatalf("library records carry their own timestamps; git should not be queried")
+		return nil, nil
+	}
+
+	if out := runExport(t, "--format", "apa"); strings.Count(out, "\n") != 3 || !strings.Contains(out, "Old Article") {
+		t.Fatalf("full export should list the whole library:\n%s", out)
+	}
+	out := runExport(t, "--since", "2024-01-01", "--format", "apa")
+	if !strings.Contains(out, "New Book") || !strings.Contains(out, "A Site") || strings.Contains(out, "Old Article") {
+		t.Fatalf("created (else modified) should decide --since:\n%s", out)
+	}
+	if out := runExport(t, "--since", "2024-02-15", "--format", "apa"); !strings.Contains(out, "New Book") || strings.Contains(out, "A Site") {
+		t.Fatalf("unexpected export after 2024-02-15:\n%s", out)
+	}
+}
+
+func TestExportTypeFilter_Library(t *testing.T) {
+	chdirLibrary(t)
+	out := runExport(t, "--type", "article,website", "--output", "out/refs.bib")
+	if !strings.Contains(out, "wrote out/refs.bib (2 entries)") {
+		t.Fatalf("unexpected output %q", out)
+	}
+	b, _ := os.ReadFile(filepath.Join("out", "refs.bib"))
+	if !strings.Contains(string(b), "Old Article") || !strings.Contains(string(b), "A Site") || strings.Contains(string(b), "New Book") {
+		t.Fatalf("filter not applied:\n%s", b)
+	}
+	if lib, _ := os.ReadFile(filepath.Join("data", "library.bib")); string(lib) != libraryFixture {
+		t.Fatalf("export must not rewrite the library")
+	}
+}
+
+func TestExportSplitByType_Library(t *testing.T) {
+	chdirLibrary(t)
+	runExport(t, "--split-by-type", "--format", "ris", "--out-dir", "refs")
+	idx, err := os.ReadFile(filepath.Join("refs", "index.txt"))
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	if string(idx) != "article.ris\t1\nbook.ris\t1\nwebsite.ris\t1\n" {
+		t.Fatalf("unexpected index: %q", idx)
+	}
+	if b, _ := os.ReadFile(filepath.Join("refs", "book.ris")); !strings.Contains(string(b), "TI  - New Book") {
+		t.Fatalf("unexpected book.ris:\n%s", b)
+	}
+}
+
+func TestExportTemplate_Library(t *testing.T) {
+	chdirLibrary(t)
+	if err := os.WriteFile("t.tmpl", []byte(`{{.Type}}: {{.APA7.Title}} ({{year .}})`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := runExport(t, "--format", "template", "--template", "t.tmpl")
+	want := "website: A Site (2024)\narticle: Old Article (2023)\nbook: New Book (2024)\n"
+	if out != want {
+		t.Fatalf("template output:\n got %q\nwant %q", out, want)
+	}
+}
+
+func TestExportFull_Library(t *testing.T) {
+	chdirLibrary(t)
+	if out := runExport(t, "--format", "ris"); strings.Count(out, "TY  - ") != 3 {
+		t.Fatalf("RIS export should hold the whole library:\n%s", out)
+	}
+	if out := runExport(t, "--format", "csl", "-o", "all.json"); !strings.Contains(out, "wrote all.json (3 entries)") {
+		t.Fatalf("unexpected output %q", out)
+	}
+	c := New()
+	c.SetOut(&bytes.Buffer{})
+	c.SilenceUsage, c.SilenceErrors = true, true
+	c.SetArgs([]string{"--type", "book", "-o", filepath.Join("data", "library.bib")})
+	if err := c.Execute(); err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Fatalf("a filtered export must not replace the library: %v", err)
+	}
+	if lib, _ := os.ReadFile(filepath.Join("data", "library.bib")); string(lib) != libraryFixture {
+		t.Fatalf("export must not rewrite the library")
+	}
+}
@@ -0,0 +1,47 @@
+package exportcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportRISFormat(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := entry{ID: "00000000-0000-4000-8000-000000000081", Type: "article", APA7: apa7{Title: "An Article", Authors: []author{{Family: "Doe", Given: "J."}}}, Annotation: annot{Summary: "s", Keywords: []string{"k"}}}
+	p := filepath.Join("data", "citations", "article", e.ID+".yaml")
+	_ = os.MkdirAll(filepath.Dir(p), 0o755)
+	b, _ := json.Marshal(e)
+	if err := os.WriteFile(p, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--format", "ris"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "TY  - JOUR\n") || !strings.Contains(buf.String(), "AU  - Doe, J.\n") {
+		t.Fatalf("unexpected RIS on stdout:\n%s", buf.String())
+	}
+
+	file := New()
+	buf.Reset()
+	file.SetOut(&buf)
+	file.SetArgs([]string{"--format", "ris", "-o", "refs.ris"})
+	if err := file.Execute(); err != nil || !strings.Contains(buf.String(), "wrote refs.ris (1 entries)") {
+		t.Fatalf("ris to file: %v %q", err, buf.String())
+	}
+	if _, err := os.Stat("refs.ris"); err != nil {
+		t.Fatalf("missing refs.ris: %v", err)
+	}
+}
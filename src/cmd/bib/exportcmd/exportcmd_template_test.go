@@ -0,0 +1,68 @@
+package exportcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestExportTemplate(t *testing.T) {
+	bundled, err := filepath.Abs(filepath.Join("..", "..", "..", "..", "docs", "templates"))
+	if err != nil {
+		t.Fatalf("abs: %v", err)
+	}
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	y := 2020
+	entries := []schema.Entry{
+		{ID: "b", Type: "book", APA7: schema.APA7{Title: "Zebra Book", Year: &y, Authors: schema.Authors{{Family: "Roe", Given: "Jane"}}}},
+		{ID: "a", Type: "article", APA7: schema.APA7{Title: "Alpha <Paper>", Authors: schema.Authors{{Family: "Doe", Given: "John"}, {Family: "Poe", Given: "Ann"}},
+			Identifiers: schema.Identifiers{DOI: "10.1/x"}}, Annotation: schema.Annotation{Keywords: []string{"k1", "k2"}}},
+	}
+	tmpl := filepath.Join(dir, "t.tmpl")
+	if err := os.WriteFile(tmpl, []byte(`{{.ID}}|{{authors .}}|{{apaAuthors .}}|{{year .}}|{{link .}}|{{join .Annotation.Keywords ";"}}`+"\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	tp, err := loadTemplate(tmpl)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	out, err := render(entries, renderOptions{format: "template", tmpl: tp})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "a|John Doe and Ann Poe|Doe, J., & Poe, A.|n.d.|https://doi.org/10.1/x|k1;k2\n" +
+		"b|Jane Roe|Roe, J.|2020||\n"
+	if string(out) != want {
+		t.Fatalf("template output:\n got %q\nwant %q", out, want)
+	}
+
+	for _, name := range []string{"markdown.tmpl", "html.tmpl", "mediawiki.tmpl"} {
+		tp, err := loadTemplate(filepath.Join(bundled, name))
+		if err != nil {
+			t.Fatalf("bundled %s: %v", name, err)
+		}
+		if _, err := render(entries, renderOptions{format: "template", tmpl: tp}); err != nil {
+			t.Fatalf("bundled %s: %v", name, err)
+		}
+	}
+	tp, _ = loadTemplate(filepath.Join(bundled, "html.tmpl"))
+	if out, _ := render(entries, renderOptions{format: "template", tmpl: tp}); !strings.Contains(string(out), "Alpha &lt;Paper&gt;") {
+		t.Fatalf("html template should escape titles: %s", out)
+	}
+
+	cmd := New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"--format", "template"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--template is required") {
+		t.Fatalf("expected missing --template error, got %v", err)
+	}
+}
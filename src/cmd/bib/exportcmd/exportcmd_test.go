@@ -1,10 +1,13 @@
 package exportcmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 type entry struct {
@@ -52,3 +55,125 @@ func TestExportBibWritesFile(t *testing.T) {
 		t.Fatalf("missing library.bib: %v", err)
 	}
 }
+
+func TestExportSinceFiltersByAddTime(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	write := func(name, id, title string) string {
+		path := filepath.Join("data", "citations", "site", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		e := entry{ID: id, Type: "website", APA7: apa7{Title: title, URL: "https://e", Accessed: "2025-01-01", Authors: []author{{Family: "Corp"}}}, Annotation: annot{Summary: "s", Keywords: []string{"k"}}}
+		b, _ := json.Marshal(e)
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return filepath.ToSlash(path)
+	}
+	oldPath := write("old.yaml", "00000000-0000-4000-8000-000000000001", "Old Page")
+	newPath := write("new.yaml", "00000000-0000-4000-8000-000000000002", "New Page")
+	// mtime fallback for the untracked file: make it old
+	stale := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	_ = os.Chtimes(filepath.FromSlash(oldPath), stale, stale)
+
+	prev := firstCommitTimes
+	t.Cleanup(func() { firstCommitTimes = prev })
+	firstCommitTimes = func(paths []string) (map[string]time.Time, error) {
+		return map[string]time.Time{newPath: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, nil
+	}
+
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--since", "2024-01-01", "--format", "apa"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "New Page") || strings.Contains(out, "Old Page") {
+		t.Fatalf("unexpected apa export: %q", out)
+	}
+
+	cmd2 := New()
+	cmd2.SetOut(&bytes.Buffer{})
+	cmd2.SetArgs([]string{"--since", "2024-01-01", "-o", "recent.bib"})
+	if err := cmd2.Execute(); err != nil {
+		t.Fatalf("execute bibtex: %v", err)
+	}
+	b, err := os.ReadFile("recent.bib")
+	if err != nil {
+		t.Fatalf("read recent.bib: %v", err)
+	}
+	if !strings.Contains(string(b), "New Page") || strings.Contains(string(b), "Old Page") {
+		t.Fatalf("unexpected bibtex export: %q", string(b))
+	}
+
+	cmd3 := New()
+	cmd3.SetArgs([]string{"--since", "2024-01-01", "--delete-yaml"})
+	if err := cmd3.Execute(); err == nil {
+		t.Fatalf("expected error combining --since with --delete-yaml")
+	}
+	cmd4 := New()
+	cmd4.SetArgs([]string{"--since", "Jan 2024"})
+	if err := cmd4.Execute(); err == nil {
+		t.Fatalf("expected error for invalid --since")
+	}
+}
+
+func TestExportSplitByType(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	write := func(sub, id, typ, title string) {
+		path := filepath.Join("data", "citations", sub, id+".yaml")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		e := entry{ID: id, Type: typ, APA7: apa7{Title: title, URL: "https://e", Accessed: "2025-01-01", Authors: []author{{Family: "Corp"}}}, Annotation: annot{Summary: "s", Keywords: []string{"k"}}}
+		b, _ := json.Marshal(e)
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write("articles", "00000000-0000-4000-8000-000000000001", "article", "Zeta Article")
+	write("articles", "00000000-0000-4000-8000-000000000002", "article", "Alpha Article")
+	write("books", "00000000-0000-4000-8000-000000000003", "book", "A Book")
+
+	cmd := New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--split-by-type", "--out-dir", "refs"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	idx, err := os.ReadFile(filepath.Join("refs", "index.txt"))
+	if err != nil {
+		t.Fatalf("read index: %v", err)
+	}
+	if string(idx) != "article.bib\t2\nbook.bib\t1\n" {
+		t.Fatalf("unexpected index: %q", string(idx))
+	}
+	art, _ := os.ReadFile(filepath.Join("refs", "article.bib"))
+	a, z := strings.Index(string(art), "Alpha Article"), strings.Index(string(art), "Zeta Article")
+	if a < 0 || z < 0 || a > z || strings.Contains(string(art), "A Book") {
+		t.Fatalf("unexpected article.bib: %s", string(art))
+	}
+	if _, err := os.Stat(filepath.Join("data", "library.bib")); err == nil {
+		t.Fatalf("split export should not write the library")
+	}
+
+	apa := New()
+	apa.SetOut(&bytes.Buffer{})
+	apa.SetArgs([]string{"--split-by-type", "--format", "apa", "--out-dir", "apa"})
+	if err := apa.Execute(); err != nil {
+		t.Fatalf("execute apa: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("apa", "book.txt")); err != nil {
+		t.Fatalf("missing book.txt: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package exportcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportTypeFilterAndOutput(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	for _, e := range []entry{
+		{ID: "00000000-0000-4000-8000-000000000061", Type: "article", APA7: apa7{Title: "An Article"}, Annotation: annot{Summary: "s", Keywords: []string{"k"}}},
+		{ID: "00000000-0000-4000-8000-000000000062", Type: "book", APA7: apa7{Title: "A Book"}, Annotation: annot{Summary: "s", Keywords: []string{"k"}}},
+		{ID: "00000000-0000-4000-8000-000000000063", Type: "website", APA7: apa7{Title: "A Site", URL: "https://e", Accessed: "2025-01-01"}, Annotation: annot{Summary: "s", Keywords: []string{"k"}}},
+	} {
+		p := filepath.Join("data", "citations", e.Type, e.ID+".yaml")
+		_ = os.MkdirAll(filepath.Dir(p), 0o755)
+		b, _ := json.Marshal(e)
+		if err := os.WriteFile(p, b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--type", "article, book", "--output", "out/refs.bib"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(buf.String(), "wrote out/refs.bib (2 entries)") {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+	b, _ := os.ReadFile(filepath.Join("out", "refs.bib"))
+	if !strings.Contains(string(b), "An Article") || !strings.Contains(string(b), "A Book") || strings.Contains(string(b), "A Site") {
+		t.Fatalf("filter not applied:\n%s", b)
+	}
+	if _, err := os.Stat(filepath.Join("data", "library.bib")); !os.IsNotExist(err) {
+		t.Fatalf("filtered export must not write the library: %v", err)
+	}
+
+	// Unfiltered full export reports its count.
+	full := New()
+	buf.Reset()
+	full.SetOut(&buf)
+	full.SetArgs([]string{"--output", "all.bib"})
+	if err := full.Execute(); err != nil || !strings.Contains(buf.String(), "wrote all.bib (3 entries)") {
+		t.Fatalf("full export: %v %q", err, buf.String())
+	}
+
+	// A file in the way of the output directory is reported clearly.
+	if err := os.WriteFile("blocked", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for args, want := range map[string]string{
+		"--type novel":                       "invalid --type",
+		"--output blocked/refs.bib":          "cannot create output directory",
+		"--type book --output blocked/x.bib": "cannot create output directory",
+		"--type book --delete-yaml -o y.bib": "no --since or --type",
+	} {
+		c := New()
+		c.SetOut(&bytes.Buffer{})
+		c.SilenceUsage, c.SilenceErrors = true, true
+		c.SetArgs(strings.Fields(args))
+		if err := c.Execute(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: want error containing %q, got %v", args, want, err)
+		}
+	}
+}
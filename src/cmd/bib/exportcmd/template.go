@@ -0,0 +1,84 @@
+package exportcmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"bibliography/src/cmd/bib/citecmd"
+	"bibliography/src/internal/schema"
+)
+
+// templateFuncs are the helpers available to --format template, in addition to the
+// text/template builtins (html, js, urlquery, printf, ...). Each entry is passed as
+// the template's dot, so fields are reached as .ID, .Type, .APA7.Title, .APA7.DOI,
+// .Annotation.Summary, .Annotation.Keywords, and so on.
+var templateFuncs = template.FuncMap{
+	// authors renders names in natural order: "Jane Doe, John Roe and Ann Poe".
+	"authors": func(e schema.Entry) string { return naturalAuthors(e.APA7.Authors) },
+	// apaAuthors renders the APA reference-list form: "Doe, J., & Roe, J.".
+	"apaAuthors": citecmd.APAAuthors,
+	// year returns the cited year or "n.d.".
+	"year": func(e schema.Entry) string {
+		if y := citecmd.APAYear(e); y != "" {
+			return y
+		}
+		return "n.d."
+	},
+	"apa":    citecmd.APACitation,
+	"intext": citecmd.InTextCitation,
+	// link returns the DOI URL when present, otherwise the URL.
+	"link": func(e schema.Entry) string {
+		if d := strings.TrimSpace(e.APA7.DOI); d != "" {
+			return "https://doi.org/" + d
+		}
+		return strings.TrimSpace(e.APA7.URL)
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// loadTemplate parses the template file at path with templateFuncs.
+func loadTemplate(path string) (*template.Template, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("--template is required with --format template")
+	}
+	t, err := template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return t, nil
+}
+
+// renderTemplate executes t once per entry and concatenates the results.
+func renderTemplate(entries []schema.Entry, t *template.Template) ([]byte, error) {
+	var b bytes.Buffer
+	for _, e := range entries {
+		if err := t.Execute(&b, e); err != nil {
+			return nil, fmt.Errorf("template: entry %s: %w", e.ID, err)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func naturalAuthors(authors schema.Authors) string {
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		n := strings.TrimSpace(strings.TrimSpace(a.Given) + " " + strings.TrimSpace(a.Family))
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	default:
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	}
+}
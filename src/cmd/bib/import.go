@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/importcmd"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(importcmd.New(optionalCommit(&noCommit)), &noCommit)
+}
+
+func newImportBibCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(importcmd.NewBib(optionalCommit(&noCommit)), &noCommit)
+}
+
+func newImportRISCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(importcmd.NewRIS(optionalCommit(&noCommit)), &noCommit)
+}
+
+func newImportZoteroCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(importcmd.NewZotero(optionalCommit(&noCommit)), &noCommit)
+}
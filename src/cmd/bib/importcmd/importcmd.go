@@ -0,0 +1,168 @@
+package importcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/importer"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// parsers maps --format values to their importer.
+var parsers = map[string]func(io.Reader) ([]importer.Record, error){
+	"bibtex":  importer.ParseBibTeX,
+	"ris":     importer.ParseRIS,
+	"csljson": importer.ParseCSLJSON,
+}
+
+// New returns the import command for RIS, CSL-JSON, and BibTeX files.
+func New(commit CommitFunc) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import references from a RIS, CSL-JSON, or BibTeX export",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parse, ok := parsers[strings.ToLower(strings.TrimSpace(format))]
+			if !ok {
+				return fmt.Errorf("unsupported --format %q (want ris, csljson, or bibtex)", format)
+			}
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			recs, err := parse(f)
+			if err != nil {
+				return err
+			}
+			store.SetWriteSource("import:" + strings.ToLower(format))
+			return importRecords(cmd, commit, recs)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "bibtex", "Input format: ris, csljson, or bibtex")
+	return cmd
+}
+
+// NewBib returns the import-bib command: import --format bibtex with the file given
+// by --file. Records without an _id get new ids; each is validated before it is written.
+func NewBib(commit CommitFunc) *cobra.Command {
+	return newFileImport(commit, "bibtex", "import-bib --file <refs.bib>", "Import references from an external BibTeX file")
+}
+
+// NewRIS returns the import-ris command: import --format ris with the file given by
+// --file, for RIS exports such as those written by bib export-bib --format ris.
+func NewRIS(commit CommitFunc) *cobra.Command {
+	return newFileImport(commit, "ris", "import-ris --file <refs.ris>", "Import references from an external RIS file")
+}
+
+// newFileImport builds a single-format import command that reads --file.
+func newFileImport(commit CommitFunc, format, use, short string) *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			recs, err := parsers[format](f)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			store.SetWriteSource("import:" + format)
+			return importRecords(cmd, commit, recs)
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "File to import")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+// importRecords dedupes records by id, DOI, and ISBN against the library and each
+// other (an imported _id that is already in the library would otherwise replace that
+// record), writes the rest, reports unmapped fields per record, and commits once.
+func importRecords(cmd *cobra.Command, commit CommitFunc, recs []importer.Record) error {
+	existing, err := store.ReadAll()
+	if err != nil {
+		return err
+	}
+	seen := map[string]string{}
+	for _, e := range existing {
+		for _, k := range dedupeKeys(e) {
+			seen[k] = e.ID
+		}
+	}
+	out := cmd.OutOrStdout()
+	added, dupes, invalid := 0, 0, 0
+	for i, rec := range recs {
+		e := rec.Entry
+		label := fmt.Sprintf("record %d (%s)", i+1, strings.TrimSpace(e.APA7.Title))
+		if len(rec.Unmapped) > 0 {
+			if _, err := fmt.Fprintf(out, "%s: unmapped fields: %s\n", label, strings.Join(rec.Unmapped, ", ")); err != nil {
+				return err
+			}
+		}
+		if of, ok := duplicateOf(seen, e); ok {
+			dupes++
+			if _, err := fmt.Fprintf(out, "%s: skipped duplicate of %s\n", label, of); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := store.WriteEntry(e); err != nil {
+			invalid++
+			if _, perr := fmt.Fprintf(out, "%s: skipped: %v\n", label, err); perr != nil {
+				return perr
+			}
+			continue
+		}
+		for _, k := range dedupeKeys(e) {
+			seen[k] = e.ID
+		}
+		added++
+	}
+	if added > 0 {
+		if err := commit([]string{store.BibFile}, fmt.Sprintf("import: add %d citations", added)); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(out, "imported %d, skipped %d duplicates, %d invalid\n", added, dupes, invalid)
+	return err
+}
+
+// dedupeKeys returns normalized id/DOI/ISBN identity keys for an entry.
+func dedupeKeys(e schema.Entry) []string {
+	var keys []string
+	if id := strings.ToLower(strings.TrimSpace(e.ID)); id != "" {
+		keys = append(keys, "id:"+id)
+	}
+	if d := store.NormalizeDOI(e.APA7.DOI); d != "" {
+		keys = append(keys, "doi:"+d)
+	}
+	if isbn := store.NormalizeISBN(e.APA7.ISBN); isbn != "" {
+		keys = append(keys, "isbn:"+isbn)
+	}
+	return keys
+}
+
+// duplicateOf returns the id of the library (or earlier imported) entry e duplicates.
+func duplicateOf(seen map[string]string, e schema.Entry) (string, bool) {
+	for _, k := range dedupeKeys(e) {
+		if id, ok := seen[k]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
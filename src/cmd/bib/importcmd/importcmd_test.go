@@ -0,0 +1,195 @@
+package importcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestImportRIS_DedupesAndCommitsOnce(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	have := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Existing", Identifiers: schema.Identifiers{DOI: "10.1000/EXISTS"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := store.WriteEntry(have); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	ris := "TY  - JOUR\nTI  - Dup of existing\nDO  - 10.1000/exists\nER  - \n" +
+		"TY  - JOUR\nTI  - Fresh\nDO  - 10.1000/fresh\nC1  - custom\nER  - \n" +
+		"TY  - JOUR\nTI  - Fresh again\nDO  - 10.1000/FRESH\nER  - \n"
+	if err := os.WriteFile("in.ris", []byte(ris), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	commits := 0
+	cmd := New(func(paths []string, msg string) error { commits++; return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"in.ris", "--format", "ris"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"record 1 (Dup of existing): skipped duplicate", "record 2 (Fresh): unmapped fields: C1", "record 3 (Fresh again): skipped duplicate", "imported 1, skipped 2 duplicates"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in output:\n%s", want, out)
+		}
+	}
+	if commits != 1 {
+		t.Fatalf("expected one commit, got %d", commits)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+
+	bad := New(func([]string, string) error { return nil })
+	bad.SetOut(&bytes.Buffer{})
+	bad.SetArgs([]string{"in.ris", "--format", "endnote"})
+	if err := bad.Execute(); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
+
+func TestImportBib_WritesValidRecordsAndReportsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	keep := "00000000-0000-4000-8000-0000000000f1"
+	bib := "@string{mit = {MIT Press}}\n" +
+		"@book{sicp,\n  title = {Structure and Interpretation of Computer Programs},\n  author = {Abelson, Harold and Sussman, Gerald Jay},\n  publisher = mit,\n  year = {1985},\n  _id = {" + keep + "},\n}\n" +
+		"@article{doe2020,\n  title = {Graph Methods},\n  author = {Doe, Jane},\n  journal = {Journal of Graphs},\n  year = {2020},\n  doi = {10.1000/graph},\n}\n" +
+		"@misc{broken,\n  author = {Nobody, N.},\n}\n"
+	if err := os.WriteFile("refs.bib", []byte(bib), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var committed []string
+	cmd := NewBib(func(paths []string, msg string) error { committed = append(committed, msg); return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--file", "refs.bib"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "record 3 (): skipped:") || !strings.Contains(out, "imported 2, skipped 0 duplicates, 1 invalid") {
+		t.Fatalf("unexpected report:\n%s", out)
+	}
+	if len(committed) != 1 || committed[0] != "import: add 2 citations" {
+		t.Fatalf("commits: %v", committed)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	byTitle := map[string]schema.Entry{}
+	for _, e := range list {
+		byTitle[e.APA7.Title] = e
+	}
+	sicp := byTitle["Structure and Interpretation of Computer Programs"]
+	if sicp.ID != keep || sicp.Type != "book" || sicp.APA7.Publisher != "MIT Press" || len(sicp.APA7.Authors) != 2 {
+		t.Fatalf("book not imported as written: %+v", sicp)
+	}
+	art := byTitle["Graph Methods"]
+	if art.ID == "" || art.ID == keep || art.APA7.Journal != "Journal of Graphs" {
+		t.Fatalf("article should get a new id: %+v", art)
+	}
+	if err := art.Validate(); err != nil {
+		t.Fatalf("imported entry invalid: %v", err)
+	}
+
+	missing := NewBib(func([]string, string) error { return nil })
+	missing.SetOut(&bytes.Buffer{})
+	missing.SetErr(&bytes.Buffer{})
+	missing.SetArgs(nil)
+	if err := missing.Execute(); err == nil {
+		t.Fatal("expected error without --file")
+	}
+}
+
+func TestImportBib_SkipsExistingID(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	have := schema.Entry{ID: "00000000-0000-4000-8000-0000000000f2", Type: "book", APA7: schema.APA7{Title: "Original", Publisher: "Pub"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := store.WriteEntry(have); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	bib := "@book{other,\n  title = {Replacement},\n  author = {Roe, R.},\n  publisher = {Other},\n  year = {2001},\n  _id = {" + strings.ToUpper(have.ID) + "},\n}\n"
+	if err := os.WriteFile("refs.bib", []byte(bib), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewBib(func([]string, string) error { return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--file", "refs.bib"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "record 1 (Replacement): skipped duplicate of "+have.ID) || !strings.Contains(out, "imported 0, skipped 1 duplicates") {
+		t.Fatalf("unexpected report:\n%s", out)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 1 || list[0].APA7.Title != "Original" {
+		t.Fatalf("existing record changed: %v %+v", err, list)
+	}
+}
+
+func TestImportRIS_ArticleAndBook(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	ris := "TY  - JOUR\nTI  - Graph Methods\nAU  - Doe, Jane\nAU  - Smith, John Q\nPY  - 2021\nJF  - Journal of Graphs\n" +
+		"SP  - 10\nEP  - 20\nDO  - 10.1000/graph\nUR  - https://example.com/graph\nAB  - The first line of the abstract\n" +
+		"  continues here.\nKW  - graphs\nKW  - networks\nER  - \n\n" +
+		"TY  - BOOK\nTI  - A Book\nAU  - Roe, R.\nPY  - 1999\nPB  - Pub\nER  - \n"
+	if err := os.WriteFile("refs.ris", []byte(ris), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewRIS(func([]string, string) error { return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--file", "refs.ris"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(buf.String(), "imported 2, skipped 0 duplicates, 0 invalid") {
+		t.Fatalf("unexpected report:\n%s", buf.String())
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	byType := map[string]schema.Entry{}
+	for _, e := range list {
+		byType[e.Type] = e
+	}
+	art := byType["article"]
+	if art.APA7.Title != "Graph Methods" || len(art.APA7.Authors) != 2 || art.APA7.Journal != "Journal of Graphs" ||
+		art.APA7.Pages != "10-20" || art.APA7.DOI != "10.1000/graph" || art.APA7.URL != "https://example.com/graph" ||
+		art.APA7.Year == nil || *art.APA7.Year != 2021 {
+		t.Fatalf("article fields: %+v", art.APA7)
+	}
+	if art.Annotation.Summary != "The first line of the abstract continues here." {
+		t.Fatalf("multi-line AB: %q", art.Annotation.Summary)
+	}
+	if strings.Join(art.Annotation.Keywords, ",") != "graphs,networks" {
+		t.Fatalf("keywords: %v", art.Annotation.Keywords)
+	}
+	book := byType["book"]
+	if book.APA7.Title != "A Book" || book.APA7.Publisher != "Pub" || len(book.APA7.Authors) != 1 || book.APA7.Authors[0].Family != "Roe" {
+		t.Fatalf("book fields: %+v", book.APA7)
+	}
+}
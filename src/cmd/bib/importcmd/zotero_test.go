@@ -0,0 +1,77 @@
+package importcmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/store"
+	"bibliography/src/internal/zotero"
+)
+
+type zoteroPages map[string]struct{ body, link string }
+
+func (z zoteroPages) Do(req *http.Request) (*http.Response, error) {
+	p, ok := z[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+	}
+	h := make(http.Header)
+	h.Set("Total-Results", "3")
+	if p.link != "" {
+		h.Set("Link", p.link)
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(p.body)), Header: h}, nil
+}
+
+func TestImportZotero_PagesAndImports(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	first := zotero.ItemsURL("42", "")
+	second := first + "&start=100"
+	zotero.SetHTTPClient(zoteroPages{
+		first: {`{"items":[
+			{"id":"42/A","type":"article-journal","title":"Graph Methods","author":[{"family":"Doe","given":"Jane"}],
+			 "container-title":"Journal of Graphs","issued":{"date-parts":[[2021,3]]},"DOI":"10.1000/graph"},
+			{"id":"42/N","type":"note","title":"A note"}]}`, `<` + second + `>; rel="next"`},
+		second: {`{"items":[{"id":"42/B","type":"book","title":"A Book","author":[{"family":"Roe","given":"R."}],
+			"publisher":"Pub","issued":{"date-parts":[[1999]]}}]}`, ""},
+	})
+	defer zotero.SetHTTPClient(&http.Client{})
+
+	commits := 0
+	cmd := NewZotero(func([]string, string) error { commits++; return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--user", "42", "--key", "k"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`item 2: skipped: unsupported CSL type "note"`, "imported 2, skipped 0 duplicates, 0 invalid"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in output:\n%s", want, out)
+		}
+	}
+	if commits != 1 {
+		t.Fatalf("expected one commit, got %d", commits)
+	}
+	list, err := store.ReadAll()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	for _, e := range list {
+		if e.Type == "article" && (e.APA7.Journal != "Journal of Graphs" || e.APA7.DOI != "10.1000/graph") {
+			t.Fatalf("article mapping: %+v", e.APA7)
+		}
+		if e.Type == "book" && e.APA7.Publisher != "Pub" {
+			t.Fatalf("book mapping: %+v", e.APA7)
+		}
+	}
+}
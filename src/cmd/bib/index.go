@@ -6,4 +6,7 @@ import (
 )
 
 // newIndexCmd creates the "index" command to rebuild metadata indexes.
-func newIndexCmd() *cobra.Command { return indexcmd.New(commitAndPush) }
+func newIndexCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(indexcmd.New(optionalCommit(&noCommit)), &noCommit)
+}
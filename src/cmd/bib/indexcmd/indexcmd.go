@@ -1,7 +1,6 @@
 package indexcmd
 
 import (
-	"bibliography/src/internal/schema"
 	"bibliography/src/internal/store"
 	"fmt"
 	"github.com/spf13/cobra"
@@ -12,11 +11,28 @@ type CommitFunc func(paths []string, message string) error
 
 // New returns the index command which rebuilds metadata indexes.
 func New(commit CommitFunc) *cobra.Command {
+	var flatView, check bool
+	var flatMode, only string
 	cmd := &cobra.Command{
 		Use:          "index",
-		Short:        "Rebuild metadata indexes (keywords, authors, titles, ISBN, DOI)",
+		Short:        "Rebuild metadata indexes (keywords, authors, titles, ISBN, DOI, decades, languages, full text)",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if flatView && !store.IsValidFlatMode(flatMode) {
+				return fmt.Errorf("invalid --flat-mode %q (want %s or %s)", flatMode, store.FlatSymlink, store.FlatCopy)
+			}
+			var names []string
+			for _, n := range strings.Split(only, ",") {
+				if n = strings.TrimSpace(n); n != "" {
+					names = append(names, n)
+				}
+			}
+			if check {
+				if flatView {
+					return fmt.Errorf("--check cannot be combined with --flat-view")
+				}
+				return checkIndexes(cmd, names)
+			}
 			// Ensure consolidated BibTeX library is present and up-to-date with current entries
 			// For legacy repos with only YAML, this creates data/library.bib once.
 			_ = store.RebuildBibLibrary()
@@ -24,24 +40,32 @@ func New(commit CommitFunc) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			builders := []func([]schema.Entry) (string, error){
-				store.BuildKeywordIndex,
-				store.BuildAuthorIndex,
-				store.BuildTitleIndex,
-				store.BuildISBNIndex,
-				store.BuildDOIIndex,
+			paths, err := store.BuildIndexes(entries, names)
+			if err != nil {
+				return err
 			}
-			for _, b := range builders {
-				p, err := b(entries)
+			for _, p := range paths {
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\n", p); err != nil {
+					return err
+				}
+			}
+			commitPaths := []string{store.MetadataDir}
+			if flatView {
+				res, err := store.BuildFlatView(entries, flatMode)
 				if err != nil {
 					return err
 				}
-				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "wrote %s\n", p); err != nil {
+				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "wrote %s (%d linked, %d copied, %d removed)\n", store.FlatDir, res.Linked, res.Copied, res.Removed); err != nil {
 					return err
 				}
+				commitPaths = append(commitPaths, store.FlatDir)
 			}
 			// Stage full metadata dir for atomic updates (captures new/removed files).
-			if err := commit([]string{store.MetadataDir}, "index: rebuild metadata"); err != nil {
+			msg := "index: rebuild metadata"
+			if len(names) > 0 {
+				msg = "index: rebuild " + strings.Join(names, ", ")
+			}
+			if err := commit(commitPaths, msg); err != nil {
 				em := err.Error()
 				if strings.Contains(em, "not a git repository") {
 					const warning = "warning: skipping git commit (not a git repository)"
@@ -55,5 +79,32 @@ func New(commit CommitFunc) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&only, "only", "", "Rebuild only these indexes (comma-separated: "+strings.Join(store.IndexNames(), ",")+")")
+	cmd.Flags().BoolVar(&check, "check", false, "Verify the on-disk indexes match a fresh rebuild without writing; exit non-zero on drift")
+	cmd.Flags().BoolVar(&flatView, "flat-view", false, "Also (re)create data/flat/<id>.yaml for every entry, removing files for deleted entries")
+	cmd.Flags().StringVar(&flatMode, "flat-mode", store.FlatSymlink, "How --flat-view files are made: symlink (falls back to copy where unsupported) or copy")
 	return cmd
 }
+
+// checkIndexes reports indexes that differ from a fresh rebuild of the library; it
+// writes nothing and fails when any index is missing or stale.
+func checkIndexes(cmd *cobra.Command, names []string) error {
+	entries, err := store.ReadAll()
+	if err != nil {
+		return err
+	}
+	drifted, err := store.CheckIndexes(entries, names)
+	if err != nil {
+		return err
+	}
+	for _, p := range drifted {
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "out of date: %s\n", p); err != nil {
+			return err
+		}
+	}
+	if len(drifted) > 0 {
+		return fmt.Errorf("%d index(es) out of date (run bib index)", len(drifted))
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), "indexes up to date")
+	return err
+}
@@ -0,0 +1,102 @@
+package indexcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func runIndex(t *testing.T, commit CommitFunc, args ...string) (string, error) {
+	t.Helper()
+	cmd := New(commit)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SilenceErrors = true
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestIndexCommand_Only(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "B", Identifiers: schema.Identifiers{DOI: "10.1/b"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	var msg string
+	out, err := runIndex(t, func(_ []string, m string) error { msg = m; return nil }, "--only", "doi, keywords")
+	if err != nil {
+		t.Fatalf("index --only: %v", err)
+	}
+	if out != "wrote "+store.KeywordsJSON+"\nwrote "+store.DOIJSON+"\n" || msg != "index: rebuild doi, keywords" {
+		t.Fatalf("out=%q msg=%q", out, msg)
+	}
+	if _, err := os.Stat(store.AuthorsJSON); !os.IsNotExist(err) {
+		t.Fatalf("authors index should not be written: %v", err)
+	}
+	if _, err := runIndex(t, func([]string, string) error { return nil }, "--only", "keywords,nope"); err == nil || !strings.Contains(err.Error(), `unknown index "nope"`) {
+		t.Fatalf("expected unknown index error, got %v", err)
+	}
+}
+
+func TestIndexCommand_CheckDetectsDrift(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	noCommit := func([]string, string) error { t.Fatalf("--check should not commit"); return nil }
+	e1 := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "First"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"alpha"}}}
+	if _, err := store.WriteEntry(e1); err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing built yet: every index is missing
+	out, err := runIndex(t, noCommit, "--check")
+	if err == nil || !strings.Contains(err.Error(), "8 index(es) out of date") {
+		t.Fatalf("missing indexes should be drift: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(store.MetadataDir); !os.IsNotExist(err) {
+		t.Fatalf("--check must not write: %v", err)
+	}
+
+	if _, err := runIndex(t, func([]string, string) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runIndex(t, noCommit, "--check")
+	if err != nil || !strings.Contains(out, "indexes up to date") {
+		t.Fatalf("fresh indexes should pass: %v\n%s", err, out)
+	}
+
+	// a new entry leaves the keyword index (among others) stale
+	e2 := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Second"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"beta"}}}
+	if _, err := store.WriteEntry(e2); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(store.KeywordsJSON)
+	out, err = runIndex(t, noCommit, "--check", "--only", "keywords,doi")
+	if err == nil || !strings.Contains(err.Error(), "1 index(es) out of date") || !strings.Contains(out, "out of date: "+store.KeywordsJSON) {
+		t.Fatalf("keyword drift not reported: %v\n%s", err, out)
+	}
+	if after, _ := os.ReadFile(store.KeywordsJSON); !bytes.Equal(before, after) {
+		t.Fatalf("--check must not rewrite the index")
+	}
+
+	// hand edits count as drift too
+	if err := os.WriteFile(store.DOIJSON, []byte(`{"x": "10.1/x"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := runIndex(t, noCommit, "--check", "--only", "doi"); !strings.Contains(out, "out of date: "+store.DOIJSON) {
+		t.Fatalf("edited doi index not reported: %s", out)
+	}
+	if _, err := runIndex(t, noCommit, "--check", "--flat-view"); err == nil {
+		t.Fatalf("--check with --flat-view should error")
+	}
+}
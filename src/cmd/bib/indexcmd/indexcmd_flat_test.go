@@ -0,0 +1,79 @@
+package indexcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestIndexCommand_FlatView(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	// one legacy YAML entry (segmented source) and one library-only entry
+	legacy := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Legacy"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	src := filepath.Join(store.CitationsDir, "books", legacy.ID+".yaml")
+	if err := os.MkdirAll(filepath.Dir(src), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(legacy)
+	if err := os.WriteFile(src, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RebuildBibLibrary(); err != nil {
+		t.Fatal(err)
+	}
+	bibOnly := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Bib only"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := store.WriteEntry(bibOnly); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(store.FlatDir, "deleted.yaml")
+	if err := os.MkdirAll(store.FlatDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotPaths []string
+	cmd := New(func(paths []string, msg string) error { gotPaths = paths; return nil })
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--flat-view"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	if len(gotPaths) != 2 || gotPaths[1] != store.FlatDir {
+		t.Fatalf("flat dir should be committed: %v", gotPaths)
+	}
+	link := filepath.Join(store.FlatDir, legacy.ID+".yaml")
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected symlink for legacy entry: %v", err)
+	}
+	if got, err := os.ReadFile(link); err != nil || string(got) != string(b) {
+		t.Fatalf("symlink should resolve to the segmented file: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(store.FlatDir, bibOnly.ID+".yaml")); err != nil || !strings.Contains(string(got), "  title: \"Bib only\"\n") {
+		t.Fatalf("library-only entry should be written out as YAML: %v\n%s", err, got)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("stale flat file should be removed")
+	}
+
+	copyCmd := New(func([]string, string) error { return nil })
+	copyCmd.SetOut(&bytes.Buffer{})
+	copyCmd.SetArgs([]string{"--flat-view", "--flat-mode", "copy"})
+	if err := copyCmd.Execute(); err != nil {
+		t.Fatalf("index copy: %v", err)
+	}
+	if fi, err := os.Lstat(link); err != nil || !fi.Mode().IsRegular() {
+		t.Fatalf("copy mode should write regular files: %v", err)
+	}
+}
@@ -3,11 +3,16 @@ package indexcmd
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 )
 
 func TestIndexCommand_WarnsWhenNotGitRepo(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
 	cmd := New(func(paths []string, msg string) error { return fmt.Errorf("not a git repository") })
 	var stderr bytes.Buffer
 	cmd.SetErr(&stderr)
@@ -0,0 +1,8 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/keywordscmd"
+	"github.com/spf13/cobra"
+)
+
+func newKeywordsCmd() *cobra.Command { return keywordscmd.New() }
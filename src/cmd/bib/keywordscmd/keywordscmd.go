@@ -0,0 +1,107 @@
+package keywordscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// KeywordCount is one keyword and the number of entries tagged with it.
+type KeywordCount struct {
+	Keyword string `json:"keyword"`
+	Entries int    `json:"entries"`
+}
+
+// New returns the keywords command which ranks annotation keywords by how many
+// entries use them.
+func New() *cobra.Command {
+	var minEntries int
+	var unused, asJSON bool
+	cmd := &cobra.Command{
+		Use:   "keywords",
+		Short: "Rank keywords by number of entries (--unused lists single-use tags)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			counts := Filter(KeywordReport(entries), minEntries, unused)
+			if asJSON {
+				if counts == nil {
+					counts = []KeywordCount{}
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(counts)
+			}
+			return writeTable(cmd, counts)
+		},
+	}
+	cmd.Flags().IntVar(&minEntries, "min", 0, "Hide keywords used by fewer than N entries")
+	cmd.Flags().BoolVar(&unused, "unused", false, "List only keywords used by exactly one entry (candidates for consolidation)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
+
+// KeywordReport counts, for each annotation keyword (lowercased and trimmed, as in
+// the keywords index), the entries that carry it, and ranks keywords by that count,
+// then alphabetically. A keyword repeated on one entry counts once.
+func KeywordReport(entries []schema.Entry) []KeywordCount {
+	counts := map[string]int{}
+	for _, e := range entries {
+		seen := map[string]bool{}
+		for _, k := range e.Annotation.Keywords {
+			k = strings.ToLower(strings.TrimSpace(k))
+			if k == "" || seen[k] {
+				continue
+			}
+			seen[k] = true
+			counts[k]++
+		}
+	}
+	out := make([]KeywordCount, 0, len(counts))
+	for k, n := range counts {
+		out = append(out, KeywordCount{Keyword: k, Entries: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Entries != out[j].Entries {
+			return out[i].Entries > out[j].Entries
+		}
+		return out[i].Keyword < out[j].Keyword
+	})
+	return out
+}
+
+// Filter drops keywords used by fewer than minEntries entries and, when unused is
+// set, keeps only those used by exactly one entry.
+func Filter(counts []KeywordCount, minEntries int, unused bool) []KeywordCount {
+	var out []KeywordCount
+	for _, c := range counts {
+		if c.Entries < minEntries || (unused && c.Entries != 1) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func writeTable(cmd *cobra.Command, counts []KeywordCount) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "RANK\tKEYWORD\tENTRIES"); err != nil {
+		return err
+	}
+	for i, c := range counts {
+		if _, err := fmt.Fprintf(tw, "%d\t%s\t%d\n", i+1, c.Keyword, c.Entries); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
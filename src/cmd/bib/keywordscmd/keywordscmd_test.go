@@ -0,0 +1,80 @@
+package keywordscmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func sampleEntries() []schema.Entry {
+	entry := func(title string, keywords ...string) schema.Entry {
+		return schema.Entry{Type: "book", APA7: schema.APA7{Title: title}, Annotation: schema.Annotation{Summary: "s", Keywords: keywords}}
+	}
+	return []schema.Entry{
+		entry("One", "go", "testing", "Go"),
+		entry("Two", "go", "concurrency"),
+		entry("Three", " GO ", "testing", "parsers"),
+	}
+}
+
+func TestKeywordReport_CountsAndFilters(t *testing.T) {
+	got := KeywordReport(sampleEntries())
+	want := []KeywordCount{{"go", 3}, {"testing", 2}, {"concurrency", 1}, {"parsers", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("row %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if f := Filter(got, 2, false); len(f) != 2 || f[1].Keyword != "testing" {
+		t.Fatalf("--min 2: %+v", f)
+	}
+	if f := Filter(got, 0, true); len(f) != 2 || f[0].Keyword != "concurrency" || f[1].Keyword != "parsers" {
+		t.Fatalf("--unused: %+v", f)
+	}
+}
+
+func TestKeywordsCommand_TableAndJSON(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	for _, e := range sampleEntries() {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	run := func(args ...string) string {
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("keywords %v: %v", args, err)
+		}
+		return buf.String()
+	}
+	out := run("--min", "2")
+	if !strings.Contains(out, "KEYWORD") || !strings.Contains(out, "go") || !strings.Contains(out, "testing") || strings.Contains(out, "parsers") {
+		t.Fatalf("unexpected table: %q", out)
+	}
+
+	var counts []KeywordCount
+	if err := json.Unmarshal([]byte(run("--unused", "--json")), &counts); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(counts) != 2 || counts[0] != (KeywordCount{"concurrency", 1}) || counts[1] != (KeywordCount{"parsers", 1}) {
+		t.Fatalf("unexpected json: %+v", counts)
+	}
+	if got := strings.TrimSpace(run("--min", "5", "--json")); got != "[]" {
+		t.Fatalf("empty report should be [], got %q", got)
+	}
+}
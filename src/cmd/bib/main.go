@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/spf13/cobra"
 )
@@ -14,6 +16,8 @@ var rootCmd = &cobra.Command{
 
 // execute attaches subcommands to the root and runs the CLI.
 func execute() error {
+	// Run persistent hooks from every level (e.g., root --as-of and add --normalize-unicode)
+	cobra.EnableTraverseRunHooks = true
 	// Attach subcommands
 	rootCmd.AddCommand(newAddCmd())
 	rootCmd.AddCommand(newSearchCmd())
@@ -21,11 +25,31 @@ func execute() error {
 	rootCmd.AddCommand(newIndexCmd())
 	rootCmd.AddCommand(newRepairDOICmd())
 	rootCmd.AddCommand(newSummarizeCmd())
-	// YAML edit command removed
+	rootCmd.AddCommand(newEditCmd())
+	rootCmd.AddCommand(newRmCmd())
 	rootCmd.AddCommand(newExportBibCmd())
 	rootCmd.AddCommand(newVerifyCmd())
+	rootCmd.AddCommand(newUnverifyCmd())
 	rootCmd.AddCommand(newFormatCmd())
-	return rootCmd.Execute()
+	rootCmd.AddCommand(newNormalizeCmd())
+	rootCmd.AddCommand(newDoctorCmd())
+	rootCmd.AddCommand(newCheckLinksCmd())
+	rootCmd.AddCommand(newArchiveCmd())
+	rootCmd.AddCommand(newImportCmd())
+	rootCmd.AddCommand(newImportBibCmd())
+	rootCmd.AddCommand(newImportRISCmd())
+	rootCmd.AddCommand(newImportZoteroCmd())
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.AddCommand(newKeywordsCmd())
+	rootCmd.AddCommand(newUndoCmd())
+	rootCmd.AddCommand(newMvCmd())
+	rootCmd.AddCommand(newDedupeCmd())
+	rootCmd.AddCommand(newMergeCmd())
+	rootCmd.AddCommand(newSchemaCmd())
+	// Ctrl-C cancels the command context so long-running work (e.g., batch adds) stops cleanly.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 // main is the entrypoint that executes the CLI and reports errors.
@@ -1,7 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/gitutil"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
 )
 
 func TestExecuteHelp(t *testing.T) {
@@ -11,3 +21,115 @@ func TestExecuteHelp(t *testing.T) {
 		t.Fatalf("execute help: %v", err)
 	}
 }
+
+func TestApplyAsOf(t *testing.T) {
+	t.Cleanup(func() { dates.SetClock(nil) })
+	if err := applyAsOf("2020-02-03"); err != nil {
+		t.Fatalf("applyAsOf: %v", err)
+	}
+	if got := dates.NowISO(); got != "2020-02-03" {
+		t.Fatalf("pinned NowISO: got %q", got)
+	}
+	if err := applyAsOf("02/03/2020"); err == nil {
+		t.Fatalf("expected error for bad --as-of")
+	}
+	if err := applyAsOf(""); err != nil {
+		t.Fatalf("empty --as-of should be a no-op: %v", err)
+	}
+}
+
+// resetDataDir restores the default data directory. Tests must not call
+// applyDataDir("", "") from the package directory: it would find the real library.
+func resetDataDir() {
+	store.SetDataDir("")
+	gitutil.SetDir("")
+	httpx.SetCacheDir("")
+}
+
+func TestApplyDataDir(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old); resetDataDir() })
+	_ = os.Chdir(dir)
+	t.Setenv("BIB_DATA_DIR", "/env/lib")
+	applyDataDir("", "")
+	if store.BibFile != "/env/lib/library.bib" {
+		t.Fatalf("BIB_DATA_DIR: %s", store.BibFile)
+	}
+	applyDataDir("/flag/lib", "/repo")
+	if store.BibFile != "/flag/lib/library.bib" || store.MetadataDir != "/flag/lib/metadata" {
+		t.Fatalf("--data-dir should win over BIB_DATA_DIR: %s", store.BibFile)
+	}
+	if httpx.CacheDir != filepath.Join("/flag/lib", ".cache", "http") {
+		t.Fatalf("http cache not under the data dir: %s", httpx.CacheDir)
+	}
+	t.Setenv("BIB_DATA_DIR", "")
+	applyDataDir("", "")
+	if store.BibFile != "data/library.bib" {
+		t.Fatalf("default: %s", store.BibFile)
+	}
+	if httpx.CacheDir != filepath.FromSlash(httpx.DefaultCacheDir) {
+		t.Fatalf("default cache: %s", httpx.CacheDir)
+	}
+}
+
+func TestApplyDataDirFindsRepoFromSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "data", "citations"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "notes", "deep", "deeper")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old); resetDataDir() })
+	t.Setenv("BIB_DATA_DIR", "")
+	if err := os.Chdir(nested); err != nil {
+		t.Fatal(err)
+	}
+	// Resolve symlinks (e.g., macOS /var -> /private/var) the way Getwd reports them.
+	wd, _ := os.Getwd()
+	want := filepath.ToSlash(filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(wd))), "data", "library.bib"))
+	applyDataDir("", "")
+	if store.BibFile != want {
+		t.Fatalf("BibFile = %s, want %s", store.BibFile, want)
+	}
+	applyDataDir("", "/explicit")
+	if store.BibFile != "/explicit/data/library.bib" {
+		t.Fatalf("--repo: %s", store.BibFile)
+	}
+}
+
+func TestSearchFromNestedDirectory(t *testing.T) {
+	root := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old); resetDataDir() })
+	t.Setenv("BIB_DATA_DIR", "")
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	applyDataDir("", "")
+	e := schema.Entry{ID: "00000000-0000-4000-8000-0000000000a9", Type: "book", APA7: schema.APA7{Title: "Nested Lookup"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"nested"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(nested); err != nil {
+		t.Fatal(err)
+	}
+	applyDataDir("", "")
+	var out bytes.Buffer
+	cmd := newSearchCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--keyword", "nested"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if !strings.Contains(out.String(), "Nested Lookup") {
+		t.Fatalf("search from a subdirectory should find the entry: %q", out.String())
+	}
+}
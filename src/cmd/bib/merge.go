@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/mergecmd"
+	"github.com/spf13/cobra"
+)
+
+// newMergeCmd creates the "merge" command to fold one citation into another.
+func newMergeCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(mergecmd.New(optionalCommit(&noCommit)), &noCommit)
+}
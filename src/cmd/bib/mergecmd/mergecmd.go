@@ -0,0 +1,96 @@
+package mergecmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the merge command, which folds one citation into another.
+func New(commit CommitFunc) *cobra.Command {
+	var intoID, fromID, prefer string
+	cmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Merge one citation into another, filling empty fields and removing the source",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			intoID, fromID = strings.TrimSpace(intoID), strings.TrimSpace(fromID)
+			prefer = strings.ToLower(strings.TrimSpace(prefer))
+			if intoID == "" || fromID == "" {
+				return fmt.Errorf("--into and --from are required")
+			}
+			if strings.EqualFold(intoID, fromID) {
+				return fmt.Errorf("--into and --from must be different entries")
+			}
+			if prefer != "" && prefer != store.PreferInto && prefer != store.PreferFrom {
+				return fmt.Errorf("invalid --prefer %q (want into or from)", prefer)
+			}
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			into, err := find(entries, intoID)
+			if err != nil {
+				return err
+			}
+			from, err := find(entries, fromID)
+			if err != nil {
+				return err
+			}
+			merged, conflicts := store.MergeEntry(into, from, prefer)
+			out := cmd.OutOrStdout()
+			if len(conflicts) > 0 && prefer == "" {
+				for _, c := range conflicts {
+					fmt.Fprintf(out, "conflict %s:\n  into: %s\n  from: %s\n", c.Field, c.Into, c.From)
+				}
+				return fmt.Errorf("%d conflicting field(s); re-run with --prefer into|from", len(conflicts))
+			}
+			if err := merged.Validate(); err != nil {
+				return err
+			}
+			if _, err := store.WriteEntry(merged); err != nil {
+				return err
+			}
+			legacy, err := store.MergeDuplicates(merged.ID, merged.Annotation.Keywords, []string{from.ID})
+			if err != nil {
+				return err
+			}
+			paths := []string{store.BibFile}
+			if legacy {
+				paths = append(paths, store.CitationsDir)
+			}
+			if err := commit(paths, fmt.Sprintf("merge citation: %s into %s", from.ID, merged.ID)); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(out, "merged %s into %s\n", from.ID, merged.ID)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&intoID, "into", "", "ID of the entry to keep (uuid)")
+	cmd.Flags().StringVar(&fromID, "from", "", "ID of the entry to merge and remove (uuid)")
+	cmd.Flags().StringVar(&prefer, "prefer", "", "Resolve conflicting fields with the value from: into|from")
+	_ = cmd.RegisterFlagCompletionFunc("into", complete.IDs)
+	_ = cmd.RegisterFlagCompletionFunc("from", complete.IDs)
+	_ = cmd.RegisterFlagCompletionFunc("prefer", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{store.PreferInto, store.PreferFrom}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+// find returns the entry with id.
+func find(entries []schema.Entry, id string) (schema.Entry, error) {
+	for _, e := range entries {
+		if strings.EqualFold(e.ID, id) {
+			return e, nil
+		}
+	}
+	return schema.Entry{}, fmt.Errorf("id not found: %s", id)
+}
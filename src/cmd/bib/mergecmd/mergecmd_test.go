@@ -0,0 +1,110 @@
+package mergecmd
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+const (
+	intoID = "00000000-0000-4000-8000-0000000000f1"
+	fromID = "00000000-0000-4000-8000-0000000000f2"
+)
+
+func setup(t *testing.T, journal string) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	y := 2021
+	for _, e := range []schema.Entry{
+		{ID: intoID, Type: "article", APA7: schema.APA7{Title: "Paper", Journal: "J"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"a"}}},
+		{ID: fromID, Type: "article", APA7: schema.APA7{Title: "Paper", Journal: journal, Year: &y, Volume: "7"}, Annotation: schema.Annotation{Summary: "longer", Keywords: []string{"b"}}},
+	} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func run(args ...string) (string, []string, error) {
+	var msgs []string
+	cmd := New(func(paths []string, message string) error {
+		msgs = append(msgs, message)
+		return nil
+	})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), msgs, err
+}
+
+func TestMergeFillsAndRemovesSource(t *testing.T) {
+	setup(t, "")
+	out, msgs, err := run("--into", intoID, "--from", fromID)
+	if err != nil {
+		t.Fatalf("merge: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(msgs, []string{"merge citation: " + fromID + " into " + intoID}) {
+		t.Fatalf("commits: %v", msgs)
+	}
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != intoID {
+		t.Fatalf("source not removed: %+v", entries)
+	}
+	e := entries[0]
+	if e.APA7.Journal != "J" || e.APA7.Volume != "7" || e.APA7.Year == nil || *e.APA7.Year != 2021 || e.Annotation.Summary != "longer" {
+		t.Fatalf("fields not merged: %+v", e)
+	}
+	if !reflect.DeepEqual(e.Annotation.Keywords, []string{"a", "b"}) {
+		t.Fatalf("keywords: %v", e.Annotation.Keywords)
+	}
+}
+
+func TestMergeConflictNeedsPrefer(t *testing.T) {
+	setup(t, "Other")
+	out, msgs, err := run("--into", intoID, "--from", fromID)
+	if err == nil || !strings.Contains(err.Error(), "1 conflicting field(s)") {
+		t.Fatalf("want conflict error, got %v", err)
+	}
+	if !strings.Contains(out, "conflict journal:") || len(msgs) != 0 {
+		t.Fatalf("conflict not reported or committed anyway: %q %v", out, msgs)
+	}
+	if entries, _ := store.ReadAll(); len(entries) != 2 {
+		t.Fatalf("library changed despite conflict: %d entries", len(entries))
+	}
+	if _, _, err := run("--into", intoID, "--from", fromID, "--prefer", "from"); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := store.ReadAll()
+	if len(entries) != 1 || entries[0].APA7.Journal != "Other" {
+		t.Fatalf("--prefer from: %+v", entries)
+	}
+}
+
+func TestMergeRejectsBadArgs(t *testing.T) {
+	setup(t, "")
+	for _, args := range [][]string{
+		{"--into", intoID},
+		{"--into", intoID, "--from", intoID},
+		{"--into", intoID, "--from", fromID, "--prefer", "both"},
+		{"--into", intoID, "--from", "00000000-0000-4000-8000-0000000000ff"},
+	} {
+		if _, _, err := run(args...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/mvcmd"
+	"github.com/spf13/cobra"
+)
+
+// newMvCmd creates the "mv" command to change an entry's type.
+func newMvCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(mvcmd.New(optionalCommit(&noCommit)), &noCommit)
+}
@@ -0,0 +1,102 @@
+package mvcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the mv command which changes an entry's type (and storage segment).
+func New(commit CommitFunc) *cobra.Command {
+	var id, typ string
+	cmd := &cobra.Command{
+		Use:   "mv",
+		Short: "Change an entry's type (e.g., website -> book), moving it to the new segment",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(id) == "" {
+				return fmt.Errorf("--id is required")
+			}
+			newType := strings.ToLower(strings.TrimSpace(typ))
+			if !schema.IsValidType(newType) {
+				return fmt.Errorf("invalid --type %q (want one of %s)", typ, strings.Join(schema.Types, ", "))
+			}
+			e, err := findByID(id)
+			if err != nil {
+				return err
+			}
+			oldType := e.Type
+			if oldType == newType {
+				return fmt.Errorf("entry %s is already a %s", e.ID, newType)
+			}
+			e.Type = newType
+			e.Annotation.Keywords = rekey(e.Annotation.Keywords, oldType, newType)
+			from, to, err := store.MoveEntry(e, oldType)
+			if err != nil {
+				return err
+			}
+			paths := []string{store.BibFile}
+			if to != "" {
+				paths = append(paths, store.CitationsDir)
+			}
+			if err := commit(paths, fmt.Sprintf("mv: %s %s -> %s", e.ID, oldType, newType)); err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if _, err := fmt.Fprintf(out, "moved %s -> %s (%s)\n", oldType, newType, e.ID); err != nil {
+				return err
+			}
+			if from != "" {
+				_, err = fmt.Fprintf(out, "  %s -> %s\n", from, to)
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&id, "id", "", "ID of the entry to move")
+	_ = cmd.RegisterFlagCompletionFunc("id", complete.IDs)
+	cmd.Flags().StringVar(&typ, "type", "", "New type ("+strings.Join(schema.Types, "|")+")")
+	_ = cmd.RegisterFlagCompletionFunc("type", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return schema.Types, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+// rekey replaces the type-derived keyword (the old type name) with the new type,
+// keeping order and dropping a duplicate if the new type was already present.
+func rekey(keywords []string, oldType, newType string) []string {
+	out := make([]string, 0, len(keywords))
+	seen := map[string]bool{}
+	for _, k := range keywords {
+		if strings.EqualFold(k, oldType) {
+			k = newType
+		}
+		if seen[strings.ToLower(k)] {
+			continue
+		}
+		seen[strings.ToLower(k)] = true
+		out = append(out, k)
+	}
+	return out
+}
+
+// findByID returns the entry with the given id (case-insensitive).
+func findByID(id string) (schema.Entry, error) {
+	entries, err := store.ReadAll()
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	for _, e := range entries {
+		if strings.EqualFold(e.ID, strings.TrimSpace(id)) {
+			return e, nil
+		}
+	}
+	return schema.Entry{}, fmt.Errorf("no citation found for id %s", id)
+}
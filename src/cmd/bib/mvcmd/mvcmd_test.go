@@ -0,0 +1,91 @@
+package mvcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMvChangesTypeAndMovesLegacyFile(t *testing.T) {
+	chdirTemp(t)
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000041", Type: "website", APA7: schema.APA7{Title: "A Book Page", URL: "https://example.com/b", Accessed: "2024-01-02"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"website", "go", "book"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	old := filepath.Join(store.CitationsDir, "site", e.ID+".yaml")
+	_ = os.MkdirAll(filepath.Dir(old), 0o755)
+	b, _ := json.Marshal(e)
+	if err := os.WriteFile(old, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var committed []string
+	var msg string
+	cmd := New(func(paths []string, m string) error { committed, msg = paths, m; return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--id", e.ID, "--type", "book"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(buf.String(), "moved website -> book") || !strings.Contains(buf.String(), "data/citations/books/"+e.ID+".yaml") {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+	if !reflect.DeepEqual(committed, []string{store.BibFile, store.CitationsDir}) || msg != "mv: "+e.ID+" website -> book" {
+		t.Fatalf("unexpected commit %v %q", committed, msg)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("old file should be removed: %v", err)
+	}
+	got, err := findByID(e.ID)
+	if err != nil || got.Type != "book" || !reflect.DeepEqual(got.Annotation.Keywords, []string{"book", "go"}) {
+		t.Fatalf("unexpected entry %+v (%v)", got, err)
+	}
+	bib, _ := os.ReadFile(store.BibFile)
+	if !strings.Contains(string(bib), "@book{") {
+		t.Fatalf("bib record not rebuilt as @book:\n%s", bib)
+	}
+	files, err := store.ReadAllYAMLFiles()
+	if err != nil || len(files) != 1 || files[0].Entry.Type != "book" {
+		t.Fatalf("moved yaml: %+v %v", files, err)
+	}
+}
+
+func TestMvRejectsBadInput(t *testing.T) {
+	chdirTemp(t)
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000042", Type: "book", APA7: schema.APA7{Title: "B"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, args := range [][]string{
+		{"--type", "book"},
+		{"--id", e.ID, "--type", "novel"},
+		{"--id", e.ID, "--type", "book"},
+		{"--id", "00000000-0000-4000-8000-000000000099", "--type", "article"},
+	} {
+		c := New(func([]string, string) error { t.Fatalf("should not commit for %v", args); return nil })
+		c.SetOut(&bytes.Buffer{})
+		c.SilenceUsage, c.SilenceErrors = true, true
+		c.SetArgs(args)
+		if err := c.Execute(); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"bibliography/src/internal/store"
+)
+
+func TestNoCommitWritesWithoutCommitting(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	calls := 0
+	prev := commitAndPush
+	commitAndPush = func([]string, string) error { calls++; return nil }
+	t.Cleanup(func() { commitAndPush = prev })
+
+	cmd := newAddCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--no-commit", "site", "https://example.com/page"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("add --no-commit: %v", err)
+	}
+	if _, err := os.Stat(store.BibFile); err != nil {
+		t.Fatalf("expected %s to be written: %v", store.BibFile, err)
+	}
+	if calls != 0 {
+		t.Fatalf("commit called %d times with --no-commit", calls)
+	}
+
+	// BIB_NO_COMMIT=1 has the same effect without the flag.
+	t.Setenv("BIB_NO_COMMIT", "1")
+	cmd = newAddCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"site", "https://example.org/other"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("add with BIB_NO_COMMIT: %v", err)
+	}
+	entries, _ := store.ReadAll()
+	if len(entries) != 2 || calls != 0 {
+		t.Fatalf("want 2 entries and no commits, got %d entries and %d commits", len(entries), calls)
+	}
+
+	// Without either, the commit func runs.
+	t.Setenv("BIB_NO_COMMIT", "")
+	cmd = newAddCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"site", "https://example.net/third"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("commit should run once without --no-commit, got %d", calls)
+	}
+}
+
+func TestNoCommitImportBib(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	calls := 0
+	prev := commitAndPush
+	commitAndPush = func([]string, string) error { calls++; return nil }
+	t.Cleanup(func() { commitAndPush = prev })
+
+	refs := "@book{one,\n  title = {One},\n  author = {Doe, Jane},\n  publisher = {P},\n  year = {2020}\n}\n" +
+		"@book{two,\n  title = {Two},\n  author = {Roe, Rick},\n  publisher = {P},\n  year = {2021}\n}\n"
+	if err := os.WriteFile("refs.bib", []byte(refs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newImportBibCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--file", "refs.bib", "--no-commit"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import-bib --no-commit: %v", err)
+	}
+	entries, _ := store.ReadAll()
+	if len(entries) != 2 || calls != 0 {
+		t.Fatalf("want 2 entries and no commits, got %d entries and %d commits", len(entries), calls)
+	}
+
+	// BIB_NO_COMMIT=1 covers the other commands too.
+	t.Setenv("BIB_NO_COMMIT", "1")
+	cmd = newRmCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--id", entries[0].ID, "--yes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("rm with BIB_NO_COMMIT: %v", err)
+	}
+	if left, _ := store.ReadAll(); len(left) != 1 || calls != 0 {
+		t.Fatalf("want 1 entry and no commits, got %d entries and %d commits", len(left), calls)
+	}
+}
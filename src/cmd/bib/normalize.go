@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/normalizecmd"
+	"github.com/spf13/cobra"
+)
+
+// newNormalizeCmd creates the "normalize" command to canonicalize all entries.
+func newNormalizeCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(normalizecmd.New(optionalCommit(&noCommit)), &noCommit)
+}
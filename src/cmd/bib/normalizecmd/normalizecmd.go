@@ -0,0 +1,130 @@
+package normalizecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/names"
+	"bibliography/src/internal/pages"
+	"bibliography/src/internal/sanitize"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+	"bibliography/src/internal/titlecase"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the normalize command, which canonicalizes every entry in the library.
+func New(commit CommitFunc) *cobra.Command {
+	var dryRun, fixCase bool
+	cmd := &cobra.Command{
+		Use:   "normalize",
+		Short: "Canonicalize all entries (sanitize, page ranges, DOI URLs, author initials, accessed dates)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			verb := "normalized"
+			if dryRun {
+				verb = "would normalize"
+			}
+			changed := 0
+			for _, e := range entries {
+				n := Normalize(e, fixCase)
+				if err := pages.Validate(n.APA7.Pages); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s: %v\n", e.ID, err)
+				}
+				fields := changedFields(e, n)
+				if len(fields) == 0 {
+					continue
+				}
+				if err := n.Validate(); err != nil {
+					return fmt.Errorf("%s: %w", e.ID, err)
+				}
+				if !dryRun {
+					if _, err := store.WriteEntry(n); err != nil {
+						return err
+					}
+				}
+				changed++
+				fmt.Fprintf(out, "%s %s: %s\n", verb, e.ID, strings.Join(fields, ", "))
+			}
+			if changed == 0 {
+				_, err := fmt.Fprintln(out, "all entries already normalized")
+				return err
+			}
+			if dryRun {
+				_, err := fmt.Fprintf(out, "%d citation(s) would change; re-run without --dry-run to apply\n", changed)
+				return err
+			}
+			return commit([]string{store.BibFile}, fmt.Sprintf("normalize %d citation(s)", changed))
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the entries and fields that would change without writing")
+	cmd.Flags().BoolVar(&fixCase, "case", false, "Also sentence-case article titles and title-case their journal names (review the result)")
+	return cmd
+}
+
+// Normalize returns e in canonical form: sanitized (trimmed, control characters
+// removed, URLs cleaned, page ranges hyphenated), an article's DOI derived from and reflected in its URL,
+// given names reduced to initials, and an accessed date set when there is a URL. With
+// fixCase an article's title is also put in sentence case and its journal and
+// container names in title case, as APA7 expects.
+func Normalize(e schema.Entry, fixCase bool) schema.Entry {
+	e.APA7.Authors = append(schema.Authors(nil), e.APA7.Authors...)
+	e.Annotation.Keywords = append([]string(nil), e.Annotation.Keywords...)
+	sanitize.CleanEntry(&e)
+	store.NormalizeArticleDOI(&e)
+	for i := range e.APA7.Authors {
+		e.APA7.Authors[i].Given = names.Initials(e.APA7.Authors[i].Given)
+	}
+	schema.EnsureAccessedIfURL(&e)
+	if fixCase && strings.EqualFold(e.Type, "article") {
+		e.APA7.Title = titlecase.SentenceCase(e.APA7.Title)
+		e.APA7.Journal = titlecase.TitleCase(e.APA7.Journal)
+		e.APA7.ContainerTitle = titlecase.TitleCase(e.APA7.ContainerTitle)
+	}
+	return e
+}
+
+// changedFields lists, by their YAML names, the fields that differ between two
+// versions of an entry.
+func changedFields(before, after schema.Entry) []string {
+	a, b := fieldValues(before), fieldValues(after)
+	var out []string
+	for k := range a {
+		if !reflect.DeepEqual(a[k], b[k]) {
+			out = append(out, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// fieldValues flattens an entry's type, APA7 fields, and annotation into a map
+// keyed by YAML name.
+func fieldValues(e schema.Entry) map[string]any {
+	var m map[string]any
+	b, _ := json.Marshal(e.APA7)
+	_ = json.Unmarshal(b, &m)
+	m["type"] = e.Type
+	m["summary"] = e.Annotation.Summary
+	if len(e.Annotation.Keywords) > 0 {
+		m["keywords"] = strings.Join(e.Annotation.Keywords, ", ")
+	}
+	return m
+}
@@ -0,0 +1,40 @@
+package normalizecmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestNormalizePageRanges(t *testing.T) {
+	setup(t)
+	for _, e := range []schema.Entry{
+		{ID: "00000000-0000-4000-8000-000000000063", Type: "article", APA7: schema.APA7{Title: "Dash", Journal: "J", Pages: "pp. 45 – 60"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"a"}}},
+		{ID: "00000000-0000-4000-8000-000000000064", Type: "article", APA7: schema.APA7{Title: "Backwards", Journal: "J", Pages: "60-45"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"a"}}},
+	} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := New(func([]string, string) error { return nil })
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "would normalize 00000000-0000-4000-8000-000000000063: pages") {
+		t.Fatalf("en dash range not normalized: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "warning: 00000000-0000-4000-8000-000000000064: invalid page range") {
+		t.Fatalf("reversed range not reported: %q", errOut.String())
+	}
+	n := Normalize(schema.Entry{APA7: schema.APA7{Pages: "e12345"}}, false)
+	if n.APA7.Pages != "e12345" {
+		t.Fatalf("electronic locator changed: %q", n.APA7.Pages)
+	}
+}
@@ -0,0 +1,143 @@
+package normalizecmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// messyBib holds an article with a full given name, a DOI only in its URL, and no
+// accessed date, followed by an already clean book.
+const messyBib = `@article{messy,
+  author = {Doe, Jane Quinn},
+  title = {Messy Title},
+  journal = {J},
+  url = {https://doi.org/10.1234/ABC},
+  abstract = {s},
+  keywords = {article},
+  _id = {00000000-0000-4000-8000-000000000061},
+  _type = {article},
+}
+
+@book{clean,
+  author = {Roe, R.},
+  title = {Clean Book},
+  abstract = {s},
+  keywords = {book},
+  _id = {00000000-0000-4000-8000-000000000062},
+  _type = {book},
+}
+`
+
+func setup(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.MkdirAll(filepath.Dir(store.BibFile), 0o755)
+	if err := os.WriteFile(store.BibFile, []byte(messyBib), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func run(args ...string) (string, []string, error) {
+	var msgs []string
+	cmd := New(func(paths []string, message string) error {
+		msgs = append(msgs, message)
+		return nil
+	})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), msgs, err
+}
+
+func TestNormalizeEntry(t *testing.T) {
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000063", Type: "website",
+		APA7:       schema.APA7{Title: "  Trailing  ", URL: " https://example.com/page ", Authors: schema.Authors{{Family: "Doe ", Given: "jane quinn"}}},
+		Annotation: schema.Annotation{Summary: "s ", Keywords: []string{" Web "}}}
+	n := Normalize(e, false)
+	a := n.APA7
+	if a.Title != "Trailing" || a.URL != "https://example.com/page" || a.Accessed != dates.NowISO() {
+		t.Fatalf("fields not cleaned: %+v", a)
+	}
+	if a.Authors[0] != (schema.Author{Family: "Doe", Given: "J. Q."}) {
+		t.Fatalf("author: %+v", a.Authors[0])
+	}
+	if n.Annotation.Summary != "s" || !reflect.DeepEqual(n.Annotation.Keywords, []string{"web"}) {
+		t.Fatalf("annotation: %+v", n.Annotation)
+	}
+	if e.APA7.Authors[0].Given != "jane quinn" {
+		t.Fatalf("Normalize modified its argument")
+	}
+	if got := changedFields(e, n); !reflect.DeepEqual(got, []string{"accessed", "authors", "keywords", "summary", "title", "url"}) {
+		t.Fatalf("changed fields: %v", got)
+	}
+}
+
+func TestNormalizeCase(t *testing.T) {
+	e := schema.Entry{Type: "article", APA7: schema.APA7{Title: "Deploying HTTP/2: A Tale Of Two Cities", Journal: "journal of web engineering"}}
+	if n := Normalize(e, false); n.APA7.Title != e.APA7.Title || n.APA7.Journal != e.APA7.Journal {
+		t.Fatalf("case changed without fixCase: %+v", n.APA7)
+	}
+	n := Normalize(e, true)
+	if n.APA7.Title != "Deploying HTTP/2: A tale of two cities" || n.APA7.Journal != "Journal of Web Engineering" {
+		t.Fatalf("case not fixed: %+v", n.APA7)
+	}
+	e.Type = "book"
+	if n := Normalize(e, true); n.APA7.Title != e.APA7.Title {
+		t.Fatalf("only article titles are recased: %q", n.APA7.Title)
+	}
+}
+
+func TestNormalizeDryRunListsChanges(t *testing.T) {
+	setup(t)
+	out, msgs, err := run("--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "would normalize 00000000-0000-4000-8000-000000000061: accessed, authors, doi") {
+		t.Fatalf("dry-run output %q", out)
+	}
+	if strings.Contains(out, "000000000062") || len(msgs) != 0 {
+		t.Fatalf("clean entry listed or commit made: %q %v", out, msgs)
+	}
+	if b, _ := os.ReadFile(store.BibFile); string(b) != messyBib {
+		t.Fatalf("dry-run wrote the library")
+	}
+}
+
+func TestNormalizeFixesAndCommitsOnce(t *testing.T) {
+	setup(t)
+	if _, msgs, err := run(); err != nil || !reflect.DeepEqual(msgs, []string{"normalize 1 citation(s)"}) {
+		t.Fatalf("normalize: %v %v", err, msgs)
+	}
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.ID != "00000000-0000-4000-8000-000000000061" {
+			continue
+		}
+		a := e.APA7
+		if a.Title != "Messy Title" || a.Authors[0].Given != "J. Q." || a.DOI != "10.1234/ABC" || a.Accessed != dates.NowISO() {
+			t.Fatalf("not normalized: %+v", a)
+		}
+	}
+	out, msgs, err := run()
+	if err != nil || !strings.Contains(out, "all entries already normalized") || len(msgs) != 0 {
+		t.Fatalf("second run should be a no-op: %v %q %v", err, out, msgs)
+	}
+}
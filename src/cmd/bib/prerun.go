@@ -0,0 +1,13 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// The root hook applies the global flags before any command runs: the cache switch,
+// the data directory (and with it the response cache location), then --as-of.
+func init() {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		applyNoCache(noCache)
+		applyDataDir(dataDir, repoRoot)
+		return applyAsOf(asOf)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 // newRepairDOICmd creates the "repair-doi" command to normalize article DOIs and URLs in-place.
 func newRepairDOICmd() *cobra.Command {
+	var noCommit bool
 	cmd := &cobra.Command{
 		Use:   "repair-doi",
 		Short: "Normalize article DOIs and doi.org URLs in-place",
@@ -25,13 +26,13 @@ func newRepairDOICmd() *cobra.Command {
 				return err
 			}
 
-			if err := commitDOIRepairs(changed); err != nil {
+			if err := commitDOIRepairs(optionalCommit(&noCommit), changed); err != nil {
 				return err
 			}
 			return reportDOIRepairs(cmd, changed)
 		},
 	}
-	return cmd
+	return addNoCommitFlag(cmd, &noCommit)
 }
 
 // normalizeArticleDOIs applies DOI normalization to article entries and writes changes.
@@ -53,11 +54,11 @@ func normalizeArticleDOIs(entries []schema.Entry) ([]string, error) {
 }
 
 // commitDOIRepairs commits updated files when there are changes.
-func commitDOIRepairs(changedPaths []string) error {
+func commitDOIRepairs(commit func([]string, string) error, changedPaths []string) error {
 	if len(changedPaths) == 0 {
 		return nil
 	}
-	return commitAndPush(changedPaths, fmt.Sprintf("normalize DOI for %d articles", len(changedPaths)))
+	return commit(changedPaths, fmt.Sprintf("normalize DOI for %d articles", len(changedPaths)))
 }
 
 // reportDOIRepairs prints updated paths or a no-op message.
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/rmcmd"
+	"github.com/spf13/cobra"
+)
+
+// newRmCmd creates the "rm" command to delete a citation.
+func newRmCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(rmcmd.New(optionalCommit(&noCommit)), &noCommit)
+}
@@ -0,0 +1,83 @@
+package rmcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the rm command, which deletes a citation from the library.
+func New(commit CommitFunc) *cobra.Command {
+	var id string
+	var dryRun, yes bool
+	cmd := &cobra.Command{
+		Use:     "rm",
+		Aliases: []string{"delete"},
+		Short:   "Remove a citation from the library",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				return fmt.Errorf("--id is required")
+			}
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			paths, err := store.EntryPaths(entries)
+			if err != nil {
+				return err
+			}
+			where, ok := paths[strings.ToLower(id)]
+			if !ok {
+				return fmt.Errorf("no citation found for id %s", id)
+			}
+			var title string
+			for _, e := range entries {
+				if strings.EqualFold(e.ID, id) {
+					id, title = e.ID, e.APA7.Title
+					break
+				}
+			}
+			out := cmd.OutOrStdout()
+			if dryRun {
+				_, err := fmt.Fprintf(out, "would remove %s (%s): %s\n", id, title, where)
+				return err
+			}
+			if !yes {
+				fmt.Fprintf(out, "remove %s (%s)? (y/n) ", id, title)
+				var resp string
+				fmt.Fscan(cmd.InOrStdin(), &resp)
+				if strings.ToLower(strings.TrimSpace(resp)) != "y" {
+					_, err := fmt.Fprintln(out, "aborted")
+					return err
+				}
+			}
+			legacy, err := store.DeleteEntry(id)
+			if err != nil {
+				return err
+			}
+			commitPaths := []string{store.BibFile}
+			if legacy != "" {
+				commitPaths = append(commitPaths, legacy)
+			}
+			if err := commit(commitPaths, fmt.Sprintf("remove citation: %s", id)); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(out, "removed %s: %s\n", id, where)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&id, "id", "", "ID of the entry to remove (uuid)")
+	_ = cmd.RegisterFlagCompletionFunc("id", complete.IDs)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print what would be removed without changing anything")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Remove without asking for confirmation")
+	return cmd
+}
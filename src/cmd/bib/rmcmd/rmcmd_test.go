@@ -0,0 +1,113 @@
+package rmcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+const (
+	keepID = "00000000-0000-4000-8000-000000000051"
+	rmID   = "00000000-0000-4000-8000-000000000052"
+)
+
+// setup writes two entries, the second with a legacy YAML file, and returns that file's path.
+func setup(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []schema.Entry{
+		{ID: keepID, Type: "book", APA7: schema.APA7{Title: "Keep Me"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}},
+		{ID: rmID, Type: "book", APA7: schema.APA7{Title: "Remove Me"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}},
+	} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	legacy := filepath.ToSlash(filepath.Join(store.CitationsDir, "books", rmID+".yaml"))
+	_ = os.MkdirAll(filepath.Dir(legacy), 0o755)
+	b, _ := json.Marshal(schema.Entry{ID: rmID, Type: "book", APA7: schema.APA7{Title: "Remove Me"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}})
+	if err := os.WriteFile(legacy, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return legacy
+}
+
+func run(stdin string, args ...string) (string, [][]string, error) {
+	var commits [][]string
+	cmd := New(func(paths []string, message string) error {
+		commits = append(commits, paths)
+		return nil
+	})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), commits, err
+}
+
+func ids(t *testing.T) []string {
+	t.Helper()
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.ID)
+	}
+	return out
+}
+
+func TestRmRemovesEntryAndCommits(t *testing.T) {
+	legacy := setup(t)
+	out, commits, err := run("", "--id", rmID, "--yes")
+	if err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Fatalf("legacy file still present: %v", err)
+	}
+	if got := ids(t); !reflect.DeepEqual(got, []string{keepID}) {
+		t.Fatalf("library ids = %v", got)
+	}
+	if !reflect.DeepEqual(commits, [][]string{{store.BibFile, legacy}}) {
+		t.Fatalf("commit paths = %v", commits)
+	}
+	if !strings.Contains(out, "removed "+rmID) {
+		t.Fatalf("output %q", out)
+	}
+}
+
+func TestRmDryRunAndPrompt(t *testing.T) {
+	legacy := setup(t)
+	out, commits, err := run("", "--id", rmID, "--dry-run")
+	if err != nil || !strings.Contains(out, "would remove "+rmID) || len(commits) != 0 {
+		t.Fatalf("dry-run: %v %q %v", err, out, commits)
+	}
+	out, commits, err = run("n\n", "--id", rmID)
+	if err != nil || !strings.Contains(out, "aborted") || len(commits) != 0 {
+		t.Fatalf("declined prompt: %v %q %v", err, out, commits)
+	}
+	if _, err := os.Stat(legacy); err != nil || len(ids(t)) != 2 {
+		t.Fatalf("entry removed without confirmation")
+	}
+	if _, commits, err = run("y\n", "--id", rmID); err != nil || len(commits) != 1 {
+		t.Fatalf("confirmed prompt: %v %v", err, commits)
+	}
+	if _, _, err := run("", "--id", rmID, "--yes"); err == nil {
+		t.Fatalf("removing a missing id should fail")
+	}
+}
@@ -0,0 +1,9 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/schemacmd"
+	"github.com/spf13/cobra"
+)
+
+// newSchemaCmd creates the "schema" command that emits the entry JSON Schema.
+func newSchemaCmd() *cobra.Command { return schemacmd.New() }
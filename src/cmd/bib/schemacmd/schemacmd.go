@@ -0,0 +1,30 @@
+package schemacmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+)
+
+// New returns the schema command, which describes the entry format for external tools.
+func New() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Describe the entry format (--json for a JSON Schema to validate YAML entries)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !asJSON {
+				return fmt.Errorf("select a format: --json")
+			}
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(schema.JSONSchema())
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Emit a JSON Schema (draft 2020-12) generated from the entry structs and validation rules")
+	return cmd
+}
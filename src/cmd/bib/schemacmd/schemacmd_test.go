@@ -0,0 +1,69 @@
+package schemacmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestSchemaJSON(t *testing.T) {
+	cmd := New()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Schema     string `json:"$schema"`
+		Required   []string
+		Properties struct {
+			Type struct {
+				Enum []string
+			}
+		}
+		Defs map[string]struct {
+			Required          []string
+			Properties        map[string]map[string]any
+			DependentRequired map[string][]string `json:"dependentRequired"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if doc.Schema != schema.JSONSchemaDraft {
+		t.Fatalf("$schema = %q", doc.Schema)
+	}
+	if !reflect.DeepEqual(doc.Properties.Type.Enum, schema.Types) {
+		t.Fatalf("type enum = %v, want %v", doc.Properties.Type.Enum, schema.Types)
+	}
+	if !reflect.DeepEqual(doc.Required, []string{"id", "type", "apa7", "annotation"}) {
+		t.Fatalf("entry required = %v", doc.Required)
+	}
+	apa7 := doc.Defs["APA7"]
+	if !reflect.DeepEqual(apa7.Required, []string{"title"}) {
+		t.Fatalf("apa7.title should be required: %v", apa7.Required)
+	}
+	if _, ok := apa7.Properties["doi"]; !ok {
+		t.Fatalf("embedded identifiers should be flattened into apa7: %v", apa7.Properties)
+	}
+	if !reflect.DeepEqual(apa7.DependentRequired["url"], []string{"accessed"}) {
+		t.Fatalf("url should require accessed: %v", apa7.DependentRequired)
+	}
+	if _, ok := doc.Defs["Author"].Properties["role"]["enum"]; !ok {
+		t.Fatalf("author role should be an enum: %v", doc.Defs["Author"].Properties)
+	}
+}
+
+func TestSchemaRequiresFormat(t *testing.T) {
+	cmd := New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error without --json")
+	}
+}
@@ -0,0 +1,184 @@
+package searchcmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+)
+
+// --cluster-by values.
+const (
+	clusterAuthor = "author"
+	clusterYear   = "year"
+	clusterType   = "type"
+)
+
+// noAuthorLabel and noYearLabel head the groups of results lacking that field.
+const (
+	noAuthorLabel = "(no author)"
+	noYearLabel   = "n.d."
+)
+
+type cluster struct {
+	label string
+	items []scored
+}
+
+// renderClusters prints a heading per group followed by that group's results,
+// rendered with the regular (unclustered) view.
+func renderClusters(cmd *cobra.Command, out []scored, view resultView) error {
+	inner := view
+	inner.clusterBy = ""
+	for i, c := range clusterResults(out, view) {
+		if i > 0 {
+			if _, err := fmt.Fprintln(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "== %s (%d)\n", c.label, len(c.items)); err != nil {
+			return err
+		}
+		if err := renderResults(cmd, c.items, inner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clusterResults groups scored results, keeping score order within each group.
+// Author groups list a work under each of its (matching) authors and are ordered by
+// size then name; years run newest first; types are alphabetical. Groups for
+// missing values come last.
+func clusterResults(out []scored, view resultView) []cluster {
+	byLabel := map[string]*cluster{}
+	var order []*cluster
+	add := func(label string, it scored) {
+		c, ok := byLabel[label]
+		if !ok {
+			c = &cluster{label: label}
+			byLabel[label] = c
+			order = append(order, c)
+		}
+		c.items = append(c.items, it)
+	}
+	for _, it := range out {
+		switch view.clusterBy {
+		case clusterAuthor:
+			seen := map[string]bool{}
+			for _, a := range it.e.APA7.Authors {
+				label := authorLabel(a)
+				if label == "" || seen[label] || (view.authorMatch != nil && !view.authorMatch(a)) {
+					continue
+				}
+				seen[label] = true
+				add(label, it)
+			}
+			if len(seen) == 0 && view.authorMatch == nil {
+				add(noAuthorLabel, it)
+			}
+		case clusterYear:
+			add(yearLabel(it.e), it)
+		case clusterType:
+			add(it.e.Type, it)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if missing(a.label) != missing(b.label) {
+			return !missing(a.label)
+		}
+		switch view.clusterBy {
+		case clusterAuthor:
+			if len(a.items) != len(b.items) {
+				return len(a.items) > len(b.items)
+			}
+			return strings.ToLower(a.label) < strings.ToLower(b.label)
+		case clusterYear:
+			return a.label > b.label
+		}
+		return a.label < b.label
+	})
+	clusters := make([]cluster, len(order))
+	for i, c := range order {
+		clusters[i] = *c
+	}
+	return clusters
+}
+
+func missing(label string) bool { return label == noAuthorLabel || label == noYearLabel }
+
+// yearLabel returns the entry's publication year, or noYearLabel.
+func yearLabel(e schema.Entry) string {
+	if e.APA7.Year != nil && *e.APA7.Year > 0 {
+		return strconv.Itoa(*e.APA7.Year)
+	}
+	if y := dates.ExtractYear(e.APA7.Date); y > 0 {
+		return strconv.Itoa(y)
+	}
+	return noYearLabel
+}
+
+// flagAuthorMatcher builds an author filter from --author/--authors-any so author
+// clusters only head the authors that were searched for; nil when neither is set.
+func flagAuthorMatcher(authorQ, authorsAnyQ string, fuzzy bool) func(schema.Author) bool {
+	q := strings.ToLower(strings.TrimSpace(authorQ))
+	var pats []*regexp.Regexp
+	for _, p := range strings.Split(authorsAnyQ, ";") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			pats = append(pats, WildcardToRegex(p))
+		}
+	}
+	if q == "" && len(pats) == 0 {
+		return nil
+	}
+	return func(a schema.Author) bool {
+		fam := strings.ToLower(strings.TrimSpace(a.Family))
+		name := strings.ToLower(strings.TrimSpace(a.Family + ", " + a.Given))
+		if q != "" && (strings.Contains(name, q) || (fuzzy && fuzzyNameMatch(q, name))) {
+			return true
+		}
+		for _, rx := range pats {
+			if rx.MatchString(fam) || rx.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var reAuthorEqualsTerm = regexp.MustCompile(`(?i)^author\s*==\s*([^\s]+)$`)
+
+// exprAuthorMatcher builds an author filter from the author==pattern terms of a search
+// expression, matching the way compileAuthorEqualsTerm does; nil when there are none.
+func exprAuthorMatcher(expr string, fuzzy bool) func(schema.Author) bool {
+	var raw []string
+	var pats []*regexp.Regexp
+	for _, tt := range exprTerms(expr) {
+		if m := reAuthorEqualsTerm.FindStringSubmatch(tt); m != nil {
+			raw = append(raw, strings.ToLower(strings.TrimSpace(m[1])))
+			pats = append(pats, WildcardToRegex(raw[len(raw)-1]))
+		}
+	}
+	if len(pats) == 0 {
+		return nil
+	}
+	return func(a schema.Author) bool {
+		name := strings.ToLower(strings.TrimSpace(a.Family))
+		if a.Given != "" {
+			name += ", " + strings.ToLower(strings.TrimSpace(a.Given))
+		}
+		for i, rx := range pats {
+			if rx.MatchString(name) || (fuzzy && fuzzyNameMatch(raw[i], name)) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,247 @@
+package searchcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// Expression grammar (|| binds looser than &&, ! binds tightest):
+//
+//	expr    = and { "||" and }
+//	and     = primary { "&&" primary }
+//	primary = "!" primary | "(" expr ")" | term
+//
+// A matching && sums its terms' scores; a matching || sums the scores of the
+// branches that matched; a negation matches when its operand fails and scores zero.
+
+// exprToken is an operator ("&&", "||", "!", "(", ")") or a term.
+type exprToken struct {
+	op   string
+	term string
+}
+
+// tokenizeExpr splits an expression into operators and terms. Parentheses only group
+// when they start a term or close a group; inside a term (e.g. title~="a (b)") and
+// inside quotes they are literal.
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	var toks []exprToken
+	var cur strings.Builder
+	depth, literal := 0, 0
+	var quote byte
+	flush := func() {
+		if t := strings.TrimSpace(cur.String()); t != "" {
+			toks = append(toks, exprToken{term: t})
+		}
+		cur.Reset()
+		literal = 0
+	}
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			cur.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote = c
+			cur.WriteByte(c)
+		case (c == '&' || c == '|') && i+1 < len(expr) && expr[i+1] == c:
+			flush()
+			toks = append(toks, exprToken{op: expr[i : i+2]})
+			i++
+		case c == '!' && strings.TrimSpace(cur.String()) == "":
+			toks = append(toks, exprToken{op: "!"})
+		case c == '(' && strings.TrimSpace(cur.String()) == "":
+			toks = append(toks, exprToken{op: "("})
+			depth++
+		case c == '(':
+			literal++
+			cur.WriteByte(c)
+		case c == ')' && literal > 0:
+			literal--
+			cur.WriteByte(c)
+		case c == ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("unbalanced parentheses: unexpected ')'")
+			}
+			flush()
+			toks = append(toks, exprToken{op: ")"})
+			depth--
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in expression")
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("unbalanced parentheses: missing ')'")
+	}
+	flush()
+	return toks, nil
+}
+
+// exprTerms returns the non-negated terms of an expression in order, ignoring its
+// structure otherwise.
+func exprTerms(expr string) []string {
+	toks, err := tokenizeExpr(expr)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for i, t := range toks {
+		if t.op == "" && (i == 0 || toks[i-1].op != "!") {
+			out = append(out, t.term)
+		}
+	}
+	return out
+}
+
+// parseExpr compiles a search expression into a single predicate; fuzzy is passed
+// to compileTerm for every term.
+func parseExpr(expr string, fuzzy bool) (predicate, error) {
+	return parseExprIndexed(expr, fuzzy, nil)
+}
+
+// parseExprIndexed is parseExpr with title/summary/all ~= and keyword== terms first
+// narrowed through the full-text index ix (when non-nil); results are unchanged.
+func parseExprIndexed(expr string, fuzzy bool, ix store.FullTextIndex) (predicate, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	toks, err := tokenizeExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks, fuzzy: fuzzy, index: ix}
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q in expression", p.toks[p.pos].String())
+	}
+	return pred, nil
+}
+
+type exprParser struct {
+	toks  []exprToken
+	pos   int
+	fuzzy bool
+	index store.FullTextIndex
+}
+
+func (t exprToken) String() string {
+	if t.op != "" {
+		return t.op
+	}
+	return t.term
+}
+
+// accept consumes the next token when it is the operator op.
+func (p *exprParser) accept(op string) bool {
+	if p.pos < len(p.toks) && p.toks[p.pos].op == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (predicate, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	branches := []predicate{first}
+	for p.accept("||") {
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, next)
+	}
+	if len(branches) == 1 {
+		return first, nil
+	}
+	return func(e schema.Entry) (bool, int) {
+		hit, score := false, 0
+		for _, b := range branches {
+			if ok, s := b(e); ok {
+				hit = true
+				score += s
+			}
+		}
+		return hit, score
+	}, nil
+}
+
+func (p *exprParser) parseAnd() (predicate, error) {
+	first, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []predicate{first}
+	for p.accept("&&") {
+		next, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, next)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return func(e schema.Entry) (bool, int) {
+		score := 0
+		for _, t := range terms {
+			ok, s := t(e)
+			if !ok {
+				return false, 0
+			}
+			score += s
+		}
+		return true, score
+	}, nil
+}
+
+func (p *exprParser) parsePrimary() (predicate, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("expression ends where a term was expected")
+	}
+	t := p.toks[p.pos]
+	switch t.op {
+	case "!":
+		p.pos++
+		inner, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e schema.Entry) (bool, int) {
+			ok, _ := inner(e)
+			return !ok, 0
+		}, nil
+	case "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("unbalanced parentheses: missing ')'")
+		}
+		return inner, nil
+	case "":
+		p.pos++
+		pred, err := compileTerm(t.term, p.fuzzy)
+		if err != nil {
+			return nil, err
+		}
+		return narrowTerm(pred, t.term, p.index), nil
+	default:
+		return nil, fmt.Errorf("expected a term before %q", t.op)
+	}
+}
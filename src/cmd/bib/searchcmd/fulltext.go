@@ -0,0 +1,76 @@
+package searchcmd
+
+import (
+	"regexp"
+	"strings"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+var (
+	reContainsTerm = regexp.MustCompile(`(?i)^(?:title|summary|all)\s*~=\s*(.+)$`)
+	reKeywordTerm  = regexp.MustCompile(`(?i)^keyword\s*==\s*(.+)$`)
+)
+
+// indexQuery returns the text a title/summary/all ~= or keyword== term looks for,
+// or false for terms the full-text index cannot narrow.
+func indexQuery(term string) (string, bool) {
+	if m := reContainsTerm.FindStringSubmatch(term); m != nil {
+		return trimQuotes(m[1]), true
+	}
+	if m := reKeywordTerm.FindStringSubmatch(term); m != nil {
+		return strings.Join(splitCSV(m[1]), " "), true
+	}
+	return "", false
+}
+
+// narrowTerm wraps pred so entries the index rules out fail without being scored.
+// The index only yields candidates; pred still decides matches and scores.
+func narrowTerm(pred predicate, term string, ix store.FullTextIndex) predicate {
+	if ix == nil {
+		return pred
+	}
+	q, ok := indexQuery(term)
+	if !ok {
+		return pred
+	}
+	cands, ok := ix.Candidates(q)
+	if !ok {
+		return pred
+	}
+	return func(e schema.Entry) (bool, int) {
+		if !cands[strings.ToLower(e.ID)] {
+			return false, 0
+		}
+		return pred(e)
+	}
+}
+
+// narrowEntries keeps the entries that may match every non-empty query (each flag is
+// an AND constraint), using the index; it returns entries unchanged without one.
+func narrowEntries(entries []schema.Entry, ix store.FullTextIndex, queries ...string) []schema.Entry {
+	if ix == nil {
+		return entries
+	}
+	for _, q := range queries {
+		if isEmpty(q) {
+			continue
+		}
+		cands, ok := ix.Candidates(q)
+		if !ok {
+			continue
+		}
+		kept := entries[:0:0]
+		for _, e := range entries {
+			if cands[strings.ToLower(e.ID)] {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+	return entries
+}
+
+// keywordQuery turns a --keyword list into index query text.
+func keywordQuery(kwCSV string) string { return strings.Join(splitCSV(kwCSV), " ") }
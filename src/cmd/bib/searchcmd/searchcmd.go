@@ -8,17 +8,24 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/spf13/cobra"
 
+	"bibliography/src/internal/complete"
 	"bibliography/src/internal/schema"
 	"bibliography/src/internal/store"
+	"bibliography/src/internal/stringsx"
 )
 
 // New returns the search command for keyword and expression-based querying.
 func New() *cobra.Command {
-	var keywords, authorQ, titleQ, summaryQ, allQ string
-	var showID bool
+	var keywords, authorQ, authorsAnyQ, titleQ, summaryQ, allQ, regexAllQ, fuzzyTitleQ string
+	var modifiedSince, verifiedSince, typeFilter string
+	var clusterBy string
+	var limit, offset, sinceYear, untilYear int
+	var showID, pathOnly, asJSON, fuzzy bool
 	cmd := &cobra.Command{
 		Use:   "search [expr]",
 		Short: "Search citations by keyword/author/title/summary or full record (expr or flags)",
@@ -28,24 +35,104 @@ func New() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if limit < 0 || offset < 0 {
+				return fmt.Errorf("--limit and --offset must not be negative")
+			}
+			view := resultView{kind: viewTable, limit: limit, offset: offset}
+			if showID {
+				view.kind = viewIDs
+			}
+			if pathOnly {
+				view.kind = viewPaths
+			}
+			if asJSON {
+				if showID || pathOnly || clusterBy != "" {
+					return fmt.Errorf("--json cannot be combined with --showId, --path-only, or --cluster-by")
+				}
+				view.kind = viewJSON
+			}
+			switch clusterBy {
+			case "", clusterAuthor, clusterYear, clusterType:
+				view.clusterBy = clusterBy
+			default:
+				return fmt.Errorf("invalid --cluster-by %q (want author, year, or type)", clusterBy)
+			}
+			view.authorMatch = flagAuthorMatcher(authorQ, authorsAnyQ, fuzzy)
+			filtered := !isEmpty(modifiedSince) || !isEmpty(verifiedSince)
+			if filtered {
+				if entries, err = filterByTimes(entries, modifiedSince, verifiedSince); err != nil {
+					return err
+				}
+			}
+			if sinceYear != 0 || untilYear != 0 {
+				if entries, err = filterByYear(entries, sinceYear, untilYear); err != nil {
+					return err
+				}
+				filtered = true
+			}
+			if !isEmpty(typeFilter) {
+				types, err := parseTypes(typeFilter)
+				if err != nil {
+					return err
+				}
+				entries = filterByType(entries, types)
+				filtered = true
+			}
+			if !isEmpty(fuzzyTitleQ) {
+				return runFuzzyTitleSearch(cmd, entries, fuzzyTitleQ, view)
+			}
+			// A current data/metadata/fulltext.json narrows text terms; without one,
+			// every entry is scanned.
+			ix, err := store.ReadFullTextIndex()
+			if err != nil {
+				return err
+			}
 			if len(args) > 0 {
-				return runExprSearch(cmd, entries, strings.Join(args, " "), showID)
+				return runExprSearch(cmd, entries, strings.Join(args, " "), fuzzy, ix, view)
 			}
-			if isEmpty(authorQ) && isEmpty(titleQ) && isEmpty(summaryQ) && isEmpty(allQ) {
+			var rx *regexp.Regexp
+			if !isEmpty(regexAllQ) {
+				if rx, err = regexp.Compile(regexAllQ); err != nil {
+					return fmt.Errorf("invalid --regex-all pattern %q: %v", regexAllQ, err)
+				}
+			}
+			if isEmpty(authorQ) && isEmpty(authorsAnyQ) && isEmpty(titleQ) && isEmpty(summaryQ) && isEmpty(allQ) && rx == nil {
+				if isEmpty(keywords) && filtered {
+					return runTimeOnlySearch(cmd, entries, view)
+				}
 				if isEmpty(keywords) {
-					return fmt.Errorf("provide an expression, --keyword, or a query flag like --all, --author, --title, or --summary")
+					return fmt.Errorf("provide an expression, --keyword, or a query flag like --all, --regex-all, --author, --authors-any, --title, or --summary")
 				}
-				return runKeywordOnlySearch(cmd, entries, keywords, showID)
+				return runKeywordOnlySearch(cmd, narrowEntries(entries, ix, keywordQuery(keywords)), keywords, view)
 			}
-			return runFlagSearch(cmd, entries, keywords, authorQ, titleQ, summaryQ, allQ, showID)
+			entries = narrowEntries(entries, ix, keywordQuery(keywords), titleQ, summaryQ, allQ)
+			return runFlagSearch(cmd, entries, keywords, authorQ, authorsAnyQ, titleQ, summaryQ, allQ, rx, fuzzy, view)
 		},
 	}
 	cmd.Flags().StringVar(&keywords, "keyword", "", "comma-delimited keywords (AND filter; boosts relevance)")
+	_ = cmd.RegisterFlagCompletionFunc("keyword", complete.Keywords)
 	cmd.Flags().StringVar(&authorQ, "author", "", "author search (matches family,given)")
+	cmd.Flags().StringVar(&authorsAnyQ, "authors-any", "", "semicolon-separated author patterns (wildcards ok); matches entries with any of them")
 	cmd.Flags().StringVar(&titleQ, "title", "", "title full-text search")
+	cmd.Flags().BoolVar(&fuzzy, "fuzzy", false, "typo-tolerant author matching for --author and author== (up to two edits per name word)")
+	cmd.Flags().StringVar(&fuzzyTitleQ, "fuzzy-title", "", "typo-tolerant title search ranked by token overlap and edit-distance similarity")
 	cmd.Flags().StringVar(&summaryQ, "summary", "", "summary full-text search")
 	cmd.Flags().StringVar(&allQ, "all", "", "full-record search (YAML)")
+	cmd.Flags().StringVar(&regexAllQ, "regex-all", "", "regular expression matched against the full serialized record (ranked by match count)")
+	cmd.Flags().StringVar(&modifiedSince, "modified-since", "", "only entries modified on/after this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().StringVar(&verifiedSince, "verified-since", "", "only entries verified on/after this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().IntVar(&sinceYear, "since", 0, "only entries published in or after this year (YYYY)")
+	cmd.Flags().IntVar(&untilYear, "until", 0, "only entries published in or before this year (YYYY)")
+	cmd.Flags().StringVar(&typeFilter, "type", "", "only entries of these types (comma-separated, e.g. article,book)")
+	_ = cmd.RegisterFlagCompletionFunc("type", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return schema.Types, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().BoolVar(&showID, "showId", false, "Print only matching IDs (one per line)")
+	cmd.Flags().StringVar(&clusterBy, "cluster-by", "", "Group results under author, year, or type headings")
+	cmd.Flags().BoolVar(&pathOnly, "path-only", false, "Print only the storage path of each match (one per line, ranked)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Show at most N results after ranking (0 = unlimited)")
+	cmd.Flags().IntVar(&offset, "offset", 0, "Skip the first N ranked results")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print matches as a JSON array (id, type, title, authors, year, score) in ranked order")
 	return cmd
 }
 
@@ -56,77 +143,404 @@ type scored struct {
 	s int
 }
 
-func runExprSearch(cmd *cobra.Command, entries []schema.Entry, expr string, showOnlyID bool) error {
-	preds, err := parseExpr(expr)
+func runExprSearch(cmd *cobra.Command, entries []schema.Entry, expr string, fuzzy bool, ix store.FullTextIndex, view resultView) error {
+	pred, err := parseExprIndexed(expr, fuzzy, ix)
 	if err != nil {
 		return err
 	}
+	if m := exprAuthorMatcher(expr, fuzzy); m != nil {
+		view.authorMatch = m
+	}
 	var out []scored
 	for _, e := range entries {
-		score := 0
-		ok := true
-		for _, p := range preds {
-			hit, sc := p(e)
-			if !hit {
-				ok = false
-				break
-			}
-			score += sc
-		}
-		if ok {
+		if ok, score := pred(e); ok {
 			out = append(out, scored{e: e, s: score})
 		}
 	}
 	sort.Slice(out, func(i, j int) bool { return out[i].s > out[j].s })
-	renderResults(cmd, out, showOnlyID)
-	return nil
+	return renderResults(cmd, out, view)
+}
+
+// runTimeOnlySearch lists the already filtered entries (by time, year, or type), most
+// recently modified first.
+func runTimeOnlySearch(cmd *cobra.Command, entries []schema.Entry, view resultView) error {
+	times, err := store.ReadRecordTimes()
+	if err != nil {
+		return err
+	}
+	out := make([]scored, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, scored{e: e})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return times[strings.ToLower(out[i].e.ID)].Modified.After(times[strings.ToLower(out[j].e.ID)].Modified)
+	})
+	return renderResults(cmd, out, view)
+}
+
+// filterByTimes keeps entries whose modified/verified timestamps are on or after the
+// given dates; entries lacking a timestamp are excluded when its filter is set.
+func filterByTimes(entries []schema.Entry, modifiedSince, verifiedSince string) ([]schema.Entry, error) {
+	mod, err := parseSince("--modified-since", modifiedSince)
+	if err != nil {
+		return nil, err
+	}
+	ver, err := parseSince("--verified-since", verifiedSince)
+	if err != nil {
+		return nil, err
+	}
+	times, err := store.ReadRecordTimes()
+	if err != nil {
+		return nil, err
+	}
+	var out []schema.Entry
+	for _, e := range entries {
+		rt := times[strings.ToLower(e.ID)]
+		if !mod.IsZero() && (rt.Modified.IsZero() || rt.Modified.Before(mod)) {
+			continue
+		}
+		if !ver.IsZero() && (rt.Verified.IsZero() || rt.Verified.Before(ver)) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
 }
 
-func runKeywordOnlySearch(cmd *cobra.Command, entries []schema.Entry, keywords string, showOnlyID bool) error {
+// filterByYear keeps entries published within [since, until] (either bound may be 0
+// for open); entries without a year are excluded.
+func filterByYear(entries []schema.Entry, since, until int) ([]schema.Entry, error) {
+	for _, b := range []struct {
+		flag string
+		v    int
+	}{{"--since", since}, {"--until", until}} {
+		if b.v != 0 && (b.v < 1000 || b.v > 9999) {
+			return nil, fmt.Errorf("invalid %s %d (want YYYY)", b.flag, b.v)
+		}
+	}
+	if since != 0 && until != 0 && since > until {
+		return nil, fmt.Errorf("--since %d is after --until %d", since, until)
+	}
+	var out []schema.Entry
+	for _, e := range entries {
+		y := store.PublicationYear(e)
+		if y == 0 || (since != 0 && y < since) || (until != 0 && y > until) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// parseTypes splits a comma-separated --type value into a set of known entry types
+// (case-insensitive).
+func parseTypes(s string) (map[string]bool, error) {
+	types := map[string]bool{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if !schema.IsValidType(t) {
+			return nil, fmt.Errorf("invalid --type %q (want one of %s)", t, strings.Join(schema.Types, ", "))
+		}
+		types[t] = true
+	}
+	return types, nil
+}
+
+// filterByType keeps entries whose type is in types.
+func filterByType(entries []schema.Entry, types map[string]bool) []schema.Entry {
+	var out []schema.Entry
+	for _, e := range entries {
+		if types[strings.ToLower(e.Type)] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// parseSince parses a YYYY-MM-DD or RFC3339 value; empty yields the zero time.
+func parseSince(flag, v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q (want YYYY-MM-DD or RFC3339)", flag, v)
+}
+
+func runKeywordOnlySearch(cmd *cobra.Command, entries []schema.Entry, keywords string, view resultView) error {
 	var out []scored
 	for _, e := range entries {
-		s := scoreEntry(e, keywords, "", "", "", "")
+		s := scoreEntry(e, keywords, "", "", "", "", "", nil, false)
 		if s > 0 {
 			out = append(out, scored{e: e, s: s})
 		}
 	}
 	sort.Slice(out, func(i, j int) bool { return out[i].s > out[j].s })
-	renderResults(cmd, out, showOnlyID)
-	return nil
+	return renderResults(cmd, out, view)
 }
 
-func runFlagSearch(cmd *cobra.Command, entries []schema.Entry, keywords, authorQ, titleQ, summaryQ, allQ string, showOnlyID bool) error {
+func runFlagSearch(cmd *cobra.Command, entries []schema.Entry, keywords, authorQ, authorsAnyQ, titleQ, summaryQ, allQ string, rx *regexp.Regexp, fuzzy bool, view resultView) error {
 	var out []scored
 	for _, e := range entries {
-		s := scoreEntry(e, keywords, authorQ, titleQ, summaryQ, allQ)
+		s := scoreEntry(e, keywords, authorQ, authorsAnyQ, titleQ, summaryQ, allQ, rx, fuzzy)
 		if s > 0 {
 			out = append(out, scored{e: e, s: s})
 		}
 	}
 	sort.Slice(out, func(i, j int) bool { return out[i].s > out[j].s })
-	renderResults(cmd, out, showOnlyID)
-	return nil
+	return renderResults(cmd, out, view)
 }
 
-func renderResults(cmd *cobra.Command, out []scored, showOnlyID bool) {
-	if showOnlyID {
+const (
+	// maxFuzzyCandidates caps how many entries get the full-title edit-distance pass.
+	maxFuzzyCandidates = 500
+	// minFuzzyScore is the lowest combined similarity reported as a match.
+	minFuzzyScore = 0.4
+	// fuzzyTokenMatch is the per-token similarity at which two words count as the same
+	// (a transposed pair in a five-letter word scores 0.6).
+	fuzzyTokenMatch = 0.6
+	// maxAuthorEdits is the largest per-word edit distance --fuzzy accepts between an
+	// author query and a name.
+	maxAuthorEdits = 2
+)
+
+// runFuzzyTitleSearch ranks entries by fuzzyTitleScore. A cheap token-overlap pass
+// (which skips word pairs whose lengths alone rule out a match) drops titles sharing no
+// word with the query and keeps at most maxFuzzyCandidates for the full-title
+// Levenshtein pass.
+func runFuzzyTitleSearch(cmd *cobra.Command, entries []schema.Entry, q string, view resultView) error {
+	qTokens := fuzzyTokens(q)
+	if len(qTokens) == 0 {
+		return fmt.Errorf("--fuzzy-title needs at least one word")
+	}
+	type candidate struct {
+		e       schema.Entry
+		overlap float64
+	}
+	cands := make([]candidate, 0, len(entries))
+	for _, e := range entries {
+		// Without a shared word the score stays below minFuzzyScore (0.4 * full < 0.4).
+		if o := tokenOverlap(qTokens, fuzzyTokens(e.APA7.Title)); o > 0 {
+			cands = append(cands, candidate{e: e, overlap: o})
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].overlap > cands[j].overlap })
+	if len(cands) > maxFuzzyCandidates {
+		cands = cands[:maxFuzzyCandidates]
+	}
+	normQ := strings.Join(qTokens, " ")
+	var out []scored
+	for _, c := range cands {
+		full := stringsx.Similarity(normQ, strings.Join(fuzzyTokens(c.e.APA7.Title), " "))
+		if score := 0.6*c.overlap + 0.4*full; score >= minFuzzyScore {
+			out = append(out, scored{e: c.e, s: int(score * 100)})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].s > out[j].s })
+	return renderResults(cmd, out, view)
+}
+
+// fuzzyTokens lowercases s and splits it into letter/digit words.
+func fuzzyTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+// authorEditBudget scales maxAuthorEdits down for short words, so "li" does not match
+// every short name: no edits up to two letters, one edit up to four.
+func authorEditBudget(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 2:
+		return 0
+	case n <= 4:
+		return 1
+	}
+	return maxAuthorEdits
+}
+
+// fuzzyNameMatch reports whether every word of q is within its edit budget of some
+// word of name, so "Gerhard" finds "Gerhards, R." and "Smtih" finds "Smith, J.".
+// Wildcards and punctuation in q are ignored.
+func fuzzyNameMatch(q, name string) bool {
+	qt, nt := fuzzyTokens(q), fuzzyTokens(name)
+	if len(qt) == 0 {
+		return false
+	}
+	for _, w := range qt {
+		found := false
+		for _, n := range nt {
+			if stringsx.Levenshtein(w, n) <= authorEditBudget(w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenOverlap returns the fraction of query tokens that approximately match some
+// title token, so word order does not matter and small typos still count. Words
+// shorter than three letters must match exactly.
+func tokenOverlap(q, title []string) float64 {
+	if len(q) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, qt := range q {
+		for _, tt := range title {
+			if qt == tt || (len(qt) >= 3 && lengthsCanMatch(qt, tt) && stringsx.Similarity(qt, tt) >= fuzzyTokenMatch) {
+				matched++
+				break
+			}
+		}
+	}
+	return float64(matched) / float64(len(q))
+}
+
+// lengthsCanMatch reports whether a and b are close enough in length to reach
+// fuzzyTokenMatch: the edit distance is at least the length difference, so a larger
+// difference rules the pair out without computing it.
+func lengthsCanMatch(a, b string) bool {
+	la, lb := len([]rune(a)), len([]rune(b))
+	diff := la - lb
+	if diff < 0 {
+		diff = -diff
+	}
+	return 1-float64(diff)/float64(max(la, lb)) >= fuzzyTokenMatch
+}
+
+// viewKind selects how each search result is printed.
+type viewKind int
+
+const (
+	viewTable viewKind = iota
+	viewIDs
+	viewPaths
+	viewJSON
+)
+
+// resultView controls result rendering: the per-result view, an optional grouping,
+// (for author clusters) which authors get a heading, and the page of ranked results.
+type resultView struct {
+	kind        viewKind
+	clusterBy   string
+	authorMatch func(schema.Author) bool
+	limit       int
+	offset      int
+}
+
+func renderResults(cmd *cobra.Command, out []scored, view resultView) error {
+	if view.limit > 0 || view.offset > 0 {
+		return renderPage(cmd, out, view)
+	}
+	if view.clusterBy != "" {
+		return renderClusters(cmd, out, view)
+	}
+	switch view.kind {
+	case viewIDs:
 		for _, it := range out {
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), it.e.ID)
 		}
-		return
+		return nil
+	case viewPaths:
+		entries := make([]schema.Entry, 0, len(out))
+		for _, it := range out {
+			entries = append(entries, it.e)
+		}
+		paths, err := store.EntryPaths(entries)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), paths[strings.ToLower(e.ID)])
+		}
+		return nil
+	case viewJSON:
+		return renderJSON(cmd.OutOrStdout(), out)
 	}
 	rows := make([][]string, 0, len(out))
 	for _, it := range out {
 		rows = append(rows, []string{it.e.ID, it.e.Type, it.e.APA7.Title, firstAuthor(it.e)})
 	}
 	renderTable(cmd.OutOrStdout(), []string{"id", "type", "title", "author"}, rows)
+	return nil
+}
+
+// jsonResult is one search match in --json output.
+type jsonResult struct {
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Title   string   `json:"title"`
+	Authors []string `json:"authors"`
+	Year    *int     `json:"year,omitempty"`
+	Score   int      `json:"score"`
+}
+
+// renderJSON writes the ranked matches as a JSON array ("[]" when there are none).
+func renderJSON(w io.Writer, out []scored) error {
+	results := make([]jsonResult, 0, len(out))
+	for _, it := range out {
+		authors := make([]string, 0, len(it.e.APA7.Authors))
+		for _, a := range it.e.APA7.Authors {
+			authors = append(authors, authorLabel(a))
+		}
+		results = append(results, jsonResult{ID: it.e.ID, Type: it.e.Type, Title: it.e.APA7.Title, Authors: authors, Year: it.e.APA7.Year, Score: it.s})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// renderPage renders results[offset:offset+limit] and, when that is not everything,
+// reports "showing N of TOTAL". The note goes to stderr for the one-per-line and JSON
+// views so pipelines only see results.
+func renderPage(cmd *cobra.Command, out []scored, view resultView) error {
+	total := len(out)
+	start := min(view.offset, total)
+	end := total
+	if view.limit > 0 {
+		end = min(start+view.limit, total)
+	}
+	page := out[start:end]
+	inner := view
+	inner.limit, inner.offset = 0, 0
+	if err := renderResults(cmd, page, inner); err != nil {
+		return err
+	}
+	if len(page) == total {
+		return nil
+	}
+	w := cmd.OutOrStdout()
+	if view.kind != viewTable {
+		w = cmd.ErrOrStderr()
+	}
+	note := fmt.Sprintf("showing %d of %d", len(page), total)
+	if start > 0 {
+		note += fmt.Sprintf(" (from #%d)", start+1)
+	}
+	_, err := fmt.Fprintln(w, note)
+	return err
 }
 
 func firstAuthor(e schema.Entry) string {
 	if len(e.APA7.Authors) == 0 {
 		return ""
 	}
-	a := e.APA7.Authors[0]
+	return authorLabel(e.APA7.Authors[0])
+}
+
+// authorLabel formats an author as "Family, Given" (or whichever part is present).
+func authorLabel(a schema.Author) string {
 	fam := strings.TrimSpace(a.Family)
 	giv := strings.TrimSpace(a.Given)
 	if fam == "" {
@@ -140,40 +554,19 @@ func firstAuthor(e schema.Entry) string {
 
 type predicate func(schema.Entry) (hit bool, score int)
 
-func parseExpr(expr string) ([]predicate, error) {
-	if strings.TrimSpace(expr) == "" {
-		return nil, fmt.Errorf("empty expression")
-	}
-	terms := splitAnd(expr)
-	var preds []predicate
-	for _, tt := range terms {
-		if p, ok, err := compileKeywordTerm(tt); err != nil {
-			return nil, err
-		} else if ok {
-			preds = append(preds, p)
-			continue
-		}
-		if p, ok, err := compileAuthorEqualsTerm(tt); err != nil {
+// compileTerm compiles a single comparison (keyword==, author==, year/date
+// comparisons, decade==, or title/summary/all ~=) into a predicate. With fuzzy, author==
+// also accepts names within a small edit distance of the pattern.
+func compileTerm(tt string, fuzzy bool) (predicate, error) {
+	authorTerm := func(tt string) (predicate, bool, error) { return compileAuthorEqualsTerm(tt, fuzzy) }
+	for _, compile := range []func(string) (predicate, bool, error){compileKeywordTerm, authorTerm, compileDateCompareTerm, compileDecadeTerm, compileContainsTerm} {
+		if p, ok, err := compile(tt); err != nil {
 			return nil, err
 		} else if ok {
-			preds = append(preds, p)
-			continue
+			return p, nil
 		}
-		if p, ok, err := compileDateCompareTerm(tt); err != nil {
-			return nil, err
-		} else if ok {
-			preds = append(preds, p)
-			continue
-		}
-		if p, ok, err := compileContainsTerm(tt); err != nil {
-			return nil, err
-		} else if ok {
-			preds = append(preds, p)
-			continue
-		}
-		return nil, fmt.Errorf("unrecognized term: %s", tt)
 	}
-	return preds, nil
+	return nil, fmt.Errorf("unrecognized term: %s", tt)
 }
 
 func compileKeywordTerm(tt string) (predicate, bool, error) {
@@ -211,8 +604,10 @@ func compileKeywordTerm(tt string) (predicate, bool, error) {
 	return p, true, nil
 }
 
-func compileAuthorEqualsTerm(tt string) (predicate, bool, error) {
-	m := regexp.MustCompile(`(?i)^author\s*==\s*([^\s]+)$`).FindStringSubmatch(tt)
+// compileAuthorEqualsTerm matches author==pattern against "family, given" (wildcards
+// ok), scoring 7; in fuzzy mode a near miss (fuzzyNameMatch) still matches, scoring 5.
+func compileAuthorEqualsTerm(tt string, fuzzy bool) (predicate, bool, error) {
+	m := reAuthorEqualsTerm.FindStringSubmatch(tt)
 	if m == nil {
 		return nil, false, nil
 	}
@@ -228,6 +623,37 @@ func compileAuthorEqualsTerm(tt string) (predicate, bool, error) {
 				return true, 7
 			}
 		}
+		if fuzzy {
+			for _, a := range e.APA7.Authors {
+				if fuzzyNameMatch(pat, a.Family+", "+a.Given) {
+					return true, 5
+				}
+			}
+		}
+		return false, 0
+	}
+	return p, true, nil
+}
+
+// compileDecadeTerm compiles decade==1990s (the trailing "s" is optional), matching
+// entries published in that decade, as bucketed by the decades index.
+func compileDecadeTerm(tt string) (predicate, bool, error) {
+	m := regexp.MustCompile(`(?i)^decade\s*==\s*(\d{4})s?$`).FindStringSubmatch(tt)
+	if m == nil {
+		if regexp.MustCompile(`(?i)^decade\s*==`).MatchString(tt) {
+			return nil, false, fmt.Errorf("invalid decade term %q (want e.g. decade==1990s)", tt)
+		}
+		return nil, false, nil
+	}
+	y, _ := strconv.Atoi(m[1])
+	if y%10 != 0 {
+		return nil, false, fmt.Errorf("invalid decade %q (want a year ending in 0, e.g. 1990s)", m[1])
+	}
+	want := store.Decade(y)
+	p := func(e schema.Entry) (bool, int) {
+		if store.Decade(store.PublicationYear(e)) == want {
+			return true, 1
+		}
 		return false, 0
 	}
 	return p, true, nil
@@ -241,16 +667,7 @@ func compileDateCompareTerm(tt string) (predicate, bool, error) {
 	op := m[2]
 	yv, _ := strconv.Atoi(m[3])
 	p := func(e schema.Entry) (bool, int) {
-		y := 0
-		if e.APA7.Year != nil {
-			y = *e.APA7.Year
-		} else if len(strings.TrimSpace(e.APA7.Date)) >= 4 {
-			var yy int
-			if _, err := fmt.Sscanf(e.APA7.Date[:4], "%d", &yy); err != nil {
-				return false, 0
-			}
-			y = yy
-		}
+		y := store.PublicationYear(e)
 		if y == 0 {
 			return false, 0
 		}
@@ -309,13 +726,6 @@ func compileContainsTerm(tt string) (predicate, bool, error) {
 	return p, true, nil
 }
 
-func splitAnd(expr string) []string {
-	var parts []string
-	for _, p := range strings.Split(expr, "&&") {
-		parts = append(parts, strings.TrimSpace(p))
-	}
-	return parts
-}
 func splitCSV(s string) []string {
 	parts := strings.Split(s, ",")
 	out := make([]string, 0, len(parts))
@@ -341,14 +751,19 @@ func WildcardToRegex(pat string) *regexp.Regexp {
 	return regexp.MustCompile(rx)
 }
 
-func scoreEntry(e schema.Entry, kwCSV, authorQ, titleQ, summaryQ, allQ string) int {
+func scoreEntry(e schema.Entry, kwCSV, authorQ, authorsAnyQ, titleQ, summaryQ, allQ string, rx *regexp.Regexp, fuzzy bool) int {
 	s := 0
 	if add, ok := scoreKeywords(e, kwCSV); !ok {
 		return 0
 	} else {
 		s += add
 	}
-	if add, ok := scoreAuthor(e, authorQ); !ok {
+	if add, ok := scoreAuthor(e, authorQ, fuzzy); !ok {
+		return 0
+	} else {
+		s += add
+	}
+	if add, ok := scoreAuthorsAny(e, authorsAnyQ); !ok {
 		return 0
 	} else {
 		s += add
@@ -368,6 +783,11 @@ func scoreEntry(e schema.Entry, kwCSV, authorQ, titleQ, summaryQ, allQ string) i
 	} else {
 		s += add
 	}
+	if add, ok := scoreRegexAll(e, rx); !ok {
+		return 0
+	} else {
+		s += add
+	}
 	if s == 0 && strings.TrimSpace(kwCSV) != "" {
 		s = 1
 	}
@@ -396,7 +816,10 @@ func scoreKeywords(e schema.Entry, kwCSV string) (int, bool) {
 	}
 	return s, true
 }
-func scoreAuthor(e schema.Entry, q string) (int, bool) {
+
+// scoreAuthor scores 5 per author whose "family, given" contains q; with fuzzy, an
+// author that only matches through fuzzyNameMatch scores 3.
+func scoreAuthor(e schema.Entry, q string, fuzzy bool) (int, bool) {
 	q = strings.ToLower(strings.TrimSpace(q))
 	if q == "" {
 		return 0, true
@@ -408,6 +831,9 @@ func scoreAuthor(e schema.Entry, q string) (int, bool) {
 		if strings.Contains(name, q) {
 			s += 5
 			hit = true
+		} else if fuzzy && fuzzyNameMatch(q, name) {
+			s += 3
+			hit = true
 		}
 	}
 	if !hit {
@@ -415,6 +841,38 @@ func scoreAuthor(e schema.Entry, q string) (int, bool) {
 	}
 	return s, true
 }
+
+// scoreAuthorsAny matches semicolon-separated wildcard patterns against each author's
+// family name or "family, given", scoring 7 per pattern matched by at least one author.
+func scoreAuthorsAny(e schema.Entry, q string) (int, bool) {
+	var pats []*regexp.Regexp
+	for _, p := range strings.Split(q, ";") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			pats = append(pats, WildcardToRegex(p))
+		}
+	}
+	if len(pats) == 0 {
+		return 0, true
+	}
+	matched := 0
+	for _, rx := range pats {
+		for _, a := range e.APA7.Authors {
+			fam := strings.ToLower(strings.TrimSpace(a.Family))
+			name := fam
+			if a.Given != "" {
+				name += ", " + strings.ToLower(strings.TrimSpace(a.Given))
+			}
+			if rx.MatchString(fam) || rx.MatchString(name) {
+				matched++
+				break
+			}
+		}
+	}
+	if matched == 0 {
+		return 0, false
+	}
+	return matched * 7, true
+}
 func scoreTitle(e schema.Entry, q string) (int, bool) {
 	q = strings.ToLower(strings.TrimSpace(q))
 	if q == "" {
@@ -460,6 +918,19 @@ func scoreAll(e schema.Entry, q string) (int, bool) {
 	return add, true
 }
 
+// scoreRegexAll matches rx against the serialized record, scoring one point per match.
+func scoreRegexAll(e schema.Entry, rx *regexp.Regexp) (int, bool) {
+	if rx == nil {
+		return 0, true
+	}
+	b, _ := json.Marshal(e)
+	add := len(rx.FindAllIndex(b, -1))
+	if add == 0 {
+		return 0, false
+	}
+	return add, true
+}
+
 func CountContains(text, q string) int {
 	if q == "" {
 		return 0
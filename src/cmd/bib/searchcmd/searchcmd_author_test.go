@@ -10,7 +10,7 @@ import (
 func TestAuthorEqualsWildcard(t *testing.T) {
 	y := 2020
 	e := schema.Entry{Type: "article", APA7: schema.APA7{Title: "T", Year: &y, Authors: schema.Authors{{Family: "Doe", Given: "Jane"}}}}
-	p, ok, err := compileAuthorEqualsTerm("author==doe*")
+	p, ok, err := compileAuthorEqualsTerm("author==doe*", false)
 	if err != nil || !ok {
 		t.Fatalf("compile author wildcard: ok=%v err=%v", ok, err)
 	}
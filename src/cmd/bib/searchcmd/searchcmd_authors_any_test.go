@@ -0,0 +1,53 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestScoreAuthorsAny_CountsMatchedTargets(t *testing.T) {
+	e := schema.Entry{APA7: schema.APA7{Authors: schema.Authors{{Family: "Smith", Given: "J."}, {Family: "Jones", Given: "A."}}}}
+	if s, ok := scoreAuthorsAny(e, "smith;jones;lee"); !ok || s != 14 {
+		t.Fatalf("two targets: got %d %v", s, ok)
+	}
+	if s, ok := scoreAuthorsAny(e, "sm*; lee"); !ok || s != 7 {
+		t.Fatalf("wildcard target: got %d %v", s, ok)
+	}
+	if _, ok := scoreAuthorsAny(e, "lee;park"); ok {
+		t.Fatalf("expected no match")
+	}
+	if s, ok := scoreAuthorsAny(e, " ; "); !ok || s != 0 {
+		t.Fatalf("empty query should be neutral: %d %v", s, ok)
+	}
+}
+
+func TestSearch_AuthorsAnyRanksByMatchedAuthors(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	one := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "One", Authors: schema.Authors{{Family: "Lee", Given: "K."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	both := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Both", Authors: schema.Authors{{Family: "Smith", Given: "J."}, {Family: "Lee", Given: "K."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	none := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "None", Authors: schema.Authors{{Family: "Park", Given: "M."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	for _, e := range []schema.Entry{one, both, none} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--authors-any", "Smith;Jones;Lee", "--showId"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	lines := strings.Fields(buf.String())
+	if len(lines) != 2 || lines[0] != both.ID || lines[1] != one.ID {
+		t.Fatalf("unexpected ranking: %v", lines)
+	}
+}
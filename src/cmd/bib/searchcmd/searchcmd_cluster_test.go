@@ -0,0 +1,87 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func clusterEntries() []scored {
+	y := func(v int) *int { return &v }
+	mk := func(id, typ, title string, year *int, authors ...schema.Author) scored {
+		return scored{e: schema.Entry{ID: id, Type: typ, APA7: schema.APA7{Title: title, Year: year, Authors: authors}}}
+	}
+	smith := schema.Author{Family: "Smith", Given: "J."}
+	smythe := schema.Author{Family: "Smithers", Given: "W."}
+	doe := schema.Author{Family: "Doe", Given: "A."}
+	return []scored{
+		mk("a1", "article", "First", y(2020), smith, doe),
+		mk("b1", "book", "Second", y(2021), smith),
+		mk("a2", "article", "Third", y(2020), smythe),
+		mk("w1", "website", "Fourth", nil),
+	}
+}
+
+func labels(cs []cluster) []string {
+	var out []string
+	for _, c := range cs {
+		out = append(out, c.label)
+	}
+	return out
+}
+
+func TestClusterResults_ByAuthorYearType(t *testing.T) {
+	out := clusterEntries()
+	got := labels(clusterResults(out, resultView{clusterBy: clusterAuthor}))
+	if strings.Join(got, "|") != "Smith, J.|Doe, A.|Smithers, W.|(no author)" {
+		t.Fatalf("author clusters: %v", got)
+	}
+	got = labels(clusterResults(out, resultView{clusterBy: clusterYear}))
+	if strings.Join(got, "|") != "2021|2020|n.d." {
+		t.Fatalf("year clusters: %v", got)
+	}
+	got = labels(clusterResults(out, resultView{clusterBy: clusterType}))
+	if strings.Join(got, "|") != "article|book|website" {
+		t.Fatalf("type clusters: %v", got)
+	}
+}
+
+func TestClusterResults_OnlyMatchingAuthors(t *testing.T) {
+	cs := clusterResults(clusterEntries(), resultView{clusterBy: clusterAuthor, authorMatch: exprAuthorMatcher("author==smith*", false)})
+	if got := strings.Join(labels(cs), "|"); got != "Smith, J.|Smithers, W." {
+		t.Fatalf("matching author clusters: %v", got)
+	}
+	if len(cs[0].items) != 2 || cs[0].items[0].e.ID != "a1" || cs[0].items[1].e.ID != "b1" {
+		t.Fatalf("works should stay in score order: %+v", cs[0].items)
+	}
+}
+
+func TestRenderResults_Clustered(t *testing.T) {
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := renderResults(cmd, clusterEntries(), resultView{kind: viewIDs, clusterBy: clusterYear}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "== 2021 (1)\nb1\n\n== 2020 (2)\na1\na2\n\n== n.d. (1)\nw1\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSearch_ClusterByInvalid(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	cmd := New()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"--cluster-by", "venue", "--all", "x"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --cluster-by") {
+		t.Fatalf("expected invalid --cluster-by error, got %v", err)
+	}
+}
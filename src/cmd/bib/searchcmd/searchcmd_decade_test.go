@@ -0,0 +1,53 @@
+package searchcmd
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestDecadeTerm(t *testing.T) {
+	year := func(y int) *int { return &y }
+	entries := map[string]schema.Entry{
+		"1985": {APA7: schema.APA7{Year: year(1985)}},
+		"1990": {APA7: schema.APA7{Year: year(1990)}},
+		"1999": {APA7: schema.APA7{Year: year(1999)}},
+		"2003": {APA7: schema.APA7{Date: "2003-02-01"}},
+		"none": {},
+	}
+	match := func(expr string) string {
+		t.Helper()
+		p, err := parseExpr(expr, false)
+		if err != nil {
+			t.Fatalf("parse %q: %v", expr, err)
+		}
+		var out []string
+		for name, e := range entries {
+			if ok, _ := p(e); ok {
+				out = append(out, name)
+			}
+		}
+		sort.Strings(out)
+		return strings.Join(out, ",")
+	}
+	cases := map[string]string{
+		"decade==1990s":                  "1990,1999",
+		"DECADE == 1980s":                "1985",
+		"decade==2000":                   "2003",
+		"decade==1990s || decade==2000s": "1990,1999,2003",
+		"decade==1990s && !year==1999":   "1990",
+		"decade==1970s":                  "",
+	}
+	for expr, want := range cases {
+		if got := match(expr); got != want {
+			t.Errorf("%s = %q, want %q", expr, got, want)
+		}
+	}
+	for _, bad := range []string{"decade==1995s", "decade==nineties"} {
+		if _, err := parseExpr(bad, false); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
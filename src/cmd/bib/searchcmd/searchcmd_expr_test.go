@@ -0,0 +1,117 @@
+package searchcmd
+
+import (
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func exprEntry(title string, year int, kw ...string) schema.Entry {
+	return schema.Entry{Type: "book", APA7: schema.APA7{Title: title, Year: &year}, Annotation: schema.Annotation{Summary: "s", Keywords: kw}}
+}
+
+func TestParseExprOrAndGrouping(t *testing.T) {
+	goBook := exprEntry("Learning Go", 2020, "go")
+	rustBook := exprEntry("Rust in Action", 2019, "rust")
+	goOld := exprEntry("Go Primer", 2010, "go", "rust")
+	cases := []struct {
+		expr  string
+		entry schema.Entry
+		hit   bool
+		score int
+	}{
+		{"keyword==go || keyword==rust", goBook, true, 5},
+		{"keyword==go || keyword==rust", rustBook, true, 5},
+		{"keyword==go || keyword==rust", goOld, true, 10},
+		// && binds tighter than ||: a || (b && c)
+		{"keyword==rust || keyword==go && year>=2015", goOld, true, 5},
+		{"keyword==rust || keyword==go && year>=2015", goBook, true, 6},
+		{"(keyword==rust || keyword==go) && year>=2015", goOld, false, 0},
+		{"(keyword==rust || keyword==go) && year>=2015", rustBook, true, 6},
+		{"(keyword==go && title~=learning) || keyword==rust", goBook, true, 8},
+		{"(keyword==go && title~=learning) || keyword==rust", goOld, true, 5},
+		{"(keyword==go && title~=learning) || (keyword==rust && year<2015)", rustBook, false, 0},
+	}
+	for _, c := range cases {
+		p, err := parseExpr(c.expr, false)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		if hit, score := p(c.entry); hit != c.hit || score != c.score {
+			t.Errorf("%s on %q: got (%v, %d), want (%v, %d)", c.expr, c.entry.APA7.Title, hit, score, c.hit, c.score)
+		}
+	}
+}
+
+func TestParseExprLiteralParens(t *testing.T) {
+	e := exprEntry("Go (2nd ed.)", 2020, "go")
+	p, err := parseExpr("(title~=go (2nd || keyword==rust)", false)
+	if err != nil {
+		t.Fatalf("parseExpr: %v", err)
+	}
+	if hit, _ := p(e); !hit {
+		t.Fatal("expected literal parenthesis inside a term to match")
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	for expr, want := range map[string]string{
+		"":                              "empty expression",
+		"(keyword==go":                  "missing ')'",
+		"keyword==go)":                  "unexpected ')'",
+		"keyword==go ||":                "term was expected",
+		"|| keyword==go":                "expected a term before \"||\"",
+		"()":                            "expected a term before \")\"",
+		"(keyword==go) keyword==rust":   "unexpected \"keyword==rust\"",
+		"keyword==go || bogus":          "unrecognized term: bogus",
+		"title~=\"open quote":           "unterminated quote",
+		"(keyword==go || keyword==rust": "missing ')'",
+	} {
+		_, err := parseExpr(expr, false)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseExpr(%q) = %v, want error containing %q", expr, err, want)
+		}
+	}
+}
+
+func TestExprTermsForAuthorClusters(t *testing.T) {
+	got := exprTerms("(author==smith* || author==doe*) && year>=2000 && !author==roe*")
+	if strings.Join(got, "|") != "author==smith*|author==doe*|year>=2000" {
+		t.Fatalf("exprTerms: %v", got)
+	}
+}
+
+func TestParseExprNegation(t *testing.T) {
+	smith := exprEntry("Go at Scale", 2022, "go")
+	smith.APA7.Authors = schema.Authors{{Family: "Smith", Given: "A."}}
+	jones := exprEntry("Go Basics", 2018, "go")
+	jones.APA7.Authors = schema.Authors{{Family: "Jones", Given: "B."}}
+	cases := []struct {
+		expr  string
+		entry schema.Entry
+		hit   bool
+		score int
+	}{
+		{"keyword==go && !author==smith*", smith, false, 0},
+		{"keyword==go && !author==smith*", jones, true, 5},
+		{"keyword==go && !title~=basics", jones, false, 0},
+		{"keyword==go && !title~=basics", smith, true, 5},
+		{"keyword==go && !year>2020", jones, true, 5},
+		{"keyword==go && !year>2020", smith, false, 0},
+		{"!(author==smith* || year<2000)", jones, true, 0},
+		{"!!keyword==go", smith, true, 0},
+	}
+	for _, c := range cases {
+		p, err := parseExpr(c.expr, false)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		if hit, score := p(c.entry); hit != c.hit || score != c.score {
+			t.Errorf("%s on %q: got (%v, %d), want (%v, %d)", c.expr, c.entry.APA7.Title, hit, score, c.hit, c.score)
+		}
+	}
+	if _, err := parseExpr("keyword==go && !", false); err == nil {
+		t.Fatal("expected error for dangling !")
+	}
+}
@@ -0,0 +1,111 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func seedFullText(t *testing.T) []schema.Entry {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	entries := []schema.Entry{
+		{ID: "00000000-0000-4000-8000-000000000121", Type: "article", APA7: schema.APA7{Title: "Graph Networks in Practice", Journal: "Journal of Networks"}, Annotation: schema.Annotation{Summary: "Applies graph theory to routing.\nLine two of the summary.", Keywords: []string{"networks", "graphs"}}},
+		{ID: "00000000-0000-4000-8000-000000000122", Type: "rfc", APA7: schema.APA7{Title: "The Syslog Protocol", Authors: schema.Authors{{Family: "Gerhards", Given: "R."}}}, Annotation: schema.Annotation{Summary: "Protocol for event notification messages.", Keywords: []string{"syslog", "logging"}}},
+		{ID: "00000000-0000-4000-8000-000000000123", Type: "book", APA7: schema.APA7{Title: "C++ & Go: Systems Programming"}, Annotation: schema.Annotation{Summary: "Compares C++ with Go for systems work.", Keywords: []string{"machine learning", "programming"}}},
+		{ID: "00000000-0000-4000-8000-000000000124", Type: "website", APA7: schema.APA7{Title: "Networked Graphs Blog", URL: "https://graphs.example.com/posts", Accessed: "2024-01-01"}, Annotation: schema.Annotation{Summary: "Posts about protocols and graph drawing.", Keywords: []string{"graphs"}}},
+	}
+	for _, e := range entries {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return entries
+}
+
+func searchOut(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs(append(args, "--json"))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("search %v: %v", args, err)
+	}
+	return buf.String()
+}
+
+func TestSearchFullTextIndexMatchesLiveScan(t *testing.T) {
+	entries := seedFullText(t)
+	queries := [][]string{
+		{"--all", "protocol"},
+		{"--all", "graphs.example"},
+		{"--title", "graph"},
+		{"--title", "c++"},
+		{"--summary", "line two"},
+		{"--summary", "rout"},
+		{"--keyword", "graphs"},
+		{"--keyword", "machine learning"},
+		{"--keyword", "networks,graphs", "--title", "practice"},
+		{"--all", "nothing-matches-this"},
+		{"title~=netw"},
+		{`summary~="line two"`},
+		{"all~=gerhards"},
+		{"keyword==machine learning"},
+		{"!all~=syslog"},
+		{"(title~=graph || summary~=protocol) && keyword==graphs"},
+		{"all~=&"},
+	}
+	live := make([]string, len(queries))
+	for i, q := range queries {
+		live[i] = searchOut(t, q...)
+	}
+	if _, err := store.BuildFullTextIndex(entries); err != nil {
+		t.Fatal(err)
+	}
+	if ix, err := store.ReadFullTextIndex(); err != nil || ix == nil {
+		t.Fatalf("fresh index should load: %v", err)
+	}
+	for i, q := range queries {
+		if got := searchOut(t, q...); got != live[i] {
+			t.Errorf("%v: indexed results differ from live scan\nindexed: %s\nlive:    %s", q, got, live[i])
+		}
+	}
+	if !strings.Contains(live[0], "00000000-0000-4000-8000-000000000122") || live[9] != "[]\n" {
+		t.Fatalf("unexpected live results: %q / %q", live[0], live[9])
+	}
+}
+
+func TestSearchFullTextIndexNarrowsAndGoesStale(t *testing.T) {
+	seedFullText(t)
+	// An index that knows no tokens rules every entry out while it is current.
+	if err := os.MkdirAll(store.MetadataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.FullTextJSON, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := searchOut(t, "--title", "graph"); got != "[]\n" {
+		t.Fatalf("search should consult the index: %s", got)
+	}
+	// Once the library changes after the index was built, search scans entries again.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(store.BibFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if ix, _ := store.ReadFullTextIndex(); ix != nil {
+		t.Fatalf("stale index should be ignored")
+	}
+	if got := searchOut(t, "--title", "graph"); !strings.Contains(got, "00000000-0000-4000-8000-000000000121") {
+		t.Fatalf("stale index should fall back to the live scan: %s", got)
+	}
+}
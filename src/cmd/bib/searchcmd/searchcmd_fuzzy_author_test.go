@@ -0,0 +1,92 @@
+package searchcmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestFuzzyNameMatch(t *testing.T) {
+	cases := []struct {
+		q, name string
+		want    bool
+	}{
+		{"gerhard", "gerhards, r.", true},
+		{"smtih", "smith, j.", true},
+		{"knuht", "knuth, d. e.", true},
+		{"gerhards r", "gerhards, r.", true},
+		{"dow", "doe, j.", true},
+		{"dxx", "doe, j.", false},
+		{"li", "lu, x.", false},
+		{"gerhard", "bernhardt, k.", false},
+		{"", "doe, j.", false},
+	}
+	for _, c := range cases {
+		if got := fuzzyNameMatch(c.q, c.name); got != c.want {
+			t.Errorf("fuzzyNameMatch(%q, %q) = %v, want %v", c.q, c.name, got, c.want)
+		}
+	}
+}
+
+func TestScoreAuthorFuzzy(t *testing.T) {
+	e := schema.Entry{APA7: schema.APA7{Authors: schema.Authors{{Family: "Gerhards", Given: "R."}}}}
+	if _, ok := scoreAuthor(e, "Gerhardz", false); ok {
+		t.Fatalf("misspelling should not match without fuzzy")
+	}
+	s, ok := scoreAuthor(e, "Gerhardz", true)
+	if !ok || s != 3 {
+		t.Fatalf("fuzzy misspelling: ok=%v score=%d", ok, s)
+	}
+	if s, _ := scoreAuthor(e, "gerhard", true); s != 5 {
+		t.Fatalf("substring match should keep the exact score, got %d", s)
+	}
+}
+
+func TestAuthorEqualsFuzzy(t *testing.T) {
+	e := schema.Entry{APA7: schema.APA7{Authors: schema.Authors{{Family: "Smith", Given: "J."}}}}
+	strict, _, _ := compileAuthorEqualsTerm("author==smtih", false)
+	if hit, _ := strict(e); hit {
+		t.Fatalf("misspelling should not match without fuzzy")
+	}
+	p, err := parseExpr("author==smtih* && !author==jones", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hit, score := p(e); !hit || score != 5 {
+		t.Fatalf("fuzzy author==: hit=%v score=%d", hit, score)
+	}
+}
+
+func TestSearchFuzzyFlag(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	for _, e := range []schema.Entry{
+		{ID: "00000000-0000-4000-8000-0000000000d1", Type: "rfc", APA7: schema.APA7{Title: "The Syslog Protocol", Authors: schema.Authors{{Family: "Gerhards", Given: "R."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"syslog"}}},
+		{ID: "00000000-0000-4000-8000-0000000000d2", Type: "book", APA7: schema.APA7{Title: "Other", Authors: schema.Authors{{Family: "Jones", Given: "A."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"other"}}},
+	} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out, _ := runSearch(t, "--author", "Gerhadrs", "--showId")
+	if out != "" {
+		t.Fatalf("expected no match without --fuzzy, got %q", out)
+	}
+	out, _ = runSearch(t, "--author", "Gerhadrs", "--fuzzy", "--showId")
+	if strings.TrimSpace(out) != "00000000-0000-4000-8000-0000000000d1" {
+		t.Fatalf("--fuzzy --author: %q", out)
+	}
+	out, _ = runSearch(t, "--fuzzy", "--showId", "author==gerhrds")
+	if strings.TrimSpace(out) != "00000000-0000-4000-8000-0000000000d1" {
+		t.Fatalf("--fuzzy author==: %q", out)
+	}
+	out, _ = runSearch(t, "--fuzzy", "--cluster-by", "author", "author==gerhrds")
+	if !strings.Contains(out, "Gerhards") {
+		t.Fatalf("fuzzy match should head its author cluster: %q", out)
+	}
+}
@@ -0,0 +1,80 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+	"bibliography/src/internal/stringsx"
+)
+
+func TestSearch_FuzzyTitle(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	ids := map[string]string{}
+	for _, title := range []string{"Graph Neural Networks", "Networks of Graphs in Practice", "Cooking with Cast Iron"} {
+		e := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: title}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		ids[title] = e.ID
+	}
+	run := func(q string) []string {
+		t.Helper()
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs([]string{"--fuzzy-title", q, "--showId"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("search %q: %v", q, err)
+		}
+		return strings.Fields(buf.String())
+	}
+	// typos and reordered words, no substring match
+	got := run("nueral netwroks grpah")
+	if len(got) == 0 || got[0] != ids["Graph Neural Networks"] {
+		t.Fatalf("want Graph Neural Networks first, got %v", got)
+	}
+	for _, id := range got {
+		if id == ids["Cooking with Cast Iron"] {
+			t.Fatalf("unrelated title matched: %v", got)
+		}
+	}
+	if got := run("zzz qqq"); len(got) != 0 {
+		t.Fatalf("expected no matches, got %v", got)
+	}
+}
+
+func TestTokenOverlap(t *testing.T) {
+	if o := tokenOverlap([]string{"graph", "netwrok"}, []string{"networks", "of", "graphs"}); o != 1 {
+		t.Fatalf("overlap = %v, want 1", o)
+	}
+	if o := tokenOverlap([]string{"graph", "iron"}, []string{"graph"}); o != 0.5 {
+		t.Fatalf("overlap = %v, want 0.5", o)
+	}
+}
+
+func TestLengthsCanMatch(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want bool
+	}{
+		{"graph", "graphs", true},
+		{"netwrok", "networks", true},
+		{"net", "networks", false},
+		{"graph", "of", false},
+	} {
+		if got := lengthsCanMatch(c.a, c.b); got != c.want {
+			t.Errorf("lengthsCanMatch(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+		// The prefilter never rejects a pair that would match.
+		if !lengthsCanMatch(c.a, c.b) && stringsx.Similarity(c.a, c.b) >= fuzzyTokenMatch {
+			t.Errorf("%q/%q rejected by length but similar", c.a, c.b)
+		}
+	}
+}
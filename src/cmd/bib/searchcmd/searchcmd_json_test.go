@@ -0,0 +1,61 @@
+package searchcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestSearchJSONOutput(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	y := 2021
+	e1 := schema.Entry{ID: "00000000-0000-4000-8000-000000000b01", Type: "book", APA7: schema.APA7{Title: "Go Go Go", Year: &y, Authors: schema.Authors{{Family: "Pike", Given: "R."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"golang", "book"}}}
+	e2 := schema.Entry{ID: "00000000-0000-4000-8000-000000000b02", Type: "book", APA7: schema.APA7{Title: "Learning Go"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"golang"}}}
+	for _, e := range []schema.Entry{e2, e1} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, args := range map[string][]string{
+		"expr":    {"--json", "title~=go"},
+		"keyword": {"--json", "--keyword", "golang", "--title", "go"},
+	} {
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var got []jsonResult
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("%s: invalid json %v\n%s", name, err, buf.String())
+		}
+		if len(got) != 2 || got[0].ID != e1.ID || got[0].Score <= got[1].Score {
+			t.Fatalf("%s: unexpected order %+v", name, got)
+		}
+		if got[0].Year == nil || *got[0].Year != 2021 || len(got[0].Authors) != 1 || got[0].Authors[0] != "Pike, R." || got[1].Year != nil {
+			t.Fatalf("%s: unexpected fields %+v", name, got)
+		}
+	}
+
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--json", "--keyword", "nothing"})
+	if err := cmd.Execute(); err != nil || buf.String() != "[]\n" {
+		t.Fatalf("empty result: %q %v", buf.String(), err)
+	}
+	cmd = New()
+	cmd.SetArgs([]string{"--json", "--showId", "--keyword", "golang"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --json/--showId conflict")
+	}
+}
@@ -0,0 +1,69 @@
+package searchcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// seedRanked writes five entries whose title scores rank them r1 (best) .. r5.
+func seedRanked(t *testing.T) []string {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	var ids []string
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("00000000-0000-4000-8000-0000000000c%d", i)
+		title := "r" + fmt.Sprint(i) + strings.Repeat(" go", 6-i)
+		e := schema.Entry{ID: id, Type: "book", APA7: schema.APA7{Title: title}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"go"}}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func runSearch(t *testing.T, args ...string) (string, string) {
+	t.Helper()
+	cmd := New()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs(args)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("search %v: %v", args, err)
+	}
+	return out.String(), errOut.String()
+}
+
+func TestSearchLimitOffset(t *testing.T) {
+	ids := seedRanked(t)
+	out, _ := runSearch(t, "--title", "go", "--showId", "--limit", "2", "--offset", "1")
+	if out != ids[1]+"\n"+ids[2]+"\n" {
+		t.Fatalf("limit/offset slice: %q", out)
+	}
+	out, stderr := runSearch(t, "--title", "go", "--showId", "--offset", "3")
+	if out != ids[3]+"\n"+ids[4]+"\n" || stderr != "showing 2 of 5 (from #4)\n" {
+		t.Fatalf("offset only: %q %q", out, stderr)
+	}
+	out, _ = runSearch(t, "--title", "go", "--limit", "2")
+	if !strings.Contains(out, "r1 go") || !strings.Contains(out, "r2 go") || strings.Contains(out, "r3 go") || !strings.HasSuffix(out, "showing 2 of 5\n") {
+		t.Fatalf("table page: %q", out)
+	}
+	out, _ = runSearch(t, "--title", "go", "--limit", "10")
+	if strings.Contains(out, "showing") {
+		t.Fatalf("no note expected when everything fits: %q", out)
+	}
+	out, stderr = runSearch(t, "--title", "go", "--showId", "--offset", "9")
+	if out != "" || stderr != "showing 0 of 5 (from #6)\n" {
+		t.Fatalf("offset past end: %q %q", out, stderr)
+	}
+}
@@ -0,0 +1,85 @@
+package searchcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestSearch_PathOnly(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	write := func(title string, kws ...string) string {
+		t.Helper()
+		e := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: title}, Annotation: schema.Annotation{Summary: "s", Keywords: kws}}
+		p := filepath.Join(store.CitationsDir, "articles", e.ID+".yaml")
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		b, _ := json.Marshal(e)
+		if err := os.WriteFile(p, b, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return filepath.ToSlash(p)
+	}
+	weak := write("Notes", "draft")
+	strong := write("Draft of a draft", "draft")
+	_ = write("Final", "final")
+
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--all", "draft", "--path-only"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	got := strings.Fields(buf.String())
+	if len(got) != 2 || got[0] != strong || got[1] != weak {
+		t.Fatalf("want ranked paths [%s %s], got %v", strong, weak, got)
+	}
+}
+
+func TestSearch_PathOnlyLibrary(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	var drafts []string
+	for _, title := range []string{"Draft one", "Draft two", "Final"} {
+		e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: title}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+		if title != "Final" {
+			drafts = append(drafts, "data/library.bib::"+e.ID)
+		}
+	}
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--all", "draft", "--path-only"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	// Library entries print per-entry references, never the library file itself,
+	// so piping the output into xargs rm cannot delete the library.
+	got := strings.Fields(buf.String())
+	if len(got) != 2 {
+		t.Fatalf("want 2 references, got %v", got)
+	}
+	for _, p := range got {
+		if p == store.BibFile || (p != drafts[0] && p != drafts[1]) {
+			t.Fatalf("want references %v, got %v", drafts, got)
+		}
+	}
+}
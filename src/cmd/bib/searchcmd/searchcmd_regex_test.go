@@ -0,0 +1,44 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestSearch_RegexAllRanksByMatchCount(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	e1 := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Funded by NSF-1234"}, Annotation: schema.Annotation{Summary: "grant NSF-1234 and NSF-5678", Keywords: []string{"k"}}}
+	e2 := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Other"}, Annotation: schema.Annotation{Summary: "grant NSF-9999", Keywords: []string{"k"}}}
+	e3 := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Unfunded"}, Annotation: schema.Annotation{Summary: "none", Keywords: []string{"k"}}}
+	for _, e := range []schema.Entry{e1, e2, e3} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--regex-all", `NSF-\d{4}`, "--showId"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	lines := strings.Fields(buf.String())
+	if len(lines) != 2 || lines[0] != e1.ID || lines[1] != e2.ID {
+		t.Fatalf("unexpected ranking: %v", lines)
+	}
+
+	bad := New()
+	bad.SetArgs([]string{"--regex-all", "("})
+	err := bad.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --regex-all pattern") {
+		t.Fatalf("expected invalid pattern error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestSearch_ModifiedAndVerifiedSince(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	t.Cleanup(func() { dates.SetClock(nil) })
+	at := func(y int, m time.Month, d int) {
+		dates.SetClock(func() time.Time { return time.Date(y, m, d, 12, 0, 0, 0, time.UTC) })
+	}
+
+	oldE := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Old Graph Paper"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	newE := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "New Graph Paper"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	other := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "New Other Paper"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	at(2024, 1, 1)
+	if _, err := store.WriteEntry(oldE); err != nil {
+		t.Fatal(err)
+	}
+	at(2024, 7, 1)
+	for _, e := range []schema.Entry{newE, other} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	at(2024, 7, 2)
+	if err := store.VerifyByID(newE.ID, "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) []string {
+		t.Helper()
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs(append(args, "--showId"))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("execute %v: %v", args, err)
+		}
+		return strings.Fields(buf.String())
+	}
+	if got := run("--modified-since", "2024-06-01"); len(got) != 2 || got[0] != newE.ID {
+		t.Fatalf("modified-since: %v", got)
+	}
+	if got := run("--modified-since", "2024-06-01", "--title", "graph"); len(got) != 1 || got[0] != newE.ID {
+		t.Fatalf("modified-since + title: %v", got)
+	}
+	if got := run("--verified-since", "2024-01-01"); len(got) != 1 || got[0] != newE.ID {
+		t.Fatalf("verified-since: %v", got)
+	}
+	if got := run("--verified-since", "2024-08-01"); len(got) != 0 {
+		t.Fatalf("verified-since future: %v", got)
+	}
+
+	bad := New()
+	bad.SetArgs([]string{"--modified-since", "June"})
+	if err := bad.Execute(); err == nil {
+		t.Fatalf("expected error for invalid --modified-since")
+	}
+}
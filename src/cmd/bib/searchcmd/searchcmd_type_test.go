@@ -0,0 +1,71 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestSearchTypeFilter(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	const (
+		article = "00000000-0000-4000-8000-000000000101"
+		book    = "00000000-0000-4000-8000-000000000102"
+		site    = "00000000-0000-4000-8000-000000000103"
+		rfc     = "00000000-0000-4000-8000-000000000104"
+	)
+	for _, e := range []schema.Entry{
+		{ID: article, Type: "article", APA7: schema.APA7{Title: "Logging at Scale"}},
+		{ID: book, Type: "book", APA7: schema.APA7{Title: "The Logging Handbook"}},
+		{ID: site, Type: "website", APA7: schema.APA7{Title: "Logging Tips", URL: "https://example.com", Accessed: "2024-01-01"}},
+		{ID: rfc, Type: "rfc", APA7: schema.APA7{Title: "The Syslog Protocol"}},
+	} {
+		e.Annotation = schema.Annotation{Summary: "s", Keywords: []string{"logging"}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) ([]string, error) {
+		t.Helper()
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		cmd.SetArgs(append(args, "--showId"))
+		err := cmd.Execute()
+		ids := strings.Fields(buf.String())
+		sort.Strings(ids)
+		return ids, err
+	}
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--type", "article,book"}, []string{article, book}},
+		{[]string{"--type", "Article, BOOK", "--title", "handbook"}, []string{book}},
+		{[]string{"--type", "website", "--keyword", "logging"}, []string{site}},
+		{[]string{"--type", "rfc,book", "keyword==logging"}, []string{book, rfc}},
+		{[]string{"--type", "article", "--fuzzy-title", "loging at scale"}, []string{article}},
+		{[]string{"--type", "book", "--title", "syslog"}, nil},
+	}
+	for _, c := range cases {
+		got, err := run(c.args...)
+		if err != nil {
+			t.Fatalf("%v: %v", c.args, err)
+		}
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("%v = %v, want %v", c.args, got, c.want)
+		}
+	}
+	if _, err := run("--type", "article,novel"); err == nil || !strings.Contains(err.Error(), `invalid --type "novel"`) {
+		t.Fatalf("expected unknown type error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package searchcmd
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestSearchSinceUntil(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	year := func(y int) *int { return &y }
+	const (
+		y1999 = "00000000-0000-4000-8000-0000000000f1"
+		y2010 = "00000000-0000-4000-8000-0000000000f2"
+		y2015 = "00000000-0000-4000-8000-0000000000f3"
+		d2020 = "00000000-0000-4000-8000-0000000000f4"
+		none  = "00000000-0000-4000-8000-0000000000f5"
+	)
+	for _, e := range []schema.Entry{
+		{ID: y1999, Type: "book", APA7: schema.APA7{Title: "Graph Theory Classic", Year: year(1999)}},
+		{ID: y2010, Type: "article", APA7: schema.APA7{Title: "Graph Networks", Year: year(2010)}},
+		{ID: y2015, Type: "article", APA7: schema.APA7{Title: "Protocol Design", Year: year(2015)}},
+		{ID: d2020, Type: "website", APA7: schema.APA7{Title: "Graph Blog Post", Date: "2020-03-04", URL: "https://example.com", Accessed: "2024-01-01"}},
+		{ID: none, Type: "website", APA7: schema.APA7{Title: "Undated Graph Page", URL: "https://example.org", Accessed: "2024-01-01"}},
+	} {
+		e.Annotation = schema.Annotation{Summary: "s", Keywords: []string{"graphs"}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) ([]string, error) {
+		t.Helper()
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		cmd.SetArgs(append(args, "--showId"))
+		err := cmd.Execute()
+		ids := strings.Fields(buf.String())
+		sort.Strings(ids)
+		return ids, err
+	}
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--since", "2010"}, []string{y2010, y2015, d2020}},
+		{[]string{"--until", "2010"}, []string{y1999, y2010}},
+		{[]string{"--since", "2010", "--until", "2015"}, []string{y2010, y2015}},
+		{[]string{"--since", "2015", "--until", "2015"}, []string{y2015}},
+		{[]string{"--since", "2016", "--title", "graph"}, []string{d2020}},
+		{[]string{"--until", "2012", "--keyword", "graphs"}, []string{y1999, y2010}},
+		{[]string{"--since", "2000", "keyword==graphs"}, []string{y2010, y2015, d2020}},
+		{[]string{"--since", "2021"}, nil},
+	}
+	for _, c := range cases {
+		got, err := run(c.args...)
+		if err != nil {
+			t.Fatalf("%v: %v", c.args, err)
+		}
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("%v = %v, want %v", c.args, got, c.want)
+		}
+	}
+	if got, _ := run("--title", "graph"); len(got) != 4 {
+		t.Fatalf("without a bound the undated entry should match: %v", got)
+	}
+	if _, err := run("--since", "2016", "--until", "2010"); err == nil || !strings.Contains(err.Error(), "after --until") {
+		t.Fatalf("expected inverted range error, got %v", err)
+	}
+	if _, err := run("--since", "99"); err == nil {
+		t.Fatalf("expected invalid year error")
+	}
+}
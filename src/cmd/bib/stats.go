@@ -0,0 +1,8 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/statscmd"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd() *cobra.Command { return statscmd.New() }
@@ -0,0 +1,142 @@
+package statscmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// AuthorStats summarizes one author's works in the library.
+type AuthorStats struct {
+	Author    string         `json:"author"`
+	Works     int            `json:"works"`
+	FirstYear int            `json:"first_year,omitempty"`
+	LastYear  int            `json:"last_year,omitempty"`
+	Types     map[string]int `json:"types"`
+}
+
+// New returns the stats command which reports aggregate statistics about the library.
+func New() *cobra.Command {
+	var authors, asJSON bool
+	var top int
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Report library statistics (e.g., --authors for top contributors)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !authors {
+				return fmt.Errorf("select a report: --authors")
+			}
+			entries, err := store.ReadAll()
+			if err != nil {
+				return err
+			}
+			stats := AuthorReport(entries)
+			if top > 0 && len(stats) > top {
+				stats = stats[:top]
+			}
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(stats)
+			}
+			return writeTable(cmd, stats)
+		},
+	}
+	cmd.Flags().BoolVar(&authors, "authors", false, "Rank authors by number of works, with year span and types")
+	cmd.Flags().IntVar(&top, "top", 0, "Limit the report to the top N rows (0 = all)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
+
+// AuthorReport groups entries by author (using the same keys as the authors index)
+// and ranks authors by number of works, then by name.
+func AuthorReport(entries []schema.Entry) []AuthorStats {
+	var out []AuthorStats
+	for name, works := range store.GroupByAuthor(entries) {
+		s := AuthorStats{Author: name, Works: len(works), Types: map[string]int{}}
+		for _, e := range works {
+			s.Types[strings.ToLower(strings.TrimSpace(e.Type))]++
+			y := entryYear(e)
+			if y == 0 {
+				continue
+			}
+			if s.FirstYear == 0 || y < s.FirstYear {
+				s.FirstYear = y
+			}
+			if y > s.LastYear {
+				s.LastYear = y
+			}
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Works != out[j].Works {
+			return out[i].Works > out[j].Works
+		}
+		return out[i].Author < out[j].Author
+	})
+	return out
+}
+
+func entryYear(e schema.Entry) int {
+	if e.APA7.Year != nil && *e.APA7.Year > 0 {
+		return *e.APA7.Year
+	}
+	if d := strings.TrimSpace(e.APA7.Date); len(d) >= 4 {
+		if y, err := strconv.Atoi(d[:4]); err == nil {
+			return y
+		}
+	}
+	return 0
+}
+
+func writeTable(cmd *cobra.Command, stats []AuthorStats) error {
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "RANK\tAUTHOR\tWORKS\tYEARS\tTYPES"); err != nil {
+		return err
+	}
+	for i, s := range stats {
+		if _, err := fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\n", i+1, s.Author, s.Works, yearSpan(s), typeSummary(s.Types)); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func yearSpan(s AuthorStats) string {
+	switch {
+	case s.FirstYear == 0:
+		return "n.d."
+	case s.FirstYear == s.LastYear:
+		return strconv.Itoa(s.FirstYear)
+	default:
+		return fmt.Sprintf("%d-%d", s.FirstYear, s.LastYear)
+	}
+}
+
+// typeSummary renders type counts as "article:3, book:1", most frequent first.
+func typeSummary(types map[string]int) string {
+	keys := make([]string, 0, len(types))
+	for k := range types {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if types[keys[i]] != types[keys[j]] {
+			return types[keys[i]] > types[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s:%d", k, types[k]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,82 @@
+package statscmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func year(y int) *int { return &y }
+
+func sampleEntries() []schema.Entry {
+	smith := schema.Author{Family: "Smith", Given: "Jane"}
+	lee := schema.Author{Family: "Lee", Given: "Kim"}
+	return []schema.Entry{
+		{ID: "a1", Type: "article", APA7: schema.APA7{Title: "One", Year: year(2001), Authors: schema.Authors{smith, lee}}},
+		{ID: "a2", Type: "article", APA7: schema.APA7{Title: "Two", Date: "2010-05-01", Authors: schema.Authors{smith, smith}}},
+		{ID: "b1", Type: "book", APA7: schema.APA7{Title: "Three", Year: year(2005), Authors: schema.Authors{smith}}},
+	}
+}
+
+func TestAuthorReport_RanksAndAggregates(t *testing.T) {
+	got := AuthorReport(sampleEntries())
+	if len(got) != 2 {
+		t.Fatalf("want 2 authors, got %+v", got)
+	}
+	s := got[0]
+	if s.Author != "Smith, Jane" || s.Works != 3 || s.FirstYear != 2001 || s.LastYear != 2010 {
+		t.Fatalf("unexpected top author: %+v", s)
+	}
+	if s.Types["article"] != 2 || s.Types["book"] != 1 {
+		t.Fatalf("unexpected types: %v", s.Types)
+	}
+	if got[1].Author != "Lee, Kim" || got[1].Works != 1 {
+		t.Fatalf("unexpected second author: %+v", got[1])
+	}
+}
+
+func TestStatsAuthors_TableAndJSON(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	for _, e := range sampleEntries() {
+		e.ID = ""
+		e.Annotation = schema.Annotation{Summary: "s", Keywords: []string{e.Type}}
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	cmd := New()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--authors"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "Smith, Jane") || !strings.Contains(out, "2001-2010") || !strings.Contains(out, "article:2, book:1") {
+		t.Fatalf("unexpected table: %q", out)
+	}
+
+	cmd = New()
+	buf.Reset()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--authors", "--json", "--top", "1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("stats json: %v", err)
+	}
+	var stats []AuthorStats
+	if err := json.Unmarshal(buf.Bytes(), &stats); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Author != "Smith, Jane" {
+		t.Fatalf("unexpected json: %+v", stats)
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 // New returns the summarize command that fills summaries/keywords via OpenAI.
 func New() *cobra.Command {
+	var localKeywords bool
 	cmd := &cobra.Command{
 		Use:   "summarize",
 		Short: "Generate summaries and keywords via OpenAI for entries missing a proper summary",
@@ -26,6 +27,9 @@ func New() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if localKeywords {
+				return runLocalKeywords(cmd, entries)
+			}
 			ctx := cmd.Context()
 			updated := 0
 			for _, e := range entries {
@@ -43,9 +47,36 @@ func New() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&localKeywords, "local-keywords", false, "Merge keywords extracted from title/summary into every entry without calling OpenAI")
 	return cmd
 }
 
+// runLocalKeywords merges summarize.LocalKeywords into each entry and rewrites the
+// entries whose keyword set changed, writing the library once.
+func runLocalKeywords(cmd *cobra.Command, entries []schema.Entry) error {
+	var updated []schema.Entry
+	for _, e := range entries {
+		ks := summarize.LocalKeywords(e.APA7.Title, e.Annotation.Summary, summarize.DefaultLocalKeywords)
+		merged := mergeSortDedupKeywords(e.Annotation.Keywords, ks, "")
+		if len(merged) == len(mergeSortDedupKeywords(e.Annotation.Keywords, nil, "")) {
+			continue
+		}
+		e.Annotation.Keywords = merged
+		updated = append(updated, e)
+	}
+	if len(updated) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "no entries needed keywords")
+		return nil
+	}
+	if _, err := store.WriteEntries(updated); err != nil {
+		return err
+	}
+	for _, e := range updated {
+		fmt.Fprintf(cmd.OutOrStdout(), "updated %s\n", e.ID)
+	}
+	return nil
+}
+
 // Injection seams for OpenAI summarize/keywords to allow faking in tests.
 var (
 	summarizeURLFunc                = summarize.SummarizeURL
@@ -91,8 +122,6 @@ func needsSummary(e schema.Entry) bool {
 	return sum == "" || strings.HasPrefix(low, "bibliographic record") || strings.HasPrefix(low, "ibliographic record")
 }
 
-const chromeUA = httpx.ChromeUA
-
 func urlAccessible(ctx context.Context, u string) bool {
 	c := &http.Client{Timeout: 10 * time.Second}
 	if req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil); err == nil {
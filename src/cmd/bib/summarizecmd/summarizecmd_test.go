@@ -1,9 +1,13 @@
 package summarizecmd
 
 import (
+	"bytes"
+	"os"
+	"strings"
 	"testing"
 
 	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
 )
 
 func TestNeedsSummary(t *testing.T) {
@@ -34,3 +38,42 @@ func TestMergeSortDedupKeywords(t *testing.T) {
 		t.Fatalf("keywords unexpected: %+v", out)
 	}
 }
+
+func TestSummarize_LocalKeywords(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Compilers and Interpreters"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	e2 := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Parsing Techniques"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := store.WriteEntry(e2); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	run := func() string {
+		cmd := New()
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetArgs([]string{"--local-keywords"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("summarize: %v", err)
+		}
+		return buf.String()
+	}
+	if out := run(); !strings.Contains(out, "updated "+e.ID) || !strings.Contains(out, "updated "+e2.ID) {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	list, _ := store.ReadAll()
+	byID := map[string]string{}
+	for _, got := range list {
+		byID[got.ID] = strings.Join(got.Annotation.Keywords, ",")
+	}
+	if byID[e.ID] != "book,compilers,interpreters" || byID[e2.ID] != "book,parsing,techniques" {
+		t.Fatalf("keywords: %v", byID)
+	}
+	if out := run(); !strings.Contains(out, "no entries needed keywords") {
+		t.Fatalf("second run should be a no-op: %q", out)
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/undocmd"
+	"github.com/spf13/cobra"
+)
+
+// newUndoCmd creates the "undo" command to revert the last bib-made commit.
+func newUndoCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(undocmd.New(optionalCommit(&noCommit)), &noCommit)
+}
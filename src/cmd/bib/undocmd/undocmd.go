@@ -0,0 +1,113 @@
+package undocmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/gitutil"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// git operations, swappable in tests
+var (
+	headCommit     = gitutil.HeadCommit
+	revertNoCommit = gitutil.RevertNoCommit
+	topLevel       = gitutil.TopLevel
+)
+
+// New returns the undo command which reverts the most recent commit made by bib.
+func New(commit CommitFunc) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Revert the most recent bib-made commit (add, edit, import, ...)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := headCommit()
+			if err != nil {
+				return err
+			}
+			if !c.Tool {
+				return fmt.Errorf("refusing to undo %s (%q): last commit was not made by bib", shortHash(c.Hash), c.Subject)
+			}
+			if c.UndoOf != "" {
+				return fmt.Errorf("refusing to undo %s (%q): it is itself an undo of %s (use git revert to redo)", shortHash(c.Hash), c.Subject, shortHash(c.UndoOf))
+			}
+			root, err := topLevel()
+			if err != nil {
+				return err
+			}
+			files := make([]string, 0, len(c.Files))
+			for _, f := range c.Files {
+				files = append(files, filepath.Join(root, filepath.FromSlash(f)))
+			}
+			out := cmd.OutOrStdout()
+			if dryRun {
+				_, err := fmt.Fprintf(out, "would undo %s %s (%s)\n", shortHash(c.Hash), c.Subject, strings.Join(c.Files, ", "))
+				return err
+			}
+			if err := revertNoCommit(c.Hash); err != nil {
+				return err
+			}
+			paths := files
+			if touchesLibrary(files) {
+				// Metadata indexes are derived from the library; rebuild them from the restored state.
+				entries, err := store.ReadAll()
+				if err != nil {
+					return err
+				}
+				if _, err := store.BuildAllIndexes(entries); err != nil {
+					return err
+				}
+				paths = append(paths, store.MetadataDir)
+			}
+			if err := commit(paths, "undo: "+c.Subject+"\n\n"+gitutil.UndoTrailer+c.Hash); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(out, "undid %s %s\n", shortHash(c.Hash), c.Subject)
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the commit that would be reverted without changing anything")
+	return cmd
+}
+
+// touchesLibrary reports whether any of the (absolute) paths is citation data the
+// metadata indexes derive from. The store paths may be relative to the working
+// directory or absolute (--data-dir), so both sides are compared in canonical form.
+func touchesLibrary(files []string) bool {
+	bib, cites := canonical(store.BibFile), canonical(store.CitationsDir)
+	for _, f := range files {
+		if f = canonical(f); f == bib || strings.HasPrefix(f, cites+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonical returns p as an absolute path with symlinks in its directory resolved
+// (git reports the repository root that way); p itself need not exist.
+func canonical(p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return filepath.Clean(p)
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		return filepath.Join(dir, filepath.Base(abs))
+	}
+	return abs
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(h string) string {
+	if len(h) > 7 {
+		return h[:7]
+	}
+	return h
+}
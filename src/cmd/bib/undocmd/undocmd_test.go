@@ -0,0 +1,137 @@
+package undocmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/gitutil"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+}
+
+// stubGit fakes a repository rooted at the working directory whose HEAD is c.
+func stubGit(t *testing.T, c gitutil.Commit, revert func(string) error) {
+	t.Helper()
+	oldHead, oldRevert, oldTop := headCommit, revertNoCommit, topLevel
+	t.Cleanup(func() { headCommit, revertNoCommit, topLevel = oldHead, oldRevert, oldTop })
+	headCommit = func() (gitutil.Commit, error) { return c, nil }
+	revertNoCommit = revert
+	root, _ := os.Getwd()
+	topLevel = func() (string, error) { return root, nil }
+}
+
+func TestUndo_RefusesNonBibCommit(t *testing.T) {
+	chdirTemp(t)
+	stubGit(t, gitutil.Commit{Hash: "abcdef0123", Subject: "manual tweak"}, func(string) error {
+		t.Fatalf("should not revert a manual commit")
+		return nil
+	})
+	cmd := New(func([]string, string) error { t.Fatalf("should not commit"); return nil })
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "not made by bib") {
+		t.Fatalf("expected refusal, got %v", err)
+	}
+}
+
+func TestUndo_RevertsAndRebuildsIndexes(t *testing.T) {
+	chdirTemp(t)
+	keep := schema.Entry{ID: "00000000-0000-4000-8000-000000000001", Type: "book", APA7: schema.APA7{Title: "Kept"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"kept"}}}
+	if _, err := store.WriteEntry(keep); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	before, err := os.ReadFile(store.BibFile)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	added := schema.Entry{ID: "00000000-0000-4000-8000-000000000002", Type: "book", APA7: schema.APA7{Title: "Mistake"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"oops"}}}
+	if _, err := store.WriteEntry(added); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var reverted string
+	stubGit(t, gitutil.Commit{Hash: "abcdef0123", Subject: "add citation: " + added.ID, Files: []string{store.BibFile}, Tool: true}, func(h string) error {
+		reverted = h
+		return os.WriteFile(store.BibFile, before, 0o644)
+	})
+	var paths []string
+	var msg string
+	cmd := New(func(p []string, m string) error { paths, msg = p, m; return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if reverted != "abcdef0123" || msg != "undo: add citation: "+added.ID+"\n\n"+gitutil.UndoTrailer+"abcdef0123" {
+		t.Fatalf("unexpected revert %q / message %q", reverted, msg)
+	}
+	if len(paths) != 2 || canonical(paths[0]) != canonical(store.BibFile) || paths[1] != store.MetadataDir {
+		t.Fatalf("unexpected commit paths: %v", paths)
+	}
+	b, err := os.ReadFile(store.KeywordsJSON)
+	if err != nil {
+		t.Fatalf("keywords index: %v", err)
+	}
+	if strings.Contains(string(b), "oops") || !strings.Contains(string(b), "kept") {
+		t.Fatalf("index should reflect the restored library: %s", b)
+	}
+	if !strings.Contains(buf.String(), "undid abcdef0 add citation:") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestUndo_RefusesUndoCommit(t *testing.T) {
+	chdirTemp(t)
+	stubGit(t, gitutil.Commit{Hash: "1234567890", Subject: "undo: add citation: x", Files: []string{"data/library.bib"}, Tool: true, UndoOf: "abcdef0123"}, func(string) error {
+		t.Fatalf("should not revert an undo")
+		return nil
+	})
+	cmd := New(func([]string, string) error { t.Fatalf("should not commit"); return nil })
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "itself an undo of abcdef0") {
+		t.Fatalf("expected refusal, got %v", err)
+	}
+}
+
+func TestUndo_RepoRelativeFilesWithDataDir(t *testing.T) {
+	chdirTemp(t)
+	root, _ := os.Getwd()
+	stubGit(t, gitutil.Commit{Hash: "abcdef0123", Subject: "add citation", Files: []string{"lib/data/library.bib"}, Tool: true}, func(string) error { return nil })
+	// run from a subdirectory with the library addressed by an absolute data dir
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chdir(filepath.Join(root, "sub"))
+	store.SetDataDir(filepath.Join(root, "lib", "data"))
+	t.Cleanup(func() { store.SetDataDir(store.DefaultDataDir) })
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000003", Type: "book", APA7: schema.APA7{Title: "Kept"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"kept"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var paths []string
+	cmd := New(func(p []string, _ string) error { paths = p; return nil })
+	cmd.SetOut(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != filepath.Join(root, "lib", "data", "library.bib") || paths[1] != store.MetadataDir {
+		t.Fatalf("library change should be recognized and committed by absolute path: %v", paths)
+	}
+	if _, err := os.Stat(store.KeywordsJSON); err != nil {
+		t.Fatalf("indexes should be rebuilt under the data dir: %v", err)
+	}
+}
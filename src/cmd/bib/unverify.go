@@ -0,0 +1,12 @@
+package main
+
+import (
+	"bibliography/src/cmd/bib/unverifycmd"
+	"github.com/spf13/cobra"
+)
+
+// newUnverifyCmd creates the "unverify" command, the inverse of "verify --id".
+func newUnverifyCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(unverifycmd.New(optionalCommit(&noCommit)), &noCommit)
+}
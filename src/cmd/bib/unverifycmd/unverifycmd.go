@@ -0,0 +1,43 @@
+package unverifycmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/complete"
+	"bibliography/src/internal/store"
+)
+
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
+// New returns the unverify command, the inverse of "verify --id".
+func New(commit CommitFunc) *cobra.Command {
+	var id string
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "unverify",
+		Short: "Mark a verified citation as unverified (clears verified_by, updates modified)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				return fmt.Errorf("--id is required")
+			}
+			if err := store.UnverifyByID(id, force); err != nil {
+				return err
+			}
+			if err := commit([]string{store.BibFile}, fmt.Sprintf("unverify citation: %s", id)); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "unverified %s\n", id)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&id, "id", "", "Entry ID (uuid)")
+	_ = cmd.RegisterFlagCompletionFunc("id", complete.IDs)
+	cmd.Flags().BoolVar(&force, "force", false, "Reset the entry even if it is not verified")
+	return cmd
+}
@@ -0,0 +1,63 @@
+package unverifycmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestUnverify(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000051", Type: "book", APA7: schema.APA7{Title: "Rotted Link"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := store.VerifyByID(e.ID, "Jane Reviewer"); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	var msgs []string
+	run := func(args ...string) (string, error) {
+		cmd := New(func(paths []string, msg string) error {
+			if len(paths) != 1 || paths[0] != store.BibFile {
+				t.Fatalf("unexpected commit paths %v", paths)
+			}
+			msgs = append(msgs, msg)
+			return nil
+		})
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("--id", e.ID)
+	if err != nil || !strings.Contains(out, "unverified "+e.ID) {
+		t.Fatalf("unverify: %v %q", err, out)
+	}
+	pending, _ := store.ListUnverified()
+	if len(pending) != 1 || pending[0].ID != e.ID {
+		t.Fatalf("entry should be pending again: %+v", pending)
+	}
+	if _, err := run("--id", e.ID); err == nil || !strings.Contains(err.Error(), "not verified") {
+		t.Fatalf("expected already-unverified error, got %v", err)
+	}
+	if _, err := run("--id", e.ID, "--force"); err != nil {
+		t.Fatalf("--force: %v", err)
+	}
+	if len(msgs) != 2 || msgs[0] != "unverify citation: "+e.ID {
+		t.Fatalf("unexpected commits %q", msgs)
+	}
+	if _, err := run(); err == nil || !strings.Contains(err.Error(), "--id is required") {
+		t.Fatalf("expected --id error, got %v", err)
+	}
+}
@@ -5,4 +5,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func newVerifyCmd() *cobra.Command { return verifycmd.New() }
+func newVerifyCmd() *cobra.Command {
+	var noCommit bool
+	return addNoCommitFlag(verifycmd.New(optionalCommit(&noCommit)), &noCommit)
+}
@@ -1,9 +1,11 @@
 package verifycmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -11,7 +13,9 @@ import (
 	"github.com/spf13/cobra"
 
 	booksearch "bibliography/src/internal/booksearch"
+	"bibliography/src/internal/complete"
 	"bibliography/src/internal/doi"
+	"bibliography/src/internal/httpx"
 	movpkg "bibliography/src/internal/movie"
 	rfcpkg "bibliography/src/internal/rfc"
 	"bibliography/src/internal/schema"
@@ -21,19 +25,26 @@ import (
 	webfetch "bibliography/src/internal/webfetch"
 )
 
+// CommitFunc commits the given paths with a message (git add/commit/push in production).
+type CommitFunc func(paths []string, message string) error
+
 // New returns the verify command which marks a record as verified.
-func New() *cobra.Command {
+func New(commit CommitFunc) *cobra.Command {
 	var id string
 	var by string
 	var listPending bool
 	var showID bool
-	var auto bool
+	var auto, yes, dryRun, asJSON bool
+	var source string
 	cmd := &cobra.Command{
 		Use:   "verify",
 		Short: "Mark a citation as verified (sets verified=true, updates modified/verified_by)",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if auto {
-				return runAuto(cmd)
+				return runAuto(cmd, autoOptions{by: by, yes: yes, dryRun: dryRun, asJSON: asJSON}, commit)
+			}
+			if yes || dryRun || asJSON {
+				return fmt.Errorf("--yes, --dry-run, and --json require --auto")
 			}
 			if listPending {
 				es, err := store.ListUnverified()
@@ -59,15 +70,29 @@ func New() *cobra.Command {
 			if err := store.VerifyByID(id, who); err != nil {
 				return err
 			}
+			// A manual --source records human verification against something the
+			// providers cannot query (a physical copy, a library catalog).
+			if src := strings.TrimSpace(source); src != "" {
+				if err := store.UpdateSourceByID(id, src); err != nil {
+					return err
+				}
+				_, err := fmt.Fprintf(cmd.OutOrStdout(), "verified %s by %s (source: %s)\n", id, who, src)
+				return err
+			}
 			_, err := fmt.Fprintf(cmd.OutOrStdout(), "verified %s by %s\n", id, who)
 			return err
 		},
 	}
 	cmd.Flags().StringVar(&id, "id", "", "Entry ID (uuid)")
+	_ = cmd.RegisterFlagCompletionFunc("id", complete.IDs)
 	cmd.Flags().StringVar(&by, "by", "", "Verifier name (defaults to git user.name)")
 	cmd.Flags().BoolVar(&listPending, "list-pending", false, "List entries where verified=false")
 	cmd.Flags().BoolVar(&showID, "showId", false, "With --list-pending, print only IDs")
+	cmd.Flags().StringVar(&source, "source", "", "With --id, record the provenance source checked by hand (e.g., \"WorldCat (manual)\")")
 	cmd.Flags().BoolVar(&auto, "auto", false, "Attempt to auto-verify unverified entries with provider consensus")
+	cmd.Flags().BoolVar(&yes, "yes", false, "With --auto, verify every entry that reaches consensus without prompting and commit once")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --auto, report provider evidence without prompting or writing")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "With --auto, print {id, title, type, providers, eligible, verified} per entry as JSON (needs --yes or --dry-run)")
 	return cmd
 }
 
@@ -142,45 +167,100 @@ func writeColumns(cmd *cobra.Command, cols []string, widths []int) {
 
 // --- Auto verification ---
 
-func runAuto(cmd *cobra.Command) error {
+// autoOptions selects how runAuto confirms and reports.
+type autoOptions struct {
+	by     string // verifier name; defaults to git user.name
+	yes    bool   // confirm every eligible entry without prompting, then commit once
+	dryRun bool   // check providers only; never prompt or write
+	asJSON bool   // print autoResults as JSON instead of prose
+}
+
+// autoResult is the provider evidence gathered for one unverified entry.
+type autoResult struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Type      string   `json:"type"`
+	Providers []string `json:"providers"`
+	Eligible  bool     `json:"eligible"`
+	Verified  bool     `json:"verified"`
+}
+
+// runAuto checks each unverified entry with verifyWithProviders and, for those reaching
+// consensus, prompts y/n before marking them verified. With yes it confirms without
+// reading stdin and commits the library once at the end; with dryRun nothing is
+// confirmed or written. With asJSON the per-entry evidence is printed as a JSON array.
+func runAuto(cmd *cobra.Command, opts autoOptions, commit CommitFunc) error {
+	if opts.asJSON && !opts.yes && !opts.dryRun {
+		return fmt.Errorf("--json cannot prompt; add --yes to verify or --dry-run to only report")
+	}
 	es, err := store.ListUnverified()
 	if err != nil {
 		return err
 	}
-	if len(es) == 0 {
+	if len(es) == 0 && !opts.asJSON {
 		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "no unverified entries")
 		return nil
 	}
-	total := len(es)
+	// In JSON mode the prose goes nowhere so stdout stays parseable.
+	out := cmd.OutOrStdout()
+	if opts.asJSON {
+		out = io.Discard
+	}
+	results := make([]autoResult, 0, len(es))
 	eligible := 0
 	verifiedCount := 0
 	for _, e := range es {
 		provs, ok := verifyWithProviders(cmd, e)
+		if provs == nil {
+			provs = []string{}
+		}
+		results = append(results, autoResult{ID: e.ID, Title: e.APA7.Title, Type: e.Type, Providers: provs, Eligible: ok})
 		if !ok {
 			continue
 		}
 		eligible++
 		// Present proposed record (current entry) and prompt
-		fmt.Fprintf(cmd.OutOrStdout(), "Proposed verification for %s (%s)\n", e.ID, e.APA7.Title)
+		fmt.Fprintf(out, "Proposed verification for %s (%s)\n", e.ID, e.APA7.Title)
 		if len(provs) > 0 {
-			fmt.Fprintf(cmd.OutOrStdout(), "providers: %s\n", strings.Join(provs, ", "))
+			fmt.Fprintf(out, "providers: %s\n", strings.Join(provs, ", "))
+		}
+		fmt.Fprintln(out, entryToYAML(e))
+		if opts.dryRun {
+			continue
 		}
-		fmt.Fprintln(cmd.OutOrStdout(), entryToYAML(e))
-		fmt.Fprint(cmd.OutOrStdout(), "verified (y/n)? ")
-		var resp string
-		fmt.Fscan(cmd.InOrStdin(), &resp)
-		if strings.ToLower(strings.TrimSpace(resp)) == "y" {
+		confirmed := opts.yes
+		if !opts.yes {
+			fmt.Fprint(out, "verified (y/n)? ")
+			var resp string
+			fmt.Fscan(cmd.InOrStdin(), &resp)
+			confirmed = strings.ToLower(strings.TrimSpace(resp)) == "y"
+		}
+		if confirmed {
 			// Update source to first provider and mark verified
 			_ = store.UpdateSourceByID(e.ID, provs[0])
-			who := store.GetGitUserName()
-			if err := store.VerifyByID(e.ID, who); err != nil {
+			who := strings.TrimSpace(opts.by)
+			if who == "" {
+				who = store.GetGitUserName()
+			}
+			if err := store.VerifyByIDWithProviders(e.ID, who, schema.VerifyConsensus, provs); err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "verified %s by %s (source=%s)\n", e.ID, who, provs[0])
+			fmt.Fprintf(out, "verified %s by %s (source=%s)\n", e.ID, who, provs[0])
+			results[len(results)-1].Verified = true
 			verifiedCount++
 		}
 	}
-	fmt.Fprintf(cmd.OutOrStdout(), "auto-verify summary: %d verified, %d eligible, %d total unverified\n", verifiedCount, eligible, total)
+	fmt.Fprintf(out, "auto-verify summary: %d verified, %d eligible, %d total unverified\n", verifiedCount, eligible, len(es))
+	if opts.yes && verifiedCount > 0 {
+		if err := commit([]string{store.BibFile}, fmt.Sprintf("verify citations: %d auto-verified", verifiedCount)); err != nil {
+			return err
+		}
+	}
+	if opts.asJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
 	return nil
 }
 
@@ -192,8 +272,8 @@ func verifyWithProviders(cmd *cobra.Command, e schema.Entry) ([]string, bool) {
 	if strings.EqualFold(e.Type, "article") {
 		doiOK := false
 		if strings.TrimSpace(e.APA7.DOI) != "" {
-			if _, err := doi.FetchArticleByDOI(cmd.Context(), e.APA7.DOI); err == nil {
-				providers = append(providers, "doi.org")
+			if _, prov, err := doi.FetchArticleByDOIWithProvider(cmd.Context(), e.APA7.DOI); err == nil {
+				providers = append(providers, prov)
 				doiOK = true
 			}
 		}
@@ -270,10 +350,10 @@ func verifyWithProviders(cmd *cobra.Command, e schema.Entry) ([]string, bool) {
 		}
 		return providers, len(providers) >= 1
 	}
-	// Video: YouTube provider (1 provider sufficient)
+	// Video: YouTube or Vimeo by URL host (1 provider sufficient)
 	if strings.EqualFold(e.Type, "video") && strings.TrimSpace(e.APA7.URL) != "" {
-		if _, err := youtube.FetchYouTube(cmd.Context(), e.APA7.URL); err == nil {
-			providers = append(providers, "youtube")
+		if _, prov, err := youtube.Fetch(cmd.Context(), e.APA7.URL); err == nil {
+			providers = append(providers, prov)
 		}
 		return providers, len(providers) >= 1
 	}
@@ -289,13 +369,43 @@ func verifyWithProviders(cmd *cobra.Command, e schema.Entry) ([]string, bool) {
 		}
 		return providers, len(providers) >= 2
 	}
-	// Movie, Song, Patent: insufficient independent providers in this CLI to form 2/3 consensus reliably
+	// Patent: Google Patents page scrape (1 provider sufficient), only when a number or patents URL is known
+	if strings.EqualFold(e.Type, "patent") {
+		if u := googlePatentsURL(e); u != "" {
+			if _, err := webfetch.FetchArticleByURL(cmd.Context(), u); err == nil {
+				providers = append(providers, "google-patents")
+			}
+		}
+		return providers, len(providers) >= 1
+	}
+	// Other types: insufficient independent providers in this CLI to form 2/3 consensus reliably
 	return providers, false
 }
 
+// googlePatentsBase is the Google Patents page prefix; replaceable in tests.
+var googlePatentsBase = "https://patents.google.com/patent/"
+
+// googlePatentsURL returns the Google Patents page for a patent entry, preferring an
+// existing patents.google.com URL and otherwise building one from the patent number.
+// Returns "" when neither is present.
+func googlePatentsURL(e schema.Entry) string {
+	if u := strings.TrimSpace(e.APA7.URL); u != "" {
+		if pu, err := url.Parse(u); err == nil && strings.EqualFold(pu.Host, "patents.google.com") {
+			return u
+		}
+	}
+	num := strings.ToUpper(strings.Join(strings.Fields(e.APA7.PatentNumber), ""))
+	num = strings.ReplaceAll(num, ",", "")
+	if num == "" {
+		return ""
+	}
+	return googlePatentsBase + url.PathEscape(num) + "/en"
+}
+
 func urlAccessible(ctx interface{ Done() <-chan struct{} }, u string) bool {
 	c := &http.Client{Timeout: 10 * time.Second}
 	if req, err := http.NewRequest(http.MethodHead, u, nil); err == nil {
+		httpx.SetUA(req)
 		if resp, err := c.Do(req); err == nil {
 			resp.Body.Close()
 			if resp.StatusCode >= 200 && resp.StatusCode < 400 {
@@ -304,6 +414,7 @@ func urlAccessible(ctx interface{ Done() <-chan struct{} }, u string) bool {
 		}
 	}
 	if req, err := http.NewRequest(http.MethodGet, u, nil); err == nil {
+		httpx.SetUA(req)
 		if resp, err := c.Do(req); err == nil {
 			resp.Body.Close()
 			if resp.StatusCode >= 200 && resp.StatusCode < 400 {
@@ -323,6 +434,7 @@ func hasHTMLTitle(u string) bool {
 	if err != nil {
 		return false
 	}
+	httpx.SetUA(req)
 	resp, err := c.Do(req)
 	if err != nil {
 		return false
@@ -336,88 +448,4 @@ func hasHTMLTitle(u string) bool {
 }
 
 // entryToYAML renders a schema.Entry in a human-friendly YAML-like format for preview only.
-func entryToYAML(e schema.Entry) string {
-	b := &strings.Builder{}
-	w := func(indent int, line string) {
-		b.WriteString(strings.Repeat(" ", indent))
-		b.WriteString(line)
-		b.WriteString("\n")
-	}
-	q := func(s string) string {
-		s = strings.ReplaceAll(s, "\"", "\\\"")
-		return "\"" + s + "\""
-	}
-	w(0, "id: "+e.ID)
-	w(0, "type: "+e.Type)
-	w(0, "apa7:")
-	if e.APA7.Title != "" {
-		w(2, "title: "+q(e.APA7.Title))
-	}
-	if e.APA7.ContainerTitle != "" {
-		w(2, "container_title: "+q(e.APA7.ContainerTitle))
-	}
-	if e.APA7.Journal != "" {
-		w(2, "journal: "+q(e.APA7.Journal))
-	}
-	if e.APA7.Publisher != "" {
-		w(2, "publisher: "+q(e.APA7.Publisher))
-	}
-	if e.APA7.PublisherLocation != "" {
-		w(2, "publisher_location: "+q(e.APA7.PublisherLocation))
-	}
-	if e.APA7.Edition != "" {
-		w(2, "edition: "+q(e.APA7.Edition))
-	}
-	if e.APA7.Volume != "" {
-		w(2, "volume: "+q(e.APA7.Volume))
-	}
-	if e.APA7.Issue != "" {
-		w(2, "issue: "+q(e.APA7.Issue))
-	}
-	if e.APA7.Pages != "" {
-		w(2, "pages: "+q(e.APA7.Pages))
-	}
-	if e.APA7.Year != nil {
-		w(2, fmt.Sprintf("year: %d", *e.APA7.Year))
-	}
-	if e.APA7.Date != "" {
-		w(2, "date: "+q(e.APA7.Date))
-	}
-	if e.APA7.DOI != "" {
-		w(2, "doi: "+q(e.APA7.DOI))
-	}
-	if e.APA7.ISBN != "" {
-		w(2, "isbn: "+q(e.APA7.ISBN))
-	}
-	if e.APA7.URL != "" {
-		w(2, "url: "+q(e.APA7.URL))
-	}
-	if e.APA7.Accessed != "" {
-		w(2, "accessed: "+q(e.APA7.Accessed))
-	}
-	if len(e.APA7.Authors) > 0 {
-		w(2, "authors:")
-		for _, a := range e.APA7.Authors {
-			if strings.TrimSpace(a.Family) == "" && strings.TrimSpace(a.Given) == "" {
-				continue
-			}
-			w(4, "- family: "+q(a.Family))
-			if strings.TrimSpace(a.Given) != "" {
-				w(6, "given: "+q(a.Given))
-			}
-		}
-	}
-	w(0, "annotation:")
-	if e.Annotation.Summary != "" {
-		w(2, "summary: "+q(e.Annotation.Summary))
-	}
-	if len(e.Annotation.Keywords) > 0 {
-		// Render keywords inline list
-		items := make([]string, 0, len(e.Annotation.Keywords))
-		for _, k := range e.Annotation.Keywords {
-			items = append(items, q(k))
-		}
-		w(2, "keywords: ["+strings.Join(items, ", ")+"]")
-	}
-	return b.String()
-}
+func entryToYAML(e schema.Entry) string { return schema.PreviewYAML(e) }
@@ -0,0 +1,74 @@
+package verifycmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/doi"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestVerifyAutoJSON_DOIOnlyArticleDryRun(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	doi.SetHTTPClient(fakeDoer(func(req *http.Request) *http.Response {
+		body := `{"title":"DOI Article","container-title":"J","issued":{"date-parts":[[2020]]},"author":[{"family":"Doe","given":"J"}]}`
+		return &http.Response{StatusCode: 200, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+	}))
+
+	art := schema.Entry{ID: "00000000-0000-4000-8000-0000000000e1", Type: "article", APA7: schema.APA7{Title: "DOI Article", Identifiers: schema.Identifiers{DOI: "10.1234/only"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"article"}}}
+	ds := schema.Entry{ID: "00000000-0000-4000-8000-0000000000e2", Type: "dataset", APA7: schema.APA7{Title: "Data"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"dataset"}}}
+	for _, e := range []schema.Entry{art, ds} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, _ := os.ReadFile(store.BibFile)
+
+	commits := 0
+	cmd := New(func([]string, string) error { commits++; return nil })
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(failingReader{t})
+	cmd.SetArgs([]string{"--auto", "--json", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("verify --auto --json --dry-run: %v", err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, out.String())
+	}
+	byID := map[string]map[string]any{}
+	for _, r := range got {
+		byID[r["id"].(string)] = r
+	}
+	want := map[string]any{"id": art.ID, "title": "DOI Article", "type": "article", "providers": []any{"doi.org"}, "eligible": true, "verified": false}
+	if !reflect.DeepEqual(byID[art.ID], want) {
+		t.Fatalf("article evidence = %v, want %v", byID[art.ID], want)
+	}
+	if r := byID[ds.ID]; r == nil || r["eligible"] != false || len(r["providers"].([]any)) != 0 {
+		t.Fatalf("dataset should be ineligible with no providers: %v", r)
+	}
+	after, _ := os.ReadFile(store.BibFile)
+	if !bytes.Equal(before, after) || commits != 0 {
+		t.Fatalf("--dry-run must not write or commit (commits=%d)", commits)
+	}
+
+	// JSON without --yes or --dry-run cannot prompt.
+	cmd = New(nil)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--auto", "--json"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "cannot prompt") {
+		t.Fatalf("expected --json prompt error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package verifycmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestVerifyWithProviders_PatentGooglePatents(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><head><title>US1234567B2 - Widget</title><meta property="og:title" content="Widget"></head></html>`))
+	}))
+	defer srv.Close()
+	old := googlePatentsBase
+	googlePatentsBase = srv.URL + "/patent/"
+	t.Cleanup(func() { googlePatentsBase = old })
+
+	e := schema.Entry{ID: schema.NewID(), Type: "patent", APA7: schema.APA7{Title: "Widget", PatentNumber: "US 1,234,567 B2"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	provs, ok := verifyWithProviders(cmd, e)
+	if !ok || len(provs) != 1 || provs[0] != "google-patents" {
+		t.Fatalf("expected google-patents provider, got ok=%v provs=%v", ok, provs)
+	}
+	if gotPath != "/patent/US1234567B2/en" {
+		t.Fatalf("unexpected request path: %q", gotPath)
+	}
+}
+
+func TestVerifyWithProviders_PatentWithoutNumberOrURL(t *testing.T) {
+	e := schema.Entry{ID: schema.NewID(), Type: "patent", APA7: schema.APA7{Title: "Widget", URL: "https://patents.example.com/p", Accessed: "2025-01-01"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if u := googlePatentsURL(e); u != "" {
+		t.Fatalf("expected no google patents url, got %q", u)
+	}
+	if provs, ok := verifyWithProviders(&cobra.Command{}, e); ok || len(provs) != 0 {
+		t.Fatalf("expected ineligible patent, got ok=%v provs=%v", ok, provs)
+	}
+	e.APA7.URL = "https://patents.google.com/patent/US1/en"
+	if u := googlePatentsURL(e); u != e.APA7.URL {
+		t.Fatalf("expected existing google patents url, got %q", u)
+	}
+}
@@ -0,0 +1,70 @@
+package verifycmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	movpkg "bibliography/src/internal/movie"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+type fakeDoer func(*http.Request) *http.Response
+
+func (f fakeDoer) Do(req *http.Request) (*http.Response, error) { return f(req), nil }
+
+// failingReader fails the test if the command reads stdin.
+type failingReader struct{ t *testing.T }
+
+func (r failingReader) Read([]byte) (int, error) {
+	r.t.Errorf("verify --auto --yes read stdin")
+	return 0, io.EOF
+}
+
+func TestVerifyAutoYes_VerifiesEligibleWithoutPrompting(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	t.Setenv("OMDB_API_KEY", "k")
+	movpkg.SetHTTPClient(fakeDoer(func(req *http.Request) *http.Response {
+		b, _ := json.Marshal(map[string]string{"Response": "True", "Title": "Known Film", "Released": "2019-02-03", "Director": "Jane Doe", "imdbID": "tt1"})
+		return &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(bytes.NewReader(b))}
+	}))
+
+	film := schema.Entry{ID: "00000000-0000-4000-8000-0000000000f1", Type: "movie", APA7: schema.APA7{Title: "Known Film", Date: "2019-02-03"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"movie"}}}
+	other := schema.Entry{ID: "00000000-0000-4000-8000-0000000000f2", Type: "dataset", APA7: schema.APA7{Title: "No Providers"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"dataset"}}}
+	for _, e := range []schema.Entry{film, other} {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var commits []string
+	cmd := New(func(paths []string, msg string) error {
+		commits = append(commits, msg+" "+strings.Join(paths, ","))
+		return nil
+	})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(failingReader{t})
+	cmd.SetArgs([]string{"--auto", "--yes", "--by", "CI"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("verify --auto --yes: %v", err)
+	}
+	if s := out.String(); strings.Contains(s, "(y/n)") || !strings.Contains(s, "verified "+film.ID+" by CI") || !strings.Contains(s, "1 verified, 1 eligible, 2 total") {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+	pending, _ := store.ListUnverified()
+	if len(pending) != 1 || pending[0].ID != other.ID {
+		t.Fatalf("only the ineligible entry should remain unverified: %+v", pending)
+	}
+	if len(commits) != 1 || commits[0] != "verify citations: 1 auto-verified "+store.BibFile {
+		t.Fatalf("expected one commit, got %q", commits)
+	}
+}
@@ -0,0 +1,43 @@
+package verifycmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func TestVerify_ManualSource(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000001", Type: "book", APA7: schema.APA7{Title: "Printed Only"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := store.WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cmd := New(func([]string, string) error { return nil })
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--id", e.ID, "--by", "Jane Reviewer", "--source", "WorldCat (manual)"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !strings.Contains(buf.String(), "source: WorldCat (manual)") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+	b, err := os.ReadFile(store.BibFile)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	lib := string(b)
+	for _, want := range []string{"{WorldCat (manual)}", "{Jane Reviewer}", "verified_at", "verified = {true}"} {
+		if !strings.Contains(lib, want) {
+			t.Fatalf("library missing %q:\n%s", want, lib)
+		}
+	}
+}
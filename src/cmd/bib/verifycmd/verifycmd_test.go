@@ -31,11 +31,15 @@ func TestTableHelpersAndYAMLPreview(t *testing.T) {
 	}
 
 	y := 2020
-	e := schema.Entry{ID: "x", Type: "article", APA7: schema.APA7{Title: "T", Year: &y, DOI: "10.1/x", URL: "https://doi.org/10.1/x", Accessed: "2025-01-01", Authors: schema.Authors{{Family: "Doe", Given: "J"}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k1", "k2"}}}
+	e := schema.Entry{ID: "x", Type: "article", APA7: schema.APA7{Title: "T", Year: &y, Identifiers: schema.Identifiers{DOI: "10.1/x"}, URL: "https://doi.org/10.1/x", Accessed: "2025-01-01", Authors: schema.Authors{{Family: "Doe", Given: "J"}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k1", "k2"}}}
 	preview := entryToYAML(e)
 	if !strings.Contains(preview, "id: x") || !strings.Contains(preview, "apa7:") || !strings.Contains(preview, "keywords:") {
 		t.Fatalf("yaml preview missing content: %q", preview)
 	}
+	e.APA7.Relations = &schema.Relations{Obsoletes: []string{"RFC 3164"}}
+	if preview := entryToYAML(e); !strings.Contains(preview, "  relations:\n    obsoletes: [\"RFC 3164\"]\n") {
+		t.Fatalf("relations missing from preview: %q", preview)
+	}
 	if a := firstAuthor(e); a != "Doe, J" {
 		t.Fatalf("firstAuthor: %q", a)
 	}
@@ -0,0 +1,137 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/names"
+	"bibliography/src/internal/sanitize"
+	"bibliography/src/internal/schema"
+)
+
+var client httpx.Doer = httpx.ProviderClient()
+
+// SetHTTPClient swaps the http client for tests.
+func SetHTTPClient(c httpx.Doer) { client = c }
+
+// Minimal subset of the arXiv API Atom feed we care about
+type feedXML struct {
+	XMLName xml.Name   `xml:"http://www.w3.org/2005/Atom feed"`
+	Entries []entryXML `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type entryXML struct {
+	ID         string      `xml:"http://www.w3.org/2005/Atom id"`
+	Title      string      `xml:"http://www.w3.org/2005/Atom title"`
+	Summary    string      `xml:"http://www.w3.org/2005/Atom summary"`
+	Published  string      `xml:"http://www.w3.org/2005/Atom published"`
+	Authors    []authorXML `xml:"http://www.w3.org/2005/Atom author"`
+	DOI        string      `xml:"http://arxiv.org/schemas/atom doi"`
+	JournalRef string      `xml:"http://arxiv.org/schemas/atom journal_ref"`
+}
+
+type authorXML struct {
+	Name string `xml:"http://www.w3.org/2005/Atom name"`
+}
+
+var reArXivID = regexp.MustCompile(`^([a-z-]+(\.[A-Z]{2})?/\d{7}|\d{4}\.\d{4,5})(v\d+)?$`)
+
+// FetchArXiv queries the arXiv API for an identifier and maps it into a schema.Entry
+// with type "article". spec may be "2101.00001", "arXiv:2101.00001v2", an old-style
+// id such as "hep-th/9901001", or an arxiv.org abs/pdf URL.
+func FetchArXiv(ctx context.Context, spec string) (schema.Entry, error) {
+	id := normalizeID(spec)
+	if id == "" {
+		return schema.Entry{}, fmt.Errorf("invalid arXiv id: %s", spec)
+	}
+	u := "http://export.arxiv.org/api/query?id_list=" + url.QueryEscape(id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	req.Header.Set("Accept", "application/atom+xml")
+	httpx.SetUA(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return schema.Entry{}, fmt.Errorf("arxiv: http %d: %s", resp.StatusCode, string(b))
+	}
+	var feed feedXML
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&feed); err != nil {
+		return schema.Entry{}, fmt.Errorf("arxiv: %w", err)
+	}
+	// Unknown ids yield an empty feed, or an entry pointing at api/errors.
+	if len(feed.Entries) == 0 || strings.Contains(feed.Entries[0].ID, "/api/errors") {
+		return schema.Entry{}, fmt.Errorf("arxiv: no record for %s", id)
+	}
+	return mapEntry(feed.Entries[0], id)
+}
+
+// mapEntry converts an Atom entry to a validated Entry.
+func mapEntry(a entryXML, id string) (schema.Entry, error) {
+	var e schema.Entry
+	e.Type = "article"
+	e.APA7.Title = collapse(a.Title)
+	for _, au := range a.Authors {
+		if fam, giv := names.Split(au.Name); fam != "" {
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: giv})
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(a.Published)); err == nil {
+		y := t.Year()
+		e.APA7.Year = &y
+		e.APA7.Date = t.Format("2006-01-02")
+	}
+	e.APA7.Journal = "arXiv"
+	e.APA7.ContainerTitle = "arXiv"
+	e.APA7.DOI = strings.TrimSpace(a.DOI)
+	e.APA7.ArXiv = id
+	e.APA7.URL = "https://arxiv.org/abs/" + id
+	e.APA7.Accessed = dates.NowISO()
+	e.Annotation.Summary = collapse(a.Summary)
+	if e.Annotation.Summary == "" {
+		e.Annotation.Summary = fmt.Sprintf("Bibliographic record for %s via arXiv.", e.APA7.Title)
+	}
+	e.Annotation.Keywords = []string{"article", "preprint"}
+	e.ID = schema.NewID()
+	sanitize.CleanEntry(&e)
+	if err := e.Validate(); err != nil {
+		return schema.Entry{}, err
+	}
+	return e, nil
+}
+
+// normalizeID strips "arXiv:" prefixes and arxiv.org abs/pdf URL wrapping, returning
+// "" when what remains is not an arXiv identifier.
+func normalizeID(spec string) string {
+	s := strings.TrimSpace(spec)
+	if i := strings.Index(s, "arxiv.org/"); i >= 0 {
+		s = s[i+len("arxiv.org/"):]
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "abs/"), "pdf/")
+		s = strings.TrimSuffix(s, ".pdf")
+	}
+	if len(s) > 6 && strings.EqualFold(s[:6], "arxiv:") {
+		s = s[6:]
+	}
+	s = strings.TrimSpace(s)
+	if !reArXivID.MatchString(s) {
+		return ""
+	}
+	return s
+}
+
+// collapse joins the hard-wrapped lines of Atom text fields.
+func collapse(s string) string { return strings.Join(strings.Fields(s), " ") }
@@ -0,0 +1,98 @@
+package arxiv
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testHTTPDoer struct {
+	status int
+	body   string
+	url    *string
+}
+
+func (t testHTTPDoer) Do(req *http.Request) (*http.Response, error) {
+	if t.url != nil {
+		*t.url = req.URL.String()
+	}
+	return &http.Response{StatusCode: t.status, Body: io.NopCloser(strings.NewReader(t.body)), Header: make(http.Header)}, nil
+}
+
+const sampleAtom = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <title type="html">ArXiv Query: id_list=1706.03762</title>
+  <entry>
+    <id>http://arxiv.org/abs/1706.03762v7</id>
+    <published>2017-06-12T17:57:34Z</published>
+    <title>Attention Is All
+      You Need</title>
+    <summary>  The dominant sequence transduction models are based on complex
+      recurrent or convolutional neural networks.</summary>
+    <author><name>Ashish Vaswani</name></author>
+    <author><name>Noam Shazeer</name></author>
+    <author><name>Niki Parmar</name></author>
+    <arxiv:doi>10.48550/arXiv.1706.03762</arxiv:doi>
+    <arxiv:primary_category term="cs.CL" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+func TestFetchArXiv_ParsesAuthorsAndYear(t *testing.T) {
+	var got string
+	SetHTTPClient(testHTTPDoer{status: 200, body: sampleAtom, url: &got})
+	defer SetHTTPClient(&http.Client{})
+
+	e, err := FetchArXiv(context.Background(), "arXiv:1706.03762")
+	if err != nil {
+		t.Fatalf("FetchArXiv: %v", err)
+	}
+	if got != "http://export.arxiv.org/api/query?id_list=1706.03762" {
+		t.Fatalf("requested %q", got)
+	}
+	if e.Type != "article" || e.APA7.Title != "Attention Is All You Need" {
+		t.Fatalf("type/title: %q %q", e.Type, e.APA7.Title)
+	}
+	if e.APA7.Year == nil || *e.APA7.Year != 2017 || e.APA7.Date != "2017-06-12" {
+		t.Fatalf("year/date: %v %q", e.APA7.Year, e.APA7.Date)
+	}
+	if len(e.APA7.Authors) != 3 || e.APA7.Authors[0].Family != "Vaswani" || e.APA7.Authors[0].Given != "A." || e.APA7.Authors[2].Family != "Parmar" {
+		t.Fatalf("authors: %+v", e.APA7.Authors)
+	}
+	if e.APA7.DOI != "10.48550/arXiv.1706.03762" || e.APA7.ArXiv != "1706.03762" || e.APA7.URL != "https://arxiv.org/abs/1706.03762" {
+		t.Fatalf("ids/url: %+v", e.APA7)
+	}
+	if !strings.HasPrefix(e.Annotation.Summary, "The dominant sequence transduction models") {
+		t.Fatalf("summary: %q", e.Annotation.Summary)
+	}
+}
+
+func TestFetchArXiv_Errors(t *testing.T) {
+	if _, err := FetchArXiv(context.Background(), "not-an-id"); err == nil {
+		t.Fatal("expected invalid id error")
+	}
+	empty := `<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>http://arxiv.org/api/errors#incorrect_id_format</id><title>Error</title></entry></feed>`
+	SetHTTPClient(testHTTPDoer{status: 200, body: empty})
+	defer SetHTTPClient(&http.Client{})
+	if _, err := FetchArXiv(context.Background(), "2101.99999"); err == nil || !strings.Contains(err.Error(), "no record") {
+		t.Fatalf("expected no record error, got %v", err)
+	}
+}
+
+func TestNormalizeID(t *testing.T) {
+	cases := map[string]string{
+		"2101.00001":                           "2101.00001",
+		"arXiv:2101.00001v2":                   "2101.00001v2",
+		"https://arxiv.org/abs/2101.00001":     "2101.00001",
+		"https://arxiv.org/pdf/2101.00001.pdf": "2101.00001",
+		"hep-th/9901001":                       "hep-th/9901001",
+		"math.GT/0309136":                      "math.GT/0309136",
+		"10.1234/abc":                          "",
+	}
+	for in, want := range cases {
+		if got := normalizeID(in); got != want {
+			t.Errorf("normalizeID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
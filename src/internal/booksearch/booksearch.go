@@ -9,18 +9,20 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	"bibliography/src/internal/dates"
+	"bibliography/src/internal/googlebooks"
 	"bibliography/src/internal/httpx"
+	isbnpkg "bibliography/src/internal/isbn"
 	"bibliography/src/internal/names"
 	"bibliography/src/internal/openlibrary"
+	"bibliography/src/internal/orcid"
 	"bibliography/src/internal/sanitize"
 	"bibliography/src/internal/schema"
 )
 
 // client is the HTTP client used by this package; replaceable in tests.
-var client httpx.Doer = &http.Client{Timeout: 12 * time.Second}
+var client httpx.Doer = httpx.ProviderClient()
 
 // SetHTTPClient allows tests to inject a fake http client.
 func SetHTTPClient(c httpx.Doer) { client = c }
@@ -32,6 +34,23 @@ type Attempt struct {
 	Error    string
 }
 
+// isbnProvider is one ISBN lookup source, tried in order by LookupBookByISBN.
+type isbnProvider struct {
+	name  string
+	fetch func(context.Context, string) (schema.Entry, error)
+}
+
+// isbnProviders is the ISBN lookup order. OpenLibrary already falls back to Google
+// Books internally; there is no OpenAI last resort for books.
+var isbnProviders = []isbnProvider{
+	{"openlibrary", openlibrary.FetchBookByISBN},
+	{"crossref", fetchCrossrefByISBN},
+	{"oclc", fetchOCLCClassifyByISBN},
+	{"bnb", fetchBNBByISBN},
+	{"openbd", fetchOpenBDByISBN},
+	{"loc", fetchLoCByISBN},
+}
+
 // LookupBookByISBN attempts to fetch book metadata from a sequence of providers.
 // Order:
 //  1. OpenLibrary (includes internal Google Books fallback)
@@ -40,52 +59,35 @@ type Attempt struct {
 //  4. British National Bibliography (BNB) SPARQL
 //  5. openBD (Japan)
 //  6. US Library of Congress
-//  7. OpenAI (last resort)
+//
+// When every provider misses, the sequence is retried with the alternate ISBN form
+// (ISBN-10 for a 978 ISBN-13 and vice versa), since some providers index only one.
+// Those attempts are recorded as "<provider> (ISBN <alt>)"; a hit still stores the
+// requested ISBN.
 func LookupBookByISBN(ctx context.Context, isbn string) (schema.Entry, string, []Attempt, error) {
 	attempts := []Attempt{}
-	// 1) OpenLibrary (already falls back to Google Books internally)
-	if e, err := openlibrary.FetchBookByISBN(ctx, isbn); err == nil {
-		attempts = append(attempts, Attempt{Provider: "openlibrary", Success: true})
-		return e, "openlibrary", attempts, nil
-	} else {
-		attempts = append(attempts, Attempt{Provider: "openlibrary", Success: false, Error: err.Error()})
-	}
-	// 2) Crossref
-	if e, err := fetchCrossrefByISBN(ctx, isbn); err == nil {
-		attempts = append(attempts, Attempt{Provider: "crossref", Success: true})
-		return e, "crossref", attempts, nil
-	} else {
-		attempts = append(attempts, Attempt{Provider: "crossref", Success: false, Error: err.Error()})
-	}
-	// 3) OCLC Classify API (WorldCat)
-	if e, err := fetchOCLCClassifyByISBN(ctx, isbn); err == nil {
-		attempts = append(attempts, Attempt{Provider: "oclc", Success: true})
-		return e, "oclc", attempts, nil
-	} else {
-		attempts = append(attempts, Attempt{Provider: "oclc", Success: false, Error: err.Error()})
-	}
-	// 4) BNB SPARQL
-	if e, err := fetchBNBByISBN(ctx, isbn); err == nil {
-		attempts = append(attempts, Attempt{Provider: "bnb", Success: true})
-		return e, "bnb", attempts, nil
-	} else {
-		attempts = append(attempts, Attempt{Provider: "bnb", Success: false, Error: err.Error()})
-	}
-	// 5) openBD
-	if e, err := fetchOpenBDByISBN(ctx, isbn); err == nil {
-		attempts = append(attempts, Attempt{Provider: "openbd", Success: true})
-		return e, "openbd", attempts, nil
-	} else {
-		attempts = append(attempts, Attempt{Provider: "openbd", Success: false, Error: err.Error()})
-	}
-	// 6) Library of Congress
-	if e, err := fetchLoCByISBN(ctx, isbn); err == nil {
-		attempts = append(attempts, Attempt{Provider: "loc", Success: true})
-		return e, "loc", attempts, nil
-	} else {
-		attempts = append(attempts, Attempt{Provider: "loc", Success: false, Error: err.Error()})
+	forms := []string{isbn}
+	if alt, ok := isbnpkg.Alternate(isbn); ok {
+		forms = append(forms, alt)
+	}
+	for i, form := range forms {
+		for _, p := range isbnProviders {
+			label := p.name
+			if i > 0 {
+				label = fmt.Sprintf("%s (ISBN %s)", p.name, form)
+			}
+			e, err := p.fetch(ctx, form)
+			if err == nil {
+				if i > 0 {
+					// Keep the form that was asked for.
+					e.APA7.ISBN = isbnpkg.Normalize(isbn)
+				}
+				attempts = append(attempts, Attempt{Provider: label, Success: true})
+				return e, p.name, attempts, nil
+			}
+			attempts = append(attempts, Attempt{Provider: label, Success: false, Error: err.Error()})
+		}
 	}
-	// No OpenAI last-resort for books; return an error including attempts
 	return schema.Entry{}, "", attempts, fmt.Errorf("no providers returned data for ISBN %s", strings.TrimSpace(isbn))
 }
 
@@ -130,7 +132,7 @@ func searchOpenLibrary(ctx context.Context, title, author string) (schema.Entry,
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
@@ -196,18 +198,16 @@ func searchGoogleBooks(ctx context.Context, title, author string) (schema.Entry,
 	v := url.Values{}
 	v.Set("q", strings.Join(qparts, "+"))
 	v.Set("maxResults", "1")
-	endpoint := "https://www.googleapis.com/books/v1/volumes?" + v.Encode()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, googlebooks.VolumesURL(v), nil)
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return schema.Entry{}, fmt.Errorf("googlebooks: http %d: %s", resp.StatusCode, string(b))
+		return schema.Entry{}, googlebooks.StatusError(resp)
 	}
 	var r struct {
 		Items []struct {
@@ -269,7 +269,8 @@ func searchCrossref(ctx context.Context, title, author string) (schema.Entry, er
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	httpx.SetCrossrefMailto(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
@@ -281,9 +282,9 @@ func searchCrossref(ctx context.Context, title, author string) (schema.Entry, er
 	var out struct {
 		Message struct {
 			Items []struct {
-				Title     []string                         `json:"title"`
-				Author    []struct{ Given, Family string } `json:"author"`
-				Publisher string                           `json:"publisher"`
+				Title     []string                                `json:"title"`
+				Author    []struct{ Given, Family, ORCID string } `json:"author"`
+				Publisher string                                  `json:"publisher"`
 				Issued    struct {
 					DateParts [][]int `json:"date-parts"`
 				} `json:"issued"`
@@ -308,7 +309,8 @@ func searchCrossref(ctx context.Context, title, author string) (schema.Entry, er
 		fam := strings.TrimSpace(it.Author[0].Family)
 		giv := strings.TrimSpace(it.Author[0].Given)
 		if fam != "" {
-			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: names.Initials(giv)})
+			id, _ := orcid.Parse(it.Author[0].ORCID)
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: names.Initials(giv), ORCID: id})
 		}
 	}
 	e.APA7.Publisher = strings.TrimSpace(it.Publisher)
@@ -343,7 +345,8 @@ func fetchCrossrefByISBN(ctx context.Context, isbn string) (schema.Entry, error)
 	}
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	httpx.SetCrossrefMailto(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
@@ -355,9 +358,10 @@ func fetchCrossrefByISBN(ctx context.Context, isbn string) (schema.Entry, error)
 	var out struct {
 		Message struct {
 			Items []struct {
-				Title          []string                         `json:"title"`
-				Author         []struct{ Given, Family string } `json:"author"`
-				Publisher      string                           `json:"publisher"`
+				Title          []string                                `json:"title"`
+				Author         []struct{ Given, Family, ORCID string } `json:"author"`
+				Editor         []struct{ Given, Family, ORCID string } `json:"editor"`
+				Publisher      string                                  `json:"publisher"`
 				PublishedPrint struct {
 					DateParts [][]int `json:"date-parts"`
 				} `json:"published-print"`
@@ -402,7 +406,14 @@ func fetchCrossrefByISBN(ctx context.Context, isbn string) (schema.Entry, error)
 		fam := strings.TrimSpace(a.Family)
 		giv := strings.TrimSpace(a.Given)
 		if fam != "" {
-			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: names.Initials(giv)})
+			id, _ := orcid.Parse(a.ORCID)
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: names.Initials(giv), ORCID: id})
+		}
+	}
+	for _, a := range it.Editor {
+		if fam := strings.TrimSpace(a.Family); fam != "" {
+			id, _ := orcid.Parse(a.ORCID)
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: names.Initials(a.Given), ORCID: id, Role: schema.RoleEditor})
 		}
 	}
 	if strings.TrimSpace(e.Annotation.Summary) == "" && e.APA7.Title != "" {
@@ -447,7 +458,7 @@ func fetchOCLCClassifyByISBN(ctx context.Context, isbn string) (schema.Entry, er
 	}
 	req.Header.Set("Accept", "application/xml")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
@@ -547,7 +558,7 @@ LIMIT 1`, norm, norm, norm)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/sparql-results+json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
@@ -610,7 +621,7 @@ func fetchOpenBDByISBN(ctx context.Context, isbn string) (schema.Entry, error) {
 	}
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
@@ -675,7 +686,7 @@ func fetchLoCByISBN(ctx context.Context, isbn string) (schema.Entry, error) {
 	}
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
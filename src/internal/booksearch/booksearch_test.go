@@ -6,12 +6,20 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 
+	"bibliography/src/internal/httpx"
 	"bibliography/src/internal/openlibrary"
 )
 
+// TestMain disables retry backoff so failing-provider cases run instantly.
+func TestMain(m *testing.M) {
+	httpx.SetDefaultRetryPolicy(httpx.RetryPolicy{MaxAttempts: 3})
+	os.Exit(m.Run())
+}
+
 // fakeDoer implements httpx.Doer for deterministic responses.
 type fakeDoer struct {
 	handler func(req *http.Request) *http.Response
@@ -252,7 +260,7 @@ func TestFetchCrossrefByISBN_PublishedPrintDate(t *testing.T) {
 						"title":           []string{"CR2 Title"},
 						"publisher":       "CR2 Pub",
 						"published-print": map[string]any{"date-parts": [][]int{{2015, 7, 9}}},
-						"author":          []map[string]string{{"family": "Doe", "given": "John"}},
+						"author":          []map[string]string{{"family": "Doe", "given": "John", "ORCID": "https://orcid.org/0000-0002-1825-0097"}},
 					}},
 				},
 			})
@@ -267,6 +275,9 @@ func TestFetchCrossrefByISBN_PublishedPrintDate(t *testing.T) {
 	if e.APA7.Year == nil || *e.APA7.Year != 2015 {
 		t.Fatalf("expected year 2015, got %+v", e.APA7.Year)
 	}
+	if len(e.APA7.Authors) != 1 || e.APA7.Authors[0].ORCID != "0000-0002-1825-0097" {
+		t.Fatalf("crossref ORCID not mapped: %+v", e.APA7.Authors)
+	}
 }
 
 func TestLookupBookByTitleAuthor_OpenLibrarySearch(t *testing.T) {
@@ -344,6 +355,63 @@ func TestLookupBookByTitleAuthor_CrossrefFallback(t *testing.T) {
 	}
 }
 
+func TestLookupBookByTitleAuthor_GoogleRateLimited(t *testing.T) {
+	t.Setenv("GOOGLE_BOOKS_API_KEY", "k123")
+	var gurl string
+	SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if strings.Contains(req.URL.Host, "openlibrary.org") {
+			return jsonResp(200, map[string]any{"docs": []any{}})
+		}
+		if strings.Contains(req.URL.Host, "googleapis.com") {
+			gurl = req.URL.String()
+			return textResp(429, "quota exceeded")
+		}
+		if strings.Contains(req.URL.Host, "api.crossref.org") {
+			return jsonResp(200, map[string]any{"message": map[string]any{"items": []map[string]any{{
+				"title": []string{"TC"}, "publisher": "CP", "issued": map[string]any{"date-parts": [][]int{{2018}}}, "author": []map[string]string{{"family": "Doe", "given": "J"}}, "URL": "https://cr",
+			}}}})
+		}
+		return textResp(404, "")
+	}})
+	t.Cleanup(func() { SetHTTPClient(&http.Client{}) })
+	_, prov, attempts, err := LookupBookByTitleAuthor(context.Background(), "TC", "Doe")
+	if err != nil || prov != "crossref" {
+		t.Fatalf("expected crossref after rate limit: %v %s", err, prov)
+	}
+	if !strings.Contains(gurl, "key=k123") {
+		t.Fatalf("api key not attached: %q", gurl)
+	}
+	if len(attempts) < 2 || attempts[1].Provider != "googlebooks" || !strings.Contains(attempts[1].Error, "rate limited") {
+		t.Fatalf("attempts: %+v", attempts)
+	}
+}
+
+func TestCrossrefMailto(t *testing.T) {
+	var urls []string
+	SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if req.URL.Host == "api.crossref.org" {
+			urls = append(urls, req.URL.String())
+		}
+		return jsonResp(200, map[string]any{"message": map[string]any{"items": []any{}}})
+	}})
+	t.Cleanup(func() { SetHTTPClient(&http.Client{}) })
+
+	t.Setenv("CROSSREF_MAILTO", "")
+	_, _ = fetchCrossrefByISBN(context.Background(), "9780132350884")
+	_, _ = searchCrossref(context.Background(), "T", "A")
+	t.Setenv("CROSSREF_MAILTO", "lib@example.org")
+	_, _ = fetchCrossrefByISBN(context.Background(), "9780132350884")
+	_, _ = searchCrossref(context.Background(), "T", "A")
+	if len(urls) != 4 {
+		t.Fatalf("crossref requests: %v", urls)
+	}
+	for i, u := range urls {
+		if has := strings.Contains(u, "mailto=lib%40example.org"); has != (i >= 2) {
+			t.Fatalf("request %d mailto presence %v: %s", i, has, u)
+		}
+	}
+}
+
 func TestLookupBookByTitleAuthor_AllFail(t *testing.T) {
 	SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response { return textResp(404, "") }})
 	t.Cleanup(func() { SetHTTPClient(&http.Client{}) })
@@ -353,3 +421,40 @@ func TestLookupBookByTitleAuthor_AllFail(t *testing.T) {
 		t.Fatalf("expected 3 attempts, got %+v", attempts)
 	}
 }
+
+func TestLookupBookByISBN_AlternateFormFallback(t *testing.T) {
+	// Only OpenLibrary knows the book, and only under its ISBN-10.
+	openlibrary.SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response {
+		if strings.Contains(req.URL.Host, "openlibrary.org") && strings.Contains(req.URL.RawQuery, "ISBN%3A0306406152") {
+			return jsonResp(200, map[string]any{
+				"ISBN:0306406152": map[string]any{
+					"title":        "Ten Digit Title",
+					"publish_date": "1985",
+					"publishers":   []map[string]string{{"name": "Pub"}},
+					"authors":      []map[string]string{{"name": "Doe, Jane"}},
+				},
+			})
+		}
+		return textResp(404, "")
+	}})
+	SetHTTPClient(fakeDoer{handler: func(req *http.Request) *http.Response { return textResp(404, "") }})
+	t.Cleanup(func() { openlibrary.SetHTTPClient(&http.Client{}); SetHTTPClient(&http.Client{}) })
+
+	e, prov, attempts, err := LookupBookByISBN(context.Background(), "9780306406157")
+	if err != nil {
+		t.Fatalf("alternate form lookup: %v", err)
+	}
+	if e.APA7.Title != "Ten Digit Title" || prov != "openlibrary" || e.APA7.ISBN != "9780306406157" {
+		t.Fatalf("unexpected result: %s %+v", prov, e)
+	}
+	if len(attempts) != len(isbnProviders)+1 {
+		t.Fatalf("expected a full primary pass plus one alternate attempt, got %+v", attempts)
+	}
+	if attempts[0].Provider != "openlibrary" || attempts[0].Success {
+		t.Fatalf("primary attempt: %+v", attempts[0])
+	}
+	last := attempts[len(attempts)-1]
+	if last.Provider != "openlibrary (ISBN 0306406152)" || !last.Success {
+		t.Fatalf("alternate attempt: %+v", last)
+	}
+}
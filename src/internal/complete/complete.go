@@ -0,0 +1,95 @@
+// Package complete provides dynamic shell-completion functions backed by the
+// metadata indexes, so suggestions do not require parsing the whole library.
+package complete
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/store"
+)
+
+// maxSuggestions caps how many candidates are returned to the shell.
+const maxSuggestions = 200
+
+// IDs completes entry ids by case-insensitive prefix, described by their title. The
+// prefix may also be typed in the short, dashless form used for citation keys
+// ("a00000000000" matches "a0000000-0000-..."); the suggestion is still the full id.
+// It reads titles.json and falls back to the library when no index has been built.
+func IDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	titles := map[string]string{}
+	index, err := store.ReadIndex(store.TitlesJSON)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	if index != nil {
+		for p, words := range index {
+			titles[store.IDFromIndexPath(p)] = strings.Join(words, " ")
+		}
+	} else {
+		entries, err := store.ReadAll()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, e := range entries {
+			titles[e.ID] = e.APA7.Title
+		}
+	}
+	prefix := strings.ToLower(toComplete)
+	short := strings.ReplaceAll(prefix, "-", "")
+	var out []string
+	for id, title := range titles {
+		lid := strings.ToLower(id)
+		if !strings.HasPrefix(lid, prefix) && !strings.HasPrefix(strings.ReplaceAll(lid, "-", ""), short) {
+			continue
+		}
+		if title != "" {
+			out = append(out, id+"\t"+title)
+		} else {
+			out = append(out, id)
+		}
+	}
+	sort.Strings(out)
+	return limit(out), cobra.ShellCompDirectiveNoFileComp
+}
+
+// Keywords completes comma-delimited keyword lists from keywords.json, completing the
+// last item and keeping the ones already typed. Keywords used by more entries come first.
+func Keywords(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	index, err := store.ReadIndex(store.KeywordsJSON)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	typed, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		typed, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	prefix := strings.ToLower(strings.TrimSpace(last))
+	var keys []string
+	for k := range index {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(index[keys[i]]) != len(index[keys[j]]) {
+			return len(index[keys[i]]) > len(index[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, typed+k)
+	}
+	return limit(out), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// limit truncates suggestions to maxSuggestions.
+func limit(out []string) []string {
+	if len(out) > maxSuggestions {
+		out = out[:maxSuggestions]
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package complete
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+func setupLibrary(t *testing.T, buildIndexes bool) {
+	t.Helper()
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	entries := []schema.Entry{
+		{ID: "a0000000-0000-4000-8000-000000000001", Type: "book", APA7: schema.APA7{Title: "Deep Learning"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"ml", "neural"}}},
+		{ID: "a0000000-0000-4000-8000-000000000002", Type: "book", APA7: schema.APA7{Title: "Networks"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"neural"}}},
+		{ID: "b0000000-0000-4000-8000-000000000003", Type: "book", APA7: schema.APA7{Title: "Gardening"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"plants"}}},
+	}
+	for _, e := range entries {
+		if _, err := store.WriteEntry(e); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if buildIndexes {
+		if _, err := store.BuildAllIndexes(entries); err != nil {
+			t.Fatalf("index: %v", err)
+		}
+	}
+}
+
+func TestIDs_FromIndexAndFallback(t *testing.T) {
+	for _, indexed := range []bool{true, false} {
+		setupLibrary(t, indexed)
+		got, dir := IDs(&cobra.Command{}, nil, "A0")
+		if dir != cobra.ShellCompDirectiveNoFileComp {
+			t.Fatalf("directive: %v", dir)
+		}
+		if len(got) != 2 || !strings.HasPrefix(got[0], "a0000000-0000-4000-8000-000000000001\t") || !strings.Contains(strings.ToLower(got[0]), "deep learning") {
+			t.Fatalf("indexed=%v: unexpected ids %q", indexed, got)
+		}
+		// Short (dashless) prefixes match too and complete to the full id.
+		got, _ = IDs(&cobra.Command{}, nil, "a00000000000400080000000000000")
+		if len(got) != 2 || !strings.HasPrefix(got[1], "a0000000-0000-4000-8000-000000000002\t") {
+			t.Fatalf("indexed=%v: short prefix: unexpected ids %q", indexed, got)
+		}
+		if got, _ = IDs(&cobra.Command{}, nil, "b00000000000"); len(got) != 1 || !strings.HasPrefix(got[0], "b0000000-0000-4000-8000-000000000003") {
+			t.Fatalf("indexed=%v: short prefix: unexpected ids %q", indexed, got)
+		}
+	}
+}
+
+func TestKeywords_CompletesLastItem(t *testing.T) {
+	setupLibrary(t, true)
+	got, _ := Keywords(&cobra.Command{}, nil, "ml,ne")
+	if len(got) == 0 || got[0] != "ml,neural" {
+		t.Fatalf("unexpected keywords %q", got)
+	}
+	got, _ = Keywords(&cobra.Command{}, nil, "pl")
+	if len(got) != 1 || got[0] != "plants" {
+		t.Fatalf("unexpected keywords %q", got)
+	}
+}
@@ -0,0 +1,188 @@
+// Package datacite fetches dataset and software metadata from the DataCite REST API.
+package datacite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/names"
+	"bibliography/src/internal/sanitize"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/stringsx"
+)
+
+var client httpx.Doer = httpx.ProviderClient()
+
+// SetHTTPClient allows tests to inject a fake HTTP client.
+func SetHTTPClient(c httpx.Doer) { client = c }
+
+// maxSubjectKeywords caps how many DataCite subjects become keywords.
+const maxSubjectKeywords = 10
+
+// doiResponse is the subset of a DataCite /dois/<doi> response used for citations.
+type doiResponse struct {
+	Data struct {
+		Attributes attributes `json:"attributes"`
+	} `json:"data"`
+}
+
+type attributes struct {
+	DOI    string `json:"doi"`
+	Titles []struct {
+		Title     string `json:"title"`
+		TitleType string `json:"titleType"`
+	} `json:"titles"`
+	Creators []struct {
+		Name       string `json:"name"`
+		NameType   string `json:"nameType"`
+		GivenName  string `json:"givenName"`
+		FamilyName string `json:"familyName"`
+	} `json:"creators"`
+	// Publisher is a string, or an object with a name when requested with ?publisher=true.
+	Publisher       json.RawMessage `json:"publisher"`
+	PublicationYear json.RawMessage `json:"publicationYear"`
+	Types           struct {
+		ResourceTypeGeneral string `json:"resourceTypeGeneral"`
+	} `json:"types"`
+	Descriptions []struct {
+		Description     string `json:"description"`
+		DescriptionType string `json:"descriptionType"`
+	} `json:"descriptions"`
+	Subjects []struct {
+		Subject string `json:"subject"`
+	} `json:"subjects"`
+	Version string `json:"version"`
+}
+
+// FetchByDOI looks up a DOI registered with DataCite and maps it into an Entry of
+// type "software" (resourceTypeGeneral Software) or "dataset" (anything else).
+func FetchByDOI(ctx context.Context, doi string) (schema.Entry, error) {
+	doi = strings.TrimSpace(doi)
+	if doi == "" {
+		return schema.Entry{}, fmt.Errorf("doi is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.datacite.org/dois/"+doi, nil)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.api+json")
+	httpx.SetUA(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return schema.Entry{}, fmt.Errorf("datacite: http %d: %s", resp.StatusCode, string(b))
+	}
+	var body doiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return schema.Entry{}, err
+	}
+	e := mapAttributes(body.Data.Attributes, doi)
+	sanitize.CleanEntry(&e)
+	if err := e.Validate(); err != nil {
+		return schema.Entry{}, err
+	}
+	return e, nil
+}
+
+// mapAttributes converts DataCite attributes into an Entry with a doi.org URL.
+func mapAttributes(a attributes, doi string) schema.Entry {
+	var e schema.Entry
+	e.Type = "dataset"
+	if strings.EqualFold(a.Types.ResourceTypeGeneral, "Software") {
+		e.Type = "software"
+	}
+	for _, t := range a.Titles {
+		// The main title has no titleType (Subtitle, AlternativeTitle, ... otherwise).
+		if strings.TrimSpace(t.TitleType) == "" && strings.TrimSpace(t.Title) != "" {
+			e.APA7.Title = strings.TrimSpace(t.Title)
+			break
+		}
+	}
+	if e.APA7.Title == "" && len(a.Titles) > 0 {
+		e.APA7.Title = strings.TrimSpace(a.Titles[0].Title)
+	}
+	for _, c := range a.Creators {
+		switch {
+		case strings.TrimSpace(c.FamilyName) != "":
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: strings.TrimSpace(c.FamilyName), Given: names.Initials(c.GivenName)})
+		case strings.EqualFold(c.NameType, "Organizational"):
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: strings.TrimSpace(c.Name)})
+		default:
+			if fam, giv := names.Split(c.Name); fam != "" {
+				e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: giv})
+			}
+		}
+	}
+	e.APA7.Publisher = publisherName(a.Publisher)
+	if y := yearOf(a.PublicationYear); y > 0 {
+		e.APA7.Year = &y
+	}
+	e.APA7.DOI = stringsx.FirstNonEmpty(a.DOI, doi)
+	e.APA7.URL = "https://doi.org/" + e.APA7.DOI
+	e.APA7.Accessed = dates.NowISO()
+	e.ID = schema.NewID()
+
+	for _, d := range a.Descriptions {
+		if strings.EqualFold(d.DescriptionType, "Abstract") && strings.TrimSpace(d.Description) != "" {
+			e.Annotation.Summary = strings.Join(strings.Fields(d.Description), " ")
+			break
+		}
+	}
+	if e.Annotation.Summary == "" {
+		if e.APA7.Publisher != "" {
+			e.Annotation.Summary = fmt.Sprintf("Bibliographic record for the %s %s published by %s via DataCite.", e.Type, e.APA7.Title, e.APA7.Publisher)
+		} else {
+			e.Annotation.Summary = fmt.Sprintf("Bibliographic record for the %s %s via DataCite.", e.Type, e.APA7.Title)
+		}
+	}
+	subjects := make([]string, 0, len(a.Subjects))
+	for _, s := range a.Subjects {
+		subjects = append(subjects, s.Subject)
+	}
+	e.Annotation.Keywords = []string{e.Type}
+	for _, k := range sanitize.CleanKeywords(subjects) {
+		if len(e.Annotation.Keywords) > maxSubjectKeywords {
+			break
+		}
+		if k != e.Type {
+			e.Annotation.Keywords = append(e.Annotation.Keywords, k)
+		}
+	}
+	return e
+}
+
+// publisherName reads the publisher as a plain string or as {"name": ...}.
+func publisherName(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+	var o struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &o); err == nil {
+		return strings.TrimSpace(o.Name)
+	}
+	return ""
+}
+
+// yearOf reads publicationYear, which DataCite serves as a number or a string.
+func yearOf(raw json.RawMessage) int {
+	s := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	y, err := strconv.Atoi(s)
+	if err != nil || y <= 0 {
+		return 0
+	}
+	return y
+}
@@ -0,0 +1,87 @@
+package datacite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testHTTP struct {
+	status int
+	body   string
+	url    *string
+}
+
+func (t testHTTP) Do(req *http.Request) (*http.Response, error) {
+	if t.url != nil {
+		*t.url = req.URL.String()
+	}
+	return &http.Response{StatusCode: t.status, Body: io.NopCloser(strings.NewReader(t.body)), Header: make(http.Header)}, nil
+}
+
+const softwareJSON = `{"data":{"id":"10.5281/zenodo.1234","type":"dois","attributes":{
+  "doi":"10.5281/zenodo.1234",
+  "titles":[{"title":"toolkit: A Toolkit"},{"title":"Alt","titleType":"AlternativeTitle"}],
+  "creators":[{"name":"Doe, Jane","nameType":"Personal","givenName":"Jane Q","familyName":"Doe"},{"name":"ACME Lab","nameType":"Organizational"}],
+  "publisher":"Zenodo",
+  "publicationYear":2022,
+  "types":{"resourceTypeGeneral":"Software"},
+  "descriptions":[{"description":"A  toolkit\nfor things.","descriptionType":"Abstract"}],
+  "subjects":[{"subject":"Data Processing"},{"subject":"software"}]
+}}}`
+
+func TestFetchByDOI_Software(t *testing.T) {
+	var got string
+	SetHTTPClient(testHTTP{status: 200, body: softwareJSON, url: &got})
+	defer SetHTTPClient(&http.Client{})
+
+	e, err := FetchByDOI(context.Background(), "10.5281/zenodo.1234")
+	if err != nil {
+		t.Fatalf("FetchByDOI: %v", err)
+	}
+	if got != "https://api.datacite.org/dois/10.5281/zenodo.1234" {
+		t.Fatalf("requested %q", got)
+	}
+	if e.Type != "software" || e.APA7.Title != "toolkit: A Toolkit" || e.APA7.Publisher != "Zenodo" {
+		t.Fatalf("bad mapping: %+v", e)
+	}
+	if e.APA7.Year == nil || *e.APA7.Year != 2022 || e.APA7.DOI != "10.5281/zenodo.1234" || e.APA7.URL != "https://doi.org/10.5281/zenodo.1234" {
+		t.Fatalf("year/doi/url: %+v", e.APA7)
+	}
+	if len(e.APA7.Authors) != 2 || e.APA7.Authors[0].Given != "J. Q." || e.APA7.Authors[1].Family != "ACME Lab" {
+		t.Fatalf("authors: %+v", e.APA7.Authors)
+	}
+	if e.Annotation.Summary != "A toolkit for things." {
+		t.Fatalf("summary: %q", e.Annotation.Summary)
+	}
+	if strings.Join(e.Annotation.Keywords, "|") != "software|data processing" {
+		t.Fatalf("keywords: %v", e.Annotation.Keywords)
+	}
+}
+
+func TestFetchByDOI_DatasetDefaults(t *testing.T) {
+	body := `{"data":{"attributes":{"titles":[{"title":"Survey Data"}],"publisher":{"name":"Dryad"},"publicationYear":"2019","types":{"resourceTypeGeneral":"Dataset"}}}}`
+	SetHTTPClient(testHTTP{status: 200, body: body})
+	defer SetHTTPClient(&http.Client{})
+
+	e, err := FetchByDOI(context.Background(), "10.5061/dryad.x1")
+	if err != nil {
+		t.Fatalf("FetchByDOI: %v", err)
+	}
+	if e.Type != "dataset" || e.APA7.Publisher != "Dryad" || e.APA7.Year == nil || *e.APA7.Year != 2019 || e.APA7.DOI != "10.5061/dryad.x1" {
+		t.Fatalf("bad mapping: %+v", e)
+	}
+	if !strings.Contains(e.Annotation.Summary, "published by Dryad") || strings.Join(e.Annotation.Keywords, "|") != "dataset" {
+		t.Fatalf("annotation: %+v", e.Annotation)
+	}
+}
+
+func TestFetchByDOI_HTTPError(t *testing.T) {
+	SetHTTPClient(testHTTP{status: 404, body: "DOI not found"})
+	defer SetHTTPClient(&http.Client{})
+	if _, err := FetchByDOI(context.Background(), "10.5281/none"); err == nil || !strings.Contains(err.Error(), "datacite: http 404") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+}
@@ -18,13 +18,47 @@ func YearFromDate(date string) int {
 	return 0
 }
 
+// dateLayouts are the accepted input layouts for NormalizeToISO, each paired with the
+// precision it carries (4 = year, 7 = year-month, 10 = full date).
+var dateLayouts = []struct {
+	layout    string
+	precision int
+}{
+	{"2006-01-02", 10}, {"2006-1-2", 10}, {"2006/01/02", 10}, {"2006/1/2", 10}, {"2006.01.02", 10},
+	{"January 2, 2006", 10}, {"Jan 2, 2006", 10}, {"2 January 2006", 10}, {"2 Jan 2006", 10},
+	{"2006-01", 7}, {"2006-1", 7}, {"2006/01", 7}, {"2006/1", 7},
+	{"January 2006", 7}, {"Jan 2006", 7},
+	{"2006", 4},
+}
+
+// NormalizeToISO parses a year-only, year-month, or full date in common layouts
+// (including RFC 3339 timestamps) and returns it at the precision given: "YYYY",
+// "YYYY-MM", or "YYYY-MM-DD". Empty input yields "".
+func NormalizeToISO(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Format("2006-01-02"), nil
+	}
+	for _, l := range dateLayouts {
+		t, err := time.Parse(l.layout, s)
+		if err != nil {
+			continue
+		}
+		return t.Format("2006-01-02")[:l.precision], nil
+	}
+	return "", fmt.Errorf("unrecognized date %q (want YYYY, YYYY-MM, or YYYY-MM-DD)", s)
+}
+
 // ExtractYear scans a string and returns a plausible 4-digit year if found.
 func ExtractYear(s string) int {
 	s = strings.TrimSpace(s)
 	for i := 0; i+4 <= len(s); i++ {
 		var y int
 		if _, err := fmt.Sscanf(s[i:i+4], "%d", &y); err == nil {
-			if y >= 1000 && y <= time.Now().Year()+1 {
+			if y >= 1000 && y <= Now().Year()+1 {
 				return y
 			}
 		}
@@ -32,5 +66,19 @@ func ExtractYear(s string) int {
 	return 0
 }
 
+// clock supplies the current time; pinned via SetClock for tests and reproducible runs.
+var clock = time.Now
+
+// SetClock replaces the clock used for "now" (e.g., accessed dates); nil restores time.Now.
+func SetClock(f func() time.Time) {
+	if f == nil {
+		f = time.Now
+	}
+	clock = f
+}
+
+// Now returns the current time from the configured clock.
+func Now() time.Time { return clock() }
+
 // NowISO returns the current UTC date as YYYY-MM-DD.
-func NowISO() string { return time.Now().UTC().Format("2006-01-02") }
+func NowISO() string { return Now().UTC().Format("2006-01-02") }
@@ -41,3 +41,47 @@ func TestNowISO(t *testing.T) {
 		t.Fatalf("NowISO not today: got %q want %q", got, today)
 	}
 }
+
+func TestSetClock(t *testing.T) {
+	t.Cleanup(func() { SetClock(nil) })
+	SetClock(func() time.Time { return time.Date(2001, 2, 3, 23, 0, 0, 0, time.UTC) })
+	if got := NowISO(); got != "2001-02-03" {
+		t.Fatalf("pinned NowISO: got %q", got)
+	}
+	if got := ExtractYear("in 2003"); got != 0 {
+		t.Fatalf("ExtractYear should respect pinned clock: got %d", got)
+	}
+	SetClock(nil)
+	if !Now().After(time.Date(2001, 2, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("SetClock(nil) should restore time.Now")
+	}
+}
+
+func TestNormalizeToISO(t *testing.T) {
+	cases := map[string]string{
+		"1999":                 "1999",
+		" 1999 ":               "1999",
+		"1999-5":               "1999-05",
+		"1999/05":              "1999-05",
+		"May 1999":             "1999-05",
+		"September 1999":       "1999-09",
+		"1999-05-03":           "1999-05-03",
+		"1999-5-3":             "1999-05-03",
+		"1999/05/03":           "1999-05-03",
+		"May 3, 1999":          "1999-05-03",
+		"3 May 1999":           "1999-05-03",
+		"1999-05-03T10:00:00Z": "1999-05-03",
+		"":                     "",
+	}
+	for in, want := range cases {
+		got, err := NormalizeToISO(in)
+		if err != nil || got != want {
+			t.Fatalf("NormalizeToISO(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"soon", "1999-13", "99", "1999-02-30"} {
+		if got, err := NormalizeToISO(bad); err == nil {
+			t.Fatalf("NormalizeToISO(%q) should fail, got %q", bad, got)
+		}
+	}
+}
@@ -7,42 +7,114 @@ import (
 	"io"
 	"net/http"
 	"strings"
-	"time"
+	"sync/atomic"
 
 	"bibliography/src/internal/dates"
 	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/lang"
 	"bibliography/src/internal/names"
+	"bibliography/src/internal/orcid"
 	"bibliography/src/internal/sanitize"
 	"bibliography/src/internal/schema"
 )
 
-var client httpx.Doer = &http.Client{Timeout: 10 * time.Second}
+var client httpx.Doer = httpx.ProviderClient()
 
 // SetHTTPClient allows tests to inject a fake HTTP client.
 func SetHTTPClient(c httpx.Doer) { client = c }
 
-// FetchArticleByDOI uses doi.org content negotiation (CSL JSON) to build an Entry.
+// maxSubjectKeywords caps how many CSL subjects become keywords; atomic because batch
+// adds fetch articles concurrently.
+var maxSubjectKeywords atomic.Int64
+
+func init() { maxSubjectKeywords.Store(10) }
+
+// SetMaxKeywords sets the cap on subject-derived keywords; 0 disables subject enrichment.
+func SetMaxKeywords(n int) {
+	if n < 0 {
+		n = 0
+	}
+	maxSubjectKeywords.Store(int64(n))
+}
+
+// Providers reported by FetchArticleByDOIWithProvider.
+const (
+	ProviderDOI      = "doi.org"
+	ProviderCrossref = "crossref"
+)
+
+// FetchArticleByDOI uses doi.org content negotiation (CSL JSON) to build an Entry,
+// falling back to the Crossref works API when doi.org fails.
 func FetchArticleByDOI(ctx context.Context, doi string) (schema.Entry, error) {
-	u := "https://doi.org/" + strings.TrimSpace(doi)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	e, _, err := FetchArticleByDOIWithProvider(ctx, doi)
+	return e, err
+}
+
+// FetchArticleByDOIWithProvider is FetchArticleByDOI that also returns the provider
+// whose metadata was used (ProviderDOI or ProviderCrossref).
+func FetchArticleByDOIWithProvider(ctx context.Context, doi string) (schema.Entry, string, error) {
+	doi = strings.TrimSpace(doi)
+	csl, err := fetchDOIOrg(ctx, doi)
+	if err == nil {
+		e, verr := entryFromCSL(csl, doi)
+		return e, ProviderDOI, verr
+	}
+	csl, cerr := fetchCrossref(ctx, doi)
+	if cerr != nil {
+		return schema.Entry{}, "", fmt.Errorf("%w; %v", err, cerr)
+	}
+	e, verr := entryFromCSL(csl, doi)
+	return e, ProviderCrossref, verr
+}
+
+// fetchDOIOrg retrieves CSL JSON for a DOI via doi.org content negotiation.
+func fetchDOIOrg(ctx context.Context, doi string) (CSL, error) {
+	var csl CSL
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://doi.org/"+doi, nil)
 	if err != nil {
-		return schema.Entry{}, err
+		return csl, err
 	}
 	req.Header.Set("Accept", "application/vnd.citationstyles.csl+json")
+	return csl, getJSON(req, "doi", &csl)
+}
+
+// fetchCrossref retrieves a work from the Crossref API; its "message" object uses
+// the same field names as CSL JSON.
+func fetchCrossref(ctx context.Context, doi string) (CSL, error) {
+	var body struct {
+		Message CSL `json:"message"`
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.crossref.org/works/"+doi, nil)
+	if err != nil {
+		return CSL{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	httpx.SetCrossrefMailto(req)
+	if err := getJSON(req, "crossref", &body); err != nil {
+		return CSL{}, err
+	}
+	return body.Message, nil
+}
+
+// getJSON performs req and decodes a 200 response into v; other statuses are
+// reported as "<label>: http <code>: <body>".
+func getJSON(req *http.Request, label string, v any) error {
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
-		return schema.Entry{}, err
+		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return schema.Entry{}, fmt.Errorf("doi: http %d: %s", resp.StatusCode, string(b))
-	}
-	var csl CSL
-	if err := json.NewDecoder(resp.Body).Decode(&csl); err != nil {
-		return schema.Entry{}, err
+		return fmt.Errorf("%s: http %d: %s", label, resp.StatusCode, string(b))
 	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// entryFromCSL maps CSL metadata to a validated Entry with a canonical doi.org URL.
+func entryFromCSL(csl CSL, doi string) (schema.Entry, error) {
+	u := "https://doi.org/" + doi
 	e := mapCSLToEntry(csl)
 	sanitize.CleanEntry(&e)
 	// Canonical URL: use doi.org link per requirement
@@ -76,6 +148,7 @@ func FetchArticleByDOI(ctx context.Context, doi string) (schema.Entry, error) {
 type CSL struct {
 	Title          any         `json:"title"`
 	Author         []CSLAuthor `json:"author"`
+	Editor         []CSLAuthor `json:"editor"`
 	ContainerTitle any         `json:"container-title"`
 	Issued         CSLIssued   `json:"issued"`
 	Volume         string      `json:"volume"`
@@ -85,11 +158,14 @@ type CSL struct {
 	URL            string      `json:"URL"`
 	Publisher      string      `json:"publisher"`
 	Type           string      `json:"type"`
+	Subject        []string    `json:"subject"`
+	Language       string      `json:"language"`
 }
 
 type CSLAuthor struct {
 	Given  string `json:"given"`
 	Family string `json:"family"`
+	ORCID  string `json:"ORCID"` // an orcid.org URL
 }
 
 type CSLIssued struct {
@@ -114,15 +190,43 @@ func mapCSLToEntry(c CSL) schema.Entry {
 	e.APA7.Pages = c.Page
 	e.APA7.DOI = strings.TrimSpace(c.DOI)
 	e.APA7.Publisher = c.Publisher
+	e.APA7.Language = lang.Normalize(c.Language)
 	for _, a := range c.Author {
 		if strings.TrimSpace(a.Family) == "" {
 			continue
 		}
-		e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: a.Family, Given: names.Initials(a.Given)})
+		id, _ := orcid.Parse(a.ORCID)
+		e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: a.Family, Given: names.Initials(a.Given), ORCID: id})
+	}
+	for _, a := range c.Editor {
+		if strings.TrimSpace(a.Family) == "" {
+			continue
+		}
+		id, _ := orcid.Parse(a.ORCID)
+		e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: a.Family, Given: names.Initials(a.Given), ORCID: id, Role: schema.RoleEditor})
+	}
+	if ks := subjectKeywords(c.Subject); len(ks) > 0 {
+		e.Annotation.Keywords = append([]string{"article"}, ks...)
 	}
 	return e
 }
 
+// subjectKeywords normalizes CSL subjects (lowercased, deduped) and applies the cap.
+func subjectKeywords(subjects []string) []string {
+	ks := sanitize.CleanKeywords(subjects)
+	var out []string
+	limit := int(maxSubjectKeywords.Load())
+	for _, k := range ks {
+		if len(out) >= limit {
+			break
+		}
+		if k != "article" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
 // yearAndDate extracts a year and optional YYYY-MM-DD string from CSL issued.
 func yearAndDate(i CSLIssued) (int, string) {
 	if len(i.DateParts) == 0 || len(i.DateParts[0]) == 0 {
@@ -1,6 +1,7 @@
 package doi
 
 import (
+	"bibliography/src/internal/httpx"
 	namespkg "bibliography/src/internal/names"
 	"context"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 type testHTTP struct {
@@ -72,6 +74,33 @@ func TestFetchArticleByDOI_Success(t *testing.T) {
 	}
 }
 
+func TestFetchArticleByDOI_AuthorORCIDAndEditors(t *testing.T) {
+	csl := `{
+        "title": "Identified Authors",
+        "author": [{"family":"Carberry","given":"Josiah","ORCID":"http://orcid.org/0000-0002-1825-0097"},{"family":"Doe","given":"Jane","ORCID":"http://orcid.org/0000-0002-1825-0098"}],
+        "editor": [{"family":"Roe","given":"Rick"}],
+        "issued": {"date-parts": [[2023]]},
+        "DOI": "10.1234/orcid"
+    }`
+	old := client
+	SetHTTPClient(testHTTP{status: 200, body: csl})
+	defer SetHTTPClient(old)
+
+	e, err := FetchArticleByDOI(context.Background(), "10.1234/orcid")
+	if err != nil {
+		t.Fatalf("FetchArticleByDOI: %v", err)
+	}
+	if got := e.APA7.Authors[0].ORCID; got != "0000-0002-1825-0097" {
+		t.Fatalf("orcid = %q", got)
+	}
+	if got := e.APA7.Authors[1].ORCID; got != "" {
+		t.Fatalf("orcid with a bad check digit should be dropped, got %q", got)
+	}
+	if len(e.APA7.Authors) != 3 || e.APA7.Authors[2].Family != "Roe" || e.APA7.Authors[2].Role != "editor" {
+		t.Fatalf("editor not mapped: %+v", e.APA7.Authors)
+	}
+}
+
 func TestFetchArticleByDOI_HTTPError(t *testing.T) {
 	old := client
 	SetHTTPClient(testHTTP{status: 404, body: "not found"})
@@ -100,3 +129,229 @@ func TestHelpers_ToStringAndDate(t *testing.T) {
 		t.Fatalf("toInitials")
 	}
 }
+
+func TestFetchArticleByDOI_SubjectsBecomeKeywords(t *testing.T) {
+	csl := `{
+        "title": "Subject Rich",
+        "author": [{"family":"Doe","given":"Jane"}],
+        "container-title": "Journal of Things",
+        "issued": {"date-parts": [[2022]]},
+        "DOI": "10.1234/subj",
+        "subject": ["Computer Science Applications", "General Medicine", "computer science applications", "Article", "Software"]
+    }`
+	old := client
+	SetHTTPClient(testHTTP{status: 200, body: csl})
+	defer SetHTTPClient(old)
+	defer SetMaxKeywords(10)
+
+	e, err := FetchArticleByDOI(context.Background(), "10.1234/subj")
+	if err != nil {
+		t.Fatalf("FetchArticleByDOI: %v", err)
+	}
+	want := []string{"article", "computer science applications", "general medicine", "software"}
+	if strings.Join(e.Annotation.Keywords, "|") != strings.Join(want, "|") {
+		t.Fatalf("keywords: got %v want %v", e.Annotation.Keywords, want)
+	}
+
+	SetMaxKeywords(1)
+	e, err = FetchArticleByDOI(context.Background(), "10.1234/subj")
+	if err != nil {
+		t.Fatalf("FetchArticleByDOI capped: %v", err)
+	}
+	if strings.Join(e.Annotation.Keywords, "|") != "article|computer science applications" {
+		t.Fatalf("capped keywords: %v", e.Annotation.Keywords)
+	}
+
+	SetMaxKeywords(0)
+	e, err = FetchArticleByDOI(context.Background(), "10.1234/subj")
+	if err != nil {
+		t.Fatalf("FetchArticleByDOI disabled: %v", err)
+	}
+	if strings.Join(e.Annotation.Keywords, "|") != "article" {
+		t.Fatalf("subjects should be disabled: %v", e.Annotation.Keywords)
+	}
+}
+
+type routeHTTP func(req *http.Request) (int, string)
+
+func (f routeHTTP) Do(req *http.Request) (*http.Response, error) {
+	code, body := f(req)
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+}
+
+func TestFetchArticleByDOI_CrossrefFallback(t *testing.T) {
+	works := `{"status":"ok","message-type":"work","message":{
+        "title": ["Fallback Article"],
+        "author": [{"family":"Roe","given":"Ann"}],
+        "container-title": ["Journal of Fallbacks"],
+        "issued": {"date-parts": [[2021,3]]},
+        "DOI": "10.1234/fallback",
+        "volume": "4",
+        "page": "1-9"
+    }}`
+	var hosts []string
+	old := client
+	SetHTTPClient(routeHTTP(func(req *http.Request) (int, string) {
+		hosts = append(hosts, req.URL.Host)
+		if req.URL.Host == "api.crossref.org" && req.URL.Path == "/works/10.1234/fallback" {
+			return 200, works
+		}
+		return 404, "DOI not found"
+	}))
+	defer SetHTTPClient(old)
+
+	e, prov, err := FetchArticleByDOIWithProvider(context.Background(), "10.1234/fallback")
+	if err != nil {
+		t.Fatalf("fallback: %v", err)
+	}
+	if prov != ProviderCrossref || strings.Join(hosts, ",") != "doi.org,api.crossref.org" {
+		t.Fatalf("provider %q, hosts %v", prov, hosts)
+	}
+	if e.APA7.Title != "Fallback Article" || e.APA7.Journal != "Journal of Fallbacks" || e.APA7.Date != "2021-03-01" {
+		t.Fatalf("bad mapping: %+v", e.APA7)
+	}
+	if e.APA7.URL != "https://doi.org/10.1234/fallback" || len(e.APA7.Authors) != 1 || e.APA7.Authors[0].Given != "A." {
+		t.Fatalf("url/authors: %+v", e.APA7)
+	}
+}
+
+func TestFetchArticleByDOI_CrossrefMailto(t *testing.T) {
+	works := `{"message":{"title":["Polite"],"author":[{"family":"Roe","given":"Ann"}],"DOI":"10.1234/polite"}}`
+	var crossref string
+	old := client
+	SetHTTPClient(routeHTTP(func(req *http.Request) (int, string) {
+		if req.URL.Host == "api.crossref.org" {
+			crossref = req.URL.String()
+			return 200, works
+		}
+		return 404, "DOI not found"
+	}))
+	defer SetHTTPClient(old)
+
+	for _, mailto := range []string{"", "lib@example.org"} {
+		t.Setenv("CROSSREF_MAILTO", mailto)
+		if _, _, err := FetchArticleByDOIWithProvider(context.Background(), "10.1234/polite"); err != nil {
+			t.Fatalf("fallback: %v", err)
+		}
+		want := "https://api.crossref.org/works/10.1234/polite"
+		if mailto != "" {
+			want += "?mailto=lib%40example.org"
+		}
+		if crossref != want {
+			t.Fatalf("CROSSREF_MAILTO=%q: requested %q, want %q", mailto, crossref, want)
+		}
+	}
+}
+
+func TestFetchArticleByDOI_SendsConfiguredUserAgent(t *testing.T) {
+	t.Setenv("BIB_USER_AGENT", "bib-test/1.0")
+	var uas []string
+	old := client
+	SetHTTPClient(routeHTTP(func(req *http.Request) (int, string) {
+		uas = append(uas, req.Header.Get("User-Agent"))
+		if req.URL.Host == "api.crossref.org" {
+			return 200, `{"message":{"title":["UA"],"author":[{"family":"Roe","given":"Ann"}],"DOI":"10.1234/ua"}}`
+		}
+		return 404, "DOI not found"
+	}))
+	defer SetHTTPClient(old)
+	if _, err := FetchArticleByDOI(context.Background(), "10.1234/ua"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if strings.Join(uas, ",") != "bib-test/1.0,bib-test/1.0" {
+		t.Fatalf("user agents: %q", uas)
+	}
+}
+
+func TestFetchArticleByDOI_PrefersDOIOrg(t *testing.T) {
+	old := client
+	SetHTTPClient(testHTTP{status: 200, body: `{"title":"Direct","container-title":"J","issued":{"date-parts":[[2020]]}}`})
+	defer SetHTTPClient(old)
+	if _, prov, err := FetchArticleByDOIWithProvider(context.Background(), "10.1234/direct"); err != nil || prov != ProviderDOI {
+		t.Fatalf("provider %q err %v", prov, err)
+	}
+}
+
+func TestFetchArticleByDOI_BothFail(t *testing.T) {
+	old := client
+	SetHTTPClient(testHTTP{status: 404, body: "nope"})
+	defer SetHTTPClient(old)
+	_, _, err := FetchArticleByDOIWithProvider(context.Background(), "10.1234/none")
+	if err == nil || !strings.Contains(err.Error(), "doi: http 404") || !strings.Contains(err.Error(), "crossref: http 404") {
+		t.Fatalf("expected both failures reported, got %v", err)
+	}
+}
+
+// flakyHTTP returns 503 for the first fails calls, then body.
+type flakyHTTP struct {
+	fails, calls *int
+	body         string
+}
+
+func (f flakyHTTP) Do(req *http.Request) (*http.Response, error) {
+	*f.calls++
+	if *f.calls <= *f.fails {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader("busy")), Header: make(http.Header)}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(f.body)), Header: make(http.Header)}, nil
+}
+
+func TestFetchArticleByDOI_RetriesTransientFailures(t *testing.T) {
+	oldPolicy := httpx.DefaultRetryPolicy()
+	httpx.SetDefaultRetryPolicy(httpx.RetryPolicy{MaxAttempts: 3})
+	defer httpx.SetDefaultRetryPolicy(oldPolicy)
+	old := client
+	defer SetHTTPClient(old)
+
+	fails, calls := 2, 0
+	SetHTTPClient(flakyHTTP{fails: &fails, calls: &calls, body: `{"title":"Retried","container-title":"J","issued":{"date-parts":[[2020]]},"author":[{"family":"Doe","given":"J"}]}`})
+	e, provider, err := FetchArticleByDOIWithProvider(context.Background(), "10.1234/flaky")
+	if err != nil || provider != ProviderDOI || e.APA7.Title != "Retried" || calls != 3 {
+		t.Fatalf("e=%q provider=%q err=%v calls=%d", e.APA7.Title, provider, err, calls)
+	}
+}
+
+func TestFetchArticleByDOI_ReplaysFromCache(t *testing.T) {
+	oldPolicy := httpx.DefaultRetryPolicy()
+	httpx.SetDefaultRetryPolicy(httpx.RetryPolicy{MaxAttempts: 1})
+	defer httpx.SetDefaultRetryPolicy(oldPolicy)
+	old := client
+	defer SetHTTPClient(old)
+	live := &testHTTP{status: 200, body: `{"title":"Cached Article","container-title":"J","issued":{"date-parts":[[2020]]},"author":[{"family":"Doe","given":"J"}]}`}
+	SetHTTPClient(&httpx.CachingDoer{Next: routeHTTP(func(*http.Request) (int, string) { return live.status, live.body }), Dir: t.TempDir(), TTL: func() time.Duration { return time.Hour }})
+	if _, err := FetchArticleByDOI(context.Background(), "10.1234/cached"); err != nil {
+		t.Fatalf("first lookup: %v", err)
+	}
+	live.status, live.body = 503, "down"
+	e, err := FetchArticleByDOI(context.Background(), "10.1234/cached")
+	if err != nil || e.APA7.Title != "Cached Article" {
+		t.Fatalf("second lookup should come from the cache: %q %v", e.APA7.Title, err)
+	}
+	if _, err := FetchArticleByDOI(context.Background(), "10.1234/uncached"); err == nil {
+		t.Fatalf("an uncached DOI should hit the failing live client")
+	}
+}
+
+func TestFetchArticleByDOI_CrossrefLanguage(t *testing.T) {
+	works := `{"status":"ok","message":{"title":["Über Dinge"],"author":[{"family":"Müller","given":"Karl"}],
+        "container-title":["Zeitschrift für Dinge"],"issued":{"date-parts":[[1999]]},"DOI":"10.1234/de","language":"de"}}`
+	old := client
+	SetHTTPClient(routeHTTP(func(req *http.Request) (int, string) {
+		if req.URL.Host == "api.crossref.org" {
+			return 200, works
+		}
+		return 404, "DOI not found"
+	}))
+	defer SetHTTPClient(old)
+
+	e, err := FetchArticleByDOI(context.Background(), "10.1234/de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.APA7.Language != "de" {
+		t.Fatalf("language = %q, want de", e.APA7.Language)
+	}
+	if c := mapCSLToEntry(CSL{Title: "T", Language: "en-US"}); c.APA7.Language != "en" {
+		t.Fatalf("en-US normalizes to %q", c.APA7.Language)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Runner abstracts command execution for testability.
@@ -17,6 +19,7 @@ type defaultRunner struct{}
 // Run executes the named program with args and returns stdout, stderr, and error.
 func (defaultRunner) Run(name string, args ...string) (string, string, error) {
 	cmd := exec.Command(name, args...)
+	cmd.Dir = workDir
 	var out, errB bytes.Buffer
 	cmd.Stdout = &out
 	cmd.Stderr = &errB
@@ -26,6 +29,22 @@ func (defaultRunner) Run(name string, args ...string) (string, string, error) {
 
 var runner Runner = defaultRunner{}
 
+// workDir is where git runs; empty means the process working directory.
+var workDir string
+
+// SetDir runs git in dir (e.g., a data directory kept in another repository) instead
+// of the working directory. Paths given to CommitAndPush are made absolute first so
+// they still name the same files.
+func SetDir(dir string) { workDir = dir }
+
+// ToolTrailer is appended to every commit made through CommitAndPush so that
+// `bib undo` can tell the tool's commits apart from manual ones.
+const ToolTrailer = "Bib-Tool: bib"
+
+// UndoTrailer, followed by the reverted hash, marks the commits made by `bib undo` so
+// that a second undo does not revert the first.
+const UndoTrailer = "Bib-Undo: "
+
 // CommitAndPush stages the given paths, commits with message, and pushes.
 // Treats "nothing to commit" as success.
 func CommitAndPush(paths []string, message string) error {
@@ -43,7 +62,15 @@ func CommitAndPush(paths []string, message string) error {
 
 // gitAdd stages additions, modifications, and deletions for the provided paths.
 func gitAdd(paths []string) error {
-	args := append([]string{"add", "-A"}, paths...)
+	args := []string{"add", "-A"}
+	for _, p := range paths {
+		if workDir != "" {
+			if abs, err := filepath.Abs(p); err == nil {
+				p = abs
+			}
+		}
+		args = append(args, p)
+	}
 	if _, stderr, err := runner.Run("git", args...); err != nil {
 		return fmt.Errorf("git add failed: %v: %s", err, stderr)
 	}
@@ -53,7 +80,7 @@ func gitAdd(paths []string) error {
 // gitCommit attempts to create a commit. It returns (noChange=true) when there
 // is nothing to commit, which callers treat as success.
 func gitCommit(message string) (noChange bool, err error) {
-	stdout, stderr, runErr := runner.Run("git", "commit", "-m", message)
+	stdout, stderr, runErr := runner.Run("git", "commit", "-m", message, "-m", ToolTrailer)
 	if runErr == nil {
 		return false, nil
 	}
@@ -89,3 +116,105 @@ func gitPushWithFallback() error {
 	}
 	return nil
 }
+
+// FirstCommitTimes returns, for each of the given paths tracked by git, the commit
+// time of the commit that first added it. Paths are matched relative to the current
+// directory; untracked paths are simply absent from the result.
+func FirstCommitTimes(paths []string) (map[string]time.Time, error) {
+	out := map[string]time.Time{}
+	if len(paths) == 0 {
+		return out, nil
+	}
+	args := append([]string{"log", "--diff-filter=A", "--relative", "--name-only", "--format=" + commitMarker + "%cI", "--"}, paths...)
+	stdout, stderr, err := runner.Run("git", args...)
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %v: %s", err, stderr)
+	}
+	var current time.Time
+	for _, line := range strings.Split(stdout, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, commitMarker) {
+			t, perr := time.Parse(time.RFC3339, strings.TrimPrefix(line, commitMarker))
+			if perr != nil {
+				return nil, fmt.Errorf("git log: invalid commit time %q: %v", line, perr)
+			}
+			current = t
+			continue
+		}
+		if current.IsZero() {
+			continue
+		}
+		// git log lists newest first; keep the earliest add for re-added files
+		if prev, ok := out[line]; !ok || current.Before(prev) {
+			out[line] = current
+		}
+	}
+	return out, nil
+}
+
+// commitMarker prefixes commit header lines in FirstCommitTimes' git log output.
+const commitMarker = "commit:"
+
+// Commit describes a single git commit.
+type Commit struct {
+	Hash    string
+	Subject string
+	Files   []string // paths changed by the commit, relative to the repository root
+	Tool    bool     // true when the commit carries ToolTrailer
+	UndoOf  string   // the hash named by UndoTrailer when the commit is a bib undo
+}
+
+// HeadCommit returns the HEAD commit with its changed files and whether bib made it.
+func HeadCommit() (Commit, error) {
+	stdout, stderr, err := runner.Run("git", "log", "-1", "--format=%H%x00%s%x00%B", "HEAD")
+	if err != nil {
+		return Commit{}, fmt.Errorf("git log failed: %v: %s", err, stderr)
+	}
+	parts := strings.SplitN(stdout, "\x00", 3)
+	if len(parts) != 3 {
+		return Commit{}, fmt.Errorf("git log: unexpected output %q", stdout)
+	}
+	c := Commit{Hash: strings.TrimSpace(parts[0]), Subject: strings.TrimSpace(parts[1])}
+	for _, line := range strings.Split(parts[2], "\n") {
+		line = strings.TrimSpace(line)
+		if line == ToolTrailer {
+			c.Tool = true
+		}
+		if h, ok := strings.CutPrefix(line, UndoTrailer); ok {
+			c.UndoOf = strings.TrimSpace(h)
+		}
+	}
+	stdout, stderr, err = runner.Run("git", "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", c.Hash)
+	if err != nil {
+		return Commit{}, fmt.Errorf("git diff-tree failed: %v: %s", err, stderr)
+	}
+	for _, line := range strings.Split(stdout, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			c.Files = append(c.Files, line)
+		}
+	}
+	return c, nil
+}
+
+// TopLevel returns the root directory of the repository git runs in; Commit.Files
+// are relative to it.
+func TopLevel() (string, error) {
+	stdout, stderr, err := runner.Run("git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %v: %s", err, stderr)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// RevertNoCommit applies the inverse of the given commit to the index and working
+// tree without committing. On failure the partial revert is aborted.
+func RevertNoCommit(hash string) error {
+	if _, stderr, err := runner.Run("git", "revert", "--no-commit", hash); err != nil {
+		_, _, _ = runner.Run("git", "revert", "--abort")
+		return fmt.Errorf("git revert failed: %v: %s", err, stderr)
+	}
+	return nil
+}
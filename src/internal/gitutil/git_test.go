@@ -4,7 +4,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 // run executes a command in a directory and returns error, if any.
@@ -198,3 +200,111 @@ func TestDefaultRunner_Run(t *testing.T) {
 		t.Fatalf("expected error for missing command")
 	}
 }
+
+func TestFirstCommitTimes(t *testing.T) {
+	old := runner
+	defer func() { runner = old }()
+	out := "commit:2024-03-01T10:00:00Z\n\ndata/citations/site/b.yaml\n" +
+		"commit:2024-01-15T09:30:00+00:00\n\ndata/citations/site/a.yaml\ndata/citations/site/b.yaml\n"
+	runner = &fakeRunner{seq: []resp{{out, "", nil}}}
+	got, err := FirstCommitTimes([]string{"data/citations/site/a.yaml", "data/citations/site/b.yaml"})
+	if err != nil {
+		t.Fatalf("FirstCommitTimes: %v", err)
+	}
+	want := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	if !got["data/citations/site/a.yaml"].Equal(want) || !got["data/citations/site/b.yaml"].Equal(want) {
+		t.Fatalf("unexpected times: %v", got)
+	}
+
+	runner = &fakeRunner{seq: []resp{{"", "fatal: not a git repository", &cmdError{s: "log fail"}}}}
+	if _, err := FirstCommitTimes([]string{"x"}); err == nil {
+		t.Fatalf("expected error when git log fails")
+	}
+	if m, err := FirstCommitTimes(nil); err != nil || len(m) != 0 {
+		t.Fatalf("expected empty result for no paths: %v %v", m, err)
+	}
+}
+
+func TestHeadCommitAndRevert_LocalRepo(t *testing.T) {
+	work := t.TempDir()
+	if err := run(work, "git", "init"); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "a.txt"), []byte("one"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(work, "git", "add", "a.txt"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	if err := run(work, "git", "commit", "-m", "manual"); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+	old := runner
+	t.Cleanup(func() { runner = old })
+	runner = runnerInDir{dir: work}
+
+	c, err := HeadCommit()
+	if err != nil || c.Tool || c.Subject != "manual" || len(c.Files) != 1 || c.Files[0] != "a.txt" {
+		t.Fatalf("manual head: %+v %v", c, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(work, "a.txt"), []byte("two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(work, "git", "add", "a.txt"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	if err := run(work, "git", "commit", "-m", "update a", "-m", ToolTrailer); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+	c, err = HeadCommit()
+	if err != nil || !c.Tool || c.Subject != "update a" || c.UndoOf != "" {
+		t.Fatalf("tool head: %+v %v", c, err)
+	}
+	real, _ := filepath.EvalSymlinks(work)
+	if root, err := TopLevel(); err != nil || (root != work && root != real) {
+		t.Fatalf("TopLevel = %q, %v; want %s", root, err, work)
+	}
+	if err := RevertNoCommit(c.Hash); err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	if b, _ := os.ReadFile(filepath.Join(work, "a.txt")); string(b) != "one" {
+		t.Fatalf("revert should restore the previous content, got %q", b)
+	}
+	if err := run(work, "git", "commit", "-m", "undo: update a\n\n"+UndoTrailer+c.Hash, "-m", ToolTrailer); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+	undo, err := HeadCommit()
+	if err != nil || !undo.Tool || undo.Subject != "undo: update a" || undo.UndoOf != c.Hash {
+		t.Fatalf("undo head: %+v %v", undo, err)
+	}
+}
+
+// recordingRunner records the arguments of every call and succeeds.
+type recordingRunner struct{ calls [][]string }
+
+func (r *recordingRunner) Run(name string, args ...string) (string, string, error) {
+	r.calls = append(r.calls, args)
+	return "", "", nil
+}
+
+func TestCommitAndPush_SetDirMakesPathsAbsolute(t *testing.T) {
+	rec := &recordingRunner{}
+	old := runner
+	t.Cleanup(func() { runner = old; SetDir("") })
+	runner = rec
+	SetDir(t.TempDir())
+	if err := CommitAndPush([]string{"data/library.bib"}, "msg"); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := filepath.Abs("data/library.bib")
+	if got := rec.calls[0]; got[len(got)-1] != want {
+		t.Fatalf("git add args = %v, want absolute %s", got, want)
+	}
+
+	// Outside the module, `go env GOMOD` names no go.mod: the command ran in the set dir.
+	out, _, err := defaultRunner{}.Run("go", "env", "GOMOD")
+	if err != nil || strings.HasSuffix(strings.TrimSpace(out), "go.mod") {
+		t.Fatalf("command should run in the set dir: %q %v", out, err)
+	}
+}
@@ -0,0 +1,44 @@
+// Package googlebooks holds the request and error handling shared by the Google Books
+// lookups in openlibrary (ISBN fallback) and booksearch (title/author search).
+package googlebooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// VolumesEndpoint is the Books API volumes search.
+const VolumesEndpoint = "https://www.googleapis.com/books/v1/volumes"
+
+// ErrRateLimited is returned (wrapped) when the API answers 429 after retries, so a
+// fallback chain can tell quota exhaustion from a miss.
+var ErrRateLimited = errors.New("googlebooks: rate limited")
+
+// APIKey returns GOOGLE_BOOKS_API_KEY; empty means unauthenticated requests.
+func APIKey() string { return strings.TrimSpace(os.Getenv("GOOGLE_BOOKS_API_KEY")) }
+
+// VolumesURL returns the volumes search URL for q, with key= added when APIKey is set.
+func VolumesURL(q url.Values) string {
+	if k := APIKey(); k != "" {
+		q.Set("key", k)
+	}
+	return VolumesEndpoint + "?" + q.Encode()
+}
+
+// StatusError describes a non-200 response: ErrRateLimited for 429 (with a hint to set
+// the key when none is in use), otherwise "googlebooks: http <code>: <body>".
+func StatusError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if APIKey() == "" {
+			return fmt.Errorf("%w (http 429); set GOOGLE_BOOKS_API_KEY for a higher quota", ErrRateLimited)
+		}
+		return fmt.Errorf("%w (http 429)", ErrRateLimited)
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("googlebooks: http %d: %s", resp.StatusCode, string(b))
+}
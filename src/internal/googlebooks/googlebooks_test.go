@@ -0,0 +1,39 @@
+package googlebooks
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestVolumesURL_Key(t *testing.T) {
+	t.Setenv("GOOGLE_BOOKS_API_KEY", "")
+	if got := VolumesURL(url.Values{"q": {"isbn:123"}}); got != VolumesEndpoint+"?q=isbn%3A123" {
+		t.Fatalf("without key: %s", got)
+	}
+	t.Setenv("GOOGLE_BOOKS_API_KEY", " abc ")
+	if got := VolumesURL(url.Values{"q": {"isbn:123"}}); got != VolumesEndpoint+"?key=abc&q=isbn%3A123" {
+		t.Fatalf("with key: %s", got)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	resp := func(code int) *http.Response {
+		return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader("quota")), Header: make(http.Header)}
+	}
+	t.Setenv("GOOGLE_BOOKS_API_KEY", "")
+	err := StatusError(resp(429))
+	if !errors.Is(err, ErrRateLimited) || !strings.Contains(err.Error(), "set GOOGLE_BOOKS_API_KEY") {
+		t.Fatalf("429 without key: %v", err)
+	}
+	t.Setenv("GOOGLE_BOOKS_API_KEY", "abc")
+	if err := StatusError(resp(429)); !errors.Is(err, ErrRateLimited) || strings.Contains(err.Error(), "set GOOGLE") {
+		t.Fatalf("429 with key: %v", err)
+	}
+	if err := StatusError(resp(500)); errors.Is(err, ErrRateLimited) || err.Error() != "googlebooks: http 500: quota" {
+		t.Fatalf("500: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package httpx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheDir is the response cache used until SetCacheDir points it elsewhere.
+const DefaultCacheDir = "data/.cache/http"
+
+// CacheDir holds cached provider responses, one JSON file per request. It belongs
+// under the library's data directory, not the working directory; whoever resolves the
+// data directory points it there with SetCacheDir.
+var CacheDir = DefaultCacheDir
+
+// SetCacheDir points the response cache at dir; an empty dir restores DefaultCacheDir.
+func SetCacheDir(dir string) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		dir = DefaultCacheDir
+	}
+	CacheDir = filepath.Clean(dir)
+}
+
+var cacheDisabled atomic.Bool
+
+// SetCacheDisabled turns the response cache off for this process (bib --no-cache).
+func SetCacheDisabled(disabled bool) { cacheDisabled.Store(disabled) }
+
+// CacheTTL returns how long cached responses stay fresh: BIB_CACHE_TTL as a Go
+// duration ("24h") or a number of seconds. Zero, the default, disables the cache.
+func CacheTTL() time.Duration {
+	v := strings.TrimSpace(os.Getenv("BIB_CACHE_TTL"))
+	if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return d
+	}
+	if s, err := strconv.ParseFloat(v, 64); err == nil && s > 0 {
+		return time.Duration(s * float64(time.Second))
+	}
+	return 0
+}
+
+// ProviderClient returns DefaultClient behind the on-disk response cache; metadata
+// providers use it so repeated lookups of the same DOI or ISBN are not refetched.
+func ProviderClient() Doer {
+	return &CachingDoer{Next: DefaultClient(), Dir: CacheDir, TTL: CacheTTL}
+}
+
+// CachingDoer replays cached 200 responses to GET requests and stores new ones. The
+// key is the URL plus the Accept header (content negotiation changes the body). Other
+// methods, errors, and non-200 responses pass through uncached. Caching is off while
+// TTL returns zero or SetCacheDisabled(true) is in effect.
+type CachingDoer struct {
+	Next Doer
+	Dir  string
+	TTL  func() time.Duration
+}
+
+// cachedResponse is the on-disk form of a cached response.
+type cachedResponse struct {
+	URL      string      `json:"url"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+// Do implements Doer.
+func (c *CachingDoer) Do(req *http.Request) (*http.Response, error) {
+	ttl := c.TTL()
+	if req.Method != http.MethodGet || ttl <= 0 || cacheDisabled.Load() {
+		return c.Next.Do(req)
+	}
+	path := c.path(req)
+	if cr, ok := readCached(path, ttl); ok {
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        cr.Header,
+			Body:          io.NopCloser(bytes.NewReader(cr.Body)),
+			ContentLength: int64(len(cr.Body)),
+			Request:       req,
+		}, nil
+	}
+	resp, err := c.Next.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// A failed cache write only costs a refetch later.
+	_ = writeCached(path, cachedResponse{URL: req.URL.String(), Header: resp.Header, Body: body, StoredAt: time.Now()})
+	return resp, nil
+}
+
+func (c *CachingDoer) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\n" + req.Header.Get("Accept")))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func readCached(path string, ttl time.Duration) (cachedResponse, bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	var cr cachedResponse
+	if err := json.Unmarshal(b, &cr); err != nil || time.Since(cr.StoredAt) > ttl {
+		return cachedResponse{}, false
+	}
+	return cr, true
+}
+
+func writeCached(path string, cr cachedResponse) error {
+	b, err := json.Marshal(cr)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,116 @@
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingDoer struct {
+	calls int
+	do    func(*http.Request) (*http.Response, error)
+}
+
+func (c *countingDoer) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return c.do(req)
+}
+
+func okResp(body string) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func get(t *testing.T, d Doer, url, accept string) (int, string, error) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := d.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b), nil
+}
+
+func TestCachingDoerReplaysAndExpires(t *testing.T) {
+	t.Cleanup(func() { SetCacheDisabled(false) })
+	ttl := time.Hour
+	live := &countingDoer{do: func(*http.Request) (*http.Response, error) { return okResp(`{"title":"cached"}`) }}
+	c := &CachingDoer{Next: live, Dir: t.TempDir(), TTL: func() time.Duration { return ttl }}
+
+	if code, body, err := get(t, c, "https://example.com/a", ""); err != nil || code != 200 || body != `{"title":"cached"}` {
+		t.Fatalf("first get: %d %q %v", code, body, err)
+	}
+	live.do = func(*http.Request) (*http.Response, error) { return nil, errors.New("network down") }
+	code, body, err := get(t, c, "https://example.com/a", "")
+	if err != nil || code != 200 || body != `{"title":"cached"}` || live.calls != 1 {
+		t.Fatalf("second get should replay: %d %q %v calls=%d", code, body, err, live.calls)
+	}
+	if _, _, err := get(t, c, "https://example.com/a", "application/x-bibtex"); err == nil {
+		t.Fatalf("a different Accept header is a different cache key")
+	}
+	SetCacheDisabled(true)
+	if _, _, err := get(t, c, "https://example.com/a", ""); err == nil {
+		t.Fatalf("--no-cache should go to the live client")
+	}
+	SetCacheDisabled(false)
+	ttl = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if _, _, err := get(t, c, "https://example.com/a", ""); err == nil {
+		t.Fatalf("expired entries should be refetched")
+	}
+}
+
+func TestCachingDoerSkipsErrorsAndWhenOff(t *testing.T) {
+	ttl := time.Duration(0)
+	live := &countingDoer{do: func(*http.Request) (*http.Response, error) { return okResp("one") }}
+	c := &CachingDoer{Next: live, Dir: t.TempDir(), TTL: func() time.Duration { return ttl }}
+	get(t, c, "https://example.com/b", "")
+	get(t, c, "https://example.com/b", "")
+	if live.calls != 2 {
+		t.Fatalf("a zero TTL disables the cache, calls=%d", live.calls)
+	}
+
+	ttl = time.Hour
+	live.calls = 0
+	live.do = func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader("busy"))}, nil
+	}
+	get(t, c, "https://example.com/c", "")
+	live.do = func(*http.Request) (*http.Response, error) { return okResp("fresh") }
+	if _, body, _ := get(t, c, "https://example.com/c", ""); body != "fresh" || live.calls != 2 {
+		t.Fatalf("error responses must not be cached: %q calls=%d", body, live.calls)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	for v, want := range map[string]time.Duration{"": 0, "24h": 24 * time.Hour, "90": 90 * time.Second, "-5m": 0, "soon": 0} {
+		t.Setenv("BIB_CACHE_TTL", v)
+		if got := CacheTTL(); got != want {
+			t.Errorf("CacheTTL(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestSetCacheDir(t *testing.T) {
+	t.Cleanup(func() { SetCacheDir("") })
+	dir := filepath.Join(t.TempDir(), "lib", ".cache", "http")
+	SetCacheDir(dir)
+	if CacheDir != dir {
+		t.Fatalf("CacheDir = %q, want %q", CacheDir, dir)
+	}
+	if d, ok := ProviderClient().(*CachingDoer); !ok || d.Dir != dir {
+		t.Fatalf("provider client should cache under %q: %+v", dir, ProviderClient())
+	}
+	SetCacheDir(" ")
+	if CacheDir != DefaultCacheDir {
+		t.Fatalf("empty dir should restore the default: %q", CacheDir)
+	}
+}
@@ -0,0 +1,28 @@
+package httpx
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the provider HTTP timeout when BIB_HTTP_TIMEOUT is unset or invalid.
+const DefaultTimeout = 15 * time.Second
+
+// Timeout returns the provider HTTP timeout: BIB_HTTP_TIMEOUT in seconds (fractions
+// allowed, e.g. "2.5") when it is a positive number, otherwise DefaultTimeout.
+func Timeout() time.Duration {
+	v := strings.TrimSpace(os.Getenv("BIB_HTTP_TIMEOUT"))
+	if s, err := strconv.ParseFloat(v, 64); err == nil && s > 0 {
+		return time.Duration(s * float64(time.Second))
+	}
+	return DefaultTimeout
+}
+
+// DefaultClient returns a new HTTP client using Timeout. Provider packages initialize
+// their client from it; tests replace that client via each package's SetHTTPClient.
+func DefaultClient() *http.Client {
+	return &http.Client{Timeout: Timeout()}
+}
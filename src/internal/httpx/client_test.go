@@ -0,0 +1,27 @@
+package httpx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultClientTimeout(t *testing.T) {
+	t.Setenv("BIB_HTTP_TIMEOUT", "")
+	if got := DefaultClient().Timeout; got != 15*time.Second {
+		t.Fatalf("unset: got %v, want %v", got, DefaultTimeout)
+	}
+	t.Setenv("BIB_HTTP_TIMEOUT", "30")
+	if got := DefaultClient().Timeout; got != 30*time.Second {
+		t.Fatalf("BIB_HTTP_TIMEOUT=30: got %v", got)
+	}
+	t.Setenv("BIB_HTTP_TIMEOUT", "2.5")
+	if got := DefaultClient().Timeout; got != 2500*time.Millisecond {
+		t.Fatalf("BIB_HTTP_TIMEOUT=2.5: got %v", got)
+	}
+	for _, bad := range []string{"0", "-1", "soon"} {
+		t.Setenv("BIB_HTTP_TIMEOUT", bad)
+		if got := DefaultClient().Timeout; got != DefaultTimeout {
+			t.Fatalf("BIB_HTTP_TIMEOUT=%q: got %v, want default", bad, got)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package httpx
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// CrossrefMailto returns CROSSREF_MAILTO, the contact address Crossref uses to route
+// requests to its more reliable "polite" pool; empty when unset.
+func CrossrefMailto() string { return strings.TrimSpace(os.Getenv("CROSSREF_MAILTO")) }
+
+// SetCrossrefMailto adds mailto=<CrossrefMailto> to the query of a Crossref API
+// request; without an address the request is left unchanged.
+func SetCrossrefMailto(req *http.Request) {
+	m := CrossrefMailto()
+	if req == nil || m == "" {
+		return
+	}
+	q := req.URL.Query()
+	q.Set("mailto", m)
+	req.URL.RawQuery = q.Encode()
+}
@@ -1,18 +1,31 @@
 package httpx
 
-import "net/http"
+import (
+	"net/http"
+	"os"
+	"strings"
+)
 
 // Doer is the minimal HTTP client interface used across packages.
 type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-// ChromeUA is a consistent, modern desktop Chrome User-Agent for all outbound HTTP.
+// ChromeUA is a consistent, modern desktop Chrome User-Agent, the default for all
+// outbound HTTP.
 const ChromeUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
 
-// SetUA sets the ChromeUA header on the request.
+// UserAgent returns BIB_USER_AGENT when set, else ChromeUA.
+func UserAgent() string {
+	if ua := strings.TrimSpace(os.Getenv("BIB_USER_AGENT")); ua != "" {
+		return ua
+	}
+	return ChromeUA
+}
+
+// SetUA sets the UserAgent header on the request; every provider request goes through it.
 func SetUA(req *http.Request) {
 	if req != nil {
-		req.Header.Set("User-Agent", ChromeUA)
+		req.Header.Set("User-Agent", UserAgent())
 	}
 }
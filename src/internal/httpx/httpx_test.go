@@ -20,3 +20,30 @@ func TestSetUA(t *testing.T) {
 		t.Fatalf("SetUA idempotent: want %q, got %q", ChromeUA, hv)
 	}
 }
+
+func TestSetCrossrefMailto(t *testing.T) {
+	t.Setenv("CROSSREF_MAILTO", "")
+	req, _ := http.NewRequest(http.MethodGet, "https://api.crossref.org/works?rows=1", nil)
+	SetCrossrefMailto(req)
+	if req.URL.Query().Has("mailto") {
+		t.Fatalf("mailto added without CROSSREF_MAILTO: %s", req.URL)
+	}
+	t.Setenv("CROSSREF_MAILTO", " me@example.org ")
+	SetCrossrefMailto(req)
+	if got := req.URL.Query().Get("mailto"); got != "me@example.org" || req.URL.Query().Get("rows") != "1" {
+		t.Fatalf("query: %s", req.URL.RawQuery)
+	}
+}
+
+func TestSetUA_Configurable(t *testing.T) {
+	t.Setenv("BIB_USER_AGENT", "bib-test/1.0 (+mailto:me@example.org)")
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	SetUA(req)
+	if hv := req.Header.Get("User-Agent"); hv != "bib-test/1.0 (+mailto:me@example.org)" {
+		t.Fatalf("SetUA with BIB_USER_AGENT: got %q", hv)
+	}
+	t.Setenv("BIB_USER_AGENT", "  ")
+	if UserAgent() != ChromeUA {
+		t.Fatalf("blank BIB_USER_AGENT should fall back to ChromeUA")
+	}
+}
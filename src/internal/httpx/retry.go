@@ -0,0 +1,111 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls DoWithRetry: at most MaxAttempts tries, waiting BaseDelay before
+// the first retry and doubling after each, with any single wait capped at MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var (
+	retryMu     sync.RWMutex
+	retryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 8 * time.Second}
+)
+
+// DefaultRetryPolicy returns the policy providers use for DoWithRetry.
+func DefaultRetryPolicy() RetryPolicy {
+	retryMu.RLock()
+	defer retryMu.RUnlock()
+	return retryPolicy
+}
+
+// SetDefaultRetryPolicy replaces the provider retry policy (tests use zero delays).
+func SetDefaultRetryPolicy(p RetryPolicy) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retryPolicy = p
+}
+
+// Retryable reports whether an HTTP status is transient: 429 Too Many Requests or any 5xx.
+func Retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// DoWithRetry sends req with c, retrying 429 and 5xx responses with exponential backoff
+// (or the server's Retry-After, capped at p.MaxDelay). It stops early, returning the last
+// response, when the wait would pass the request context's deadline, and returns the
+// context error if the context is cancelled while waiting. Transport errors are not
+// retried, nor are requests whose body cannot be replayed.
+func DoWithRetry(c Doer, req *http.Request, p RetryPolicy) (*http.Response, error) {
+	ctx := req.Context()
+	delay := p.BaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := c.Do(req)
+		if err != nil || !Retryable(resp.StatusCode) || attempt >= p.MaxAttempts {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+		wait := delay
+		if ra, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			wait = ra
+		}
+		if p.MaxDelay > 0 && wait > p.MaxDelay {
+			wait = p.MaxDelay
+		}
+		if dl, ok := ctx.Deadline(); ok && time.Until(dl) < wait {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		_ = resp.Body.Close()
+		if wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return nil, ctx.Err()
+			case <-t.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		delay *= 2
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
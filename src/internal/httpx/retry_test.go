@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flakyDoer answers with statuses in order, repeating the last one.
+type flakyDoer struct {
+	statuses []int
+	header   http.Header
+	calls    int
+}
+
+func (f *flakyDoer) Do(req *http.Request) (*http.Response, error) {
+	i := f.calls
+	if i >= len(f.statuses) {
+		i = len(f.statuses) - 1
+	}
+	f.calls++
+	return &http.Response{StatusCode: f.statuses[i], Header: f.header, Body: io.NopCloser(strings.NewReader("body"))}, nil
+}
+
+func TestDoWithRetry_FailsTwiceThenSucceeds(t *testing.T) {
+	for _, first := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		d := &flakyDoer{statuses: []int{first, first, http.StatusOK}}
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		resp, err := DoWithRetry(d, req, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+		if err != nil || resp.StatusCode != http.StatusOK || d.calls != 3 {
+			t.Fatalf("%d: resp=%v err=%v calls=%d", first, resp, err, d.calls)
+		}
+	}
+}
+
+func TestDoWithRetry_StopsAtMaxAttemptsAndOnClientErrors(t *testing.T) {
+	d := &flakyDoer{statuses: []int{http.StatusBadGateway}}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	resp, err := DoWithRetry(d, req, RetryPolicy{MaxAttempts: 3})
+	if err != nil || resp.StatusCode != http.StatusBadGateway || d.calls != 3 {
+		t.Fatalf("resp=%v err=%v calls=%d", resp, err, d.calls)
+	}
+	if b, _ := io.ReadAll(resp.Body); string(b) != "body" {
+		t.Fatalf("last response body should be readable, got %q", b)
+	}
+	d = &flakyDoer{statuses: []int{http.StatusNotFound, http.StatusOK}}
+	if resp, _ := DoWithRetry(d, req, RetryPolicy{MaxAttempts: 3}); resp.StatusCode != http.StatusNotFound || d.calls != 1 {
+		t.Fatalf("404 should not be retried: status=%d calls=%d", resp.StatusCode, d.calls)
+	}
+}
+
+func TestDoWithRetry_HonorsRetryAfterAndContext(t *testing.T) {
+	// Retry-After beyond the deadline: give up with the last response instead of waiting.
+	d := &flakyDoer{statuses: []int{http.StatusTooManyRequests, http.StatusOK}, header: http.Header{"Retry-After": {"30"}}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	start := time.Now()
+	resp, err := DoWithRetry(d, req, RetryPolicy{MaxAttempts: 3, MaxDelay: time.Minute})
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests || d.calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("resp=%v err=%v calls=%d", resp, err, d.calls)
+	}
+
+	// MaxDelay caps Retry-After.
+	d = &flakyDoer{statuses: []int{http.StatusTooManyRequests, http.StatusOK}, header: http.Header{"Retry-After": {"30"}}}
+	req, _ = http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if resp, err := DoWithRetry(d, req, RetryPolicy{MaxAttempts: 3, MaxDelay: time.Millisecond}); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("capped Retry-After: resp=%v err=%v", resp, err)
+	}
+
+	// Cancellation while waiting returns the context error.
+	ctx, cancel = context.WithCancel(context.Background())
+	d = &flakyDoer{statuses: []int{http.StatusServiceUnavailable}}
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := DoWithRetry(d, req, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute}); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("2"); !ok || d != 2*time.Second {
+		t.Fatalf("seconds: %v %v", d, ok)
+	}
+	if d, ok := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || d < 59*time.Minute {
+		t.Fatalf("http date: %v %v", d, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Fatalf("invalid Retry-After should be ignored")
+	}
+}
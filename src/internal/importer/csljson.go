@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"bibliography/src/internal/lang"
+	"bibliography/src/internal/orcid"
+	"bibliography/src/internal/schema"
+)
+
+// cslTypes maps CSL item types to entry types.
+var cslTypes = map[string]string{
+	"article-journal": "article", "article": "article", "article-magazine": "article", "article-newspaper": "article",
+	"paper-conference": "article",
+	"book":             "book", "chapter": "book",
+	"webpage": "website", "post-weblog": "website", "post": "website",
+	"patent":         "patent",
+	"motion_picture": "movie",
+	"broadcast":      "video",
+	"song":           "song",
+	"report":         "report",
+	"dataset":        "dataset",
+	"software":       "software",
+	"standard":       "standard",
+}
+
+// cslMapped lists the CSL keys cslRecord understands; others are reported as unmapped.
+var cslMapped = map[string]bool{
+	"id": true, "type": true, "title": true, "author": true, "container-title": true, "issued": true,
+	"volume": true, "issue": true, "page": true, "DOI": true, "ISBN": true, "URL": true, "publisher": true,
+	"publisher-place": true, "edition": true, "abstract": true, "keyword": true, "accessed": true, "number": true,
+	"authority": true, "ISSN": true, "PMID": true, "custom": true, "editor": true, "translator": true, "language": true,
+}
+
+type cslName struct {
+	Family  string `json:"family"`
+	Given   string `json:"given"`
+	Literal string `json:"literal"`
+	ORCID   string `json:"ORCID"`
+}
+
+type cslDate struct {
+	DateParts [][]any `json:"date-parts"`
+	Raw       string  `json:"raw"`
+}
+
+type cslItem struct {
+	Type           string         `json:"type"`
+	Title          string         `json:"title"`
+	Author         []cslName      `json:"author"`
+	Editor         []cslName      `json:"editor"`
+	Translator     []cslName      `json:"translator"`
+	Narrator       []cslName      `json:"narrator"`
+	ContainerTitle string         `json:"container-title"`
+	Issued         cslDate        `json:"issued"`
+	Accessed       cslDate        `json:"accessed"`
+	Volume         any            `json:"volume"`
+	Issue          any            `json:"issue"`
+	Page           any            `json:"page"`
+	Number         any            `json:"number"`
+	DOI            string         `json:"DOI"`
+	ISBN           string         `json:"ISBN"`
+	URL            string         `json:"URL"`
+	Publisher      string         `json:"publisher"`
+	PublisherPlace string         `json:"publisher-place"`
+	Edition        any            `json:"edition"`
+	Abstract       string         `json:"abstract"`
+	Keyword        string         `json:"keyword"`
+	Authority      string         `json:"authority"`
+	ISSN           string         `json:"ISSN"`
+	PMID           string         `json:"PMID"`
+	Custom         map[string]any `json:"custom"`
+	Language       string         `json:"language"`
+}
+
+// ParseCSLJSON reads a CSL-JSON array (as exported by Zotero/Mendeley) into records.
+func ParseCSLJSON(r io.Reader) ([]Record, error) {
+	var raws []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raws); err != nil {
+		return nil, fmt.Errorf("csljson: %w", err)
+	}
+	out := make([]Record, 0, len(raws))
+	for i, raw := range raws {
+		rec, err := cslRecord(raw)
+		if err != nil {
+			return nil, fmt.Errorf("csljson: item %d: %w", i+1, err)
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// ParseCSLItem maps one CSL-JSON item (as served by the Zotero API) to a Record.
+func ParseCSLItem(raw json.RawMessage) (Record, error) { return cslRecord(raw) }
+
+// cslRecord maps one CSL-JSON item to a Record.
+func cslRecord(raw json.RawMessage) (Record, error) {
+	var it cslItem
+	if err := json.Unmarshal(raw, &it); err != nil {
+		return Record{}, err
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return Record{}, err
+	}
+	typ, ok := cslTypes[strings.ToLower(strings.TrimSpace(it.Type))]
+	if !ok {
+		return Record{}, fmt.Errorf("unsupported CSL type %q", it.Type)
+	}
+	var rec Record
+	e := &rec.Entry
+	e.Type = typ
+	e.APA7.Title = it.Title
+	e.APA7.Authors = append(e.APA7.Authors, cslAuthors(it.Author, "")...)
+	e.APA7.Authors = append(e.APA7.Authors, cslAuthors(it.Editor, schema.RoleEditor)...)
+	e.APA7.Authors = append(e.APA7.Authors, cslAuthors(it.Translator, schema.RoleTranslator)...)
+	e.APA7.Authors = append(e.APA7.Authors, cslAuthors(it.Narrator, schema.RoleNarrator)...)
+	e.APA7.ContainerTitle = it.ContainerTitle
+	if typ == "article" {
+		e.APA7.Journal = it.ContainerTitle
+	}
+	y, m, d := cslDateParts(it.Issued)
+	setDate(e, y, m, d)
+	if ay, am, ad := cslDateParts(it.Accessed); ay > 0 && am > 0 && ad > 0 {
+		e.APA7.Accessed = fmt.Sprintf("%04d-%02d-%02d", ay, am, ad)
+	}
+	e.APA7.Volume = str(it.Volume)
+	e.APA7.Issue = str(it.Issue)
+	e.APA7.Pages = str(it.Page)
+	e.APA7.Edition = str(it.Edition)
+	e.APA7.DOI = it.DOI
+	e.APA7.ISBN = it.ISBN
+	e.APA7.ISSN = it.ISSN
+	e.APA7.PMID = it.PMID
+	for k, v := range it.Custom {
+		s, _ := v.(string)
+		switch strings.ToLower(k) {
+		case "arxiv":
+			e.APA7.ArXiv = s
+		case "bibcode":
+			e.APA7.Bibcode = s
+		case "isrc":
+			e.APA7.ISRC = s
+		default:
+			rec.Unmapped = append(rec.Unmapped, "custom."+k)
+		}
+	}
+	e.APA7.URL = it.URL
+	e.APA7.Language = lang.Normalize(it.Language)
+	e.APA7.Publisher = it.Publisher
+	e.APA7.PublisherLocation = it.PublisherPlace
+	switch typ {
+	case "patent":
+		e.APA7.PatentNumber = str(it.Number)
+	case "standard":
+		e.APA7.Designation = str(it.Number)
+		e.APA7.StandardsBody = strings.TrimSpace(it.Authority)
+	}
+	e.Annotation.Summary = it.Abstract
+	e.Annotation.Keywords = splitKeywords(it.Keyword)
+	for k := range keys {
+		if !cslMapped[k] || (k == "number" && typ != "patent" && typ != "standard") || (k == "authority" && typ != "standard") {
+			rec.Unmapped = append(rec.Unmapped, k)
+		}
+	}
+	sort.Strings(rec.Unmapped)
+	finish(e, "CSL-JSON")
+	return rec, nil
+}
+
+// cslAuthors maps CSL names to authors with the given role.
+func cslAuthors(ns []cslName, role string) schema.Authors {
+	var out schema.Authors
+	for _, a := range ns {
+		id, _ := orcid.Parse(a.ORCID)
+		switch {
+		case strings.TrimSpace(a.Family) != "":
+			out = append(out, schema.Author{Family: strings.TrimSpace(a.Family), Given: initials(a.Given), ORCID: id, Role: role})
+		case strings.TrimSpace(a.Literal) != "":
+			out = append(out, schema.Author{Family: strings.TrimSpace(a.Literal), ORCID: id, Role: role})
+		}
+	}
+	return out
+}
+
+// cslDateParts returns year, month, day from date-parts (numbers or numeric strings).
+func cslDateParts(d cslDate) (y, m, day int) {
+	if len(d.DateParts) == 0 {
+		if len(d.Raw) >= 4 {
+			return risDate(d.Raw)
+		}
+		return 0, 0, 0
+	}
+	nums := make([]int, 3)
+	for i, p := range d.DateParts[0] {
+		if i >= 3 {
+			break
+		}
+		_, _ = fmt.Sscanf(str(p), "%d", &nums[i])
+	}
+	return nums[0], nums[1], nums[2]
+}
+
+// str renders CSL values that may be strings or numbers.
+func str(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(t)
+	case float64:
+		return fmt.Sprintf("%g", t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
@@ -0,0 +1,90 @@
+// Package importer parses reference-manager exports (RIS, CSL-JSON) into entries.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/names"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+// Record is one parsed reference plus the source fields that had no mapping.
+type Record struct {
+	Entry    schema.Entry
+	Unmapped []string
+}
+
+// ParseBibTeX reads BibTeX records using the library's own parser.
+func ParseBibTeX(r io.Reader) ([]Record, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := store.ParseBibTeX(string(b))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Record, 0, len(entries))
+	for _, e := range entries {
+		finish(&e, "BibTeX")
+		out = append(out, Record{Entry: e})
+	}
+	return out, nil
+}
+
+// parseName splits "Family, Given" or "Given Family" into an Author.
+func parseName(s string) schema.Author {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ","); i >= 0 {
+		return schema.Author{Family: strings.TrimSpace(s[:i]), Given: names.Initials(strings.TrimSpace(s[i+1:]))}
+	}
+	fam, giv := names.Split(s)
+	return schema.Author{Family: fam, Given: giv}
+}
+
+func initials(given string) string { return names.Initials(strings.TrimSpace(given)) }
+
+// setDate stores a year and, when month/day are present, a YYYY-MM-DD date.
+func setDate(e *schema.Entry, y, m, d int) {
+	if y <= 0 {
+		return
+	}
+	e.APA7.Year = &y
+	switch {
+	case m > 0 && d > 0:
+		e.APA7.Date = fmt.Sprintf("%04d-%02d-%02d", y, m, d)
+	case m > 0:
+		e.APA7.Date = fmt.Sprintf("%04d-%02d-01", y, m)
+	}
+}
+
+// finish fills the fields every stored entry needs (id, summary, keywords, accessed).
+func finish(e *schema.Entry, format string) {
+	if strings.TrimSpace(e.ID) == "" {
+		e.ID = schema.NewID()
+	}
+	if strings.TrimSpace(e.Annotation.Summary) == "" && strings.TrimSpace(e.APA7.Title) != "" {
+		e.Annotation.Summary = fmt.Sprintf("Bibliographic record for %s (imported from %s).", e.APA7.Title, format)
+	}
+	if len(e.Annotation.Keywords) == 0 && e.Type != "" {
+		e.Annotation.Keywords = []string{e.Type}
+	}
+	if strings.TrimSpace(e.APA7.URL) != "" && strings.TrimSpace(e.APA7.Accessed) == "" {
+		e.APA7.Accessed = dates.NowISO()
+	}
+}
+
+// splitKeywords splits a comma/semicolon-delimited keyword string.
+func splitKeywords(s string) []string {
+	var out []string
+	for _, k := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if k = strings.TrimSpace(k); k != "" {
+			out = append(out, strings.ToLower(k))
+		}
+	}
+	return out
+}
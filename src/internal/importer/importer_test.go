@@ -0,0 +1,235 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/store"
+)
+
+const sampleRIS = `TY  - JOUR
+TI  - Graph Methods
+AU  - Doe, Jane
+AU  - Smith, John Q
+PY  - 2021/03/04/
+JO  - Journal of Graphs
+VL  - 7
+IS  - 2
+SP  - 10
+EP  - 20
+DO  - 10.1000/graph
+KW  - graphs
+KW  - networks
+AB  - An abstract.
+N1  - a private note
+ER  - 
+
+TY  - BOOK
+TI  - A Book
+AU  - Roe, R.
+PY  - 1999
+PB  - Pub
+SN  - 978-0-00-000000-2
+UR  - https://example.com/book
+ER  - 
+`
+
+func TestParseRIS(t *testing.T) {
+	recs, err := ParseRIS(strings.NewReader(sampleRIS))
+	if err != nil {
+		t.Fatalf("ParseRIS: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("want 2 records, got %d", len(recs))
+	}
+	a := recs[0].Entry
+	if a.Type != "article" || a.APA7.Title != "Graph Methods" || a.APA7.Journal != "Journal of Graphs" {
+		t.Fatalf("article mapping: %+v", a)
+	}
+	if len(a.APA7.Authors) != 2 || a.APA7.Authors[1].Family != "Smith" || a.APA7.Authors[1].Given != "J. Q." {
+		t.Fatalf("authors: %+v", a.APA7.Authors)
+	}
+	if a.APA7.Year == nil || *a.APA7.Year != 2021 || a.APA7.Date != "2021-03-04" || a.APA7.Pages != "10-20" {
+		t.Fatalf("date/pages: %+v", a.APA7)
+	}
+	if strings.Join(a.Annotation.Keywords, ",") != "graphs,networks" || a.Annotation.Summary != "An abstract." {
+		t.Fatalf("annotation: %+v", a.Annotation)
+	}
+	if strings.Join(recs[0].Unmapped, ",") != "N1" {
+		t.Fatalf("unmapped: %v", recs[0].Unmapped)
+	}
+	b := recs[1].Entry
+	if b.Type != "book" || b.APA7.ISBN == "" || b.APA7.Accessed == "" || b.Annotation.Summary == "" {
+		t.Fatalf("book mapping: %+v", b)
+	}
+	for _, r := range recs {
+		if err := r.Entry.Validate(); err != nil {
+			t.Fatalf("invalid entry: %v", err)
+		}
+	}
+
+	if _, err := ParseRIS(strings.NewReader("TY  - XYZ\nER  - \n")); err == nil {
+		t.Fatalf("expected error for unsupported type")
+	}
+	if _, err := ParseRIS(strings.NewReader("TY  - JOUR\nTI  - x\n")); err == nil {
+		t.Fatalf("expected error for missing ER")
+	}
+}
+
+func TestParseCSLJSON(t *testing.T) {
+	in := `[
+	  {"id":"item1","type":"article-journal","title":"CSL Article","author":[{"family":"Doe","given":"Jane"}],
+	   "container-title":"J","issued":{"date-parts":[[2020,5]]},"volume":3,"page":"1-9","DOI":"10.1/x",
+	   "keyword":"alpha, beta","note":"extra"},
+	  {"type":"webpage","title":"Site","author":[{"literal":"ACME Corp"}],"URL":"https://e.com",
+	   "accessed":{"date-parts":[["2024","01","02"]]}}
+	]`
+	recs, err := ParseCSLJSON(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseCSLJSON: %v", err)
+	}
+	a := recs[0].Entry
+	if a.Type != "article" || a.APA7.Journal != "J" || a.APA7.Volume != "3" || a.APA7.Date != "2020-05-01" || a.APA7.DOI != "10.1/x" {
+		t.Fatalf("article mapping: %+v", a.APA7)
+	}
+	if strings.Join(a.Annotation.Keywords, ",") != "alpha,beta" || strings.Join(recs[0].Unmapped, ",") != "note" {
+		t.Fatalf("keywords/unmapped: %v %v", a.Annotation.Keywords, recs[0].Unmapped)
+	}
+	w := recs[1].Entry
+	if w.Type != "website" || w.APA7.Authors[0].Family != "ACME Corp" || w.APA7.Accessed != "2024-01-02" {
+		t.Fatalf("website mapping: %+v", w)
+	}
+	std, err := ParseCSLJSON(strings.NewReader(`[{"type":"standard","title":"ISMS","number":"ISO/IEC 27001:2022","authority":"ISO"}]`))
+	if err != nil || std[0].Entry.Type != "standard" || std[0].Entry.APA7.Designation != "ISO/IEC 27001:2022" || std[0].Entry.APA7.StandardsBody != "ISO" || len(std[0].Unmapped) != 0 {
+		t.Fatalf("standard mapping: %v %+v", err, std)
+	}
+	if _, err := ParseCSLJSON(strings.NewReader(`[{"type":"map","title":"x"}]`)); err == nil {
+		t.Fatalf("expected error for unsupported type")
+	}
+}
+
+func TestParseRIS_AccessionIdentifiers(t *testing.T) {
+	in := "TY  - JOUR\nTI  - Trial\nSN  - 1234-5678\nDB  - PubMed\nAN  - 31415926\nER  - \n" +
+		"TY  - JOUR\nTI  - Other\nDB  - Local Catalog\nAN  - X1\nER  - \n"
+	recs, err := ParseRIS(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseRIS: %v", err)
+	}
+	if a := recs[0].Entry.APA7; a.PMID != "31415926" || a.ISSN != "1234-5678" || len(recs[0].Unmapped) != 0 {
+		t.Fatalf("identifiers: %+v unmapped %v", a.Identifiers, recs[0].Unmapped)
+	}
+	if strings.Join(recs[1].Unmapped, ",") != "AN" {
+		t.Fatalf("unknown database should leave AN unmapped: %v", recs[1].Unmapped)
+	}
+}
+
+func TestParseBibTeX_StringMacrosAndComments(t *testing.T) {
+	bib := `% exported from a reference manager
+@string{acm = {ACM}}
+@STRING(cacm = "Communications of the " # acm)
+@comment{ignored {nested} block}
+@book{knuth1968,
+  title = {The Art of Computer Programming},
+  author = {Knuth, Donald E.},
+  publisher = acm # { Press},
+  year = 1968,
+}
+% second record
+@article{doe2020,
+  title = "Graph Methods",
+  journal = cacm,
+  year = {2020},
+}
+`
+	recs, err := ParseBibTeX(strings.NewReader(bib))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("want 2 records (macros and comments are not records), got %d", len(recs))
+	}
+	book, art := recs[0].Entry, recs[1].Entry
+	if book.APA7.Publisher != "ACM Press" || book.APA7.Year == nil || *book.APA7.Year != 1968 {
+		t.Fatalf("book publisher/year: %q %v", book.APA7.Publisher, book.APA7.Year)
+	}
+	if art.APA7.Journal != "Communications of the ACM" {
+		t.Fatalf("journal macro not expanded: %q", art.APA7.Journal)
+	}
+}
+
+func TestCSLJSON_ExportRoundTrip(t *testing.T) {
+	y := 2022
+	entries := []schema.Entry{
+		{ID: "00000000-0000-4000-8000-0000000000d1", Type: "article", APA7: schema.APA7{
+			Title: "Trial", Journal: "J", Year: &y, Authors: schema.Authors{{Family: "Doe", Given: "J."}},
+			Identifiers: schema.Identifiers{DOI: "10.1/t", ISSN: "1234-5678", PMID: "31415926"}},
+			Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}},
+		{ID: "00000000-0000-4000-8000-0000000000d2", Type: "standard", APA7: schema.APA7{
+			Title: "ISMS", Year: &y, Designation: "ISO/IEC 27001:2022", StandardsBody: "ISO", Publisher: "ISO Central Secretariat"},
+			Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}},
+	}
+	b, err := store.EntriesToCSLJSON(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recs, err := ParseCSLJSON(bytes.NewReader(b))
+	if err != nil || len(recs) != 2 {
+		t.Fatalf("re-import: %v %d", err, len(recs))
+	}
+	for _, r := range recs {
+		if len(r.Unmapped) != 0 {
+			t.Fatalf("%s: unmapped %v", r.Entry.APA7.Title, r.Unmapped)
+		}
+	}
+	a, s := recs[0].Entry.APA7, recs[1].Entry.APA7
+	if a.PMID != "31415926" || a.ISSN != "1234-5678" || a.DOI != "10.1/t" {
+		t.Fatalf("article identifiers lost: %+v", a.Identifiers)
+	}
+	if s.StandardsBody != "ISO" || s.Designation != "ISO/IEC 27001:2022" || s.Publisher != "ISO Central Secretariat" {
+		t.Fatalf("standard fields lost: %+v", s)
+	}
+}
+
+func TestAccessionIdentifiers_ExportRoundTrip(t *testing.T) {
+	ids := schema.Identifiers{DOI: "10.1/t", PMID: "31415926", ArXiv: "2101.00001", Bibcode: "2021ApJ...1..1D", ISRC: "USRC17607839"}
+	entries := []schema.Entry{
+		{ID: "00000000-0000-4000-8000-0000000000d3", Type: "article", APA7: schema.APA7{Title: "Trial", Journal: "J", Identifiers: ids},
+			Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}},
+		{ID: "00000000-0000-4000-8000-0000000000d4", Type: "standard", APA7: schema.APA7{Title: "ISMS", Designation: "ISO/IEC 27001:2022", StandardsBody: "ISO"},
+			Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}},
+	}
+	ris := store.EntriesToRIS(entries)
+	for _, line := range []string{"DB  - PubMed\nAN  - 31415926\n", "N1  - arXiv: 2101.00001\n", "N1  - ADS: 2021ApJ...1..1D\n", "M1  - ISO/IEC 27001:2022\n"} {
+		if !strings.Contains(string(ris), line) {
+			t.Fatalf("RIS export missing %q:\n%s", line, ris)
+		}
+	}
+	csl, err := store.EntriesToCSLJSON(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, parse := range map[string]func() ([]Record, error){
+		"RIS":      func() ([]Record, error) { return ParseRIS(bytes.NewReader(ris)) },
+		"CSL-JSON": func() ([]Record, error) { return ParseCSLJSON(bytes.NewReader(csl)) },
+	} {
+		recs, err := parse()
+		if err != nil || len(recs) != 2 {
+			t.Fatalf("%s re-import: %v %d", name, err, len(recs))
+		}
+		byTitle := map[string]Record{}
+		for _, r := range recs {
+			if len(r.Unmapped) != 0 {
+				t.Fatalf("%s %s: unmapped %v", name, r.Entry.APA7.Title, r.Unmapped)
+			}
+			byTitle[r.Entry.APA7.Title] = r
+		}
+		if got := byTitle["Trial"].Entry.APA7.Identifiers; got != ids {
+			t.Fatalf("%s identifiers:\n got %+v\nwant %+v", name, got, ids)
+		}
+		if s := byTitle["ISMS"].Entry.APA7; s.Designation != "ISO/IEC 27001:2022" {
+			t.Fatalf("%s designation lost: %+v", name, s)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bibliography/src/internal/lang"
+	"bibliography/src/internal/schema"
+)
+
+// risTypes maps RIS TY codes to entry types.
+var risTypes = map[string]string{
+	"JOUR": "article", "JFULL": "article", "MGZN": "article", "NEWS": "article", "CPAPER": "article", "CONF": "article",
+	"BOOK": "book", "CHAP": "book", "EBOOK": "book", "ECHAP": "book", "EDBOOK": "book",
+	"ELEC": "website", "WEB": "website", "BLOG": "website",
+	"PAT":   "patent",
+	"VIDEO": "video",
+	"MPCT":  "movie",
+	"SOUND": "song", "MUSIC": "song",
+	"RPRT": "report", "GOVDOC": "report",
+	"DATA":  "dataset",
+	"COMP":  "software",
+	"STAND": "standard",
+}
+
+// risMapped lists the RIS tags risRecord understands; anything else is reported as unmapped.
+var risMapped = map[string]bool{
+	"TY": true, "ID": true, "TI": true, "T1": true, "AU": true, "A1": true, "ED": true, "A4": true, "PY": true, "Y1": true, "DA": true,
+	"JO": true, "JF": true, "JA": true, "T2": true, "VL": true, "IS": true, "SP": true, "EP": true, "PB": true,
+	"CY": true, "ET": true, "DO": true, "UR": true, "LA": true, "KW": true, "AB": true, "N2": true, "Y2": true,
+}
+
+// ParseRIS reads RIS tag blocks ("TY  - JOUR" ... "ER  -") into records.
+func ParseRIS(r io.Reader) ([]Record, error) {
+	var out []Record
+	var cur map[string][]string
+	last := ""
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimRight(strings.TrimPrefix(sc.Text(), "\ufeff"), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line) < 5 || line[2:5] != "  -" {
+			if cur != nil && last != "" {
+				// continuation of the previous tag's value
+				vals := cur[last]
+				vals[len(vals)-1] += " " + strings.TrimSpace(line)
+				continue
+			}
+			return nil, fmt.Errorf("ris: line %d: expected \"TAG  - value\"", n)
+		}
+		tag := strings.ToUpper(strings.TrimSpace(line[:2]))
+		val := strings.TrimSpace(line[5:])
+		switch tag {
+		case "TY":
+			cur = map[string][]string{"TY": {val}}
+		case "ER":
+			if cur == nil {
+				return nil, fmt.Errorf("ris: line %d: ER without TY", n)
+			}
+			rec, err := risRecord(cur)
+			if err != nil {
+				return nil, fmt.Errorf("ris: record ending line %d: %w", n, err)
+			}
+			out = append(out, rec)
+			cur = nil
+		default:
+			if cur == nil {
+				return nil, fmt.Errorf("ris: line %d: %s before TY", n, tag)
+			}
+			cur[tag] = append(cur[tag], val)
+		}
+		last = tag
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("ris: missing ER for last record")
+	}
+	return out, nil
+}
+
+// risRecord maps one RIS tag block to a Record.
+func risRecord(tags map[string][]string) (Record, error) {
+	var rec Record
+	e := &rec.Entry
+	ty := strings.ToUpper(first(tags, "TY"))
+	typ, ok := risTypes[ty]
+	if !ok {
+		return Record{}, fmt.Errorf("unsupported RIS type %q", ty)
+	}
+	e.Type = typ
+	e.APA7.Title = first(tags, "TI", "T1")
+	for _, a := range append(tags["AU"], tags["A1"]...) {
+		if au := parseName(a); au.Family != "" {
+			e.APA7.Authors = append(e.APA7.Authors, au)
+		}
+	}
+	// ED lists editors and A4 translators, as written by the RIS export
+	for _, tr := range []struct{ tag, role string }{{"ED", schema.RoleEditor}, {"A4", schema.RoleTranslator}} {
+		for _, a := range tags[tr.tag] {
+			if au := parseName(a); au.Family != "" {
+				au.Role = tr.role
+				e.APA7.Authors = append(e.APA7.Authors, au)
+			}
+		}
+	}
+	y, m, d := risDate(first(tags, "DA", "PY", "Y1"))
+	if m == 0 {
+		// DA may carry only the month/day; prefer PY for the year
+		if py, _, _ := risDate(first(tags, "PY", "Y1")); py > 0 {
+			y = py
+		}
+	}
+	setDate(e, y, m, d)
+	if c := first(tags, "T2", "JO", "JF", "JA"); c != "" {
+		e.APA7.ContainerTitle = c
+		if typ == "article" {
+			e.APA7.Journal = c
+		}
+	}
+	e.APA7.Volume = first(tags, "VL")
+	e.APA7.Issue = first(tags, "IS")
+	e.APA7.Pages = first(tags, "SP")
+	if ep := first(tags, "EP"); ep != "" && e.APA7.Pages != "" {
+		e.APA7.Pages += "-" + ep
+	}
+	e.APA7.Publisher = first(tags, "PB")
+	e.APA7.PublisherLocation = first(tags, "CY")
+	e.APA7.Edition = first(tags, "ET")
+	e.APA7.DOI = first(tags, "DO")
+	e.APA7.URL = first(tags, "UR")
+	e.APA7.Language = lang.Normalize(first(tags, "LA"))
+	if ay, am, ad := risDate(first(tags, "Y2")); ay > 0 && am > 0 && ad > 0 {
+		e.APA7.Accessed = fmt.Sprintf("%04d-%02d-%02d", ay, am, ad)
+	}
+	for _, k := range tags["KW"] {
+		e.Annotation.Keywords = append(e.Annotation.Keywords, splitKeywords(k)...)
+	}
+	e.Annotation.Summary = first(tags, "AB", "N2")
+	for tag := range tags {
+		switch {
+		case tag == "SN" && typ == "book":
+			e.APA7.ISBN = first(tags, "SN")
+		case tag == "SN":
+			e.APA7.ISSN = first(tags, "SN")
+		case tag == "AN":
+			if !risAccession(e, first(tags, "DB"), first(tags, "AN")) {
+				rec.Unmapped = append(rec.Unmapped, tag)
+			}
+		case tag == "DB" && tags["AN"] != nil:
+			// consumed with AN
+		case tag == "N1":
+			// notes of the form "arXiv: 2101.00001" carry further accession ids
+			for _, n := range tags["N1"] {
+				db, num, ok := strings.Cut(n, ":")
+				if !ok || !risAccession(e, db, strings.TrimSpace(num)) {
+					rec.Unmapped = append(rec.Unmapped, tag)
+					break
+				}
+			}
+		case tag == "M1" && typ == "standard":
+			e.APA7.Designation = first(tags, "M1")
+		case !risMapped[tag]:
+			rec.Unmapped = append(rec.Unmapped, tag)
+		}
+	}
+	sort.Strings(rec.Unmapped)
+	finish(e, "RIS")
+	return rec, nil
+}
+
+// risAccession stores an AN accession number in the identifier named by the DB
+// database tag, reporting whether the database was recognized.
+func risAccession(e *schema.Entry, db, an string) bool {
+	switch d := strings.ToLower(db); {
+	case strings.Contains(d, "pubmed") || strings.Contains(d, "medline"):
+		e.APA7.PMID = an
+	case strings.Contains(d, "arxiv"):
+		e.APA7.ArXiv = an
+	case strings.Contains(d, "ads") || strings.Contains(d, "astrophysics data system"):
+		e.APA7.Bibcode = an
+	case strings.TrimSpace(d) == "isrc":
+		e.APA7.ISRC = an
+	default:
+		return false
+	}
+	return true
+}
+
+// risDate parses "YYYY", "YYYY/MM/DD/other", or "YYYY-MM-DD".
+func risDate(s string) (y, m, d int) {
+	nums := make([]int, 3)
+	for i, p := range strings.FieldsFunc(s, func(r rune) bool { return r == '/' || r == '-' }) {
+		if i >= 3 {
+			break
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			break
+		}
+		nums[i] = v
+	}
+	return nums[0], nums[1], nums[2]
+}
+
+// first returns the first value of the first tag present.
+func first(tags map[string][]string, keys ...string) string {
+	for _, k := range keys {
+		if vs := tags[k]; len(vs) > 0 && strings.TrimSpace(vs[0]) != "" {
+			return vs[0]
+		}
+	}
+	return ""
+}
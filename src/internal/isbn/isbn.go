@@ -0,0 +1,120 @@
+// Package isbn normalizes ISBN-10 and ISBN-13 strings and verifies their check digits.
+package isbn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalize strips spaces, hyphens, and other separators (and an "ISBN" prefix),
+// keeping only digits and an upper-case X. It does not validate the result.
+func Normalize(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "ISBN"), ":")
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Parse normalizes s and verifies it is a well-formed ISBN-10 or ISBN-13 with a
+// correct check digit, returning the compact form (e.g. "9780132350884").
+func Parse(s string) (string, error) {
+	n := Normalize(s)
+	if n == "" {
+		return "", fmt.Errorf("invalid ISBN %q: no digits", strings.TrimSpace(s))
+	}
+	if x := strings.IndexByte(n, 'X'); x >= 0 && !(len(n) == 10 && x == 9) {
+		return "", fmt.Errorf("invalid ISBN %q: X may only be the last character of an ISBN-10", s)
+	}
+	var want string
+	switch len(n) {
+	case 10:
+		want = CheckDigit10(n[:9])
+	case 13:
+		if !strings.HasPrefix(n, "978") && !strings.HasPrefix(n, "979") {
+			return "", fmt.Errorf("invalid ISBN %q: an ISBN-13 starts with 978 or 979", s)
+		}
+		want = CheckDigit13(n[:12])
+	default:
+		return "", fmt.Errorf("invalid ISBN %q: want 10 or 13 digits, got %d", s, len(n))
+	}
+	if got := n[len(n)-1:]; got != want {
+		return "", fmt.Errorf("invalid ISBN %q: check digit is %s, expected %s (typo?)", s, got, want)
+	}
+	return n, nil
+}
+
+// Valid reports whether s is an ISBN-10 or ISBN-13 with a correct check digit.
+func Valid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// CheckDigit10 computes the ISBN-10 check digit ("0"-"9" or "X") for a 9-digit core.
+func CheckDigit10(core string) string {
+	sum := 0
+	for i, ch := range core {
+		sum += (10 - i) * int(ch-'0')
+	}
+	cd := (11 - sum%11) % 11
+	if cd == 10 {
+		return "X"
+	}
+	return fmt.Sprint(cd)
+}
+
+// CheckDigit13 computes the ISBN-13 check digit for a 12-digit core (weights 1,3,1,3...).
+func CheckDigit13(core string) string {
+	sum := 0
+	for i, ch := range core {
+		w := 1
+		if i%2 == 1 {
+			w = 3
+		}
+		sum += w * int(ch-'0')
+	}
+	return fmt.Sprint((10 - sum%10) % 10)
+}
+
+// To13 converts an ISBN-10 to its 978-prefixed ISBN-13 form; ok is false when s is
+// not ten characters after normalization.
+func To13(s string) (string, bool) {
+	n := Normalize(s)
+	if len(n) != 10 || !digits(n[:9]) {
+		return "", false
+	}
+	core := "978" + n[:9]
+	return core + CheckDigit13(core), true
+}
+
+// To10 converts a 978-prefixed ISBN-13 to its ISBN-10 form; 979 ISBNs have none.
+func To10(s string) (string, bool) {
+	n := Normalize(s)
+	if len(n) != 13 || !strings.HasPrefix(n, "978") || !digits(n) {
+		return "", false
+	}
+	core := n[3:12]
+	return core + CheckDigit10(core), true
+}
+
+// Alternate returns the other form of s (ISBN-10 for ISBN-13 and vice versa), when
+// one exists.
+func Alternate(s string) (string, bool) {
+	if alt, ok := To13(s); ok {
+		return alt, true
+	}
+	return To10(s)
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
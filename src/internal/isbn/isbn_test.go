@@ -0,0 +1,86 @@
+package isbn
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseValid(t *testing.T) {
+	cases := map[string]string{
+		"0306406152":          "0306406152",
+		"0-306-40615-2":       "0306406152",
+		"080442957x":          "080442957X",
+		"ISBN 0-8044-2957-X":  "080442957X",
+		"978-0-13-235088-4":   "9780132350884",
+		"ISBN: 9780306406157": "9780306406157",
+		"979 10 90636 07 1":   "9791090636071",
+	}
+	for in, want := range cases {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v; want %q", in, got, err, want)
+		}
+		if !Valid(in) {
+			t.Errorf("Valid(%q) = false", in)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := map[string]string{
+		"0306406153":        "check digit is 3, expected 2",
+		"1234567890":        "check digit is 0, expected X",
+		"978-0-13-235088-5": "check digit is 5, expected 4",
+		"9770132350884":     "starts with 978 or 979",
+		"12345":             "want 10 or 13 digits, got 5",
+		"03X6406152":        "X may only be the last",
+		"abc":               "no digits",
+	}
+	for in, want := range cases {
+		_, err := Parse(in)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want it to mention %q", in, err, want)
+		}
+		if Valid(in) {
+			t.Errorf("Valid(%q) = true", in)
+		}
+	}
+}
+
+func TestCheckDigits(t *testing.T) {
+	if got := CheckDigit10("030640615"); got != "2" {
+		t.Fatalf("CheckDigit10 = %q", got)
+	}
+	if got := CheckDigit10("080442957"); got != "X" {
+		t.Fatalf("CheckDigit10 X = %q", got)
+	}
+	if got := CheckDigit13("978030640615"); got != "7" {
+		t.Fatalf("CheckDigit13 = %q", got)
+	}
+}
+
+func TestAlternate(t *testing.T) {
+	cases := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"0-306-40615-2", "9780306406157", true},
+		{"080442957X", "9780804429573", true},
+		{"978-0-306-40615-7", "0306406152", true},
+		{"9780804429573", "080442957X", true},
+		{"9791090636071", "", false},
+		{"12345", "", false},
+	}
+	for _, c := range cases {
+		got, ok := Alternate(c.in)
+		if got != c.want || ok != c.ok {
+			t.Errorf("Alternate(%q) = %q, %v; want %q, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+	if _, ok := To10("0306406152"); ok {
+		t.Fatalf("To10 of an ISBN-10 should fail")
+	}
+	if _, ok := To13("9780306406157"); ok {
+		t.Fatalf("To13 of an ISBN-13 should fail")
+	}
+}
@@ -0,0 +1,86 @@
+// Package lang normalizes the language of a work to an ISO 639-1 code ("de") and
+// names it in English for citations.
+package lang
+
+import "strings"
+
+// language is one known language: its ISO 639-1 code, English name, and the ISO 639-2
+// bibliographic/terminology codes (as used by MARC records and OpenLibrary).
+type language struct {
+	code, name string
+	alt        []string
+}
+
+var languages = []language{
+	{"ar", "Arabic", []string{"ara"}},
+	{"cs", "Czech", []string{"cze", "ces"}},
+	{"da", "Danish", []string{"dan"}},
+	{"de", "German", []string{"ger", "deu"}},
+	{"el", "Greek", []string{"gre", "ell"}},
+	{"en", "English", []string{"eng"}},
+	{"es", "Spanish", []string{"spa"}},
+	{"fi", "Finnish", []string{"fin"}},
+	{"fr", "French", []string{"fre", "fra"}},
+	{"he", "Hebrew", []string{"heb"}},
+	{"hi", "Hindi", []string{"hin"}},
+	{"hu", "Hungarian", []string{"hun"}},
+	{"it", "Italian", []string{"ita"}},
+	{"ja", "Japanese", []string{"jpn"}},
+	{"ko", "Korean", []string{"kor"}},
+	{"la", "Latin", []string{"lat"}},
+	{"nl", "Dutch", []string{"dut", "nld"}},
+	{"no", "Norwegian", []string{"nor", "nob", "nb"}},
+	{"pl", "Polish", []string{"pol"}},
+	{"pt", "Portuguese", []string{"por"}},
+	{"ru", "Russian", []string{"rus"}},
+	{"sv", "Swedish", []string{"swe"}},
+	{"tr", "Turkish", []string{"tur"}},
+	{"uk", "Ukrainian", []string{"ukr"}},
+	{"zh", "Chinese", []string{"chi", "zho"}},
+}
+
+var byCode = map[string]language{}
+
+func init() {
+	for _, l := range languages {
+		byCode[l.code] = l
+		byCode[strings.ToLower(l.name)] = l
+		for _, a := range l.alt {
+			byCode[a] = l
+		}
+	}
+}
+
+// Normalize returns the ISO 639-1 code for a language tag, code, or English name:
+// "de-DE", "ger", "deu", and "German" all give "de". Region and script subtags are
+// dropped; an OpenLibrary key ("/languages/ger") is reduced to its code. Unknown
+// two-letter codes are kept lowercased; anything else unknown gives "".
+func Normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "/languages/")
+	if i := strings.IndexAny(s, "-_"); i > 0 {
+		s = s[:i]
+	}
+	if l, ok := byCode[s]; ok {
+		return l.code
+	}
+	if len(s) == 2 && s[0] >= 'a' && s[0] <= 'z' && s[1] >= 'a' && s[1] <= 'z' {
+		return s
+	}
+	return ""
+}
+
+// Name returns the English name of a normalized code ("German" for "de"), or the code
+// itself when it is not in the table.
+func Name(code string) string {
+	if l, ok := byCode[Normalize(code)]; ok {
+		return l.name
+	}
+	return strings.TrimSpace(code)
+}
+
+// IsEnglish reports whether code is English or empty (works are English by default).
+func IsEnglish(code string) bool {
+	c := Normalize(code)
+	return c == "" || c == "en"
+}
@@ -0,0 +1,23 @@
+package lang
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"de": "de", "DE": "de", "de-DE": "de", "de_AT": "de", "ger": "de", "deu": "de", "German": "de",
+		"/languages/fre": "fr", "en-US": "en", "eng": "en", "zh-Hans": "zh", "xx": "xx", "": "", "klingon": "",
+	} {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNameAndIsEnglish(t *testing.T) {
+	if Name("de") != "German" || Name("ger") != "German" || Name("xx") != "xx" {
+		t.Fatalf("names: %q %q %q", Name("de"), Name("ger"), Name("xx"))
+	}
+	if !IsEnglish("") || !IsEnglish("en-GB") || IsEnglish("fr") {
+		t.Fatal("IsEnglish")
+	}
+}
@@ -0,0 +1,154 @@
+// Package linkcheck probes URLs and DOIs for reachability and classifies the outcome.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"bibliography/src/internal/httpx"
+)
+
+// Status is the category a probe falls into.
+type Status string
+
+const (
+	OK         Status = "ok"
+	Redirected Status = "redirected"
+	NotFound   Status = "404"
+	DNSError   Status = "dns error"
+	Timeout    Status = "timeout"
+	Failed     Status = "error" // any other HTTP status or network failure
+)
+
+// Statuses lists every category in report order.
+var Statuses = []Status{OK, Redirected, NotFound, DNSError, Timeout, Failed}
+
+// Reachable reports whether the status counts toward the reachable headline (OK or redirected).
+func (s Status) Reachable() bool { return s == OK || s == Redirected }
+
+// Kinds of target.
+const (
+	KindURL = "url"
+	KindDOI = "doi"
+)
+
+// Target is one URL or DOI to probe on behalf of an entry.
+type Target struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	// Value is the URL, or the bare DOI for KindDOI.
+	Value string `json:"target"`
+}
+
+// Result is the outcome of probing a Target.
+type Result struct {
+	Target
+	Status   Status `json:"status"`
+	Code     int    `json:"code,omitempty"`
+	Location string `json:"location,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Redirects are reported, not followed, so "redirected" can be told apart from "ok".
+var client httpx.Doer = &http.Client{
+	Timeout:       httpx.Timeout(),
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+// SetHTTPClient sets the HTTP client used for probes (for tests). The client should
+// not follow redirects.
+func SetHTTPClient(c httpx.Doer) { client = c }
+
+// Check probes a single target. URLs are tried with HEAD and, when the server rejects
+// HEAD, with GET. DOIs are resolved through doi.org, where a redirect to the landing
+// page means the DOI resolves (OK).
+func Check(ctx context.Context, t Target) Result {
+	u := t.Value
+	if t.Kind == KindDOI {
+		u = "https://doi.org/" + strings.TrimSpace(t.Value)
+	}
+	r := Result{Target: t}
+	code, loc, err := probe(ctx, http.MethodHead, u)
+	if err == nil && (code == http.StatusMethodNotAllowed || code == http.StatusForbidden || code == http.StatusNotImplemented) {
+		code, loc, err = probe(ctx, http.MethodGet, u)
+	}
+	if err != nil {
+		r.Status, r.Detail = classifyErr(err), err.Error()
+		return r
+	}
+	r.Code, r.Location = code, loc
+	switch {
+	case code >= 200 && code < 300:
+		r.Status = OK
+	case code >= 300 && code < 400:
+		r.Status = Redirected
+		if t.Kind == KindDOI {
+			r.Status = OK
+		}
+	case code == http.StatusNotFound || code == http.StatusGone:
+		r.Status = NotFound
+	default:
+		r.Status = Failed
+	}
+	return r
+}
+
+// CheckAll probes targets with up to workers concurrent requests and returns results
+// in the same order as targets.
+func CheckAll(ctx context.Context, targets []Target, workers int) []Result {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]Result, len(targets))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, t Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = Check(ctx, t)
+		}(i, t)
+	}
+	wg.Wait()
+	return results
+}
+
+// probe issues one request and returns the status code and Location header.
+func probe(ctx context.Context, method, u string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	httpx.SetUA(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("Location"), nil
+}
+
+// classifyErr maps a transport error to DNSError, Timeout, or Failed.
+func classifyErr(err error) Status {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return Timeout
+		}
+		return DNSError
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return Timeout
+	}
+	return Failed
+}
@@ -0,0 +1,70 @@
+package linkcheck
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fakeHTTP answers by URL (and method) with a status, or fails with err.
+type fakeHTTP map[string]any
+
+func (f fakeHTTP) Do(req *http.Request) (*http.Response, error) {
+	v, ok := f[req.Method+" "+req.URL.String()]
+	if !ok {
+		v = f[req.URL.String()]
+	}
+	switch v := v.(type) {
+	case error:
+		return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: v}
+	case int:
+		h := make(http.Header)
+		if v >= 300 && v < 400 {
+			h.Set("Location", "https://landing.example.com/")
+		}
+		return &http.Response{StatusCode: v, Body: io.NopCloser(strings.NewReader("")), Header: h}, nil
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+func TestCheckAllClassifies(t *testing.T) {
+	old := client
+	t.Cleanup(func() { client = old })
+	SetHTTPClient(fakeHTTP{
+		"https://ok.example.com/":           200,
+		"https://moved.example.com/":        301,
+		"https://gone.example.com/":         404,
+		"https://nohost.invalid/":           &net.DNSError{Err: "no such host", Name: "nohost.invalid", IsNotFound: true},
+		"https://slow.example.com/":         context.DeadlineExceeded,
+		"HEAD https://nohead.example.com/":  405,
+		"GET https://nohead.example.com/":   200,
+		"https://broken.example.com/":       500,
+		"https://doi.org/10.1/resolves":     302,
+		"https://doi.org/10.1/unregistered": 404,
+	})
+	targets := []Target{
+		{ID: "a", Kind: KindURL, Value: "https://ok.example.com/"},
+		{ID: "b", Kind: KindURL, Value: "https://moved.example.com/"},
+		{ID: "c", Kind: KindURL, Value: "https://gone.example.com/"},
+		{ID: "d", Kind: KindURL, Value: "https://nohost.invalid/"},
+		{ID: "e", Kind: KindURL, Value: "https://slow.example.com/"},
+		{ID: "f", Kind: KindURL, Value: "https://nohead.example.com/"},
+		{ID: "g", Kind: KindURL, Value: "https://broken.example.com/"},
+		{ID: "h", Kind: KindDOI, Value: "10.1/resolves"},
+		{ID: "i", Kind: KindDOI, Value: "10.1/unregistered"},
+	}
+	want := []Status{OK, Redirected, NotFound, DNSError, Timeout, OK, Failed, OK, NotFound}
+	got := CheckAll(context.Background(), targets, 3)
+	for i, r := range got {
+		if r.ID != targets[i].ID || r.Status != want[i] {
+			t.Fatalf("%s: got %q (%+v), want %q", targets[i].Value, r.Status, r, want[i])
+		}
+	}
+	if got[1].Location != "https://landing.example.com/" || got[6].Code != 500 {
+		t.Fatalf("expected location/code details: %+v %+v", got[1], got[6])
+	}
+}
@@ -17,7 +17,7 @@ import (
 	"bibliography/src/internal/schema"
 )
 
-var client httpx.Doer = &http.Client{Timeout: 10 * time.Second}
+var client httpx.Doer = httpx.ProviderClient()
 
 // SetHTTPClient sets the HTTP client used for external movie APIs (for tests).
 func SetHTTPClient(c httpx.Doer) { client = c }
@@ -23,6 +23,7 @@ func Initials(given string) string {
 
 // Split splits a full name into (family, givenInitials). It accepts either
 // "Family, Given Names" or "Given Names Family" and returns initials for given.
+// Lowercase surname particles ("van der Berg", "von Neumann") stay with the family name.
 func Split(name string) (family, givenInitials string) {
 	name = strings.TrimSpace(name)
 	if name == "" {
@@ -37,7 +38,35 @@ func Split(name string) (family, givenInitials string) {
 	if len(parts) == 1 {
 		return parts[0], ""
 	}
-	family = parts[len(parts)-1]
-	given := strings.Join(parts[:len(parts)-1], " ")
+	start := len(parts) - 1
+	for start > 1 && isParticle(parts[start-1]) {
+		start--
+	}
+	family = strings.Join(parts[start:], " ")
+	given := strings.Join(parts[:start], " ")
 	return family, Initials(given)
 }
+
+// particles lists lowercase nobiliary/surname particles kept with the family name.
+var particles = map[string]bool{
+	"von": true, "van": true, "der": true, "den": true, "de": true, "des": true,
+	"del": true, "della": true, "di": true, "da": true, "das": true, "dos": true,
+	"du": true, "la": true, "le": true, "zu": true, "zur": true, "ten": true, "ter": true,
+}
+
+// isParticle reports whether w is a lowercase surname particle.
+func isParticle(w string) bool { return particles[w] }
+
+// SplitParticle separates leading lowercase particles from a family name:
+// "van der Berg" -> ("van der", "Berg"). Names without particles return ("", family).
+func SplitParticle(family string) (particle, last string) {
+	parts := strings.Fields(family)
+	i := 0
+	for i < len(parts)-1 && isParticle(parts[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", strings.TrimSpace(family)
+	}
+	return strings.Join(parts[:i], " "), strings.Join(parts[i:], " ")
+}
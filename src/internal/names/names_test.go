@@ -21,3 +21,33 @@ func TestSplit(t *testing.T) {
 		t.Fatalf("Split space: got (%q,%q)", fam, giv)
 	}
 }
+
+func TestSplitParticles(t *testing.T) {
+	cases := []struct{ in, fam, giv string }{
+		{"Jan van der Berg", "van der Berg", "J."},
+		{"John von Neumann", "von Neumann", "J."},
+		{"Ludwig van Beethoven", "van Beethoven", "L."},
+		{"van der Berg, Jan", "van der Berg", "J."},
+		// capitalized words are not particles
+		{"Dick Van Dyke", "Dyke", "D. V."},
+	}
+	for _, c := range cases {
+		fam, giv := Split(c.in)
+		if fam != c.fam || giv != c.giv {
+			t.Fatalf("Split(%q): got (%q,%q) want (%q,%q)", c.in, fam, giv, c.fam, c.giv)
+		}
+	}
+}
+
+func TestSplitParticle(t *testing.T) {
+	if p, l := SplitParticle("van der Berg"); p != "van der" || l != "Berg" {
+		t.Fatalf("SplitParticle: got (%q,%q)", p, l)
+	}
+	if p, l := SplitParticle("Doe"); p != "" || l != "Doe" {
+		t.Fatalf("SplitParticle no particle: got (%q,%q)", p, l)
+	}
+	// a lone particle word is the surname itself
+	if p, l := SplitParticle("van"); p != "" || l != "van" {
+		t.Fatalf("SplitParticle lone: got (%q,%q)", p, l)
+	}
+}
@@ -3,21 +3,25 @@ package openlibrary
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
-	"time"
 
 	"bibliography/src/internal/dates"
+	"bibliography/src/internal/googlebooks"
 	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/isbn"
+	"bibliography/src/internal/lang"
 	"bibliography/src/internal/names"
 	"bibliography/src/internal/sanitize"
 	"bibliography/src/internal/schema"
 )
 
-var client httpx.Doer = &http.Client{Timeout: 10 * time.Second}
+var client httpx.Doer = httpx.ProviderClient()
 
 // SetHTTPClient allows tests to inject a fake HTTP client.
 func SetHTTPClient(c httpx.Doer) { client = c }
@@ -26,7 +30,7 @@ func SetHTTPClient(c httpx.Doer) { client = c }
 func FetchBookByISBN(ctx context.Context, isbn string) (schema.Entry, error) {
 	norm := normalizeISBN(isbn)
 	req := buildOpenLibraryRequest(ctx, norm)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
@@ -40,9 +44,13 @@ func FetchBookByISBN(ctx context.Context, isbn string) (schema.Entry, error) {
 		return schema.Entry{}, err
 	}
 	if !ok {
-		if e, err := fetchGoogleBookByISBN(ctx, norm); err == nil {
+		e, gerr := fetchGoogleBookByISBN(ctx, norm)
+		if gerr == nil {
 			return e, nil
 		}
+		if errors.Is(gerr, googlebooks.ErrRateLimited) {
+			return schema.Entry{}, fmt.Errorf("openlibrary: no data for ISBN:%s; %w", norm, gerr)
+		}
 		return schema.Entry{}, fmt.Errorf("openlibrary: no data for ISBN:%s", norm)
 	}
 	e := mapOpenLibraryToEntry(ctx, data, norm, isbn)
@@ -53,11 +61,33 @@ func FetchBookByISBN(ctx context.Context, isbn string) (schema.Entry, error) {
 	return e, nil
 }
 
+// reEditedBy matches an edition's by_statement naming its editors, e.g.
+// "edited by Jane Doe and John Roe".
+var reEditedBy = regexp.MustCompile(`(?i)^\s*(?:edited by|eds?\.?:?)\s+(.+?)\.?\s*$`)
+var reNameSep = regexp.MustCompile(`\s*(?:,|;|&|\band\b)\s*`)
+
+// editorsFromByStatement returns the editors named by an "edited by ..." by_statement,
+// or nil when the statement does not name editors.
+func editorsFromByStatement(s string) schema.Authors {
+	m := reEditedBy.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	var out schema.Authors
+	for _, part := range reNameSep.Split(m[1], -1) {
+		if fam, giv := names.Split(part); fam != "" {
+			out = append(out, schema.Author{Family: fam, Given: giv, Role: schema.RoleEditor})
+		}
+	}
+	return out
+}
+
 type olData struct {
 	Title       string                  `json:"title"`
 	PublishDate string                  `json:"publish_date"`
 	URL         string                  `json:"url"`
 	Authors     []struct{ Name string } `json:"authors"`
+	ByStatement string                  `json:"by_statement"`
 	Publishers  []struct{ Name string } `json:"publishers"`
 	Subjects    []struct{ Name string } `json:"subjects"`
 }
@@ -110,13 +140,17 @@ func mapOpenLibraryToEntry(ctx context.Context, data olData, norm string, origIS
 		fam, giv := names.Split(a.Name)
 		e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: giv})
 	}
+	if len(e.APA7.Authors) == 0 {
+		e.APA7.Authors = editorsFromByStatement(data.ByStatement)
+	}
 	for _, s := range data.Subjects {
 		name := strings.TrimSpace(s.Name)
 		if name != "" {
 			e.Annotation.Keywords = append(e.Annotation.Keywords, strings.ToLower(name))
 		}
 	}
-	desc, moreKs := fetchDescriptionFallback(ctx, norm)
+	desc, moreKs, language := fetchDescriptionFallback(ctx, norm)
+	e.APA7.Language = language
 	if len(moreKs) > 0 {
 		e.Annotation.Keywords = append(e.Annotation.Keywords, moreKs...)
 	}
@@ -151,14 +185,13 @@ func mapOpenLibraryToEntry(ctx context.Context, data olData, norm string, origIS
 // fetchGoogleBookByISBN queries Google Books API for a given ISBN and maps the first result.
 func fetchGoogleBookByISBN(ctx context.Context, isbn string) (schema.Entry, error) {
 	req := buildGoogleBooksRequest(ctx, isbn)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return schema.Entry{}, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return schema.Entry{}, fmt.Errorf("googlebooks: http %d: %s", resp.StatusCode, string(b))
+		return schema.Entry{}, googlebooks.StatusError(resp)
 	}
 	gb, err := decodeGoogleBooks(resp)
 	if err != nil {
@@ -191,13 +224,13 @@ type gVolume struct {
 	Description   string   `json:"description"`
 	Categories    []string `json:"categories"`
 	InfoLink      string   `json:"infoLink"`
+	Language      string   `json:"language"`
 }
 
 func buildGoogleBooksRequest(ctx context.Context, isbn string) *http.Request {
 	q := url.Values{}
 	q.Set("q", "isbn:"+isbn)
-	endpoint := "https://www.googleapis.com/books/v1/volumes?" + q.Encode()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, googlebooks.VolumesURL(q), nil)
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
 	return req
@@ -217,6 +250,7 @@ func mapGoogleBookToEntry(v gVolume, isbn string) schema.Entry {
 	e.APA7.Title = v.Title
 	e.APA7.Publisher = v.Publisher
 	e.APA7.ISBN = isbn
+	e.APA7.Language = lang.Normalize(v.Language)
 	if y := dates.ExtractYear(v.PublishedDate); y > 0 {
 		e.APA7.Year = &y
 	}
@@ -252,50 +286,23 @@ func mapGoogleBookToEntry(v gVolume, isbn string) schema.Entry {
 }
 
 // normalizeISBN cleans input and, if a 9-digit core is provided, computes the ISBN-10 check digit.
-func normalizeISBN(isbn string) string {
-	s := strings.ToUpper(strings.TrimSpace(isbn))
-	// keep digits only initially
-	core := make([]rune, 0, len(s))
-	for _, r := range s {
-		if r >= '0' && r <= '9' || r == 'X' {
-			core = append(core, r)
-		}
-	}
-	// If 9 digits, compute ISBN-10 check digit
-	digitsOnly := true
-	for _, r := range core {
-		if r < '0' || r > '9' {
-			digitsOnly = false
-			break
-		}
+func normalizeISBN(s string) string {
+	core := isbn.Normalize(s)
+	if len(core) == 9 && !strings.Contains(core, "X") {
+		return core + isbn.CheckDigit10(core)
 	}
-	if len(core) == 9 && digitsOnly {
-		cd := isbn10CheckDigit(string(core))
-		return string(core) + cd
-	}
-	return string(core)
+	return core
 }
 
 // isbn10CheckDigit computes the ISBN-10 check digit for a 9-digit string, returning "0"-"9" or "X".
-func isbn10CheckDigit(s string) string {
-	sum := 0
-	for i, ch := range s { // i=0..8 corresponds to position 1..9
-		d := int(ch - '0')
-		sum += (i + 1) * d
-	}
-	mod := sum % 11
-	cd := (11 - mod) % 11
-	if cd == 10 {
-		return "X"
-	}
-	return fmt.Sprintf("%d", cd)
-}
+func isbn10CheckDigit(s string) string { return isbn.CheckDigit10(s) }
 
 // fetchDescriptionFallback attempts to retrieve a richer description by calling
 // the OpenLibrary Books API with jscmd=details, and if a work key is present,
-// fetches the work JSON to extract description. Errors are swallowed; returns
+// fetches the work JSON to extract description. It also returns the subjects and the
+// edition's first language as an ISO 639-1 code. Errors are swallowed; returns
 // empty string if no description can be obtained.
-func fetchDescriptionFallback(ctx context.Context, isbn string) (string, []string) {
+func fetchDescriptionFallback(ctx context.Context, isbn string) (string, []string, string) {
 	// details endpoint
 	q := url.Values{}
 	q.Set("bibkeys", "ISBN:"+strings.ReplaceAll(isbn, " ", ""))
@@ -304,26 +311,26 @@ func fetchDescriptionFallback(ctx context.Context, isbn string) (string, []strin
 	endpoint := "https://openlibrary.org/api/books?" + q.Encode()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return "", nil
+		return "", nil, ""
 	}
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
-		return "", nil
+		return "", nil, ""
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return "", nil
+		return "", nil, ""
 	}
 	var raw map[string]json.RawMessage
 	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
-		return "", nil
+		return "", nil, ""
 	}
 	key := "ISBN:" + strings.ReplaceAll(isbn, " ", "")
 	entryRaw, ok := raw[key]
 	if !ok || len(entryRaw) == 0 {
-		return "", nil
+		return "", nil, ""
 	}
 	var entry struct {
 		Details struct {
@@ -331,27 +338,34 @@ func fetchDescriptionFallback(ctx context.Context, isbn string) (string, []strin
 			Works       []struct {
 				Key string `json:"key"`
 			} `json:"works"`
-			Subjects any `json:"subjects"`
+			Subjects  any `json:"subjects"`
+			Languages []struct {
+				Key string `json:"key"`
+			} `json:"languages"`
 		} `json:"details"`
 	}
 	if err := json.Unmarshal(entryRaw, &entry); err != nil {
-		return "", nil
+		return "", nil, ""
 	}
 	// Prefer details.description if present
 	var subjects []string
 	if sub := parseSubjects(entry.Details.Subjects); len(sub) > 0 {
 		subjects = sub
 	}
+	language := ""
+	if len(entry.Details.Languages) > 0 {
+		language = lang.Normalize(entry.Details.Languages[0].Key)
+	}
 	if d := toDescription(entry.Details.Description); d != "" {
-		return d, subjects
+		return d, subjects, language
 	}
 	// Fallback to work description
 	if len(entry.Details.Works) > 0 {
 		if w := fetchWorkDescription(ctx, entry.Details.Works[0].Key); w != "" {
-			return w, subjects
+			return w, subjects, language
 		}
 	}
-	return "", subjects
+	return "", subjects, language
 }
 
 // fetchWorkDescription loads a work JSON by key and returns its description text.
@@ -370,7 +384,7 @@ func fetchWorkDescription(ctx context.Context, workKey string) string {
 	}
 	req.Header.Set("Accept", "application/json")
 	httpx.SetUA(req)
-	resp, err := client.Do(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
 	if err != nil {
 		return ""
 	}
@@ -1,14 +1,25 @@
 package openlibrary
 
 import (
+	"bibliography/src/internal/googlebooks"
+	"bibliography/src/internal/httpx"
 	namespkg "bibliography/src/internal/names"
+	"bibliography/src/internal/schema"
 	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 )
 
+// TestMain disables retry backoff so failing-provider cases run instantly.
+func TestMain(m *testing.M) {
+	httpx.SetDefaultRetryPolicy(httpx.RetryPolicy{MaxAttempts: 3})
+	os.Exit(m.Run())
+}
+
 type fakeResp struct {
 	status int
 	body   string
@@ -90,17 +101,62 @@ func TestFetchBookByISBN_FallbackGoogle(t *testing.T) {
 	}
 }
 
+// urlRecorder serves routeHTTP responses and records every requested URL.
+type urlRecorder struct {
+	routeHTTP
+	urls *[]string
+}
+
+func (r urlRecorder) Do(req *http.Request) (*http.Response, error) {
+	*r.urls = append(*r.urls, req.URL.String())
+	return r.routeHTTP.Do(req)
+}
+
+func TestFetchBookByISBN_GoogleAPIKeyAndRateLimit(t *testing.T) {
+	old := client
+	defer func() { client = old }()
+	t.Setenv("GOOGLE_BOOKS_API_KEY", "test-key")
+	var urls []string
+	google := `{"items":[{"volumeInfo":{"title":"Clean Code","authors":["Robert C. Martin"],"publisher":"Prentice Hall","publishedDate":"2008"}}]}`
+	client = urlRecorder{routeHTTP{routes: []route{{"openlibrary.org/api/books", 200, "{}"}, {"googleapis.com/books", 200, google}}}, &urls}
+	if _, err := FetchBookByISBN(context.Background(), "9780132350884"); err != nil {
+		t.Fatalf("fallback google: %v", err)
+	}
+	var gurl string
+	for _, u := range urls {
+		if strings.Contains(u, "googleapis.com") {
+			gurl = u
+		}
+	}
+	if !strings.Contains(gurl, "key=test-key") {
+		t.Fatalf("api key not attached: %q", gurl)
+	}
+
+	t.Setenv("GOOGLE_BOOKS_API_KEY", "")
+	urls = nil
+	client = urlRecorder{routeHTTP{routes: []route{{"openlibrary.org/api/books", 200, "{}"}, {"googleapis.com/books", 429, "quota"}}}, &urls}
+	_, err := FetchBookByISBN(context.Background(), "9780132350884")
+	if !errors.Is(err, googlebooks.ErrRateLimited) {
+		t.Fatalf("want rate-limited error, got %v", err)
+	}
+	for _, u := range urls {
+		if strings.Contains(u, "key=") {
+			t.Fatalf("key sent without GOOGLE_BOOKS_API_KEY: %s", u)
+		}
+	}
+}
+
 func TestFetchBookByISBN_Normalizes9DigitISBN(t *testing.T) {
 	// OpenLibrary empty; Google returns a volume for normalized 10-digit
 	old := client
 	defer func() { client = old }()
 	google := `{"items":[{"volumeInfo":{"title":"Some Title","authors":["Jane Roe"],"publisher":"Acme","publishedDate":"1999","description":"Desc","categories":["Cat"],"infoLink":"https://books.google.com/..."}}]}`
-	client = routeHTTP{routes: []route{{"openlibrary.org/api/books", 200, "{}"}, {"isbn%3A0262060164", 200, google}}}
+	client = routeHTTP{routes: []route{{"openlibrary.org/api/books", 200, "{}"}, {"isbn%3A0262060167", 200, google}}}
 	e, err := FetchBookByISBN(context.Background(), "026206016")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
-	if e.APA7.ISBN != "0262060164" {
+	if e.APA7.ISBN != "0262060167" {
 		t.Fatalf("expected normalized ISBN-10, got %q", e.APA7.ISBN)
 	}
 	if len(e.APA7.Authors) == 0 || e.APA7.Authors[0].Family == "" {
@@ -119,11 +175,26 @@ func TestSplitName(t *testing.T) {
 	}
 }
 
+func TestEditorsFromByStatement(t *testing.T) {
+	got := editorsFromByStatement("edited by Jane Q Public and John Roe.")
+	if len(got) != 2 || got[0].Family != "Public" || got[0].Given != "J. Q." || got[1].Family != "Roe" {
+		t.Fatalf("editors: %+v", got)
+	}
+	for _, a := range got {
+		if a.Role != schema.RoleEditor {
+			t.Fatalf("role: %+v", a)
+		}
+	}
+	if got := editorsFromByStatement("by Jane Public"); got != nil {
+		t.Fatalf("a plain by-statement names no editors: %+v", got)
+	}
+}
+
 func TestNormalizeISBNAndCheckDigit(t *testing.T) {
-	if normalizeISBN("0-262-06016") != "0262060164" {
+	if normalizeISBN("0-262-06016") != "0262060167" {
 		t.Fatalf("normalizeISBN failed")
 	}
-	if isbn10CheckDigit("026206016") != "4" {
+	if isbn10CheckDigit("026206016") != "7" {
 		t.Fatalf("isbn10CheckDigit failed")
 	}
 }
@@ -0,0 +1,62 @@
+// Package orcid normalizes ORCID iDs and verifies their check digits.
+package orcid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalize strips an orcid.org URL prefix, spaces, and hyphens, keeping only digits
+// and an upper-case X. It does not validate the result.
+func Normalize(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if i := strings.Index(s, "ORCID.ORG/"); i >= 0 {
+		s = s[i+len("ORCID.ORG/"):]
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Parse normalizes s (a bare iD or an orcid.org URL) and verifies its ISO 7064 11,2
+// check digit, returning the hyphenated form (e.g. "0000-0002-1825-0097").
+func Parse(s string) (string, error) {
+	n := Normalize(s)
+	if len(n) != 16 {
+		return "", fmt.Errorf("invalid ORCID %q: want 16 digits, got %d", strings.TrimSpace(s), len(n))
+	}
+	if x := strings.IndexByte(n, 'X'); x >= 0 && x != 15 {
+		return "", fmt.Errorf("invalid ORCID %q: X may only be the check digit", s)
+	}
+	if got, want := n[15:], CheckDigit(n[:15]); got != want {
+		return "", fmt.Errorf("invalid ORCID %q: check digit is %s, expected %s", s, got, want)
+	}
+	return n[0:4] + "-" + n[4:8] + "-" + n[8:12] + "-" + n[12:16], nil
+}
+
+// Valid reports whether s is an ORCID iD with a correct check digit.
+func Valid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// URL returns the canonical https://orcid.org/ form of a hyphenated iD.
+func URL(id string) string { return "https://orcid.org/" + id }
+
+// CheckDigit computes the ISO 7064 11,2 check digit ("0"-"9" or "X") for the first
+// 15 digits of an iD.
+func CheckDigit(core string) string {
+	total := 0
+	for _, ch := range core {
+		total = (total + int(ch-'0')) * 2
+	}
+	cd := (12 - total%11) % 11
+	if cd == 10 {
+		return "X"
+	}
+	return fmt.Sprint(cd)
+}
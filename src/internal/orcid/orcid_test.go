@@ -0,0 +1,38 @@
+package orcid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseValid(t *testing.T) {
+	cases := map[string]string{
+		"0000-0002-1825-0097":                   "0000-0002-1825-0097",
+		"http://orcid.org/0000-0002-1694-233X":  "0000-0002-1694-233X",
+		"https://orcid.org/0000-0001-5109-3700": "0000-0001-5109-3700",
+		"0000000218250097":                      "0000-0002-1825-0097",
+	}
+	for in, want := range cases {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v; want %q", in, got, err, want)
+		}
+		if !Valid(in) {
+			t.Errorf("Valid(%q) = false", in)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cases := map[string]string{
+		"0000-0002-1825-0098": "check digit is 8, expected 7",
+		"0000-0002-1825":      "want 16 digits, got 12",
+		"0000-000X-1825-0097": "X may only be the check digit",
+	}
+	for in, want := range cases {
+		_, err := Parse(in)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want it to mention %q", in, err, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package pages normalizes and validates page ranges such as "45-60" or "e12345".
+package pages
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// reDash matches a range separator: a hyphen, en or em dash, minus sign, or a
+// doubled hyphen, with any surrounding spaces.
+var reDash = regexp.MustCompile(`\s*(?:--|[-‐‑‒–—−])\s*`)
+
+// rePrefix matches a leading "p." or "pp." label.
+var rePrefix = regexp.MustCompile(`(?i)^pp?\.\s*`)
+
+// Normalize trims s, drops a "p."/"pp." label, and writes a range with a single
+// ASCII hyphen ("45–60" and "45 -- 60" become "45-60"). An abbreviated numeric end
+// page is expanded ("1234-56" becomes "1234-1256"). Single pages and electronic
+// locators ("e12345") are returned as given. It does not validate the result.
+func Normalize(s string) string {
+	s = rePrefix.ReplaceAllString(strings.TrimSpace(s), "")
+	parts := reDash.Split(s, -1)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return s
+	}
+	start, end := parts[0], parts[1]
+	if isNumber(start) && isNumber(end) && len(end) < len(start) {
+		end = start[:len(start)-len(end)] + end
+	}
+	return start + "-" + end
+}
+
+// Split returns the start and end pages of a normalized range; end is empty for a
+// single page or locator.
+func Split(s string) (start, end string) {
+	start, end, _ = strings.Cut(Normalize(s), "-")
+	return start, end
+}
+
+// Validate reports an error when a numeric range ends before it starts.
+func Validate(s string) error {
+	start, end := Split(s)
+	if !isNumber(start) || !isNumber(end) {
+		return nil
+	}
+	a, _ := strconv.Atoi(start)
+	b, _ := strconv.Atoi(end)
+	if a > b {
+		return fmt.Errorf("invalid page range %q: ends (%d) before it starts (%d)", s, b, a)
+	}
+	return nil
+}
+
+// Parse normalizes s and validates the result.
+func Parse(s string) (string, error) {
+	n := Normalize(s)
+	if err := Validate(n); err != nil {
+		return "", err
+	}
+	return n, nil
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
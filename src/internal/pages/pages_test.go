@@ -0,0 +1,51 @@
+package pages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"45-60":     "45-60",
+		"45–60":     "45-60",
+		"45 — 60":   "45-60",
+		"45--60":    "45-60",
+		"pp. 45-60": "45-60",
+		"1234-56":   "1234-1256",
+		"45":        "45",
+		" p. 7 ":    "7",
+		"e12345":    "e12345",
+		"S12–S19":   "S12-S19",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	cases := map[string][2]string{
+		"45–60":  {"45", "60"},
+		"45":     {"45", ""},
+		"e12345": {"e12345", ""},
+		"":       {"", ""},
+	}
+	for in, want := range cases {
+		if s, e := Split(in); s != want[0] || e != want[1] {
+			t.Errorf("Split(%q) = %q, %q; want %q, %q", in, s, e, want[0], want[1])
+		}
+	}
+}
+
+func TestParseValidatesOrder(t *testing.T) {
+	for _, ok := range []string{"45-60", "45", "e12345", "7-7", "S12-S9"} {
+		if _, err := Parse(ok); err != nil {
+			t.Errorf("Parse(%q): %v", ok, err)
+		}
+	}
+	if _, err := Parse("60–45"); err == nil || !strings.Contains(err.Error(), "ends (45) before it starts (60)") {
+		t.Fatalf("reversed range error = %v", err)
+	}
+}
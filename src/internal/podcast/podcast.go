@@ -0,0 +1,267 @@
+// Package podcast builds podcast episode entries from RSS feeds and Apple Podcasts links.
+package podcast
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/sanitize"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/stringsx"
+)
+
+var client httpx.Doer = httpx.ProviderClient()
+
+// SetHTTPClient sets the HTTP client used for feed and iTunes lookups (for tests).
+func SetHTTPClient(c httpx.Doer) { client = c }
+
+// rssFeed maps the parts of an RSS 2.0 podcast feed used for citations.
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel struct {
+		Title  string    `xml:"title"`
+		Link   string    `xml:"link"`
+		Author string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+		Owner  string    `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd owner>name"`
+		Items  []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+	Summary     string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	Author      string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	Episode     string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	Enclosure   struct {
+		URL string `xml:"url,attr"`
+	} `xml:"enclosure"`
+}
+
+var reApplePodcast = regexp.MustCompile(`/id(\d+)`)
+var reTag = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// FetchEpisode builds a podcast entry from an Apple Podcasts URL or a direct RSS feed
+// URL. The episode is the one whose title contains episode (case-insensitive); with
+// no episode, an Apple link's ?i= episode, then the item whose link or guid is pageURL, then
+// the newest item.
+func FetchEpisode(ctx context.Context, pageURL, episode string) (schema.Entry, error) {
+	u, err := url.Parse(strings.TrimSpace(pageURL))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return schema.Entry{}, fmt.Errorf("invalid podcast url %q", pageURL)
+	}
+	feedURL, citeURL := u.String(), ""
+	if isApple(u) {
+		m := reApplePodcast.FindStringSubmatch(u.Path)
+		if m == nil {
+			return schema.Entry{}, fmt.Errorf("apple podcasts url has no show id: %s", pageURL)
+		}
+		if feedURL, err = lookupFeed(ctx, m[1]); err != nil {
+			return schema.Entry{}, err
+		}
+		citeURL = u.String()
+		if ep := u.Query().Get("i"); ep != "" && strings.TrimSpace(episode) == "" {
+			if episode, err = lookupEpisodeTitle(ctx, m[1], ep); err != nil {
+				return schema.Entry{}, err
+			}
+		}
+	}
+	body, err := get(ctx, feedURL, "podcast feed")
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	feed, err := parseFeed(body)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	item, err := pickItem(feed.Channel.Items, episode, pageURL)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	return entryFromItem(feed, item, citeURL)
+}
+
+func isApple(u *url.URL) bool {
+	h := strings.ToLower(u.Hostname())
+	return h == "podcasts.apple.com" || h == "itunes.apple.com"
+}
+
+// parseFeed decodes an RSS podcast feed.
+func parseFeed(b []byte) (rssFeed, error) {
+	var feed rssFeed
+	d := xml.NewDecoder(bytes.NewReader(b))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	if err := d.Decode(&feed); err != nil {
+		return rssFeed{}, fmt.Errorf("podcast feed: %w", err)
+	}
+	if len(feed.Channel.Items) == 0 {
+		return rssFeed{}, fmt.Errorf("podcast feed: no episodes")
+	}
+	return feed, nil
+}
+
+// pickItem selects the episode as described on FetchEpisode.
+func pickItem(items []rssItem, episode, pageURL string) (rssItem, error) {
+	if q := strings.ToLower(strings.TrimSpace(episode)); q != "" {
+		for _, it := range items {
+			if strings.Contains(strings.ToLower(it.Title), q) {
+				return it, nil
+			}
+		}
+		return rssItem{}, fmt.Errorf("podcast feed: no episode matching %q", episode)
+	}
+	pageURL = strings.TrimSpace(pageURL)
+	for _, it := range items {
+		if pageURL != "" && (strings.TrimSpace(it.Link) == pageURL || strings.TrimSpace(it.GUID) == pageURL) {
+			return it, nil
+		}
+	}
+	return items[0], nil
+}
+
+// entryFromItem maps an RSS item and its channel to a podcast entry: the show is the
+// container, the host (itunes:author) the author, and the description the summary.
+func entryFromItem(feed rssFeed, it rssItem, citeURL string) (schema.Entry, error) {
+	ch := feed.Channel
+	var e schema.Entry
+	e.Type = "podcast"
+	e.APA7.Title = text(it.Title)
+	e.APA7.ContainerTitle = text(ch.Title)
+	e.APA7.Publisher = text(stringsx.FirstNonEmpty(ch.Owner, ch.Author))
+	if e.APA7.Title == "" {
+		return schema.Entry{}, fmt.Errorf("podcast feed: episode has no title")
+	}
+	if host := text(stringsx.FirstNonEmpty(it.Author, ch.Author)); host != "" {
+		e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: host})
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(it.Episode)); err == nil && n > 0 {
+		e.APA7.Issue = strconv.Itoa(n)
+	}
+	if t, ok := pubDate(it.PubDate); ok {
+		y := t.Year()
+		e.APA7.Year = &y
+		e.APA7.Date = t.Format("2006-01-02")
+	}
+	e.APA7.URL = stringsx.FirstNonEmpty(citeURL, it.Link, ch.Link, it.Enclosure.URL)
+	if e.APA7.URL != "" {
+		e.APA7.Accessed = dates.NowISO()
+	}
+	e.Annotation.Summary = text(stringsx.FirstNonEmpty(it.Description, it.Summary))
+	if e.Annotation.Summary == "" {
+		e.Annotation.Summary = fmt.Sprintf("Podcast episode: %s.", e.APA7.Title)
+		if e.APA7.ContainerTitle != "" {
+			e.Annotation.Summary = fmt.Sprintf("Podcast episode of %s: %s.", e.APA7.ContainerTitle, e.APA7.Title)
+		}
+	}
+	e.Annotation.Keywords = []string{"podcast"}
+	e.ID = schema.NewID()
+	sanitize.CleanEntry(&e)
+	if err := e.Validate(); err != nil {
+		return schema.Entry{}, err
+	}
+	return e, nil
+}
+
+// pubDate parses an RSS pubDate (RFC 1123 with a named or numeric zone; feeds often
+// drop the weekday or zero-padding).
+func pubDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", "2 Jan 2006 15:04:05 -0700", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// text strips markup (show notes are often HTML) and collapses whitespace.
+func text(s string) string {
+	s = html.UnescapeString(reTag.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// lookupFeed resolves an Apple Podcasts show id to its RSS feed via the iTunes
+// Lookup API.
+func lookupFeed(ctx context.Context, showID string) (string, error) {
+	res, err := itunesLookup(ctx, url.Values{"id": {showID}, "entity": {"podcast"}})
+	if err != nil {
+		return "", err
+	}
+	for _, r := range res {
+		if f := strings.TrimSpace(r.FeedURL); f != "" {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("itunes lookup: no feed for podcast id %s", showID)
+}
+
+// lookupEpisodeTitle finds the title of Apple episode episodeID among the show's
+// recent episodes, so it can be matched in the feed.
+func lookupEpisodeTitle(ctx context.Context, showID, episodeID string) (string, error) {
+	res, err := itunesLookup(ctx, url.Values{"id": {showID}, "entity": {"podcastEpisode"}, "limit": {"200"}})
+	if err != nil {
+		return "", err
+	}
+	for _, r := range res {
+		if strconv.FormatInt(r.TrackID, 10) == episodeID && strings.TrimSpace(r.TrackName) != "" {
+			return r.TrackName, nil
+		}
+	}
+	return "", fmt.Errorf("itunes lookup: episode %s not found for podcast id %s", episodeID, showID)
+}
+
+type itunesResult struct {
+	TrackID   int64  `json:"trackId"`
+	TrackName string `json:"trackName"`
+	FeedURL   string `json:"feedUrl"`
+}
+
+func itunesLookup(ctx context.Context, q url.Values) ([]itunesResult, error) {
+	body, err := get(ctx, "https://itunes.apple.com/lookup?"+q.Encode(), "itunes lookup")
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Results []itunesResult `json:"results"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("itunes lookup: %w", err)
+	}
+	return out.Results, nil
+}
+
+// get fetches u and returns the body; label prefixes HTTP errors.
+func get(ctx context.Context, u, label string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpx.SetUA(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%s: http %d: %s", label, resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,114 @@
+package podcast
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const feedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Talking Systems</title>
+    <link>https://talking.example.com</link>
+    <itunes:author>Jane Host</itunes:author>
+    <itunes:owner><itunes:name>Example Network</itunes:name></itunes:owner>
+    <item>
+      <title>Logging at Scale</title>
+      <link>https://talking.example.com/42</link>
+      <guid>tag:talking.example.com,2023:42</guid>
+      <pubDate>Tue, 7 Mar 2023 09:00:00 -0500</pubDate>
+      <itunes:episode>42</itunes:episode>
+      <description><![CDATA[<p>We discuss <b>syslog</b> &amp; structured logs.</p>]]></description>
+      <enclosure url="https://cdn.example.com/42.mp3" type="audio/mpeg" length="1"/>
+    </item>
+    <item>
+      <title>Pilot</title>
+      <pubDate>Mon, 02 Jan 2023 09:00:00 GMT</pubDate>
+      <itunes:summary>Where it all starts.</itunes:summary>
+      <enclosure url="https://cdn.example.com/1.mp3" type="audio/mpeg" length="1"/>
+    </item>
+  </channel>
+</rss>`
+
+type fakeDoer struct {
+	handler func(req *http.Request) (int, string)
+}
+
+func (f fakeDoer) Do(req *http.Request) (*http.Response, error) {
+	code, body := f.handler(req)
+	return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+}
+
+func feedOnly(req *http.Request) (int, string) {
+	if req.URL.Host == "feeds.example.com" {
+		return 200, feedXML
+	}
+	return 404, "not found"
+}
+
+func TestFetchEpisode_RSSItem(t *testing.T) {
+	SetHTTPClient(fakeDoer{handler: feedOnly})
+	t.Cleanup(func() { SetHTTPClient(&http.Client{}) })
+
+	e, err := FetchEpisode(context.Background(), "https://feeds.example.com/talking.rss", "")
+	if err != nil {
+		t.Fatalf("FetchEpisode: %v", err)
+	}
+	a := e.APA7
+	if e.Type != "podcast" || a.Title != "Logging at Scale" || a.ContainerTitle != "Talking Systems" || a.Publisher != "Example Network" {
+		t.Fatalf("entry: %+v", a)
+	}
+	if len(a.Authors) != 1 || a.Authors[0].Family != "Jane Host" {
+		t.Fatalf("authors: %+v", a.Authors)
+	}
+	if a.Date != "2023-03-07" || a.Year == nil || *a.Year != 2023 || a.Issue != "42" {
+		t.Fatalf("date/episode: %q %v %q", a.Date, a.Year, a.Issue)
+	}
+	if a.URL != "https://talking.example.com/42" || a.Accessed == "" {
+		t.Fatalf("url: %q %q", a.URL, a.Accessed)
+	}
+	if e.Annotation.Summary != "We discuss syslog & structured logs." || e.Annotation.Keywords[0] != "podcast" {
+		t.Fatalf("annotation: %+v", e.Annotation)
+	}
+
+	e, err = FetchEpisode(context.Background(), "https://feeds.example.com/talking.rss", "pilot")
+	if err != nil || e.APA7.Title != "Pilot" || e.Annotation.Summary != "Where it all starts." || e.APA7.URL != "https://talking.example.com" {
+		t.Fatalf("--episode pick: %+v %v", e, err)
+	}
+	if _, err := FetchEpisode(context.Background(), "https://feeds.example.com/talking.rss", "missing"); err == nil || !strings.Contains(err.Error(), `no episode matching "missing"`) {
+		t.Fatalf("missing episode: %v", err)
+	}
+}
+
+func TestFetchEpisode_ApplePodcastsURL(t *testing.T) {
+	var lookups []string
+	SetHTTPClient(fakeDoer{handler: func(req *http.Request) (int, string) {
+		if req.URL.Host == "itunes.apple.com" && req.URL.Path == "/lookup" {
+			lookups = append(lookups, req.URL.Query().Get("entity"))
+			if req.URL.Query().Get("entity") == "podcastEpisode" {
+				return 200, `{"results":[{"wrapperType":"track","kind":"podcast","trackId":1234,"feedUrl":"https://feeds.example.com/talking.rss"},{"wrapperType":"podcastEpisode","trackId":1000600000001,"trackName":"Pilot"}]}`
+			}
+			return 200, `{"resultCount":1,"results":[{"trackId":1234,"feedUrl":"https://feeds.example.com/talking.rss"}]}`
+		}
+		return feedOnly(req)
+	}})
+	t.Cleanup(func() { SetHTTPClient(&http.Client{}) })
+
+	page := "https://podcasts.apple.com/us/podcast/talking-systems/id1234?i=1000600000001"
+	e, err := FetchEpisode(context.Background(), page, "")
+	if err != nil {
+		t.Fatalf("FetchEpisode apple: %v", err)
+	}
+	if e.APA7.Title != "Pilot" || e.APA7.ContainerTitle != "Talking Systems" || e.APA7.URL != page {
+		t.Fatalf("apple entry: %+v", e.APA7)
+	}
+	if strings.Join(lookups, ",") != "podcast,podcastEpisode" {
+		t.Fatalf("lookups: %v", lookups)
+	}
+	if _, err := FetchEpisode(context.Background(), "https://podcasts.apple.com/us/podcast/talking-systems", ""); err == nil {
+		t.Fatalf("apple url without an id should fail")
+	}
+}
@@ -0,0 +1,195 @@
+// Package pubmed looks up biomedical articles by PMID through NCBI E-utilities.
+package pubmed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/httpx"
+	"bibliography/src/internal/sanitize"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/stringsx"
+)
+
+var client httpx.Doer = httpx.ProviderClient()
+
+// SetHTTPClient allows tests to inject a fake HTTP client.
+func SetHTTPClient(c httpx.Doer) { client = c }
+
+// summary is the subset of an ESummary (retmode=json) document used for citations.
+type summary struct {
+	UID             string `json:"uid"`
+	Title           string `json:"title"`
+	Source          string `json:"source"`
+	FullJournalName string `json:"fulljournalname"`
+	SortPubDate     string `json:"sortpubdate"`
+	PubDate         string `json:"pubdate"`
+	Volume          string `json:"volume"`
+	Issue           string `json:"issue"`
+	Pages           string `json:"pages"`
+	ISSN            string `json:"issn"`
+	Authors         []struct {
+		Name     string `json:"name"`
+		AuthType string `json:"authtype"`
+	} `json:"authors"`
+	ArticleIDs []struct {
+		IDType string `json:"idtype"`
+		Value  string `json:"value"`
+	} `json:"articleids"`
+	Error string `json:"error"`
+}
+
+// FetchByPMID queries ESummary for a PubMed id and maps it into an Entry of type "article".
+func FetchByPMID(ctx context.Context, pmid string) (schema.Entry, error) {
+	pmid = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(pmid)), "PMID:")
+	pmid = strings.TrimSpace(pmid)
+	if _, err := strconv.Atoi(pmid); err != nil || pmid == "" {
+		return schema.Entry{}, fmt.Errorf("invalid PMID: %q", pmid)
+	}
+	q := url.Values{"db": {"pubmed"}, "id": {pmid}, "retmode": {"json"}}
+	u := "https://eutils.ncbi.nlm.nih.gov/entrez/eutils/esummary.fcgi?" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	httpx.SetUA(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return schema.Entry{}, fmt.Errorf("pubmed: http %d: %s", resp.StatusCode, string(b))
+	}
+	var body struct {
+		Result map[string]json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return schema.Entry{}, err
+	}
+	raw, ok := body.Result[pmid]
+	if !ok {
+		return schema.Entry{}, fmt.Errorf("pubmed: no record for PMID %s", pmid)
+	}
+	var s summary
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return schema.Entry{}, err
+	}
+	if s.Error != "" {
+		return schema.Entry{}, fmt.Errorf("pubmed: PMID %s: %s", pmid, s.Error)
+	}
+	e := mapSummary(s, pmid)
+	sanitize.CleanEntry(&e)
+	if err := e.Validate(); err != nil {
+		return schema.Entry{}, err
+	}
+	return e, nil
+}
+
+// mapSummary converts an ESummary record into an Entry.
+func mapSummary(s summary, pmid string) schema.Entry {
+	var e schema.Entry
+	e.Type = "article"
+	// PubMed titles end with a period that APA adds back itself.
+	e.APA7.Title = strings.TrimSuffix(strings.TrimSpace(s.Title), ".")
+	e.APA7.Journal = stringsx.FirstNonEmpty(s.FullJournalName, s.Source)
+	e.APA7.ContainerTitle = e.APA7.Journal
+	e.APA7.Volume = strings.TrimSpace(s.Volume)
+	e.APA7.Issue = strings.TrimSpace(s.Issue)
+	e.APA7.Pages = expandPages(s.Pages)
+	e.APA7.ISSN = strings.TrimSpace(s.ISSN)
+	if y, date := pubDate(s.SortPubDate); y > 0 {
+		e.APA7.Year = &y
+		e.APA7.Date = date
+	}
+	for _, a := range s.Authors {
+		if a.AuthType != "" && !strings.EqualFold(a.AuthType, "Author") {
+			continue
+		}
+		if fam, giv := splitMedlineName(a.Name); fam != "" {
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: giv})
+		}
+	}
+	for _, id := range s.ArticleIDs {
+		if strings.EqualFold(id.IDType, "doi") {
+			e.APA7.DOI = strings.TrimSpace(id.Value)
+		}
+	}
+	e.APA7.PMID = pmid
+	e.APA7.URL = "https://pubmed.ncbi.nlm.nih.gov/" + pmid + "/"
+	e.APA7.Accessed = dates.NowISO()
+	if e.APA7.Journal != "" {
+		e.Annotation.Summary = fmt.Sprintf("Bibliographic record for %s in %s via PubMed.", e.APA7.Title, e.APA7.Journal)
+	} else {
+		e.Annotation.Summary = fmt.Sprintf("Bibliographic record for %s via PubMed.", e.APA7.Title)
+	}
+	e.Annotation.Keywords = []string{"article"}
+	e.ID = schema.NewID()
+	return e
+}
+
+// splitMedlineName splits a MEDLINE author name ("Smith JA") into the family name
+// and dotted initials ("J. A."). Names without trailing initials are kept whole.
+func splitMedlineName(name string) (family, given string) {
+	parts := strings.Fields(name)
+	if len(parts) == 0 {
+		return "", ""
+	}
+	last := parts[len(parts)-1]
+	if len(parts) == 1 || !isInitials(last) {
+		return strings.Join(parts, " "), ""
+	}
+	var in []string
+	for _, r := range last {
+		in = append(in, string(r)+".")
+	}
+	return strings.Join(parts[:len(parts)-1], " "), strings.Join(in, " ")
+}
+
+func isInitials(s string) bool {
+	if len(s) > 3 {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// expandPages restores MEDLINE-abbreviated end pages ("100-10" -> "100-110").
+func expandPages(p string) string {
+	p = strings.TrimSpace(p)
+	first, last, ok := strings.Cut(p, "-")
+	if !ok {
+		return p
+	}
+	if _, err := strconv.Atoi(first); err != nil {
+		return p
+	}
+	if _, err := strconv.Atoi(last); err != nil || len(last) >= len(first) {
+		return p
+	}
+	return first + "-" + first[:len(first)-len(last)] + last
+}
+
+// pubDate parses sortpubdate ("2020/01/15 00:00") into a year and ISO date.
+func pubDate(s string) (int, string) {
+	t, err := time.Parse("2006/01/02 15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, ""
+	}
+	return t.Year(), t.Format("2006-01-02")
+}
@@ -0,0 +1,85 @@
+package pubmed
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testHTTP struct {
+	status int
+	body   string
+	url    *string
+}
+
+func (t testHTTP) Do(req *http.Request) (*http.Response, error) {
+	if t.url != nil {
+		*t.url = req.URL.String()
+	}
+	return &http.Response{StatusCode: t.status, Body: io.NopCloser(strings.NewReader(t.body)), Header: make(http.Header)}, nil
+}
+
+const esummary = `{"header":{"type":"esummary","version":"0.3"},"result":{"uids":["31452104"],"31452104":{
+  "uid":"31452104",
+  "pubdate":"2019 Sep",
+  "source":"Nat Med",
+  "authors":[{"name":"Topol EJ","authtype":"Author"},{"name":"van der Berg A","authtype":"Author"},{"name":"Example Consortium","authtype":"CollectiveName"}],
+  "title":"High-performance medicine: the convergence of human and artificial intelligence.",
+  "volume":"25",
+  "issue":"1",
+  "pages":"44-56",
+  "issn":"1078-8956",
+  "fulljournalname":"Nature medicine",
+  "articleids":[{"idtype":"pubmed","value":"31452104"},{"idtype":"doi","value":"10.1038/s41591-018-0300-7"}],
+  "sortpubdate":"2019/01/07 00:00"
+}}}`
+
+func TestFetchByPMID_MapsJournalAndPages(t *testing.T) {
+	var got string
+	SetHTTPClient(testHTTP{status: 200, body: esummary, url: &got})
+	defer SetHTTPClient(&http.Client{})
+
+	e, err := FetchByPMID(context.Background(), "PMID: 31452104")
+	if err != nil {
+		t.Fatalf("FetchByPMID: %v", err)
+	}
+	if !strings.Contains(got, "esummary.fcgi?db=pubmed&id=31452104&retmode=json") {
+		t.Fatalf("requested %q", got)
+	}
+	if e.APA7.Journal != "Nature medicine" || e.APA7.Pages != "44-56" {
+		t.Fatalf("journal/pages: %q %q", e.APA7.Journal, e.APA7.Pages)
+	}
+	if e.APA7.Title != "High-performance medicine: the convergence of human and artificial intelligence" {
+		t.Fatalf("title: %q", e.APA7.Title)
+	}
+	if e.APA7.Volume != "25" || e.APA7.Issue != "1" || e.APA7.Year == nil || *e.APA7.Year != 2019 || e.APA7.Date != "2019-01-07" {
+		t.Fatalf("volume/issue/date: %+v", e.APA7)
+	}
+	if e.APA7.DOI != "10.1038/s41591-018-0300-7" || e.APA7.PMID != "31452104" || e.APA7.URL != "https://pubmed.ncbi.nlm.nih.gov/31452104/" {
+		t.Fatalf("ids/url: %+v", e.APA7)
+	}
+	if len(e.APA7.Authors) != 2 || e.APA7.Authors[0].Family != "Topol" || e.APA7.Authors[0].Given != "E. J." || e.APA7.Authors[1].Family != "van der Berg" {
+		t.Fatalf("authors: %+v", e.APA7.Authors)
+	}
+}
+
+func TestFetchByPMID_Errors(t *testing.T) {
+	if _, err := FetchByPMID(context.Background(), "abc"); err == nil {
+		t.Fatal("expected invalid PMID error")
+	}
+	SetHTTPClient(testHTTP{status: 200, body: `{"result":{"uids":["1"],"1":{"uid":"1","error":"cannot get document summary"}}}`})
+	defer SetHTTPClient(&http.Client{})
+	if _, err := FetchByPMID(context.Background(), "1"); err == nil || !strings.Contains(err.Error(), "cannot get document summary") {
+		t.Fatalf("expected summary error, got %v", err)
+	}
+}
+
+func TestExpandPages(t *testing.T) {
+	for in, want := range map[string]string{"100-10": "100-110", "1234-56": "1234-1256", "44-56": "44-56", "e123": "e123", "S1-9": "S1-9"} {
+		if got := expandPages(in); got != want {
+			t.Errorf("expandPages(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,29 +2,32 @@ package rfc
 
 import (
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
+	"bibliography/src/internal/dates"
 	"bibliography/src/internal/httpx"
 	"bibliography/src/internal/names"
 	"bibliography/src/internal/sanitize"
 	"bibliography/src/internal/schema"
 )
 
-var client httpx.Doer = &http.Client{Timeout: 10 * time.Second}
+var client httpx.Doer = httpx.ProviderClient()
 
 // SetHTTPClient swaps the http client for tests.
 func SetHTTPClient(c httpx.Doer) { client = c }
 
 // Minimal subset of RFC Editor XML structures we care about
 type rfcXML struct {
-	XMLName xml.Name `xml:"rfc"`
-	Front   frontXML `xml:"front"`
+	XMLName   xml.Name `xml:"rfc"`
+	Obsoletes string   `xml:"obsoletes,attr"`
+	Updates   string   `xml:"updates,attr"`
+	Front     frontXML `xml:"front"`
 }
 
 type frontXML struct {
@@ -62,8 +65,21 @@ type abstractXML struct {
 }
 
 // FetchRFC fetches an RFC HTML page and maps it into a schema.Entry with type "rfc".
-// spec may be "rfc5424", "RFC5424", or just "5424".
+// spec may be "rfc5424", "RFC5424", or just "5424". Obsoletes/updates relationships
+// come from the RFC Editor's JSON metadata when available, else from the XML.
 func FetchRFC(ctx context.Context, spec string) (schema.Entry, error) {
+	e, err := fetchRFC(ctx, spec)
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	if rel, err := fetchRelations(ctx, normalizeRFCNumber(spec)); err == nil && len(rel.List()) > 0 {
+		e.APA7.Relations = rel
+	}
+	return e, nil
+}
+
+// fetchRFC tries the datatracker BibTeX, then the RFC Editor XML, then datatracker HTML.
+func fetchRFC(ctx context.Context, spec string) (schema.Entry, error) {
 	num := normalizeRFCNumber(spec)
 	if num == "" {
 		return schema.Entry{}, fmt.Errorf("invalid RFC spec: %s", spec)
@@ -94,6 +110,13 @@ func FetchRFC(ctx context.Context, spec string) (schema.Entry, error) {
 	}
 	var doc rfcXML
 	dec := xml.NewDecoder(io.LimitReader(resp.Body, 4<<20))
+	// Older RFC sources declare US-ASCII, a subset of UTF-8.
+	dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(label, "us-ascii") {
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset: %s", label)
+	}
 	if err := dec.Decode(&doc); err != nil {
 		// Fallback to datatracker HTML if xml parse fails
 		return fetchRFCFromDatatracker(ctx, num)
@@ -160,7 +183,7 @@ func FetchRFC(ctx context.Context, spec string) (schema.Entry, error) {
 	e.APA7.Publisher = "Internet Engineering Task Force"
 	e.APA7.URL = fmt.Sprintf("https://www.rfc-editor.org/rfc/rfc%s.html", num)
 	e.APA7.BibTeXURL = fmt.Sprintf("https://datatracker.ietf.org/doc/rfc%s/bibtex/", num)
-	e.APA7.Accessed = time.Now().UTC().Format("2006-01-02")
+	e.APA7.Accessed = dates.NowISO()
 	if yearPtr != nil {
 		e.APA7.Year = yearPtr
 	}
@@ -171,6 +194,9 @@ func FetchRFC(ctx context.Context, spec string) (schema.Entry, error) {
 		e.APA7.DOI = doi
 	}
 	e.APA7.Authors = authors
+	if obs, upd := rfcList(doc.Obsoletes), rfcList(doc.Updates); len(obs)+len(upd) > 0 {
+		e.APA7.Relations = &schema.Relations{Obsoletes: obs, Updates: upd}
+	}
 	if abs := strings.TrimSpace(strings.Join(doc.Front.Abstract.Paras, "\n\n")); abs != "" {
 		e.Annotation.Summary = sanitizeAbstract(abs)
 	} else {
@@ -184,6 +210,56 @@ func FetchRFC(ctx context.Context, spec string) (schema.Entry, error) {
 	return e, nil
 }
 
+// rfcList turns an RFC number list ("3164, 5424" or "RFC3164 RFC5424") into
+// labels like "RFC 3164".
+func rfcList(s string) []string {
+	var out []string
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if n := normalizeRFCNumber(f); n != "" {
+			out = append(out, "RFC "+strings.TrimLeft(n, "0"))
+		}
+	}
+	return out
+}
+
+// rfcMetaJSON is the subset of the RFC Editor's per-document JSON metadata used here.
+type rfcMetaJSON struct {
+	Obsoletes   []string `json:"obsoletes"`
+	ObsoletedBy []string `json:"obsoleted_by"`
+	Updates     []string `json:"updates"`
+	UpdatedBy   []string `json:"updated_by"`
+}
+
+// fetchRelations loads obsoletes/updates (in both directions) from
+// https://www.rfc-editor.org/rfc/rfc<num>.json.
+func fetchRelations(ctx context.Context, num string) (*schema.Relations, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://www.rfc-editor.org/rfc/rfc%s.json", num), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	httpx.SetUA(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rfc json: http %d", resp.StatusCode)
+	}
+	var m rfcMetaJSON
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&m); err != nil {
+		return nil, err
+	}
+	join := func(ids []string) []string { return rfcList(strings.Join(ids, ",")) }
+	return &schema.Relations{
+		Obsoletes:   join(m.Obsoletes),
+		Updates:     join(m.Updates),
+		ObsoletedBy: join(m.ObsoletedBy),
+		UpdatedBy:   join(m.UpdatedBy),
+	}, nil
+}
+
 // normalizeRFCNumber extracts the numeric RFC identifier from strings like "rfc5424".
 func normalizeRFCNumber(s string) string {
 	s = strings.TrimSpace(strings.ToLower(s))
@@ -328,7 +404,7 @@ func fetchRFCFromDatatracker(ctx context.Context, num string) (schema.Entry, err
 	e.APA7.ContainerTitle = "RFC " + num
 	e.APA7.Publisher = "Internet Engineering Task Force"
 	e.APA7.URL = url
-	e.APA7.Accessed = time.Now().UTC().Format("2006-01-02")
+	e.APA7.Accessed = dates.NowISO()
 	if yearPtr != nil {
 		e.APA7.Year = yearPtr
 	}
@@ -397,7 +473,7 @@ func fetchRFCFromBibtex(ctx context.Context, num string) (schema.Entry, error) {
 		e.APA7.URL = fmt.Sprintf("https://www.rfc-editor.org/rfc/rfc%s.html", num)
 	}
 	e.APA7.BibTeXURL = fmt.Sprintf("https://datatracker.ietf.org/doc/rfc%s/bibtex/", num)
-	e.APA7.Accessed = time.Now().UTC().Format("2006-01-02")
+	e.APA7.Accessed = dates.NowISO()
 	if y := toInt(yearVal); y > 0 {
 		y2 := y
 		e.APA7.Year = &y2
@@ -4,16 +4,26 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/schema"
 )
 
 type testHTTPDoer struct {
 	status int
 	body   string
+	ua     *string
 }
 
 func (t testHTTPDoer) Do(req *http.Request) (*http.Response, error) {
+	if t.ua != nil {
+		*t.ua = req.Header.Get("User-Agent")
+	}
 	return &http.Response{StatusCode: t.status, Body: io.NopCloser(strings.NewReader(t.body)), Header: make(http.Header)}, nil
 }
 
@@ -29,6 +39,8 @@ func TestFetchRFC_ParsesTitleDateAuthorsDOI(t *testing.T) {
     </front></rfc>`
 	SetHTTPClient(testHTTPDoer{status: 200, body: xml})
 	defer SetHTTPClient(&http.Client{})
+	dates.SetClock(func() time.Time { return time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC) })
+	defer dates.SetClock(nil)
 
 	e, err := FetchRFC(context.Background(), "rfc5424")
 	if err != nil {
@@ -55,6 +67,9 @@ func TestFetchRFC_ParsesTitleDateAuthorsDOI(t *testing.T) {
 	if e.APA7.URL == "" {
 		t.Fatalf("url missing")
 	}
+	if e.APA7.Accessed != "2024-05-06" {
+		t.Fatalf("accessed should follow pinned clock: %s", e.APA7.Accessed)
+	}
 	if len(e.APA7.Authors) == 0 || e.APA7.Authors[0].Family != "Gerhards" {
 		t.Fatalf("authors not parsed correctly: %+v", e.APA7.Authors)
 	}
@@ -66,6 +81,22 @@ func TestFetchRFC_ParsesTitleDateAuthorsDOI(t *testing.T) {
 	}
 }
 
+func TestFetchRFC_SendsConfiguredUserAgent(t *testing.T) {
+	t.Setenv("BIB_USER_AGENT", "bib-test/1.0")
+	xml := `<?xml version="1.0"?><rfc><front><title>The Syslog Protocol</title>
+        <author fullname="Rainer Gerhards"><name><given>Rainer</given><surname>Gerhards</surname></name></author>
+        <date month="Mar" year="2009"/></front></rfc>`
+	var ua string
+	SetHTTPClient(testHTTPDoer{status: 200, body: xml, ua: &ua})
+	defer SetHTTPClient(&http.Client{})
+	if _, err := FetchRFC(context.Background(), "rfc5424"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if ua != "bib-test/1.0" {
+		t.Fatalf("user agent %q", ua)
+	}
+}
+
 func TestFetchRFC_XMLPath(t *testing.T) {
 	// Minimal RFC Editor XML structure
 	xml := `<?xml version="1.0"?><rfc><front>
@@ -202,3 +233,41 @@ func (r routeHTTP) Do(req *http.Request) (*http.Response, error) {
 	}
 	return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("not found")), Header: make(http.Header)}, nil
 }
+
+func TestFetchRFC_RelationsFromXML(t *testing.T) {
+	xml, err := os.ReadFile("testdata/rfc5424.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := client
+	defer func() { client = old }()
+	client = routeHTTP{routes: []route{{"rfc5424.xml", 200, string(xml)}}}
+	e, err := FetchRFC(context.Background(), "rfc5424")
+	if err != nil {
+		t.Fatalf("FetchRFC: %v", err)
+	}
+	if e.APA7.Relations == nil || !reflect.DeepEqual(e.APA7.Relations.Obsoletes, []string{"RFC 3164"}) || len(e.APA7.Relations.Updates) != 0 {
+		t.Fatalf("relations: %+v", e.APA7.Relations)
+	}
+}
+
+func TestFetchRFC_RelationsFromEditorJSON(t *testing.T) {
+	xml, err := os.ReadFile("testdata/rfc5424.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := client
+	defer func() { client = old }()
+	client = routeHTTP{routes: []route{
+		{"rfc5424.xml", 200, string(xml)},
+		{"rfc5424.json", 200, `{"doc_id":"RFC5424","obsoletes":["RFC3164"],"obsoleted_by":[],"updates":[],"updated_by":["RFC8996"]}`},
+	}}
+	e, err := FetchRFC(context.Background(), "5424")
+	if err != nil {
+		t.Fatalf("FetchRFC: %v", err)
+	}
+	want := &schema.Relations{Obsoletes: []string{"RFC 3164"}, UpdatedBy: []string{"RFC 8996"}}
+	if !reflect.DeepEqual(e.APA7.Relations, want) {
+		t.Fatalf("relations: %+v", e.APA7.Relations)
+	}
+}
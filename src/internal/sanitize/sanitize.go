@@ -4,6 +4,9 @@ import (
 	"net/url"
 	"strings"
 
+	"bibliography/src/internal/lang"
+	"bibliography/src/internal/orcid"
+	"bibliography/src/internal/pages"
 	"bibliography/src/internal/schema"
 )
 
@@ -71,7 +74,7 @@ func CleanKeywords(keys []string) []string {
 	return out
 }
 
-// CleanAuthors sanitizes author names.
+// CleanAuthors sanitizes author names and normalizes their ORCID iDs.
 func CleanAuthors(authors schema.Authors) schema.Authors {
 	if len(authors) == 0 {
 		return nil
@@ -84,7 +87,12 @@ func CleanAuthors(authors schema.Authors) schema.Authors {
 		if fam == "" && giv == "" {
 			continue
 		}
-		out = append(out, schema.Author{Family: fam, Given: giv})
+		id, _ := orcid.Parse(a.ORCID) // an invalid iD is dropped rather than stored
+		role := strings.ToLower(strings.TrimSpace(a.Role))
+		if role == schema.RoleAuthor {
+			role = ""
+		}
+		out = append(out, schema.Author{Family: fam, Given: giv, ORCID: id, Role: role})
 	}
 	if len(out) == 0 {
 		return nil
@@ -108,15 +116,23 @@ func CleanEntry(e *schema.Entry) {
 	e.APA7.Journal = CleanString(e.APA7.Journal, 512)
 	e.APA7.Volume = CleanString(e.APA7.Volume, 64)
 	e.APA7.Issue = CleanString(e.APA7.Issue, 64)
-	e.APA7.Pages = CleanString(e.APA7.Pages, 64)
+	e.APA7.Pages = pages.Normalize(CleanString(e.APA7.Pages, 64))
 	e.APA7.DOI = CleanString(e.APA7.DOI, 128)
 	e.APA7.ISBN = CleanString(e.APA7.ISBN, 64)
+	e.APA7.PatentNumber = CleanString(e.APA7.PatentNumber, 64)
+	e.APA7.Medium = strings.ToLower(CleanString(e.APA7.Medium, 32))
+	e.APA7.Language = CleanString(e.APA7.Language, 32)
+	if code := lang.Normalize(e.APA7.Language); code != "" {
+		e.APA7.Language = code
+	}
 	e.APA7.URL = CleanURL(e.APA7.URL)
 	e.APA7.BibTeXURL = CleanURL(e.APA7.BibTeXURL)
+	e.APA7.ArchivedURL = CleanURL(e.APA7.ArchivedURL)
 	e.APA7.Accessed = CleanString(e.APA7.Accessed, 32)
 	e.APA7.Date = CleanString(e.APA7.Date, 32)
 	// Authors and annotations
 	e.APA7.Authors = CleanAuthors(e.APA7.Authors)
 	e.Annotation.Summary = CleanString(e.Annotation.Summary, 12000)
 	e.Annotation.Keywords = CleanKeywords(e.Annotation.Keywords)
+	ApplyUnicodeNormalization(e)
 }
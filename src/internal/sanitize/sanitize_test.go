@@ -56,7 +56,7 @@ func TestCleanAuthorsAndEntry(t *testing.T) {
 		t.Fatalf("CleanAuthors failed: %+v", ca)
 	}
 
-	e := schema.Entry{ID: " id ", Type: " article ", APA7: schema.APA7{Title: "  Title  ", URL: "https://e x", Accessed: " ", DOI: " 10.1/ABC ", Authors: authors}, Annotation: schema.Annotation{Summary: "  s  ", Keywords: []string{"X", "x"}}}
+	e := schema.Entry{ID: " id ", Type: " article ", APA7: schema.APA7{Title: "  Title  ", URL: "https://e x", Accessed: " ", Authors: authors, Identifiers: schema.Identifiers{DOI: " 10.1/ABC "}}, Annotation: schema.Annotation{Summary: "  s  ", Keywords: []string{"X", "x"}}}
 	CleanEntry(&e)
 	if e.ID == " id " || e.Type != "article" {
 		t.Fatalf("CleanEntry did not trim/type: %+v", e)
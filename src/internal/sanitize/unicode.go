@@ -0,0 +1,90 @@
+package sanitize
+
+import (
+	"strings"
+
+	"bibliography/src/internal/schema"
+)
+
+// normalizeUnicode enables NormalizeUnicode in CleanEntry (opt-in via add --normalize-unicode).
+var normalizeUnicode bool
+
+// SetNormalizeUnicode turns Unicode folding in CleanEntry on or off.
+func SetNormalizeUnicode(on bool) { normalizeUnicode = on }
+
+// unicodeFolds replaces typographic punctuation and ligatures with plain equivalents.
+var unicodeFolds = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"“", "\"", "”", "\"", "„", "\"", "‟", "\"", "″", "\"",
+	"‐", "-", "‑", "-", "‒", "-", "–", "-", "−", "-",
+	"—", "--", "―", "--",
+	"…", "...",
+	"\u00a0", " ", "\u202f", " ", "\u2009", " ",
+	"ﬀ", "ff", "ﬁ", "fi", "ﬂ", "fl", "ﬃ", "ffi", "ﬄ", "ffl", "ﬅ", "st", "ﬆ", "st",
+)
+
+// compositions maps a combining mark to the ASCII letters it composes with and the
+// precomposed results (same order). It covers Latin-1 Supplement through Latin
+// Extended-B, which is what provider and PDF text decomposes in practice.
+var compositions = map[rune]struct{ bases, composed string }{
+	0x0300: {"AEIOUaeiouNn", "ÀÈÌÒÙàèìòùǸǹ"},
+	0x0301: {"AEIOUYaeiouyCcLlNnRrSsZzGg", "ÁÉÍÓÚÝáéíóúýĆćĹĺŃńŔŕŚśŹźǴǵ"},
+	0x0302: {"AEIOUaeiouCcGgHhJjSsWwYy", "ÂÊÎÔÛâêîôûĈĉĜĝĤĥĴĵŜŝŴŵŶŷ"},
+	0x0303: {"ANOanoIiUu", "ÃÑÕãñõĨĩŨũ"},
+	0x0304: {"AaEeIiOoUuYy", "ĀāĒēĪīŌōŪūȲȳ"},
+	0x0306: {"AaEeGgIiOoUu", "ĂăĔĕĞğĬĭŎŏŬŭ"},
+	0x0307: {"CcEeGgIZzAaOo", "ĊċĖėĠġİŻżȦȧȮȯ"},
+	0x0308: {"AEIOUaeiouyY", "ÄËÏÖÜäëïöüÿŸ"},
+	0x030A: {"AaUu", "ÅåŮů"},
+	0x030B: {"OoUu", "ŐőŰű"},
+	0x030C: {"CcDdEeLlNnRrSsTtZzAaIiOoUuGgKkjHh", "ČčĎďĚěĽľŇňŘřŠšŤťŽžǍǎǏǐǑǒǓǔǦǧǨǩǰȞȟ"},
+	0x030F: {"AaEeIiOoRrUu", "ȀȁȄȅȈȉȌȍȐȑȔȕ"},
+	0x0311: {"AaEeIiOoRrUu", "ȂȃȆȇȊȋȎȏȒȓȖȗ"},
+	0x031B: {"OoUu", "ƠơƯư"},
+	0x0326: {"SsTt", "ȘșȚț"},
+	0x0327: {"CcGgKkLlNnRrSsTtEe", "ÇçĢģĶķĻļŅņŖŗŞşŢţȨȩ"},
+	0x0328: {"AaEeIiUuOo", "ĄąĘęĮįŲųǪǫ"},
+}
+
+// NormalizeUnicode folds smart quotes, dashes, ellipses, special spaces, and ligatures
+// to ASCII and composes decomposed Latin letters (NFC for the common cases).
+func NormalizeUnicode(s string) string {
+	if s == "" {
+		return s
+	}
+	s = unicodeFolds.Replace(s)
+	rs := []rune(s)
+	out := make([]rune, 0, len(rs))
+	for _, r := range rs {
+		if c, ok := compositions[r]; ok && len(out) > 0 {
+			if i := strings.IndexRune(c.bases, out[len(out)-1]); i >= 0 {
+				out[len(out)-1] = []rune(c.composed)[i]
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// ApplyUnicodeNormalization runs NormalizeUnicode over the entry's free-text fields when
+// enabled via SetNormalizeUnicode; otherwise it leaves the entry untouched.
+func ApplyUnicodeNormalization(e *schema.Entry) {
+	if !normalizeUnicode || e == nil {
+		return
+	}
+	for _, p := range []*string{
+		&e.APA7.Title, &e.APA7.ContainerTitle, &e.APA7.Journal, &e.APA7.Publisher,
+		&e.APA7.PublisherLocation, &e.APA7.Edition, &e.APA7.Pages, &e.Annotation.Summary,
+	} {
+		*p = NormalizeUnicode(*p)
+	}
+	for i := range e.APA7.Authors {
+		e.APA7.Authors[i].Family = NormalizeUnicode(e.APA7.Authors[i].Family)
+		e.APA7.Authors[i].Given = NormalizeUnicode(e.APA7.Authors[i].Given)
+	}
+	for i := range e.Annotation.Keywords {
+		e.Annotation.Keywords[i] = NormalizeUnicode(e.Annotation.Keywords[i])
+	}
+	e.Annotation.Keywords = CleanKeywords(e.Annotation.Keywords)
+}
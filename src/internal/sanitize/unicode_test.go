@@ -0,0 +1,45 @@
+package sanitize
+
+import (
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestNormalizeUnicode(t *testing.T) {
+	cases := map[string]string{
+		// PDF-extracted title with ligatures and an em dash
+		"Eﬃcient ﬁle systems—a survey": "Efficient file systems--a survey",
+		// web title with smart quotes, en dash range, ellipsis, and NBSP
+		"“Don’t panic”:\u00a01990–2000 …": "\"Don't panic\": 1990-2000 ...",
+		// decomposed accents (e + U+0301, n + U+0303, u + U+0308)
+		"Cafe\u0301 Espan\u0303ol Mu\u0308ller": "Caf\u00e9 Espa\u00f1ol M\u00fcller",
+		"plain ascii":                           "plain ascii",
+	}
+	for in, want := range cases {
+		if got := NormalizeUnicode(in); got != want {
+			t.Fatalf("NormalizeUnicode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCleanEntry_NormalizeUnicodeOptIn(t *testing.T) {
+	t.Cleanup(func() { SetNormalizeUnicode(false) })
+	mk := func() schema.Entry {
+		return schema.Entry{APA7: schema.APA7{Title: "The ﬁrst “word”", Authors: schema.Authors{{Family: "Mu\u0308ller", Given: "J."}}}, Annotation: schema.Annotation{Keywords: []string{"ﬁles", "files"}}}
+	}
+	e := mk()
+	CleanEntry(&e)
+	if e.APA7.Title != "The ﬁrst “word”" {
+		t.Fatalf("normalization should be opt-in, got %q", e.APA7.Title)
+	}
+	SetNormalizeUnicode(true)
+	e = mk()
+	CleanEntry(&e)
+	if e.APA7.Title != "The first \"word\"" || e.APA7.Authors[0].Family != "M\u00fcller" {
+		t.Fatalf("unexpected normalized entry: %+v", e.APA7)
+	}
+	if len(e.Annotation.Keywords) != 1 || e.Annotation.Keywords[0] != "files" {
+		t.Fatalf("keywords should fold and dedupe: %v", e.Annotation.Keywords)
+	}
+}
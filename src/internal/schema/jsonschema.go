@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect JSONSchema emits.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaRequired mirrors the required fields checked by Validate, per struct.
+var jsonSchemaRequired = map[reflect.Type][]string{
+	reflect.TypeOf(Entry{}):        {"id", "type", "apa7", "annotation"},
+	reflect.TypeOf(APA7{}):         {"title"},
+	reflect.TypeOf(Annotation{}):   {"summary", "keywords"},
+	reflect.TypeOf(Author{}):       {"family"},
+	reflect.TypeOf(Verification{}): {"by"},
+}
+
+// jsonSchemaRules adds the value constraints Validate enforces, keyed by struct and
+// JSON field name.
+func jsonSchemaRules(t reflect.Type, field string) map[string]any {
+	switch {
+	case t == reflect.TypeOf(Entry{}) && field == "id":
+		p := strings.ReplaceAll(reUUIDv4.String(), "0-9a-f", "0-9a-fA-F")
+		return map[string]any{"pattern": strings.ReplaceAll(p, "[89ab]", "[89abAB]")}
+	case t == reflect.TypeOf(Entry{}) && field == "type":
+		return map[string]any{"enum": Types}
+	case t == reflect.TypeOf(APA7{}) && field == "title":
+		return map[string]any{"minLength": 1}
+	case t == reflect.TypeOf(APA7{}) && field == "medium":
+		return map[string]any{"enum": append([]string{""}, Media...)}
+	case t == reflect.TypeOf(Author{}) && field == "role":
+		return map[string]any{"enum": append([]string{""}, AuthorRoles...)}
+	case t == reflect.TypeOf(Annotation{}) && field == "summary":
+		return map[string]any{"minLength": 1}
+	case t == reflect.TypeOf(Annotation{}) && field == "keywords":
+		return map[string]any{"minItems": 1}
+	case t == reflect.TypeOf(Verification{}) && field == "method":
+		return map[string]any{"enum": []string{VerifyManual, VerifyConsensus}}
+	}
+	return nil
+}
+
+// JSONSchema describes a serialized Entry as a JSON Schema document. It is generated
+// from the Go structs (field names and types from their json tags) and the rules of
+// Validate (required fields, allowed types, media, and roles, the id format, and
+// accessed being required with a url), so it stays in step with both.
+func JSONSchema() map[string]any {
+	defs := map[string]any{}
+	root := objectSchema(reflect.TypeOf(Entry{}), defs)
+	root["$schema"] = JSONSchemaDraft
+	root["title"] = "Bibliography entry"
+	root["$defs"] = defs
+	return root
+}
+
+// objectSchema builds the schema of struct t, registering nested structs in defs.
+func objectSchema(t reflect.Type, defs map[string]any) map[string]any {
+	props := map[string]any{}
+	addFields(t, t, props, defs)
+	s := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	if req := jsonSchemaRequired[t]; len(req) > 0 {
+		s["required"] = req
+	}
+	if t == reflect.TypeOf(APA7{}) {
+		s["dependentRequired"] = map[string]any{"url": []string{"accessed"}}
+	}
+	return s
+}
+
+// addFields adds the json-tagged fields of t to props; embedded structs (Identifiers)
+// are flattened into owner, as encoding/json does.
+func addFields(owner, t reflect.Type, props, defs map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			addFields(owner, f.Type, props, defs)
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		s := typeSchema(f.Type, defs)
+		if f.Type.Kind() == reflect.Slice && !strings.Contains(opts, "omitempty") && !slices.Contains(jsonSchemaRequired[owner], name) {
+			// An empty optional list without omitempty serializes as null.
+			s["type"] = []string{"array", "null"}
+		}
+		for k, v := range jsonSchemaRules(owner, name) {
+			s[k] = v
+		}
+		props[name] = s
+	}
+}
+
+// typeSchema maps a Go type to its schema; structs become $refs into defs.
+func typeSchema(t reflect.Type, defs map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem(), defs)
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return map[string]any{"type": "integer"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), defs)}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := defs[name]; !ok {
+			defs[name] = nil // reserve against recursion
+			defs[name] = objectSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PreviewYAML renders e in a human-friendly YAML-like format for previews (verify, add
+// --dry-run); it is not read back.
+func PreviewYAML(e Entry) string {
+	b := &strings.Builder{}
+	w := func(indent int, line string) {
+		b.WriteString(strings.Repeat(" ", indent))
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	q := func(s string) string {
+		s = strings.ReplaceAll(s, "\"", "\\\"")
+		return "\"" + s + "\""
+	}
+	w(0, "id: "+e.ID)
+	w(0, "type: "+e.Type)
+	w(0, "apa7:")
+	if e.APA7.Title != "" {
+		w(2, "title: "+q(e.APA7.Title))
+	}
+	if e.APA7.ContainerTitle != "" {
+		w(2, "container_title: "+q(e.APA7.ContainerTitle))
+	}
+	if e.APA7.Journal != "" {
+		w(2, "journal: "+q(e.APA7.Journal))
+	}
+	if e.APA7.Publisher != "" {
+		w(2, "publisher: "+q(e.APA7.Publisher))
+	}
+	if e.APA7.PublisherLocation != "" {
+		w(2, "publisher_location: "+q(e.APA7.PublisherLocation))
+	}
+	if e.APA7.Edition != "" {
+		w(2, "edition: "+q(e.APA7.Edition))
+	}
+	if e.APA7.Volume != "" {
+		w(2, "volume: "+q(e.APA7.Volume))
+	}
+	if e.APA7.Issue != "" {
+		w(2, "issue: "+q(e.APA7.Issue))
+	}
+	if e.APA7.Pages != "" {
+		w(2, "pages: "+q(e.APA7.Pages))
+	}
+	if e.APA7.Year != nil {
+		w(2, fmt.Sprintf("year: %d", *e.APA7.Year))
+	}
+	if e.APA7.Date != "" {
+		w(2, "date: "+q(e.APA7.Date))
+	}
+	if e.APA7.DOI != "" {
+		w(2, "doi: "+q(e.APA7.DOI))
+	}
+	if e.APA7.ISBN != "" {
+		w(2, "isbn: "+q(e.APA7.ISBN))
+	}
+	if e.APA7.PatentNumber != "" {
+		w(2, "patent_number: "+q(e.APA7.PatentNumber))
+	}
+	if e.APA7.Medium != "" {
+		w(2, "medium: "+q(e.APA7.Medium))
+	}
+	if e.APA7.URL != "" {
+		w(2, "url: "+q(e.APA7.URL))
+	}
+	if e.APA7.Accessed != "" {
+		w(2, "accessed: "+q(e.APA7.Accessed))
+	}
+	if rels := e.APA7.Relations.List(); len(rels) > 0 {
+		w(2, "relations:")
+		for _, rel := range rels {
+			docs := make([]string, len(rel.Docs))
+			for i, d := range rel.Docs {
+				docs[i] = q(d)
+			}
+			w(4, rel.Key+": ["+strings.Join(docs, ", ")+"]")
+		}
+	}
+	if len(e.APA7.Authors) > 0 {
+		w(2, "authors:")
+		for _, a := range e.APA7.Authors {
+			if strings.TrimSpace(a.Family) == "" && strings.TrimSpace(a.Given) == "" {
+				continue
+			}
+			w(4, "- family: "+q(a.Family))
+			if strings.TrimSpace(a.Given) != "" {
+				w(6, "given: "+q(a.Given))
+			}
+			if r := a.RoleOf(); r != RoleAuthor {
+				w(6, "role: "+r)
+			}
+		}
+	}
+	w(0, "annotation:")
+	if e.Annotation.Summary != "" {
+		w(2, "summary: "+q(e.Annotation.Summary))
+	}
+	if len(e.Annotation.Keywords) > 0 {
+		// Render keywords inline list
+		items := make([]string, 0, len(e.Annotation.Keywords))
+		for _, k := range e.Annotation.Keywords {
+			items = append(items, q(k))
+		}
+		w(2, "keywords: ["+strings.Join(items, ", ")+"]")
+	}
+	return b.String()
+}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
@@ -16,6 +17,22 @@ type Entry struct {
 	Type       string     `yaml:"type" json:"type"`
 	APA7       APA7       `yaml:"apa7" json:"apa7"`
 	Annotation Annotation `yaml:"annotation" json:"annotation"`
+	// Verification is set (read-only) from the library record for verified entries.
+	Verification *Verification `yaml:"verification,omitempty" json:"verification,omitempty"`
+}
+
+// Verification methods recorded with a verified entry.
+const (
+	VerifyManual    = "manual"    // a person checked the record (bib verify --id)
+	VerifyConsensus = "consensus" // independent providers agreed (bib verify --auto)
+)
+
+// Verification records who verified an entry, when, against which providers, and how.
+type Verification struct {
+	By        string   `yaml:"by" json:"by"`
+	At        string   `yaml:"at,omitempty" json:"at,omitempty"`
+	Providers []string `yaml:"providers,omitempty" json:"providers,omitempty"`
+	Method    string   `yaml:"method,omitempty" json:"method,omitempty"`
 }
 
 // APA7 holds bibliographic fields (subset as per spec).
@@ -32,16 +49,226 @@ type APA7 struct {
 	Volume            string  `yaml:"volume,omitempty" json:"volume,omitempty"`
 	Issue             string  `yaml:"issue,omitempty" json:"issue,omitempty"`
 	Pages             string  `yaml:"pages,omitempty" json:"pages,omitempty"`
-	DOI               string  `yaml:"doi,omitempty" json:"doi,omitempty"`
-	ISBN              string  `yaml:"isbn,omitempty" json:"isbn,omitempty"`
-	URL               string  `yaml:"url,omitempty" json:"url,omitempty"`
-	BibTeXURL         string  `yaml:"bibtex_url,omitempty" json:"bibtex_url,omitempty"`
-	Accessed          string  `yaml:"accessed,omitempty" json:"accessed,omitempty"`
+	PatentNumber      string  `yaml:"patent_number,omitempty" json:"patent_number,omitempty"`
+	Designation       string  `yaml:"designation,omitempty" json:"designation,omitempty"`
+	StandardsBody     string  `yaml:"standards_body,omitempty" json:"standards_body,omitempty"`
+	Medium            string  `yaml:"medium,omitempty" json:"medium,omitempty"`
+	// Language is the ISO 639-1 code of a work not in English ("de"); empty means English.
+	Language  string `yaml:"language,omitempty" json:"language,omitempty"`
+	URL       string `yaml:"url,omitempty" json:"url,omitempty"`
+	BibTeXURL string `yaml:"bibtex_url,omitempty" json:"bibtex_url,omitempty"`
+	Accessed  string `yaml:"accessed,omitempty" json:"accessed,omitempty"`
+	// ArchivedURL is a Wayback Machine snapshot of URL, kept against link rot.
+	ArchivedURL string `yaml:"archived_url,omitempty" json:"archived_url,omitempty"`
+	// AuthorCount is the full number of authors when Authors was truncated on add.
+	AuthorCount int `yaml:"author_count,omitempty" json:"author_count,omitempty"`
+	// Relations links a document to others in its series (RFC obsoletes/updates).
+	Relations *Relations `yaml:"relations,omitempty" json:"relations,omitempty"`
+	// Identifiers is embedded so e.APA7.DOI and e.APA7.ISBN keep working and the
+	// serialized shape stays flat.
+	Identifiers `yaml:",inline"`
+}
+
+// Identifiers groups the optional standard identifiers of a work so exporters and
+// indexes read them from one place.
+type Identifiers struct {
+	DOI     string `yaml:"doi,omitempty" json:"doi,omitempty"`
+	ISBN    string `yaml:"isbn,omitempty" json:"isbn,omitempty"`
+	ISSN    string `yaml:"issn,omitempty" json:"issn,omitempty"`
+	PMID    string `yaml:"pmid,omitempty" json:"pmid,omitempty"`
+	ArXiv   string `yaml:"arxiv,omitempty" json:"arxiv,omitempty"`
+	Bibcode string `yaml:"bibcode,omitempty" json:"bibcode,omitempty"`
+	ISRC    string `yaml:"isrc,omitempty" json:"isrc,omitempty"`
+}
+
+// Relations lists related documents by label, e.g. Obsoletes: ["RFC 3164"].
+type Relations struct {
+	Obsoletes   []string `yaml:"obsoletes,omitempty" json:"obsoletes,omitempty"`
+	Updates     []string `yaml:"updates,omitempty" json:"updates,omitempty"`
+	ObsoletedBy []string `yaml:"obsoleted_by,omitempty" json:"obsoleted_by,omitempty"`
+	UpdatedBy   []string `yaml:"updated_by,omitempty" json:"updated_by,omitempty"`
+}
+
+// Relation is one non-empty relation: its storage key, display label, and documents.
+type Relation struct {
+	Key   string
+	Label string
+	Docs  []string
+}
+
+// List returns the non-empty relations in a fixed order; nil-safe.
+func (r *Relations) List() []Relation {
+	if r == nil {
+		return nil
+	}
+	var out []Relation
+	for _, rel := range []Relation{
+		{"obsoletes", "Obsoletes", r.Obsoletes},
+		{"updates", "Updates", r.Updates},
+		{"obsoleted_by", "Obsoleted by", r.ObsoletedBy},
+		{"updated_by", "Updated by", r.UpdatedBy},
+	} {
+		if len(rel.Docs) > 0 {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+// Set stores docs under the relation key ("obsoletes", "updated_by", ...); unknown
+// keys are ignored.
+func (r *Relations) Set(key string, docs []string) {
+	switch key {
+	case "obsoletes":
+		r.Obsoletes = docs
+	case "updates":
+		r.Updates = docs
+	case "obsoleted_by":
+		r.ObsoletedBy = docs
+	case "updated_by":
+		r.UpdatedBy = docs
+	}
+}
+
+// RelationKeys lists the relation storage keys in List order.
+var RelationKeys = []string{"obsoletes", "updates", "obsoleted_by", "updated_by"}
+
+// Accession is a database-specific identifier, e.g. {"PubMed", "12345678"}.
+type Accession struct {
+	Database string
+	Number   string
+}
+
+// Accessions returns the non-empty database accession ids (PMID, arXiv, bibcode,
+// ISRC) in a fixed order. DOI, ISBN, and ISSN have dedicated fields in every format.
+func (ids Identifiers) Accessions() []Accession {
+	var out []Accession
+	for _, a := range []Accession{
+		{"PubMed", ids.PMID},
+		{"arXiv", ids.ArXiv},
+		{"ADS", ids.Bibcode},
+		{"ISRC", ids.ISRC},
+	} {
+		if a.Number = strings.TrimSpace(a.Number); a.Number != "" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// DefaultMaxAuthorsStored is the default cap on authors stored from provider metadata.
+const DefaultMaxAuthorsStored = 25
+
+// AuthorsTruncated reports whether Authors holds only the first AuthorCount authors.
+func (a APA7) AuthorsTruncated() bool { return a.AuthorCount > len(a.Authors) }
+
+// TruncateAuthors keeps the first max authors and records the original count in
+// AuthorCount. It reports whether anything was dropped; max <= 0 disables the cap.
+func TruncateAuthors(e *Entry, max int) bool {
+	if e == nil || max <= 0 || len(e.APA7.Authors) <= max {
+		return false
+	}
+	e.APA7.AuthorCount = len(e.APA7.Authors)
+	e.APA7.Authors = e.APA7.Authors[:max]
+	return true
 }
 
 type Author struct {
 	Family string `yaml:"family" json:"family"`
 	Given  string `yaml:"given,omitempty" json:"given,omitempty"`
+	ORCID  string `yaml:"orcid,omitempty" json:"orcid,omitempty"` // hyphenated iD, e.g. 0000-0002-1825-0097
+	Role   string `yaml:"role,omitempty" json:"role,omitempty"`   // one of AuthorRoles; empty means RoleAuthor
+}
+
+// Author roles. Editors of an edited volume stand in the author position; translators
+// and audiobook narrators are credited after the title.
+const (
+	RoleAuthor     = "author"
+	RoleEditor     = "editor"
+	RoleTranslator = "translator"
+	RoleNarrator   = "narrator"
+)
+
+// AuthorRoles lists the allowed Author.Role values.
+var AuthorRoles = []string{RoleAuthor, RoleEditor, RoleTranslator, RoleNarrator}
+
+// IsValidAuthorRole reports whether r is empty or one of AuthorRoles (case-insensitive).
+func IsValidAuthorRole(r string) bool {
+	r = strings.ToLower(strings.TrimSpace(r))
+	if r == "" {
+		return true
+	}
+	for _, v := range AuthorRoles {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleOf returns a's role, lowercased, with RoleAuthor for an empty role.
+func (a Author) RoleOf() string {
+	if r := strings.ToLower(strings.TrimSpace(a.Role)); r != "" {
+		return r
+	}
+	return RoleAuthor
+}
+
+// WithRole returns the authors whose RoleOf is role.
+func (as Authors) WithRole(role string) Authors {
+	var out Authors
+	for _, a := range as {
+		if a.RoleOf() == role {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// UnmarshalJSON decodes an APA7 record, folding the legacy "contributors" list (people
+// with a role such as "narrator", stored apart from the authors) into Authors.
+func (a *APA7) UnmarshalJSON(b []byte) error {
+	type plain APA7
+	var v struct {
+		plain
+		Contributors Authors `json:"contributors"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*a = APA7(v.plain)
+	a.Authors = append(a.Authors, v.Contributors...)
+	return nil
+}
+
+// Types lists the allowed entry types.
+var Types = []string{"website", "book", "movie", "video", "song", "article", "patent", "report", "dataset", "software", "rfc", "standard", "podcast"}
+
+// IsValidType reports whether t is one of Types (exact match).
+func IsValidType(t string) bool {
+	for _, v := range Types {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Media lists the allowed APA7.Medium values; an empty medium means "print".
+var Media = []string{"print", "ebook", "audiobook"}
+
+// IsValidMedium reports whether m is empty or one of Media (case-insensitive).
+func IsValidMedium(m string) bool {
+	m = strings.ToLower(strings.TrimSpace(m))
+	if m == "" {
+		return true
+	}
+	for _, v := range Media {
+		if m == v {
+			return true
+		}
+	}
+	return false
 }
 
 type Annotation struct {
@@ -65,9 +292,7 @@ func (e *Entry) Validate() error {
 	if !isUUIDv4(e.ID) {
 		return fmt.Errorf("id must be uuidv4 (36-char canonical), got %q", e.ID)
 	}
-	switch e.Type {
-	case "website", "book", "movie", "video", "song", "article", "patent", "report", "dataset", "software", "rfc":
-	default:
+	if !IsValidType(e.Type) {
 		return fmt.Errorf("invalid type: %s", e.Type)
 	}
 	if strings.TrimSpace(e.APA7.Title) == "" {
@@ -82,6 +307,14 @@ func (e *Entry) Validate() error {
 	if strings.TrimSpace(e.APA7.URL) != "" && strings.TrimSpace(e.APA7.Accessed) == "" {
 		return errors.New("apa7.accessed is required when apa7.url is present")
 	}
+	if !IsValidMedium(e.APA7.Medium) {
+		return fmt.Errorf("invalid apa7.medium: %s (want one of %s)", e.APA7.Medium, strings.Join(Media, ", "))
+	}
+	for _, a := range e.APA7.Authors {
+		if !IsValidAuthorRole(a.Role) {
+			return fmt.Errorf("invalid role for author %s: %s (want one of %s)", a.Family, a.Role, strings.Join(AuthorRoles, ", "))
+		}
+	}
 	return nil
 }
 
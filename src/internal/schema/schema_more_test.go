@@ -1,6 +1,10 @@
 package schema
 
-import "testing"
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
 
 func TestValidateFailuresAndSuccess(t *testing.T) {
 	// Missing ID should fail because not UUIDv4 (empty string)
@@ -39,6 +43,16 @@ func TestValidateFailuresAndSuccess(t *testing.T) {
 		t.Fatalf("expected error for missing accessed when url present")
 	}
 
+	// Medium outside the allowed set
+	e = Entry{ID: NewID(), Type: "book", APA7: APA7{Title: "X", Medium: "vinyl"}, Annotation: Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if err := e.Validate(); err == nil {
+		t.Fatalf("expected error for invalid medium")
+	}
+	e.APA7.Medium = "Audiobook"
+	if err := e.Validate(); err != nil {
+		t.Fatalf("unexpected medium validation error: %v", err)
+	}
+
 	// Success case
 	e = Entry{ID: NewID(), Type: "website", APA7: APA7{Title: "X", URL: "https://x", Accessed: "2025-01-01"}, Annotation: Annotation{Summary: "s", Keywords: []string{"k"}}}
 	if err := e.Validate(); err != nil {
@@ -59,3 +73,31 @@ func TestSlugifyAndNewID(t *testing.T) {
 		t.Fatalf("NewID not uuidv4: %q", id)
 	}
 }
+
+func TestTruncateAuthors(t *testing.T) {
+	e := Entry{APA7: APA7{Authors: Authors{{Family: "A"}, {Family: "B"}, {Family: "C"}}}}
+	if TruncateAuthors(&e, 0) || TruncateAuthors(&e, 3) || e.APA7.AuthorsTruncated() {
+		t.Fatalf("no truncation expected at or under the cap")
+	}
+	if !TruncateAuthors(&e, 2) || len(e.APA7.Authors) != 2 || e.APA7.AuthorCount != 3 || !e.APA7.AuthorsTruncated() {
+		t.Fatalf("unexpected truncation: %+v", e.APA7)
+	}
+}
+
+func TestAPA7UnmarshalFoldsLegacyContributors(t *testing.T) {
+	var a APA7
+	in := `{"title":"Listen","authors":[{"family":"Writer","given":"W."}],"contributors":[{"family":"Reader","given":"A.","role":"narrator"}],"doi":"10.1/x"}`
+	if err := json.Unmarshal([]byte(in), &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Title != "Listen" || a.DOI != "10.1/x" || len(a.Authors) != 2 {
+		t.Fatalf("unexpected decode: %+v", a)
+	}
+	if n := a.Authors.WithRole(RoleNarrator); len(n) != 1 || n[0].Family != "Reader" {
+		t.Fatalf("narrator not folded into authors: %+v", a.Authors)
+	}
+	out, _ := json.Marshal(a)
+	if strings.Contains(string(out), "contributors") {
+		t.Fatalf("contributors should not be written back: %s", out)
+	}
+}
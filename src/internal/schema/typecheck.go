@@ -0,0 +1,152 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks a type-specific finding: an error means the citation cannot be
+// rendered properly; a warning means it would be better with the field.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+// String returns "warning" or "error".
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Issue is one type-specific finding about an entry.
+type Issue struct {
+	Severity Severity
+	// Fields are the JSON names of the missing fields; any one would satisfy the rule.
+	Fields  []string
+	Message string
+}
+
+// typeRule requires at least one of fields (JSON names; "year" is also met by date).
+type typeRule struct {
+	severity Severity
+	fields   []string
+}
+
+func required(fields ...string) typeRule    { return typeRule{SeverityError, fields} }
+func recommended(fields ...string) typeRule { return typeRule{SeverityWarning, fields} }
+
+// typeRules lists the per-type requirements checked by CheckType, beyond Validate.
+var typeRules = map[string][]typeRule{
+	"book":     {required("publisher", "isbn"), recommended("year")},
+	"article":  {recommended("journal", "container_title"), recommended("year"), recommended("doi", "url")},
+	"website":  {required("url"), required("accessed")},
+	"movie":    {recommended("year"), recommended("publisher")},
+	"video":    {required("url"), recommended("year")},
+	"song":     {recommended("year"), recommended("container_title", "publisher")},
+	"patent":   {required("patent_number"), recommended("year")},
+	"report":   {recommended("publisher"), recommended("year")},
+	"dataset":  {recommended("publisher", "doi", "url"), recommended("year")},
+	"software": {recommended("url", "doi"), recommended("year")},
+	"rfc":      {recommended("url"), recommended("year")},
+	"standard": {required("designation"), recommended("standards_body", "publisher")},
+	"podcast":  {required("container_title"), recommended("url"), recommended("year")},
+}
+
+// strictRules replace typeRules under CheckTypeStrict where a type needs more than
+// its usual requirements: a standard must name its issuing body, not just a publisher.
+var strictRules = map[string][]typeRule{
+	"standard": {required("designation"), required("standards_body")},
+}
+
+// fieldLabels spells field names that are acronyms in messages.
+var fieldLabels = map[string]string{"isbn": "ISBN", "doi": "DOI", "url": "URL"}
+
+// CheckType applies the requirements of e's type (see typeRules) and returns the
+// unmet ones, errors first, with messages such as "book entry <id> missing publisher
+// and ISBN". Entries of unknown type get none; Validate reports those.
+func (e *Entry) CheckType() []Issue {
+	return e.checkRules(typeRules[e.Type])
+}
+
+// CheckTypeStrict is CheckType for strict mode: strictRules take the place of
+// typeRules for their types, and every unmet rule is an error.
+func (e *Entry) CheckTypeStrict() []Issue {
+	rules, ok := strictRules[e.Type]
+	if !ok {
+		rules = typeRules[e.Type]
+	}
+	issues := e.checkRules(rules)
+	for i := range issues {
+		issues[i].Severity = SeverityError
+	}
+	return issues
+}
+
+func (e *Entry) checkRules(rules []typeRule) []Issue {
+	var errs, warns []Issue
+	for _, r := range rules {
+		if r.satisfied(e.APA7) {
+			continue
+		}
+		labels := make([]string, len(r.fields))
+		for i, f := range r.fields {
+			labels[i] = strings.ReplaceAll(f, "_", " ")
+			if l, ok := fieldLabels[f]; ok {
+				labels[i] = l
+			}
+		}
+		is := Issue{Severity: r.severity, Fields: r.fields, Message: fmt.Sprintf("%s entry %s missing %s", e.Type, e.ID, strings.Join(labels, " and "))}
+		if r.severity == SeverityError {
+			errs = append(errs, is)
+		} else {
+			warns = append(warns, is)
+		}
+	}
+	return append(errs, warns...)
+}
+
+func (r typeRule) satisfied(a APA7) bool {
+	for _, f := range r.fields {
+		if apa7Field(a, f) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// apa7Field returns the trimmed value of the APA7 field with JSON name f.
+func apa7Field(a APA7, f string) string {
+	var v string
+	switch f {
+	case "year":
+		if a.Year != nil {
+			return fmt.Sprint(*a.Year)
+		}
+		v = a.Date
+	case "publisher":
+		v = a.Publisher
+	case "isbn":
+		v = a.ISBN
+	case "journal":
+		v = a.Journal
+	case "container_title":
+		v = a.ContainerTitle
+	case "doi":
+		v = a.DOI
+	case "url":
+		v = a.URL
+	case "accessed":
+		v = a.Accessed
+	case "patent_number":
+		v = a.PatentNumber
+	case "designation":
+		v = a.Designation
+	case "standards_body":
+		v = a.StandardsBody
+	}
+	return strings.TrimSpace(v)
+}
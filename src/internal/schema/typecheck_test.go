@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckTypeMessages(t *testing.T) {
+	e := Entry{ID: "id1", Type: "book", APA7: APA7{Title: "T"}}
+	got := e.CheckType()
+	if len(got) != 2 || got[0].Severity != SeverityError || got[0].Message != "book entry id1 missing publisher and ISBN" {
+		t.Fatalf("book issues: %+v", got)
+	}
+	if got[1].Severity != SeverityWarning || got[1].Message != "book entry id1 missing year" {
+		t.Fatalf("book warning: %+v", got[1])
+	}
+	if SeverityError.String() != "error" || SeverityWarning.String() != "warning" {
+		t.Fatalf("severity strings")
+	}
+}
+
+func TestCheckTypePerType(t *testing.T) {
+	y := 2020
+	cases := []struct {
+		name     string
+		e        Entry
+		errors   [][]string
+		warnings [][]string
+	}{
+		{"book with isbn", Entry{Type: "book", APA7: APA7{Year: &y, Identifiers: Identifiers{ISBN: "9780306406157"}}}, nil, nil},
+		{"book with publisher, dated", Entry{Type: "book", APA7: APA7{Publisher: "P", Date: "2020-01-02"}}, nil, nil},
+		{"article bare", Entry{Type: "article"}, nil, [][]string{{"journal", "container_title"}, {"year"}, {"doi", "url"}}},
+		{"article complete", Entry{Type: "article", APA7: APA7{Journal: "J", Year: &y, Identifiers: Identifiers{DOI: "10.1/x"}}}, nil, nil},
+		{"website bare", Entry{Type: "website"}, [][]string{{"url"}, {"accessed"}}, nil},
+		{"website complete", Entry{Type: "website", APA7: APA7{URL: "https://x", Accessed: "2024-01-01"}}, nil, nil},
+		{"movie bare", Entry{Type: "movie"}, nil, [][]string{{"year"}, {"publisher"}}},
+		{"video bare", Entry{Type: "video"}, [][]string{{"url"}}, [][]string{{"year"}}},
+		{"song bare", Entry{Type: "song"}, nil, [][]string{{"year"}, {"container_title", "publisher"}}},
+		{"patent bare", Entry{Type: "patent"}, [][]string{{"patent_number"}}, [][]string{{"year"}}},
+		{"report bare", Entry{Type: "report"}, nil, [][]string{{"publisher"}, {"year"}}},
+		{"dataset with doi", Entry{Type: "dataset", APA7: APA7{Year: &y, Identifiers: Identifiers{DOI: "10.1/d"}}}, nil, nil},
+		{"software bare", Entry{Type: "software"}, nil, [][]string{{"url", "doi"}, {"year"}}},
+		{"rfc bare", Entry{Type: "rfc"}, nil, [][]string{{"url"}, {"year"}}},
+		{"standard bare", Entry{Type: "standard"}, [][]string{{"designation"}}, [][]string{{"standards_body", "publisher"}}},
+		{"podcast bare", Entry{Type: "podcast"}, [][]string{{"container_title"}}, [][]string{{"url"}, {"year"}}},
+		{"unknown type", Entry{Type: "zine"}, nil, nil},
+	}
+	for _, c := range cases {
+		var errs, warns [][]string
+		for _, is := range c.e.CheckType() {
+			if is.Severity == SeverityError {
+				errs = append(errs, is.Fields)
+			} else {
+				warns = append(warns, is.Fields)
+			}
+		}
+		if !reflect.DeepEqual(errs, c.errors) || !reflect.DeepEqual(warns, c.warnings) {
+			t.Errorf("%s: errors %v warnings %v; want %v %v", c.name, errs, warns, c.errors, c.warnings)
+		}
+	}
+}
+
+func TestTypeRulesCoverEveryType(t *testing.T) {
+	for _, typ := range Types {
+		if _, ok := typeRules[typ]; !ok {
+			t.Errorf("no type rules for %s", typ)
+		}
+	}
+}
+
+func TestCheckTypeStrict(t *testing.T) {
+	y := 2020
+	cases := []struct {
+		name   string
+		e      Entry
+		fields [][]string
+	}{
+		{"standard with publisher only", Entry{Type: "standard", APA7: APA7{Designation: "X 1", Publisher: "P"}}, [][]string{{"standards_body"}}},
+		{"standard bare", Entry{Type: "standard"}, [][]string{{"designation"}, {"standards_body"}}},
+		{"standard complete", Entry{Type: "standard", APA7: APA7{Designation: "X 1", StandardsBody: "ISO"}}, nil},
+		{"article warnings become errors", Entry{Type: "article", APA7: APA7{Journal: "J", Year: &y}}, [][]string{{"doi", "url"}}},
+		{"unknown type", Entry{Type: "zine"}, nil},
+	}
+	for _, c := range cases {
+		var fields [][]string
+		for _, is := range c.e.CheckTypeStrict() {
+			if is.Severity != SeverityError {
+				t.Errorf("%s: %q is a %s", c.name, is.Message, is.Severity)
+			}
+			fields = append(fields, is.Fields)
+		}
+		if !reflect.DeepEqual(fields, c.fields) {
+			t.Errorf("%s: fields %v; want %v", c.name, fields, c.fields)
+		}
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	"bibliography/src/internal/dates"
 	"bibliography/src/internal/httpx"
@@ -16,41 +15,55 @@ import (
 	"bibliography/src/internal/stringsx"
 )
 
-var client httpx.Doer = &http.Client{Timeout: 10 * time.Second}
+var client httpx.Doer = httpx.ProviderClient()
 
 // SetHTTPClient sets the HTTP client used for external API calls (for tests).
 func SetHTTPClient(c httpx.Doer) { client = c }
 
 // chrome UA constant removed; use httpx.SetUA
 
-// FetchSong tries iTunes Search API first, then MusicBrainz. Returns a minimally valid APA7 entry of type "song".
-func FetchSong(ctx context.Context, title string, artist string, date string) (schema.Entry, error) {
+// Attempt captures a single provider attempt outcome.
+type Attempt struct {
+	Provider string
+	Success  bool
+	Error    string
+}
+
+// LookupSong tries iTunes Search API first, then MusicBrainz, and returns the entry,
+// the provider label ("itunes" or "musicbrainz"), and a trace of every attempt.
+func LookupSong(ctx context.Context, title, artist, date string) (schema.Entry, string, []Attempt, error) {
 	t := strings.TrimSpace(title)
 	if t == "" {
-		return schema.Entry{}, fmt.Errorf("title is required")
-	}
-	if e, err := fetchFromITunes(ctx, t, artist, date); err == nil {
-		return e, nil
-	}
-	if e, err := fetchFromMusicBrainz(ctx, t, artist, date); err == nil {
-		return e, nil
+		return schema.Entry{}, "", nil, fmt.Errorf("title is required")
+	}
+	attempts := []Attempt{}
+	for _, p := range []struct {
+		name  string
+		fetch func(context.Context, string, string, string) (schema.Entry, error)
+	}{
+		{"itunes", fetchFromITunes},
+		{"musicbrainz", fetchFromMusicBrainz},
+	} {
+		e, err := p.fetch(ctx, t, artist, date)
+		if err == nil {
+			attempts = append(attempts, Attempt{Provider: p.name, Success: true})
+			return e, p.name, attempts, nil
+		}
+		attempts = append(attempts, Attempt{Provider: p.name, Success: false, Error: err.Error()})
 	}
-	return schema.Entry{}, fmt.Errorf("no song metadata provider succeeded")
+	return schema.Entry{}, "", attempts, fmt.Errorf("no song metadata provider succeeded")
+}
+
+// FetchSong tries iTunes Search API first, then MusicBrainz. Returns a minimally valid APA7 entry of type "song".
+func FetchSong(ctx context.Context, title string, artist string, date string) (schema.Entry, error) {
+	e, _, _, err := LookupSong(ctx, title, artist, date)
+	return e, err
 }
 
 // FetchSongWithProvider returns a song entry and provider label ("itunes" or "musicbrainz").
 func FetchSongWithProvider(ctx context.Context, title string, artist string, date string) (schema.Entry, string, error) {
-	t := strings.TrimSpace(title)
-	if t == "" {
-		return schema.Entry{}, "", fmt.Errorf("title is required")
-	}
-	if e, err := fetchFromITunes(ctx, t, artist, date); err == nil {
-		return e, "itunes", nil
-	}
-	if e, err := fetchFromMusicBrainz(ctx, t, artist, date); err == nil {
-		return e, "musicbrainz", nil
-	}
-	return schema.Entry{}, "", fmt.Errorf("no song metadata provider succeeded")
+	e, provider, _, err := LookupSong(ctx, title, artist, date)
+	return e, provider, err
 }
 
 // fetchFromITunes queries the iTunes Search API and maps the first result to an Entry.
@@ -74,3 +74,18 @@ func TestFetchSong_MusicBrainzFallback(t *testing.T) {
 		t.Fatalf("expected fields from MB: %+v", e)
 	}
 }
+
+func TestLookupSong_AttemptTrace(t *testing.T) {
+	SetHTTPClient(fakeDoerMB{})
+	_, provider, attempts, err := LookupSong(context.Background(), "Title", "Artist", "")
+	if err != nil || provider != "musicbrainz" {
+		t.Fatalf("LookupSong: %q %v", provider, err)
+	}
+	if len(attempts) != 2 || attempts[0].Provider != "itunes" || attempts[0].Success || attempts[0].Error == "" ||
+		attempts[1].Provider != "musicbrainz" || !attempts[1].Success {
+		t.Fatalf("attempts: %+v", attempts)
+	}
+	if _, _, attempts, err := LookupSong(context.Background(), "  ", "", ""); err == nil || len(attempts) != 0 {
+		t.Fatalf("blank title should fail before any attempt: %v %+v", err, attempts)
+	}
+}
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"bibliography/src/internal/schema"
+)
+
+// BibKeyFormat returns the citation-key template from BIB_BIBKEY_FORMAT, e.g.
+// "{authorlast}{year}{titleword}". Empty, the default, keeps the dashless UUID keys.
+func BibKeyFormat() string {
+	return strings.TrimSpace(os.Getenv("BIB_BIBKEY_FORMAT"))
+}
+
+var (
+	keyPlaceholder = regexp.MustCompile(`\{[a-z]+\}`)
+	keyUnsafe      = regexp.MustCompile(`[^a-z0-9]+`)
+	// keyFold spells common accented Latin letters in ASCII so "Müller" keys as "muller".
+	keyFold = strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a", "æ", "ae",
+		"ç", "c", "é", "e", "è", "e", "ê", "e", "ë", "e", "í", "i", "ì", "i", "î", "i", "ï", "i",
+		"ñ", "n", "ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o", "œ", "oe",
+		"ß", "ss", "ú", "u", "ù", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y",
+	)
+	// keySkipWords are not used as {titleword}.
+	keySkipWords = map[string]bool{"a": true, "an": true, "the": true, "on": true, "of": true, "in": true, "for": true, "and": true, "to": true, "with": true}
+)
+
+// keySafe lowercases s and keeps only ASCII letters and digits.
+func keySafe(s string) string {
+	return keyUnsafe.ReplaceAllString(keyFold.Replace(strings.ToLower(s)), "")
+}
+
+// FormatBibKey expands format for e. Placeholders are {authorlast} (family name of
+// the first author or editor, else the standards body or publisher), {year}, {titleword}
+// (the first title word that is not an article or short preposition), {type}, and
+// {id}; unknown placeholders expand to nothing, and the result is reduced to lowercase
+// ASCII letters and digits. An empty format or expansion gives the dashless UUID.
+func FormatBibKey(e schema.Entry, format string) string {
+	if format == "" {
+		return bibKeyFor(e)
+	}
+	k := keyPlaceholder.ReplaceAllStringFunc(format, func(p string) string {
+		switch p {
+		case "{authorlast}":
+			if cs := bibCreators(e.APA7.Authors); len(cs) > 0 {
+				return keySafe(cs[0].Family)
+			}
+			return keySafe(coalesce(e.APA7.StandardsBody, e.APA7.Publisher))
+		case "{year}":
+			if e.APA7.Year != nil {
+				return fmt.Sprint(*e.APA7.Year)
+			}
+			return ""
+		case "{titleword}":
+			for _, w := range strings.Fields(e.APA7.Title) {
+				if w = keySafe(w); w != "" && !keySkipWords[w] {
+					return w
+				}
+			}
+			return ""
+		case "{type}":
+			return keySafe(e.Type)
+		case "{id}":
+			return bibKeyFor(e)
+		}
+		return ""
+	})
+	if k = keySafe(k); k == "" {
+		return bibKeyFor(e)
+	}
+	return k
+}
+
+// assignBibKeys returns the keys for entries (in the same order) under format. An
+// entry already in the library keeps its key there while that key still fits the
+// format (the bare key or the bare key plus a suffix), so adding a record never
+// renames the ones cited before it; see settleKeys. UUID keys (empty format) are left
+// alone.
+func assignBibKeys(entries []schema.Entry, format string) []string {
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = FormatBibKey(e, format)
+	}
+	if format == "" {
+		return keys
+	}
+	stored := libraryKeys()
+	current := make([]string, len(entries))
+	for i, e := range entries {
+		current[i] = stored[strings.ToLower(strings.TrimSpace(e.ID))]
+	}
+	return settleKeys(entries, keys, current)
+}
+
+// libraryKeys maps each lowercased _id in BibFile to its citation key.
+func libraryKeys() map[string]string {
+	out := map[string]string{}
+	b, err := os.ReadFile(BibFile)
+	if err != nil {
+		return out
+	}
+	records, err := parseBib(string(b))
+	if err != nil {
+		return out
+	}
+	for _, r := range records {
+		if id := strings.ToLower(strings.TrimSpace(r.fields["_id"])); id != "" {
+			out[id] = r.key
+		}
+	}
+	return out
+}
+
+// keyFits reports whether key is base itself or base plus a letter suffix.
+func keyFits(key, base string) bool {
+	rest, ok := strings.CutPrefix(key, base)
+	if !ok {
+		return false
+	}
+	for _, r := range rest {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// settleKeys resolves the formatted base keys of entries into unique keys. An entry
+// whose current key fits its base keeps it; the others, in title then id order, take
+// the bare base when it is free and otherwise the first free suffix a, b, c, ... (then
+// aa, ab, ...).
+func settleKeys(entries []schema.Entry, bases, current []string) []string {
+	keys := make([]string, len(entries))
+	taken := map[string]bool{}
+	var pending []int
+	for i := range entries {
+		if c := current[i]; c != "" && keyFits(c, bases[i]) && !taken[c] {
+			keys[i] = c
+			taken[c] = true
+			continue
+		}
+		pending = append(pending, i)
+	}
+	sort.SliceStable(pending, func(a, b int) bool {
+		ea, eb := entries[pending[a]], entries[pending[b]]
+		ta, tb := strings.ToLower(strings.TrimSpace(ea.APA7.Title)), strings.ToLower(strings.TrimSpace(eb.APA7.Title))
+		if ta != tb {
+			return ta < tb
+		}
+		return strings.ToLower(ea.ID) < strings.ToLower(eb.ID)
+	})
+	for _, i := range pending {
+		k := bases[i]
+		for n := 0; taken[k]; n++ {
+			k = bases[i] + keySuffix(n)
+		}
+		keys[i] = k
+		taken[k] = true
+	}
+	return keys
+}
+
+// keySuffix returns the n-th (0-based) disambiguation suffix: a..z, aa, ab, ...
+func keySuffix(n int) string {
+	s := ""
+	for n++; n > 0; n = (n - 1) / 26 {
+		s = string(rune('a'+(n-1)%26)) + s
+	}
+	return s
+}
+
+// rekeyRecords gives records the keys assigned by BibKeyFormat, when one is set;
+// otherwise the keys are left as they are. A record keeps its key while it fits the
+// format, so only new or changed records (and UUID-keyed ones) get new keys.
+func rekeyRecords(records []bibRecord) {
+	format := BibKeyFormat()
+	if format == "" {
+		return
+	}
+	entries := bibToEntries(records)
+	bases := make([]string, len(entries))
+	current := make([]string, len(records))
+	for i, e := range entries {
+		bases[i] = FormatBibKey(e, format)
+		current[i] = records[i].key
+	}
+	for i, k := range settleKeys(entries, bases, current) {
+		records[i].key = k
+	}
+}
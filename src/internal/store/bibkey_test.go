@@ -0,0 +1,111 @@
+package store
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestFormatBibKey(t *testing.T) {
+	y := 2009
+	e := schema.Entry{ID: "00000000-0000-4000-8000-0000000000d1", Type: "rfc", APA7: schema.APA7{Title: "The Syslog Protocol", Year: &y, Authors: schema.Authors{{Family: "Gerhards", Given: "R."}}}}
+	if got := FormatBibKey(e, "{authorlast}{year}{titleword}"); got != "gerhards2009syslog" {
+		t.Errorf("key %q, want gerhards2009syslog", got)
+	}
+	if got := FormatBibKey(e, ""); got != "000000000000400080000000000000d1" {
+		t.Errorf("default key %q", got)
+	}
+	e.APA7.Authors = schema.Authors{{Family: "Müller-Lüdenscheidt"}}
+	if got := FormatBibKey(e, "{authorlast}:{year}"); got != "mullerludenscheidt2009" {
+		t.Errorf("folded key %q", got)
+	}
+	e.APA7.Authors, e.APA7.Title, e.APA7.Year = nil, "", nil
+	if got := FormatBibKey(e, "{authorlast}{year}"); got != "000000000000400080000000000000d1" {
+		t.Errorf("empty expansion should fall back to the id, got %q", got)
+	}
+}
+
+func TestBibKeysDisambiguated(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	t.Setenv("BIB_BIBKEY_FORMAT", "{authorlast}{year}")
+
+	y := 2020
+	mk := func(id, title string) schema.Entry {
+		return schema.Entry{ID: id, Type: "article", APA7: schema.APA7{Title: title, Year: &y, Journal: "J", Authors: schema.Authors{{Family: "Smith", Given: "J."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	}
+	e1 := mk("00000000-0000-4000-8000-0000000000d2", "Beta")
+	e2 := mk("00000000-0000-4000-8000-0000000000d3", "Alpha")
+	if _, err := WriteEntry(e1); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	if !strings.Contains(string(b), "@article{smith2020,") {
+		t.Fatalf("single entry should have the bare key:\n%s", b)
+	}
+	if _, err := WriteEntry(e2); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(BibFile)
+	s := string(b)
+	// The first record keeps its key; only the newcomer ("Alpha") is suffixed, even
+	// though it sorts first.
+	if !strings.Contains(s, "@article{smith2020,") || !strings.Contains(s, "@article{smith2020a,") {
+		t.Fatalf("colliding keys not disambiguated:\n%s", s)
+	}
+	if i, j := strings.Index(s, "smith2020a,"), strings.Index(s, "_id = {"+e2.ID+"}"); i < 0 || j < i {
+		t.Fatalf("suffix not on the new record:\n%s", s)
+	}
+	// Updating an entry and writing a third one leave existing keys alone.
+	e1.APA7.Title = "Aardvark"
+	if _, err := WriteEntry(e1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteEntry(mk("00000000-0000-4000-8000-0000000000d1", "Gamma")); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = os.ReadFile(BibFile)
+	s = string(b)
+	for id, key := range map[string]string{e1.ID: "smith2020", e2.ID: "smith2020a", "00000000-0000-4000-8000-0000000000d1": "smith2020b"} {
+		if i, j := strings.Index(s, "@article{"+key+","), strings.Index(s, "_id = {"+id+"}"); i < 0 || j < i || strings.Contains(s[i+1:j], "@article{") {
+			t.Fatalf("%s should keep key %s:\n%s", id, key, s)
+		}
+	}
+	if err := FormatBibLibrary(0); err != nil {
+		t.Fatal(err)
+	}
+	if b2, _ := os.ReadFile(BibFile); strings.Count(string(b2), "@article{smith2020") != 3 || !strings.Contains(string(b2), "@article{smith2020b,") {
+		t.Fatalf("format should keep fitting keys:\n%s", b2)
+	}
+	es, err := ReadAll()
+	if err != nil || len(es) != 3 {
+		t.Fatalf("read back: %v %d", err, len(es))
+	}
+	// Exports reuse the library's keys.
+	out := string(EntriesToBibTeX(es))
+	for _, k := range []string{"{smith2020,", "{smith2020a,", "{smith2020b,"} {
+		if !strings.Contains(out, k) {
+			t.Fatalf("export missing %s:\n%s", k, out)
+		}
+	}
+	// Outside the library, a fresh set is suffixed in title order.
+	if err := os.Remove(BibFile); err != nil {
+		t.Fatal(err)
+	}
+	fresh := []schema.Entry{mk("00000000-0000-4000-8000-0000000000e1", "Beta"), mk("00000000-0000-4000-8000-0000000000e2", "Alpha")}
+	if keys := assignBibKeys(fresh, BibKeyFormat()); keys[0] != "smith2020a" || keys[1] != "smith2020" {
+		t.Fatalf("fresh keys: %v", keys)
+	}
+}
+
+func TestKeySuffix(t *testing.T) {
+	for n, want := range map[int]string{0: "a", 1: "b", 25: "z", 26: "aa", 27: "ab"} {
+		if got := keySuffix(n); got != want {
+			t.Errorf("keySuffix(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package store
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -10,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/names"
 	"bibliography/src/internal/schema"
 )
 
@@ -38,8 +42,9 @@ func RebuildBibLibrary() error {
 		}
 		return ei.ID < ej.ID
 	})
-	for _, e := range entries {
-		buf.WriteString(entryToBibTeX(e))
+	keys := assignBibKeys(entries, BibKeyFormat())
+	for i, e := range entries {
+		buf.WriteString(entryToBibTeX(e, keys[i]))
 		if !strings.HasSuffix(buf.String(), "\n\n") {
 			buf.WriteString("\n")
 		}
@@ -48,16 +53,41 @@ func RebuildBibLibrary() error {
 }
 
 // ExportYAMLToBib reads all YAML entries from data/citations and writes a consolidated
-// BibTeX file to target. This is intended for one-time migrations.
-func ExportYAMLToBib(target string) error {
+// BibTeX file to target, returning the number of records written. This is intended
+// for one-time migrations.
+func ExportYAMLToBib(target string) (int, error) {
 	entries, err := readAllYAML()
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if err := ExportEntriesToBib(target, entries); err != nil {
+		return 0, err
 	}
+	return len(entries), nil
+}
+
+// ExportEntriesToBib writes the given entries to target as a consolidated BibTeX
+// file using the same deterministic ordering as the library.
+func ExportEntriesToBib(target string, entries []schema.Entry) error {
 	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
-		return err
+		return fmt.Errorf("cannot create output directory for %s: %w", target, err)
 	}
+	return os.WriteFile(target, EntriesToBibTeX(entries), 0o644)
+}
+
+// EntriesToBibTeX renders entries as BibTeX records ordered by type, title, then id.
+func EntriesToBibTeX(entries []schema.Entry) []byte {
 	var buf bytes.Buffer
+	sortForExport(entries)
+	keys := assignBibKeys(entries, BibKeyFormat())
+	for i, e := range entries {
+		buf.WriteString(entryToBibTeX(e, keys[i]))
+	}
+	return buf.Bytes()
+}
+
+// sortForExport orders entries in place by type, title, then id.
+func sortForExport(entries []schema.Entry) {
 	sort.Slice(entries, func(i, j int) bool {
 		ei, ej := entries[i], entries[j]
 		if ei.Type != ej.Type {
@@ -70,17 +100,12 @@ func ExportYAMLToBib(target string) error {
 		}
 		return ei.ID < ej.ID
 	})
-	for _, e := range entries {
-		buf.WriteString(entryToBibTeX(e))
-	}
-	return os.WriteFile(target, buf.Bytes(), 0o644)
 }
 
-// entryToBibTeX converts a schema.Entry into a BibTeX record string.
+// entryToBibTeX converts a schema.Entry into a BibTeX record string with citation key.
 // We use non-standard fields 'abstract' and 'keywords' to capture annotations.
-func entryToBibTeX(e schema.Entry) string {
+func entryToBibTeX(e schema.Entry, key string) string {
 	typ := bibTypeFor(e.Type)
-	key := bibKeyFor(e)
 	// field helpers
 	w := func(k, v string) string {
 		v = strings.TrimSpace(v)
@@ -91,7 +116,7 @@ func entryToBibTeX(e schema.Entry) string {
 	}
 	wEmpty := func(k, v string) string { return fmt.Sprintf("  %s = {%s},\n", k, escapeBib(v)) }
 	// Authors
-	authors := formatAuthors(e.APA7.Authors)
+	authors := authorField(e.APA7)
 	year := ""
 	if e.APA7.Year != nil {
 		year = fmt.Sprintf("%d", *e.APA7.Year)
@@ -100,8 +125,14 @@ func entryToBibTeX(e schema.Entry) string {
 	var b bytes.Buffer
 	fmt.Fprintf(&b, "@%s{%s,\n", typ, key)
 	if authors != "" {
-		b.WriteString(w("author", authors))
+		fmt.Fprintf(&b, "  author = {%s},\n", braceParticles(escapeBib(authors)))
 	}
+	for _, role := range authorRoleFields {
+		if v := formatAuthors(e.APA7.Authors.WithRole(role)); v != "" {
+			fmt.Fprintf(&b, "  %s = {%s},\n", role, braceParticles(escapeBib(v)))
+		}
+	}
+	b.WriteString(w("orcid", orcidField(e.APA7.Authors)))
 	b.WriteString(w("title", e.APA7.Title))
 	switch strings.ToLower(strings.TrimSpace(e.Type)) {
 	case "article":
@@ -118,16 +149,29 @@ func entryToBibTeX(e schema.Entry) string {
 		b.WriteString(w("isbn", e.APA7.ISBN))
 		b.WriteString(w("doi", e.APA7.DOI))
 		b.WriteString(w("url", e.APA7.URL))
+		b.WriteString(w("medium", e.APA7.Medium))
+		b.WriteString(w(schema.RoleNarrator, formatAuthors(e.APA7.Authors.WithRole(schema.RoleNarrator))))
 	case "patent":
 		// Map to @misc; include publisher/assignee and url
 		b.WriteString(w("howpublished", e.APA7.Publisher))
+		b.WriteString(w("patent_number", e.APA7.PatentNumber))
+		b.WriteString(w("url", e.APA7.URL))
+	case "standard":
+		// Map to @techreport: the designation is the report number, the body the institution
+		b.WriteString(w("institution", e.APA7.StandardsBody))
+		b.WriteString(w("publisher", e.APA7.Publisher))
+		b.WriteString(w("number", e.APA7.Designation))
+		b.WriteString(w("doi", e.APA7.DOI))
 		b.WriteString(w("url", e.APA7.URL))
 	case "website":
 		b.WriteString(w("howpublished", coalesce(e.APA7.Publisher, "Website")))
 		b.WriteString(w("url", e.APA7.URL))
-		if strings.TrimSpace(e.APA7.Accessed) != "" {
-			b.WriteString(w("note", "Accessed: "+e.APA7.Accessed))
-		}
+	case "podcast":
+		// Map to @misc: the show is the booktitle, the episode number the number
+		b.WriteString(w("booktitle", e.APA7.ContainerTitle))
+		b.WriteString(w("publisher", e.APA7.Publisher))
+		b.WriteString(w("number", e.APA7.Issue))
+		b.WriteString(w("url", e.APA7.URL))
 	case "movie", "video", "song", "rfc", "report", "dataset", "software":
 		// Generic mapping; try to set container/publisher/url
 		b.WriteString(w("howpublished", coalesce(e.APA7.Publisher, e.APA7.ContainerTitle)))
@@ -137,6 +181,22 @@ func entryToBibTeX(e schema.Entry) string {
 		b.WriteString(w("url", e.APA7.URL))
 		b.WriteString(w("doi", e.APA7.DOI))
 	}
+	for _, f := range identifierFields(&e.APA7.Identifiers) {
+		b.WriteString(w(f.key, *f.val))
+	}
+	for _, rel := range e.APA7.Relations.List() {
+		b.WriteString(w(rel.Key, strings.Join(rel.Docs, ", ")))
+	}
+	if strings.TrimSpace(e.APA7.ArXiv) != "" {
+		b.WriteString(w("archiveprefix", "arXiv"))
+	}
+	b.WriteString(w("archived_url", e.APA7.ArchivedURL))
+	b.WriteString(w("language", e.APA7.Language))
+	note := entryNote(e)
+	if strings.EqualFold(strings.TrimSpace(e.Type), "website") && strings.TrimSpace(e.APA7.Accessed) != "" {
+		note = joinNote("Accessed: "+e.APA7.Accessed, note)
+	}
+	b.WriteString(w("note", note))
 	b.WriteString(w("year", year))
 	if strings.TrimSpace(e.APA7.Date) != "" {
 		b.WriteString(w("date", e.APA7.Date))
@@ -168,6 +228,75 @@ func entryToBibTeX(e schema.Entry) string {
 	return out
 }
 
+// braceParticles wraps leading lowercase surname particles of each author in an
+// "a and b" list in braces ("von der Berg, J." -> "{von der} Berg, J.") so BibTeX
+// keeps them with the family name when sorting and abbreviating. A multi-word name
+// with no given part (an organization) is braced whole so BibTeX reads it as one
+// literal name rather than first names plus a surname.
+func braceParticles(authors string) string {
+	parts := strings.Split(authors, " and ")
+	for i, p := range parts {
+		if !strings.Contains(p, ",") && strings.Contains(strings.TrimSpace(p), " ") {
+			parts[i] = "{" + strings.TrimSpace(p) + "}"
+			continue
+		}
+		fam, rest := p, ""
+		if j := strings.Index(p, ","); j >= 0 {
+			fam, rest = p[:j], p[j:]
+		}
+		if particle, last := names.SplitParticle(fam); particle != "" {
+			parts[i] = "{" + particle + "} " + last + rest
+		}
+	}
+	return strings.Join(parts, " and ")
+}
+
+// stripBraces removes BibTeX grouping braces from a field value.
+func stripBraces(s string) string { return strings.NewReplacer("{", "", "}", "").Replace(s) }
+
+// bracedWhole reports whether s is a single brace group ("{World Health Organization}"),
+// the BibTeX form of a literal name, and returns its contents.
+func bracedWhole(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return "", false
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 && i != len(s)-1 {
+				return "", false
+			}
+		}
+	}
+	return s[1 : len(s)-1], depth == 0
+}
+
+// splitNames splits a BibTeX name list on " and " outside braces, so a braced
+// literal such as "{Barnes and Noble}" stays one name.
+func splitNames(s string) []string {
+	var out []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ' ':
+			if depth == 0 && strings.HasPrefix(s[i:], " and ") {
+				out = append(out, s[start:i])
+				start = i + len(" and ")
+				i = start - 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
 func escapeBib(s string) string {
 	// Minimal escaping; preserve LaTeX-friendly characters as-is
 	s = strings.ReplaceAll(s, "\n", " ")
@@ -207,12 +336,144 @@ func formatAuthors(as schema.Authors) string {
 	return strings.Join(parts, " and ")
 }
 
+// mediumNote describes a non-print medium (and narrators) for the BibTeX note field.
+func mediumNote(e schema.Entry) string {
+	var note string
+	switch strings.ToLower(strings.TrimSpace(e.APA7.Medium)) {
+	case "audiobook":
+		note = "Audiobook"
+	case "ebook":
+		note = "E-book"
+	default:
+		return ""
+	}
+	if n := formatAuthors(e.APA7.Authors.WithRole(schema.RoleNarrator)); n != "" {
+		note += "; narrated by " + n
+	}
+	return note
+}
+
+// authorRoleFields are the non-author roles stored as their own BibTeX name list.
+var authorRoleFields = []string{schema.RoleEditor, schema.RoleTranslator}
+
+// bibCreators orders authors as they are written to BibTeX: authors, then each of
+// authorRoleFields.
+func bibCreators(as schema.Authors) schema.Authors {
+	out := as.WithRole(schema.RoleAuthor)
+	for _, role := range authorRoleFields {
+		out = append(out, as.WithRole(role)...)
+	}
+	return out
+}
+
+// authorField renders the BibTeX author list, ending in "and others" when truncated.
+func authorField(a schema.APA7) string {
+	s := formatAuthors(a.Authors.WithRole(schema.RoleAuthor))
+	if s != "" && a.AuthorsTruncated() {
+		s += " and others"
+	}
+	return s
+}
+
+// orcidField lists author ORCID iDs in bibCreators order, comma-separated, leaving a
+// position empty for an author without one (trailing empties are dropped).
+func orcidField(as schema.Authors) string {
+	as = bibCreators(as)
+	ids := make([]string, len(as))
+	last := -1
+	for i, a := range as {
+		if ids[i] = strings.TrimSpace(a.ORCID); ids[i] != "" {
+			last = i
+		}
+	}
+	return strings.Join(ids[:last+1], ", ")
+}
+
+// applyORCIDs sets author ORCID iDs from an orcid field written by orcidField.
+func applyORCIDs(as schema.Authors, field string) {
+	if strings.TrimSpace(field) == "" {
+		return
+	}
+	for i, id := range strings.Split(field, ",") {
+		if i < len(as) {
+			as[i].ORCID = strings.TrimSpace(id)
+		}
+	}
+}
+
+// identifierField pairs a BibTeX field with the identifier it stores.
+type identifierField struct {
+	key string
+	val *string
+}
+
+// identifierFields lists the identifiers stored in their own BibTeX fields for every
+// type; DOI and ISBN are written by the per-type mappings.
+func identifierFields(ids *schema.Identifiers) []identifierField {
+	return []identifierField{
+		{"issn", &ids.ISSN},
+		{"pmid", &ids.PMID},
+		{"eprint", &ids.ArXiv},
+		{"bibcode", &ids.Bibcode},
+		{"isrc", &ids.ISRC},
+	}
+}
+
+// entryNote builds the BibTeX note: the medium description followed by database
+// accession numbers (e.g., "PubMed: 12345678"), document relations (e.g.,
+// "Obsoletes: RFC 3164"), and the archived snapshot ("Archived at <url>"), which most
+// styles ignore as fields.
+func entryNote(e schema.Entry) string {
+	note := mediumNote(e)
+	for _, a := range e.APA7.Accessions() {
+		note = joinNote(note, a.Database+": "+a.Number)
+	}
+	for _, rel := range e.APA7.Relations.List() {
+		note = joinNote(note, rel.Label+": "+strings.Join(rel.Docs, ", "))
+	}
+	if v := strings.TrimSpace(e.APA7.ArchivedURL); v != "" {
+		note = joinNote(note, "Archived at "+v)
+	}
+	return note
+}
+
+// recordRelations reads the relation fields of a record, or nil when there are none.
+func recordRelations(r bibRecord) *schema.Relations {
+	var rel schema.Relations
+	found := false
+	for _, k := range schema.RelationKeys {
+		var docs []string
+		for _, d := range strings.Split(r.fields[k], ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				docs = append(docs, d)
+			}
+		}
+		if len(docs) > 0 {
+			rel.Set(k, docs)
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &rel
+}
+
+func joinNote(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + "; " + b
+}
+
 func bibTypeFor(t string) string {
 	switch strings.ToLower(strings.TrimSpace(t)) {
 	case "article":
 		return "article"
 	case "book":
 		return "book"
+	case "standard":
+		return "techreport"
 	default:
 		return "misc"
 	}
@@ -236,10 +497,16 @@ type bibRecord struct {
 	typ    string
 	key    string
 	fields map[string]string
+	// comments are the "%" lines that preceded the record, written back before it.
+	comments []string
 }
 
 // UpdateBibEntry inserts or replaces the entry with the same _id in BibFile.
-func UpdateBibEntry(e schema.Entry) error {
+func UpdateBibEntry(e schema.Entry) error { return UpdateBibEntries([]schema.Entry{e}) }
+
+// UpdateBibEntries inserts or replaces each of es by _id in BibFile, reading and
+// rewriting the library once for the whole set.
+func UpdateBibEntries(es []schema.Entry) error {
 	var records []bibRecord
 	if b, err := os.ReadFile(BibFile); err == nil && len(b) > 0 {
 		rs, perr := parseBib(string(b))
@@ -248,24 +515,32 @@ func UpdateBibEntry(e schema.Entry) error {
 		}
 		records = rs
 	}
-	// Convert to record
-	rec := entryToRecord(e)
-	// Replace by _id match else append
-	id := strings.ToLower(strings.TrimSpace(e.ID))
-	found := false
-	for i := range records {
-		if strings.ToLower(records[i].fields["_id"]) == id && id != "" {
-			records[i] = rec
-			found = true
-			break
+	updated := map[string]bool{}
+	for _, e := range es {
+		// Convert to record
+		rec := entryToRecord(e)
+		// Replace by _id match else append
+		id := strings.ToLower(strings.TrimSpace(e.ID))
+		updated[id] = true
+		found := false
+		for i := range records {
+			if strings.ToLower(records[i].fields["_id"]) == id && id != "" {
+				rec.comments = records[i].comments
+				if BibKeyFormat() != "" {
+					rec.key = records[i].key
+				}
+				records[i] = rec
+				found = true
+				break
+			}
+		}
+		if !found {
+			records = append(records, rec)
 		}
 	}
-	if !found {
-		records = append(records, rec)
-	}
+	rekeyRecords(records)
 	// Ensure metadata fields
 	now := nowISO()
-	idLower := strings.ToLower(id)
 	src := currentWriteSource()
 	for i := range records {
 		r := &records[i]
@@ -285,8 +560,11 @@ func UpdateBibEntry(e schema.Entry) error {
 		} else {
 			// must be present but empty when not verified
 			r.fields["verified_by"] = ""
+			delete(r.fields, "verified_at")
+			delete(r.fields, "verified_method")
+			delete(r.fields, "verified_providers")
 		}
-		if strings.ToLower(strings.TrimSpace(r.fields["_id"])) == idLower {
+		if updated[strings.ToLower(strings.TrimSpace(r.fields["_id"]))] {
 			r.fields["modified"] = now
 			if strings.TrimSpace(src) != "" {
 				r.fields["source"] = src
@@ -329,8 +607,19 @@ func entryToRecord(e schema.Entry) bibRecord {
 	// We render via renderRecord to keep formatting in one place.
 	// Minimal map to ease deterministic ordering later.
 	m := map[string]string{}
-	if len(e.APA7.Authors) > 0 {
-		m["author"] = formatAuthors(e.APA7.Authors)
+	if v := authorField(e.APA7); v != "" {
+		m["author"] = v
+	}
+	for _, role := range authorRoleFields {
+		if v := formatAuthors(e.APA7.Authors.WithRole(role)); v != "" {
+			m[role] = v
+		}
+	}
+	if e.APA7.AuthorsTruncated() {
+		m["author_count"] = fmt.Sprintf("%d", e.APA7.AuthorCount)
+	}
+	if v := orcidField(e.APA7.Authors); v != "" {
+		m["orcid"] = v
 	}
 	m["title"] = e.APA7.Title
 	switch strings.ToLower(strings.TrimSpace(e.Type)) {
@@ -372,6 +661,35 @@ func entryToRecord(e schema.Entry) bibRecord {
 		if v := e.APA7.URL; v != "" {
 			m["url"] = v
 		}
+	case "standard":
+		if v := e.APA7.StandardsBody; v != "" {
+			m["institution"] = v
+		}
+		if v := e.APA7.Publisher; v != "" {
+			m["publisher"] = v
+		}
+		if v := e.APA7.Designation; v != "" {
+			m["number"] = v
+		}
+		if v := e.APA7.DOI; v != "" {
+			m["doi"] = v
+		}
+		if v := e.APA7.URL; v != "" {
+			m["url"] = v
+		}
+	case "podcast":
+		if v := e.APA7.ContainerTitle; v != "" {
+			m["booktitle"] = v
+		}
+		if v := e.APA7.Publisher; v != "" {
+			m["publisher"] = v
+		}
+		if v := e.APA7.Issue; v != "" {
+			m["number"] = v
+		}
+		if v := e.APA7.URL; v != "" {
+			m["url"] = v
+		}
 	default:
 		if v := coalesce(e.APA7.Publisher, e.APA7.ContainerTitle); v != "" {
 			m["howpublished"] = v
@@ -383,6 +701,38 @@ func entryToRecord(e schema.Entry) bibRecord {
 			m["doi"] = v
 		}
 	}
+	if v := e.APA7.PatentNumber; strings.TrimSpace(v) != "" {
+		m["patent_number"] = v
+	}
+	if v := strings.TrimSpace(e.APA7.Accessed); v != "" && strings.TrimSpace(e.APA7.URL) != "" {
+		m["urldate"] = v
+	}
+	if v := strings.TrimSpace(e.APA7.ArchivedURL); v != "" {
+		m["archived_url"] = v
+	}
+	if v := strings.TrimSpace(e.APA7.Language); v != "" {
+		m["language"] = v
+	}
+	if v := strings.ToLower(strings.TrimSpace(e.APA7.Medium)); v != "" {
+		m["medium"] = v
+	}
+	if v := formatAuthors(e.APA7.Authors.WithRole(schema.RoleNarrator)); v != "" {
+		m[schema.RoleNarrator] = v
+	}
+	for _, f := range identifierFields(&e.APA7.Identifiers) {
+		if v := strings.TrimSpace(*f.val); v != "" {
+			m[f.key] = v
+		}
+	}
+	for _, rel := range e.APA7.Relations.List() {
+		m[rel.Key] = strings.Join(rel.Docs, ", ")
+	}
+	if strings.TrimSpace(e.APA7.ArXiv) != "" {
+		m["archiveprefix"] = "arXiv"
+	}
+	if v := entryNote(e); v != "" {
+		m["note"] = v
+	}
 	if e.APA7.Year != nil {
 		m["year"] = fmt.Sprintf("%d", *e.APA7.Year)
 	}
@@ -404,9 +754,12 @@ var lineWrap = 120
 
 func renderRecord(r bibRecord) string {
 	var b bytes.Buffer
+	for _, c := range r.comments {
+		b.WriteString(c + "\n")
+	}
 	fmt.Fprintf(&b, "@%s{%s,\n", r.typ, r.key)
 	// stable field order: author, title, journal/howpublished/publisher..., then remaining sorted
-	order := []string{"author", "title", "journal", "booktitle", "howpublished", "publisher", "address", "edition", "volume", "number", "pages", "year", "date", "doi", "isbn", "url", "abstract", "keywords", "_id", "_type", "created", "modified", "source", "verified", "verified_by"}
+	order := []string{"author", "author_count", "editor", "translator", "orcid", "title", "journal", "booktitle", "howpublished", "institution", "publisher", "address", "edition", "volume", "number", "pages", "year", "date", "doi", "isbn", "issn", "pmid", "eprint", "archiveprefix", "bibcode", "isrc", "obsoletes", "updates", "obsoleted_by", "updated_by", "patent_number", "medium", "narrator", "language", "note", "url", "urldate", "archived_url", "abstract", "keywords", "_id", "_type", "created", "modified", "source", "verified", "verified_by", "verified_at", "verified_method", "verified_providers"}
 	seen := map[string]bool{}
 	for _, k := range order {
 		v, ok := r.fields[k]
@@ -449,7 +802,11 @@ func writeWrappedField(b *bytes.Buffer, key, val string, width int) {
 		fmt.Fprintf(b, "  %s = {},\n", key)
 		return
 	}
-	val = escapeBib(val)
+	if key == "author" || key == schema.RoleEditor || key == schema.RoleTranslator || key == schema.RoleNarrator {
+		val = braceParticles(escapeBib(formatAuthors(parseAuthorsField(val))))
+	} else {
+		val = escapeBib(val)
+	}
 	prefix := fmt.Sprintf("  %s = {", key)
 	// remaining width for first line content
 	// width available on first line is width - prefix - closing
@@ -476,10 +833,16 @@ func writeWrappedField(b *bytes.Buffer, key, val string, width int) {
 	fmt.Fprintln(b, line)
 }
 
+// parseBib reads the records of a BibTeX file. "%" comment lines directly before a
+// record are kept with it (see bibRecord.comments); @string macros are resolved where
+// referenced as bare values, including "#" concatenation; @comment and @preamble
+// blocks are skipped.
 func parseBib(s string) ([]bibRecord, error) {
 	i := 0
 	n := len(s)
 	var recs []bibRecord
+	var comments []string
+	macros := map[string]string{}
 	skipWS := func() {
 		for i < n {
 			if s[i] == '%' {
@@ -502,8 +865,114 @@ func parseBib(s string) ([]bibRecord, error) {
 		}
 		return s[start:i]
 	}
+	// skipBlock skips a delimited block whose opening delimiter is at i.
+	skipBlock := func() {
+		open, close := s[i], byte('}')
+		if open == '(' {
+			close = ')'
+		}
+		depth := 0
+		for ; i < n; i++ {
+			switch s[i] {
+			case open:
+				depth++
+			case close:
+				depth--
+				if depth == 0 {
+					i++
+					return
+				}
+			}
+		}
+	}
+	// readPart reads one brace-delimited, quoted, or bare value; bare names are macros.
+	readPart := func() string {
+		val := ""
+		if i < n && s[i] == '{' {
+			depth := 0
+			i++
+			vstart := i
+			for i < n {
+				if s[i] == '\\' {
+					i += 2
+					continue
+				}
+				if s[i] == '{' {
+					depth++
+					i++
+					continue
+				}
+				if s[i] == '}' {
+					if depth == 0 {
+						val = s[vstart:i]
+						i++
+						break
+					}
+					depth--
+					i++
+					continue
+				}
+				i++
+			}
+		} else if i < n && s[i] == '"' {
+			i++
+			vstart := i
+			for i < n {
+				if s[i] == '\\' {
+					i += 2
+					continue
+				}
+				if s[i] == '"' {
+					val = s[vstart:i]
+					i++
+					break
+				}
+				i++
+			}
+		} else {
+			// bare value (number or macro name) until comma/brace/concatenation
+			vstart := i
+			for i < n && s[i] != ',' && s[i] != '}' && s[i] != ')' && s[i] != '#' {
+				i++
+			}
+			val = strings.TrimSpace(s[vstart:i])
+			if m, ok := macros[strings.ToLower(val)]; ok {
+				val = m
+			}
+		}
+		return val
+	}
+	// readValue reads parts joined by "#".
+	readValue := func() string {
+		val := readPart()
+		for {
+			skipWS()
+			if i >= n || s[i] != '#' {
+				return val
+			}
+			i++
+			skipWS()
+			val += readPart()
+		}
+	}
 	for {
-		skipWS()
+		// collect comment lines before the next record
+		for i < n {
+			if s[i] == '%' {
+				end := strings.IndexByte(s[i:], '\n')
+				if end < 0 {
+					end = n - i
+				}
+				comments = append(comments, strings.TrimRight(s[i:i+end], " \t\r"))
+				i += end
+				continue
+			}
+			if strings.IndexByte(" \t\r\n", s[i]) >= 0 {
+				i++
+				continue
+			}
+			break
+		}
 		if i >= n {
 			break
 		}
@@ -518,7 +987,27 @@ func parseBib(s string) ([]bibRecord, error) {
 		if i >= n || (s[i] != '{' && s[i] != '(') {
 			return nil, fmt.Errorf("invalid bib: expected '{' after type")
 		}
-		// use '{'
+		switch typ {
+		case "comment", "preamble":
+			skipBlock()
+			continue
+		case "string":
+			i++
+			skipWS()
+			name := strings.ToLower(strings.TrimSpace(readIdent()))
+			skipWS()
+			if i >= n || s[i] != '=' {
+				return nil, fmt.Errorf("invalid bib: expected '=' in @string")
+			}
+			i++
+			skipWS()
+			macros[name] = readValue()
+			skipWS()
+			if i < n && (s[i] == '}' || s[i] == ')') {
+				i++
+			}
+			continue
+		}
 		// advance past delimiter
 		i++
 		skipWS()
@@ -554,58 +1043,7 @@ func parseBib(s string) ([]bibRecord, error) {
 			}
 			i++
 			skipWS()
-			// value: brace-delimited or quote-delimited
-			val := ""
-			if i < n && s[i] == '{' {
-				depth := 0
-				i++
-				vstart := i
-				for i < n {
-					if s[i] == '\\' {
-						i += 2
-						continue
-					}
-					if s[i] == '{' {
-						depth++
-						i++
-						continue
-					}
-					if s[i] == '}' {
-						if depth == 0 {
-							val = s[vstart:i]
-							i++
-							break
-						}
-						depth--
-						i++
-						continue
-					}
-					i++
-				}
-			} else if i < n && s[i] == '"' {
-				i++
-				vstart := i
-				for i < n {
-					if s[i] == '\\' {
-						i += 2
-						continue
-					}
-					if s[i] == '"' {
-						val = s[vstart:i]
-						i++
-						break
-					}
-					i++
-				}
-			} else {
-				// bare value until comma/brace
-				vstart := i
-				for i < n && s[i] != ',' && s[i] != '}' && s[i] != ')' {
-					i++
-				}
-				val = strings.TrimSpace(s[vstart:i])
-			}
-			fields[fname] = unescapeBib(val)
+			fields[fname] = unescapeBib(readValue())
 			skipWS()
 			if i < n && s[i] == ',' {
 				i++
@@ -616,7 +1054,8 @@ func parseBib(s string) ([]bibRecord, error) {
 				break
 			}
 		}
-		recs = append(recs, bibRecord{typ: typ, key: key, fields: fields})
+		recs = append(recs, bibRecord{typ: typ, key: key, fields: fields, comments: comments})
+		comments = nil
 	}
 	return recs, nil
 }
@@ -628,6 +1067,15 @@ func unescapeBib(s string) string {
 	return strings.TrimSpace(s)
 }
 
+// ParseBibTeX parses BibTeX text into entries; records without an _id get a new one.
+func ParseBibTeX(data string) ([]schema.Entry, error) {
+	rs, err := parseBib(data)
+	if err != nil {
+		return nil, err
+	}
+	return bibToEntries(rs), nil
+}
+
 // bibToEntries converts bib records into schema entries.
 func bibToEntries(rs []bibRecord) []schema.Entry {
 	out := make([]schema.Entry, 0, len(rs))
@@ -654,7 +1102,23 @@ func bibToEntries(rs []bibRecord) []schema.Entry {
 		// Authors
 		if a := strings.TrimSpace(r.fields["author"]); a != "" {
 			e.APA7.Authors = parseAuthorsField(a)
+			// "and others" marks a truncated list; the full count is in author_count
+			if n := len(e.APA7.Authors); n > 0 && e.APA7.Authors[n-1] == (schema.Author{Family: "others"}) {
+				e.APA7.Authors = e.APA7.Authors[:n-1]
+				fmt.Sscanf(r.fields["author_count"], "%d", &e.APA7.AuthorCount)
+				if e.APA7.AuthorCount < n {
+					// unknown total (e.g., imported); any count beyond the list marks truncation
+					e.APA7.AuthorCount = n
+				}
+			}
+		}
+		for _, role := range authorRoleFields {
+			for _, a := range parseAuthorsField(r.fields[role]) {
+				a.Role = role
+				e.APA7.Authors = append(e.APA7.Authors, a)
+			}
 		}
+		applyORCIDs(e.APA7.Authors, r.fields["orcid"])
 		e.APA7.Title = r.fields["title"]
 		e.APA7.Journal = r.fields["journal"]
 		if e.APA7.Journal == "" {
@@ -665,7 +1129,24 @@ func bibToEntries(rs []bibRecord) []schema.Entry {
 		e.APA7.Pages = r.fields["pages"]
 		e.APA7.DOI = r.fields["doi"]
 		e.APA7.ISBN = r.fields["isbn"]
+		e.APA7.PatentNumber = r.fields["patent_number"]
+		for _, f := range identifierFields(&e.APA7.Identifiers) {
+			*f.val = r.fields[f.key]
+		}
+		e.APA7.Relations = recordRelations(r)
+		if t == "standard" {
+			e.APA7.Designation, e.APA7.Issue = e.APA7.Issue, ""
+			e.APA7.StandardsBody = r.fields["institution"]
+		}
+		e.APA7.Accessed = r.fields["urldate"]
+		e.APA7.Medium = r.fields["medium"]
+		for _, a := range parseAuthorsField(r.fields[schema.RoleNarrator]) {
+			a.Role = schema.RoleNarrator
+			e.APA7.Authors = append(e.APA7.Authors, a)
+		}
 		e.APA7.URL = r.fields["url"]
+		e.APA7.ArchivedURL = r.fields["archived_url"]
+		e.APA7.Language = r.fields["language"]
 		e.APA7.Publisher = r.fields["publisher"]
 		e.APA7.PublisherLocation = r.fields["address"]
 		e.APA7.Edition = r.fields["edition"]
@@ -683,20 +1164,27 @@ func bibToEntries(rs []bibRecord) []schema.Entry {
 		if kw := strings.TrimSpace(r.fields["keywords"]); kw != "" {
 			e.Annotation.Keywords = splitKeywords(kw)
 		}
+		e.Verification = recordVerification(r)
 		out = append(out, e)
 	}
 	return out
 }
 
 func parseAuthorsField(s string) schema.Authors {
-	// Split on ' and ' outside braces (we don't emit braces), simple split works
-	parts := strings.Split(s, " and ")
+	parts := splitNames(s)
 	out := make([]schema.Author, 0, len(parts))
 	for _, p := range parts {
-		p = strings.TrimSpace(p)
+		// Collapse the line breaks and indentation of a wrapped field.
+		p = strings.Join(strings.Fields(p), " ")
 		if p == "" {
 			continue
 		}
+		if lit, ok := bracedWhole(p); ok {
+			// A wholly braced name is a literal (an organization): one family name.
+			out = append(out, schema.Author{Family: strings.TrimSpace(stripBraces(lit))})
+			continue
+		}
+		p = stripBraces(p)
 		// Prefer "Family, Given"
 		if i := strings.Index(p, ","); i >= 0 {
 			fam := strings.TrimSpace(p[:i])
@@ -744,7 +1232,7 @@ func currentWriteSource() string {
 	return writeSource
 }
 
-func nowISO() string { return time.Now().UTC().Format(time.RFC3339) }
+func nowISO() string { return dates.Now().UTC().Format(time.RFC3339) }
 
 func gitUserName() string {
 	cmd := exec.Command("git", "config", "--global", "user.name")
@@ -762,8 +1250,33 @@ func gitUserName() string {
 // GetGitUserName returns the configured git user.name (or "unknown").
 func GetGitUserName() string { return gitUserName() }
 
-// VerifyByID marks a record as verified=true, updates modified and verified_by.
+// recordVerification returns the verification details of a verified record, or nil.
+func recordVerification(r bibRecord) *schema.Verification {
+	if !strings.EqualFold(strings.TrimSpace(r.fields["verified"]), "true") {
+		return nil
+	}
+	v := &schema.Verification{
+		By:     strings.TrimSpace(r.fields["verified_by"]),
+		At:     strings.TrimSpace(r.fields["verified_at"]),
+		Method: strings.TrimSpace(r.fields["verified_method"]),
+	}
+	for _, p := range strings.Split(r.fields["verified_providers"], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			v.Providers = append(v.Providers, p)
+		}
+	}
+	return v
+}
+
+// VerifyByID marks a record as manually verified (see VerifyByIDWithProviders).
 func VerifyByID(id string, by string) error {
+	return VerifyByIDWithProviders(id, by, schema.VerifyManual, nil)
+}
+
+// VerifyByIDWithProviders marks a record as verified=true, updating modified and
+// verified_by/verified_at, and records how it was verified (method) and which
+// providers formed the consensus.
+func VerifyByIDWithProviders(id, by, method string, providers []string) error {
 	id = strings.ToLower(strings.TrimSpace(id))
 	if id == "" {
 		return fmt.Errorf("id is required")
@@ -787,6 +1300,9 @@ func VerifyByID(id string, by string) error {
 		if rid == id {
 			r.fields["verified"] = "true"
 			r.fields["verified_by"] = by
+			r.fields["verified_at"] = now
+			r.fields["verified_method"] = method
+			r.fields["verified_providers"] = strings.Join(providers, ", ")
 			r.fields["modified"] = now
 			if strings.TrimSpace(r.fields["created"]) == "" {
 				r.fields["created"] = now
@@ -820,6 +1336,54 @@ func VerifyByID(id string, by string) error {
 	return os.WriteFile(BibFile, buf.Bytes(), 0o644)
 }
 
+// UnverifyByID reverses VerifyByIDWithProviders: it sets verified=false, clears
+// verified_by and the verification details, and updates modified. An entry that is not
+// verified is an error unless force is set.
+func UnverifyByID(id string, force bool) error {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" {
+		return fmt.Errorf("id is required")
+	}
+	b, err := os.ReadFile(BibFile)
+	if err != nil {
+		return err
+	}
+	records, err := parseBib(string(b))
+	if err != nil {
+		return err
+	}
+	found := false
+	for i := range records {
+		r := &records[i]
+		if strings.ToLower(strings.TrimSpace(r.fields["_id"])) != id {
+			continue
+		}
+		if recordVerification(*r) == nil && !force {
+			return fmt.Errorf("%s is not verified (use --force to reset it anyway)", id)
+		}
+		now := nowISO()
+		r.fields["verified"] = "false"
+		r.fields["verified_by"] = ""
+		delete(r.fields, "verified_at")
+		delete(r.fields, "verified_method")
+		delete(r.fields, "verified_providers")
+		r.fields["modified"] = now
+		if strings.TrimSpace(r.fields["created"]) == "" {
+			r.fields["created"] = now
+		}
+		found = true
+		break
+	}
+	if !found {
+		return fmt.Errorf("id not found: %s", id)
+	}
+	var buf bytes.Buffer
+	for _, r := range records {
+		buf.WriteString(renderRecord(r))
+	}
+	return os.WriteFile(BibFile, buf.Bytes(), 0o644)
+}
+
 // UpdateSourceByID sets the 'source' field for the given id and updates modified.
 func UpdateSourceByID(id string, source string) error {
 	id = strings.ToLower(strings.TrimSpace(id))
@@ -866,6 +1430,40 @@ func UpdateSourceByID(id string, source string) error {
 	return os.WriteFile(BibFile, buf.Bytes(), 0o644)
 }
 
+// RecordTimes holds the bookkeeping timestamps of a library record; zero when absent.
+type RecordTimes struct {
+	Created  time.Time
+	Modified time.Time
+	Verified time.Time
+}
+
+// ReadRecordTimes returns the created, modified, and verified timestamps for each
+// record in BibFile, keyed by lowercase id.
+func ReadRecordTimes() (map[string]RecordTimes, error) {
+	out := map[string]RecordTimes{}
+	b, err := os.ReadFile(BibFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	records, err := parseBib(string(b))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		var rt RecordTimes
+		rt.Created, _ = time.Parse(time.RFC3339, strings.TrimSpace(r.fields["created"]))
+		rt.Modified, _ = time.Parse(time.RFC3339, strings.TrimSpace(r.fields["modified"]))
+		if strings.EqualFold(strings.TrimSpace(r.fields["verified"]), "true") {
+			rt.Verified, _ = time.Parse(time.RFC3339, strings.TrimSpace(r.fields["verified_at"]))
+		}
+		out[strings.ToLower(strings.TrimSpace(r.fields["_id"]))] = rt
+	}
+	return out, nil
+}
+
 // ListUnverified returns entries whose verified field is not true.
 func ListUnverified() ([]schema.Entry, error) {
 	b, err := os.ReadFile(BibFile)
@@ -896,7 +1494,8 @@ func ListUnverified() ([]schema.Entry, error) {
 	return es, nil
 }
 
-// FormatBibLibrary rewrites the entire library with canonical ordering and wrapping.
+// FormatBibLibrary rewrites the entire library with canonical ordering and wrapping,
+// reassigning citation keys when BibKeyFormat is set.
 func FormatBibLibrary(maxWidth int) error {
 	if maxWidth > 0 {
 		lineWrap = maxWidth
@@ -909,6 +1508,7 @@ func FormatBibLibrary(maxWidth int) error {
 	if err != nil {
 		return err
 	}
+	rekeyRecords(records)
 	sort.Slice(records, func(i, j int) bool {
 		if records[i].typ != records[j].typ {
 			return records[i].typ < records[j].typ
@@ -929,3 +1529,106 @@ func FormatBibLibrary(maxWidth int) error {
 	}
 	return os.WriteFile(BibFile, buf.Bytes(), 0o644)
 }
+
+// MergeDuplicates folds duplicate records into keepID: the keeper's keywords are
+// replaced with keywords (its verification is preserved), and the records in drop
+// are removed from BibFile along with any legacy YAML files. It reports whether a
+// legacy file was removed.
+func MergeDuplicates(keepID string, keywords []string, drop []string) (bool, error) {
+	keepID = strings.ToLower(strings.TrimSpace(keepID))
+	b, err := os.ReadFile(BibFile)
+	if err != nil {
+		return false, err
+	}
+	records, err := parseBib(string(b))
+	if err != nil {
+		return false, err
+	}
+	dropSet := map[string]bool{}
+	for _, id := range drop {
+		dropSet[strings.ToLower(strings.TrimSpace(id))] = true
+	}
+	now := nowISO()
+	found := false
+	kept := records[:0]
+	for _, r := range records {
+		rid := strings.ToLower(strings.TrimSpace(r.fields["_id"]))
+		if dropSet[rid] {
+			continue
+		}
+		if rid == keepID {
+			if len(keywords) > 0 {
+				r.fields["keywords"] = strings.Join(keywords, ", ")
+			}
+			r.fields["modified"] = now
+			found = true
+		}
+		kept = append(kept, r)
+	}
+	if !found {
+		return false, fmt.Errorf("id not found: %s", keepID)
+	}
+	var buf bytes.Buffer
+	for _, r := range kept {
+		buf.WriteString(renderRecord(r))
+	}
+	if err := os.WriteFile(BibFile, buf.Bytes(), 0o644); err != nil {
+		return false, err
+	}
+	removed, err := removeLegacyFiles(dropSet)
+	return len(removed) > 0, err
+}
+
+// DeleteEntry removes the record with id from BibFile along with its legacy YAML
+// file, if any, and returns that file's path ("" when the entry lived only in the
+// library).
+func DeleteEntry(id string) (string, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	b, err := os.ReadFile(BibFile)
+	if err != nil {
+		return "", err
+	}
+	records, err := parseBib(string(b))
+	if err != nil {
+		return "", err
+	}
+	found := false
+	var buf bytes.Buffer
+	for _, r := range records {
+		if strings.ToLower(strings.TrimSpace(r.fields["_id"])) == id {
+			found = true
+			continue
+		}
+		buf.WriteString(renderRecord(r))
+	}
+	if !found {
+		return "", fmt.Errorf("id not found: %s", id)
+	}
+	if err := os.WriteFile(BibFile, buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	removed, err := removeLegacyFiles(map[string]bool{id: true})
+	if err != nil || len(removed) == 0 {
+		return "", err
+	}
+	return removed[0], nil
+}
+
+// removeLegacyFiles deletes the legacy YAML files of the (lowercased) ids in drop and
+// returns their paths.
+func removeLegacyFiles(drop map[string]bool) ([]string, error) {
+	files, err := ReadAllYAMLFiles()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, f := range files {
+		if drop[strings.ToLower(f.Entry.ID)] {
+			if err := os.Remove(filepath.FromSlash(f.Path)); err != nil {
+				return removed, err
+			}
+			removed = append(removed, f.Path)
+		}
+	}
+	return removed, nil
+}
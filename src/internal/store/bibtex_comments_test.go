@@ -0,0 +1,66 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestBibCommentsSurviveRewrites(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	id := "00000000-0000-4000-8000-0000000000e1"
+	lib := "% Curated by the systems group.\n% Do not remove.\n@string{ietf = {IETF}}\n" +
+		"@misc{" + strings.ReplaceAll(id, "-", "") + ",\n  title = {Syslog},\n  publisher = ietf,\n  _id = {" + id + "},\n  _type = {website},\n" +
+		"  url = {https://example.com},\n  urldate = {2025-01-01},\n  abstract = {s},\n  keywords = {k},\n}\n"
+	if err := os.MkdirAll(filepath.Dir(BibFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(BibFile, []byte(lib), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	check := func(step string) {
+		t.Helper()
+		b, _ := os.ReadFile(BibFile)
+		s := string(b)
+		if !strings.Contains(s, "% Curated by the systems group.\n% Do not remove.\n@misc{") {
+			t.Fatalf("%s: comment lost or moved:\n%s", step, s)
+		}
+		if !strings.Contains(s, " = {IETF}") {
+			t.Fatalf("%s: macro not expanded:\n%s", step, s)
+		}
+	}
+	if err := FormatBibLibrary(0); err != nil {
+		t.Fatal(err)
+	}
+	check("format")
+	y := 2021
+	other := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Other", Year: &y, Journal: "J"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if err := UpdateBibEntry(other); err != nil {
+		t.Fatal(err)
+	}
+	check("add another")
+	es, err := ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range es {
+		if e.ID == id {
+			e.APA7.Title = "The Syslog Protocol"
+			if err := UpdateBibEntry(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	check("update commented entry")
+	if err := VerifyByID(id, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	check("verify")
+}
@@ -168,3 +168,197 @@ func TestSetWriteSourceAndReplace(t *testing.T) {
 		t.Fatalf("expected single updated record: %s", string(b2))
 	}
 }
+
+func TestAuthorParticlesBracedAndRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Particles", Authors: schema.Authors{{Family: "van der Berg", Given: "J."}, {Family: "Doe", Given: "J."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// Rewrite once more so the stored (braced) value is re-rendered
+	other := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Other"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(other); err != nil {
+		t.Fatalf("write other: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	if !strings.Contains(string(b), "author = {{van der} Berg, J. and Doe, J.}") {
+		t.Fatalf("expected braced particle in bib: %s", string(b))
+	}
+	list, err := ReadAll()
+	if err != nil {
+		t.Fatalf("readall: %v", err)
+	}
+	for _, got := range list {
+		if got.ID == e.ID && (got.APA7.Authors[0].Family != "van der Berg" || got.APA7.Authors[0].Given != "J.") {
+			t.Fatalf("particle did not round-trip: %+v", got.APA7.Authors)
+		}
+	}
+	if s := string(entryToBibTeX(e, bibKeyFor(e))); !strings.Contains(s, "author = {{van der} Berg, J. and Doe, J.}") {
+		t.Fatalf("export missing braced particle: %s", s)
+	}
+}
+
+func TestAudiobookMediumAndNarratorRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{
+		Title: "Listen", Medium: "audiobook",
+		Authors: schema.Authors{{Family: "Reader", Given: "A.", Role: schema.RoleNarrator}},
+	}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	for _, want := range []string{"medium = {audiobook}", "narrator = {Reader, A.}", "note = {Audiobook; narrated by Reader, A.}"} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("missing %q in bib: %s", want, string(b))
+		}
+	}
+	list, err := ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	got := list[0].APA7
+	if got.Medium != "audiobook" || len(got.Authors) != 1 || got.Authors[0].Family != "Reader" || got.Authors[0].Role != schema.RoleNarrator {
+		t.Fatalf("medium/narrator did not round-trip: %+v", got)
+	}
+	if s := string(entryToBibTeX(e, bibKeyFor(e))); !strings.Contains(s, "medium = {audiobook}") || !strings.Contains(s, "narrator = {Reader, A.}") {
+		t.Fatalf("export missing medium/narrator: %s", s)
+	}
+}
+
+func TestIdentifiersRoundTripAndNote(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{
+		Title:       "Preprint",
+		Identifiers: schema.Identifiers{DOI: "10.1/p", ISSN: "1234-5678", PMID: "31415926", ArXiv: "2101.00001"},
+	}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	for _, want := range []string{"issn = {1234-5678}", "pmid = {31415926}", "eprint = {2101.00001}", "archiveprefix = {arXiv}", "note = {PubMed: 31415926; arXiv: 2101.00001}"} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("missing %q in bib: %s", want, string(b))
+		}
+	}
+	list, err := ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	if got := list[0].APA7.Identifiers; got != e.APA7.Identifiers {
+		t.Fatalf("identifiers did not round-trip: %+v", got)
+	}
+	if s := entryToBibTeX(e, bibKeyFor(e)); !strings.Contains(s, "pmid = {31415926}") || !strings.Contains(s, "note = {PubMed: 31415926; arXiv: 2101.00001}") {
+		t.Fatalf("export missing identifiers: %s", s)
+	}
+}
+
+func TestTruncatedAuthorsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{
+		Title: "Big Collaboration", Authors: schema.Authors{{Family: "Aad", Given: "G."}}, AuthorCount: 2932,
+	}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	if !strings.Contains(string(b), "author = {Aad, G. and others}") || !strings.Contains(string(b), "author_count = {2932}") {
+		t.Fatalf("missing truncation markers: %s", b)
+	}
+	list, err := ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	if got := list[0].APA7; len(got.Authors) != 1 || got.AuthorCount != 2932 {
+		t.Fatalf("truncation did not round-trip: %+v", got)
+	}
+	imported, err := ParseBibTeX("@article{x,\n  author = {Doe, J. and others},\n  title = {T},\n}\n")
+	if err != nil || len(imported) != 1 || len(imported[0].APA7.Authors) != 1 || !imported[0].APA7.AuthorsTruncated() {
+		t.Fatalf("imported \"and others\" should mark truncation: %v %+v", err, imported)
+	}
+}
+
+func TestEditorsAndTranslatorsRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Edited Volume", Authors: schema.Authors{
+		{Family: "Doe", Given: "J.", Role: schema.RoleEditor, ORCID: "0000-0002-1825-0097"},
+		{Family: "Poe", Given: "P.", Role: schema.RoleTranslator},
+	}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	s := string(b)
+	if strings.Contains(s, "author =") || !strings.Contains(s, "editor = {Doe, J.}") || !strings.Contains(s, "translator = {Poe, P.}") {
+		t.Fatalf("roles not written as their own fields: %s", s)
+	}
+	list, err := ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	if got := list[0].APA7.Authors; len(got) != 2 || got[0] != e.APA7.Authors[0] || got[1] != e.APA7.Authors[1] {
+		t.Fatalf("roles did not round-trip: %+v", got)
+	}
+}
+
+func TestAuthorLiteralNamesKeepTheirBraces(t *testing.T) {
+	got := parseAuthorsField("{World Health Organization} and {Barnes and Noble} and {von der} Berg, J. and {Doe}, Jane")
+	want := schema.Authors{{Family: "World Health Organization"}, {Family: "Barnes and Noble"}, {Family: "von der Berg", Given: "J."}, {Family: "Doe", Given: "Jane"}}
+	if len(got) != len(want) {
+		t.Fatalf("parse: %+v", got)
+	}
+	for i := range want {
+		if got[i].Family != want[i].Family || got[i].Given != want[i].Given {
+			t.Fatalf("author %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	e := schema.Entry{ID: schema.NewID(), Type: "report", APA7: schema.APA7{Title: "Global Report", Publisher: "WHO", Authors: schema.Authors{{Family: "World Health Organization"}, {Family: "Doe", Given: "J."}}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if s := entryToBibTeX(e, bibKeyFor(e)); !strings.Contains(s, "author = {{World Health Organization} and Doe, J.}") {
+		t.Fatalf("export should brace the literal name: %s", s)
+	}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// A second write re-renders the stored record from its parsed fields.
+	if _, err := WriteEntry(schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "Other"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}); err != nil {
+		t.Fatalf("write other: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	if !strings.Contains(string(b), "author = {{World Health Organization} and Doe, J.}") {
+		t.Fatalf("library lost the literal braces: %s", b)
+	}
+	list, err := ReadAll()
+	if err != nil {
+		t.Fatalf("readall: %v", err)
+	}
+	for _, got := range list {
+		if got.ID == e.ID && (len(got.APA7.Authors) != 2 || got.APA7.Authors[0].Family != "World Health Organization" || got.APA7.Authors[0].Given != "") {
+			t.Fatalf("literal name did not round-trip: %+v", got.APA7.Authors)
+		}
+	}
+}
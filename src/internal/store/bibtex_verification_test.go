@@ -0,0 +1,94 @@
+package store
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestVerifyByIDWithProviders_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000021", Type: "article", APA7: schema.APA7{Title: "T", Journal: "J", Identifiers: schema.Identifiers{DOI: "10.1/x"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	all, err := ReadAll()
+	if err != nil || len(all) != 1 || all[0].Verification != nil {
+		t.Fatalf("unverified entry should have no verification: %v %+v", err, all)
+	}
+	if err := VerifyByIDWithProviders(e.ID, "tester", schema.VerifyConsensus, []string{"doi.org", "web"}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	all, err = ReadAll()
+	if err != nil || len(all) != 1 {
+		t.Fatalf("read: %v", err)
+	}
+	v := all[0].Verification
+	if v == nil || v.By != "tester" || v.At == "" || v.Method != schema.VerifyConsensus || !reflect.DeepEqual(v.Providers, []string{"doi.org", "web"}) {
+		t.Fatalf("unexpected verification: %+v", v)
+	}
+
+	// A later edit clears verification, including the audit details.
+	if err := UpdateBibEntry(all[0]); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	all, _ = ReadAll()
+	if all[0].Verification != nil {
+		t.Fatalf("edit should clear verification: %+v", all[0].Verification)
+	}
+	if err := VerifyByID(e.ID, "tester"); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	all, _ = ReadAll()
+	if v := all[0].Verification; v == nil || v.Method != schema.VerifyManual || len(v.Providers) != 0 {
+		t.Fatalf("unexpected manual verification: %+v", v)
+	}
+}
+
+func TestUnverifyByID(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000022", Type: "book", APA7: schema.APA7{Title: "T"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := UnverifyByID(e.ID, false); err == nil || !strings.Contains(err.Error(), "is not verified") {
+		t.Fatalf("expected not-verified error, got %v", err)
+	}
+	if err := UnverifyByID(e.ID, true); err != nil {
+		t.Fatalf("forced unverify: %v", err)
+	}
+	if err := VerifyByIDWithProviders(e.ID, "tester", schema.VerifyConsensus, []string{"openlibrary"}); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := UnverifyByID(strings.ToUpper(e.ID), false); err != nil {
+		t.Fatalf("unverify: %v", err)
+	}
+	all, _ := ReadAll()
+	if len(all) != 1 || all[0].Verification != nil {
+		t.Fatalf("entry should be unverified: %+v", all)
+	}
+	b, _ := os.ReadFile(BibFile)
+	lib := string(b)
+	for _, gone := range []string{"verified = {true}", "tester", "verified_at", "verified_method", "openlibrary"} {
+		if strings.Contains(lib, gone) {
+			t.Fatalf("library still contains %q:\n%s", gone, lib)
+		}
+	}
+	if !strings.Contains(lib, "verified = {false}") || !strings.Contains(lib, "modified = ") {
+		t.Fatalf("expected verified=false and a modified stamp:\n%s", lib)
+	}
+	if err := UnverifyByID("00000000-0000-4000-8000-000000000099", true); err == nil || !strings.Contains(err.Error(), "id not found") {
+		t.Fatalf("expected id not found, got %v", err)
+	}
+}
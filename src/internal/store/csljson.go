@@ -0,0 +1,218 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"bibliography/src/internal/orcid"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/stringsx"
+)
+
+// cslTypeFor maps an entry type to its CSL item type (the inverse of the importer's map).
+func cslTypeFor(typ string) string {
+	switch strings.ToLower(strings.TrimSpace(typ)) {
+	case "article":
+		return "article-journal"
+	case "book":
+		return "book"
+	case "website":
+		return "webpage"
+	case "rfc", "standard":
+		return "standard"
+	case "patent":
+		return "patent"
+	case "movie":
+		return "motion_picture"
+	case "video":
+		return "broadcast"
+	case "song":
+		return "song"
+	case "podcast":
+		return "broadcast"
+	case "report":
+		return "report"
+	case "dataset":
+		return "dataset"
+	case "software":
+		return "software"
+	default:
+		return "document"
+	}
+}
+
+// cslName is a CSL name: family/given for people, literal for single-part names.
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+	ORCID   string `json:"ORCID,omitempty"`
+}
+
+// cslDate holds CSL date-parts ([[year, month, day]]).
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// cslItem is one CSL-JSON item as written by EntriesToCSLJSON.
+type cslItem struct {
+	ID             string            `json:"id"`
+	Type           string            `json:"type"`
+	Title          string            `json:"title"`
+	Author         []cslName         `json:"author,omitempty"`
+	Editor         []cslName         `json:"editor,omitempty"`
+	Translator     []cslName         `json:"translator,omitempty"`
+	Narrator       []cslName         `json:"narrator,omitempty"`
+	Issued         *cslDate          `json:"issued,omitempty"`
+	ContainerTitle string            `json:"container-title,omitempty"`
+	Volume         string            `json:"volume,omitempty"`
+	Issue          string            `json:"issue,omitempty"`
+	Page           string            `json:"page,omitempty"`
+	Number         string            `json:"number,omitempty"`
+	Edition        string            `json:"edition,omitempty"`
+	Publisher      string            `json:"publisher,omitempty"`
+	Authority      string            `json:"authority,omitempty"`
+	PublisherPlace string            `json:"publisher-place,omitempty"`
+	DOI            string            `json:"DOI,omitempty"`
+	ISBN           string            `json:"ISBN,omitempty"`
+	ISSN           string            `json:"ISSN,omitempty"`
+	PMID           string            `json:"PMID,omitempty"`
+	Custom         map[string]string `json:"custom,omitempty"`
+	URL            string            `json:"URL,omitempty"`
+	Language       string            `json:"language,omitempty"`
+	Accessed       *cslDate          `json:"accessed,omitempty"`
+	Abstract       string            `json:"abstract,omitempty"`
+	Keyword        string            `json:"keyword,omitempty"`
+}
+
+// ExportCSLJSON reads all entries (see ReadAll: data/library.bib, else the legacy
+// YAML tree) and writes them to target as a CSL-JSON array, returning the number of
+// items written.
+func ExportCSLJSON(target string) (int, error) {
+	entries, err := ReadAll()
+	if err != nil {
+		return 0, err
+	}
+	b, err := EntriesToCSLJSON(entries)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, fmt.Errorf("cannot create output directory for %s: %w", target, err)
+	}
+	if err := os.WriteFile(target, b, 0o644); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// EntriesToCSLJSON renders entries as an indented CSL-JSON array in the same order as
+// EntriesToBibTeX.
+func EntriesToCSLJSON(entries []schema.Entry) ([]byte, error) {
+	sortForExport(entries)
+	items := make([]cslItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, entryToCSL(e))
+	}
+	b, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// entryToCSL converts a schema.Entry into a CSL item. A standard's body is written as
+// authority rather than publisher, which is where the CSL-JSON importer reads it.
+func entryToCSL(e schema.Entry) cslItem {
+	a := e.APA7
+	it := cslItem{
+		ID:             e.ID,
+		Type:           cslTypeFor(e.Type),
+		Title:          strings.TrimSpace(a.Title),
+		ContainerTitle: strings.TrimSpace(stringsx.FirstNonEmpty(a.Journal, a.ContainerTitle)),
+		Volume:         strings.TrimSpace(a.Volume),
+		Issue:          strings.TrimSpace(a.Issue),
+		Page:           strings.TrimSpace(a.Pages),
+		Number:         strings.TrimSpace(stringsx.FirstNonEmpty(a.Designation, a.PatentNumber)),
+		Edition:        strings.TrimSpace(a.Edition),
+		Publisher:      strings.TrimSpace(stringsx.FirstNonEmpty(a.Publisher, a.StandardsBody)),
+		PublisherPlace: strings.TrimSpace(a.PublisherLocation),
+		DOI:            strings.TrimSpace(a.DOI),
+		ISBN:           strings.TrimSpace(a.ISBN),
+		ISSN:           strings.TrimSpace(a.ISSN),
+		PMID:           strings.TrimSpace(a.PMID),
+		URL:            strings.TrimSpace(a.URL),
+		Language:       strings.TrimSpace(a.Language),
+		Accessed:       cslDateOf(a.Accessed, nil),
+		Abstract:       strings.TrimSpace(e.Annotation.Summary),
+		Keyword:        strings.Join(e.Annotation.Keywords, ", "),
+	}
+	for _, au := range a.Authors {
+		fam, giv := strings.TrimSpace(au.Family), strings.TrimSpace(au.Given)
+		var n cslName
+		switch {
+		case fam == "":
+			continue
+		case giv == "":
+			n = cslName{Literal: fam, ORCID: orcidURL(au.ORCID)}
+		default:
+			n = cslName{Family: fam, Given: giv, ORCID: orcidURL(au.ORCID)}
+		}
+		switch au.RoleOf() {
+		case schema.RoleEditor:
+			it.Editor = append(it.Editor, n)
+		case schema.RoleTranslator:
+			it.Translator = append(it.Translator, n)
+		case schema.RoleNarrator:
+			it.Narrator = append(it.Narrator, n)
+		default:
+			it.Author = append(it.Author, n)
+		}
+	}
+	// CSL has no variables for these accession ids; they go in the custom object
+	for k, v := range map[string]string{"arxiv": a.ArXiv, "bibcode": a.Bibcode, "isrc": a.ISRC} {
+		if v = strings.TrimSpace(v); v != "" {
+			if it.Custom == nil {
+				it.Custom = map[string]string{}
+			}
+			it.Custom[k] = v
+		}
+	}
+	if strings.EqualFold(e.Type, "standard") {
+		it.Authority = strings.TrimSpace(a.StandardsBody)
+		it.Publisher = strings.TrimSpace(a.Publisher)
+	}
+	it.Issued = cslDateOf(a.Date, a.Year)
+	return it
+}
+
+// orcidURL returns the orcid.org URL CSL-JSON uses for an iD ("" when there is none).
+func orcidURL(id string) string {
+	if id = strings.TrimSpace(id); id == "" {
+		return ""
+	}
+	return orcid.URL(id)
+}
+
+// cslDateOf builds date-parts from a YYYY[-MM[-DD]] date, falling back to year alone.
+func cslDateOf(date string, year *int) *cslDate {
+	var parts []int
+	for _, p := range strings.SplitN(strings.TrimSpace(date), "-", 3) {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 {
+			break
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) == 0 && year != nil && *year > 0 {
+		parts = []int{*year}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return &cslDate{DateParts: [][]int{parts}}
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestEntriesToCSLJSON(t *testing.T) {
+	y := 2021
+	entries := []schema.Entry{
+		{
+			ID:   "00000000-0000-4000-8000-000000000091",
+			Type: "article",
+			APA7: schema.APA7{
+				Authors: schema.Authors{{Family: "Doe", Given: "J."}, {Family: "Example Consortium"}},
+				Year:    &y, Date: "2021-03", Title: "Measuring things", Journal: "Journal of Tests",
+				Volume: "12", Issue: "3", Pages: "101-118", URL: "https://doi.org/10.1/x", Accessed: "2024-05-06",
+				Identifiers: schema.Identifiers{DOI: "10.1/x"},
+			},
+			Annotation: schema.Annotation{Summary: "A study.", Keywords: []string{"article", "testing"}},
+		},
+		{
+			ID:         "00000000-0000-4000-8000-000000000092",
+			Type:       "rfc",
+			APA7:       schema.APA7{Title: "An RFC", Year: &y, ContainerTitle: "RFC 9000"},
+			Annotation: schema.Annotation{Summary: "s", Keywords: []string{"rfc"}},
+		},
+	}
+	b, err := EntriesToCSLJSON(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []map[string]any
+	if err := json.Unmarshal(b, &items); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, b)
+	}
+	if len(items) != 2 {
+		t.Fatalf("want 2 items, got %d", len(items))
+	}
+	got, _ := json.Marshal(items[0])
+	want := `{"DOI":"10.1/x","URL":"https://doi.org/10.1/x","abstract":"A study.","accessed":{"date-parts":[[2024,5,6]]},` +
+		`"author":[{"family":"Doe","given":"J."},{"literal":"Example Consortium"}],"container-title":"Journal of Tests",` +
+		`"id":"00000000-0000-4000-8000-000000000091","issue":"3","issued":{"date-parts":[[2021,3]]},"keyword":"article, testing",` +
+		`"page":"101-118","title":"Measuring things","type":"article-journal","volume":"12"}`
+	if string(got) != want {
+		t.Fatalf("article item:\n%s\nwant:\n%s", got, want)
+	}
+	if items[1]["type"] != "standard" || items[1]["container-title"] != "RFC 9000" {
+		t.Fatalf("rfc item: %v", items[1])
+	}
+	if dp, _ := json.Marshal(items[1]["issued"]); string(dp) != `{"date-parts":[[2021]]}` {
+		t.Fatalf("year-only issued: %s", dp)
+	}
+}
+
+func TestExportCSLJSON_ReadsLibrary(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	e := schema.Entry{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "Trial", Journal: "J", Identifiers: schema.Identifiers{PMID: "31415926"}},
+		Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	n, err := ExportCSLJSON("refs.json")
+	if err != nil || n != 1 {
+		t.Fatalf("export from library.bib: %d %v", n, err)
+	}
+	var items []map[string]any
+	b, _ := os.ReadFile("refs.json")
+	if err := json.Unmarshal(b, &items); err != nil || len(items) != 1 || items[0]["PMID"] != "31415926" {
+		t.Fatalf("unexpected CSL-JSON: %v\n%s", err, b)
+	}
+}
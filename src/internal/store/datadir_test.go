@@ -0,0 +1,71 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestSetDataDirRedirectsReadsWritesAndIndexes(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old); SetDataDir("") })
+	_ = os.Chdir(dir)
+	lib := filepath.Join(dir, "elsewhere", "lib")
+	SetDataDir(lib)
+	if BibFile != filepath.ToSlash(filepath.Join(lib, "library.bib")) || KeywordsJSON != filepath.ToSlash(filepath.Join(lib, "metadata", "keywords.json")) {
+		t.Fatalf("paths not derived from the base: %s %s", BibFile, KeywordsJSON)
+	}
+	e := schema.Entry{ID: "00000000-0000-4000-8000-0000000000f1", Type: "book", APA7: schema.APA7{Title: "Moved"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadAll()
+	if err != nil || len(entries) != 1 || entries[0].ID != e.ID {
+		t.Fatalf("read from data dir: %v %+v", err, entries)
+	}
+	paths, err := BuildAllIndexes(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil || filepath.Dir(p) != filepath.Join(lib, "metadata") {
+			t.Fatalf("index %s not written under the data dir: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data")); err == nil {
+		t.Fatalf("nothing should be written to ./data")
+	}
+
+	SetDataDir("")
+	if BibFile != "data/library.bib" || FlatDir != "data/flat" {
+		t.Fatalf("empty dir should restore the default: %s %s", BibFile, FlatDir)
+	}
+	if entries, err := ReadAll(); err != nil || len(entries) != 0 {
+		t.Fatalf("default dir should be empty: %v %d", err, len(entries))
+	}
+}
+
+func TestFindRepoRootFromNestedDirectory(t *testing.T) {
+	root := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	nested := filepath.Join(root, "a", "b", "c")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chdir(nested)
+	if got, ok := FindRepoRoot(); ok {
+		t.Fatalf("no data directory yet, found %s", got)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "data", "citations"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := FindRepoRoot()
+	want, _ := filepath.EvalSymlinks(root)
+	if gotReal, _ := filepath.EvalSymlinks(got); !ok || gotReal != want {
+		t.Fatalf("FindRepoRoot = %s, %v; want %s", got, ok, want)
+	}
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestBuildDecadeIndex(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	year := func(y int) *int { return &y }
+	entries := []schema.Entry{
+		{ID: "00000000-0000-4000-8000-000000000111", Type: "book", APA7: schema.APA7{Title: "A", Year: year(1990)}},
+		{ID: "00000000-0000-4000-8000-000000000112", Type: "book", APA7: schema.APA7{Title: "B", Year: year(1999)}},
+		{ID: "00000000-0000-4000-8000-000000000113", Type: "book", APA7: schema.APA7{Title: "C", Year: year(2004)}},
+		{ID: "00000000-0000-4000-8000-000000000114", Type: "website", APA7: schema.APA7{Title: "D", Date: "2021-05-06"}},
+		{ID: "00000000-0000-4000-8000-000000000115", Type: "book", APA7: schema.APA7{Title: "E"}},
+	}
+	out, err := BuildDecadeIndex(entries)
+	if err != nil || out != DecadesJSON {
+		t.Fatalf("BuildDecadeIndex = %q, %v", out, err)
+	}
+	index, err := ReadIndex(DecadesJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index) != 3 {
+		t.Fatalf("want 3 decades, got %v", index)
+	}
+	ids := func(decade string) string {
+		var out []string
+		for _, p := range index[decade] {
+			out = append(out, IDFromIndexPath(p)[len("00000000-0000-4000-8000-000000000"):])
+		}
+		return strings.Join(out, ",")
+	}
+	if got := ids("1990s"); got != "111,112" {
+		t.Fatalf("1990s = %s", got)
+	}
+	if got := ids("2000s"); got != "113" {
+		t.Fatalf("2000s = %s", got)
+	}
+	if got := ids("2020s"); got != "114" {
+		t.Fatalf("2020s falls back to the date year, got %s", got)
+	}
+	if Decade(0) != "" || Decade(1889) != "1880s" {
+		t.Fatalf("Decade: %q %q", Decade(0), Decade(1889))
+	}
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"os"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestFindByDOIAndISBN(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	if _, found, err := FindByDOI("10.1234/x"); err != nil || found {
+		t.Fatalf("empty library: %v %v", found, err)
+	}
+	a := schema.Entry{ID: "00000000-0000-4000-8000-0000000000a1", Type: "article", APA7: schema.APA7{Title: "T", Identifiers: schema.Identifiers{DOI: "10.1234/X"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	b := schema.Entry{ID: "00000000-0000-4000-8000-0000000000a2", Type: "book", APA7: schema.APA7{Title: "B", Identifiers: schema.Identifiers{ISBN: "0-13-235088-2"}}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	for _, e := range []schema.Entry{a, b} {
+		if _, err := WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, q := range []string{"10.1234/x", "doi:10.1234/X", "https://doi.org/10.1234/x"} {
+		if got, found, err := FindByDOI(q); err != nil || !found || got.ID != a.ID {
+			t.Fatalf("FindByDOI(%q): %q %v %v", q, got.ID, found, err)
+		}
+	}
+	if got, found, err := FindByISBN("0132350882"); err != nil || !found || got.ID != b.ID {
+		t.Fatalf("FindByISBN: %q %v %v", got.ID, found, err)
+	}
+	// ISBN-10 and ISBN-13 forms of one book are the same identity.
+	if got, found, err := FindByISBN("978-0-13-235088-4"); err != nil || !found || got.ID != b.ID {
+		t.Fatalf("FindByISBN by ISBN-13: %q %v %v", got.ID, found, err)
+	}
+	if _, found, _ := FindByISBN(""); found {
+		t.Fatalf("empty isbn should not match")
+	}
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"bibliography/src/internal/schema"
+)
+
+// FlatDir holds the optional flat view: one <id>.yaml per entry (set by SetDataDir).
+var FlatDir string
+
+// Flat view modes: symlink to the type-segmented file, or copy it.
+const (
+	FlatSymlink = "symlink"
+	FlatCopy    = "copy"
+)
+
+// IsValidFlatMode reports whether mode is a supported flat-view mode.
+func IsValidFlatMode(mode string) bool { return mode == FlatSymlink || mode == FlatCopy }
+
+// symlink is swappable so tests can simulate platforms without symlink support.
+var symlink = os.Symlink
+
+// FlatViewResult summarizes a BuildFlatView run.
+type FlatViewResult struct {
+	Linked  int // symlinks created
+	Copied  int // files copied or generated
+	Removed int // stale files removed for deleted entries
+}
+
+// BuildFlatView (re)creates FlatDir/<id>.yaml for every entry. With FlatSymlink each
+// file is a relative symlink to the entry's type-segmented YAML under CitationsDir,
+// falling back to a copy where symlinks are unavailable; FlatCopy always copies.
+// Entries that exist only in the BibTeX library are written out as YAML (see
+// marshalYAML). Files for entries that no longer exist are removed.
+func BuildFlatView(entries []schema.Entry, mode string) (FlatViewResult, error) {
+	var res FlatViewResult
+	if !IsValidFlatMode(mode) {
+		return res, fmt.Errorf("invalid flat view mode %q (want %s or %s)", mode, FlatSymlink, FlatCopy)
+	}
+	if err := os.MkdirAll(FlatDir, 0o755); err != nil {
+		return res, err
+	}
+	files, err := ReadAllYAMLFiles()
+	if err != nil {
+		return res, err
+	}
+	sources := map[string]string{}
+	for _, f := range files {
+		sources[f.Entry.ID] = filepath.FromSlash(f.Path)
+	}
+	keep := map[string]bool{}
+	for _, e := range entries {
+		name := e.ID + ".yaml"
+		keep[name] = true
+		target := filepath.Join(FlatDir, name)
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return res, err
+		}
+		src, ok := sources[e.ID]
+		if !ok {
+			if err := os.WriteFile(target, marshalYAML(e), 0o644); err != nil {
+				return res, err
+			}
+			res.Copied++
+			continue
+		}
+		if mode == FlatSymlink {
+			rel, err := filepath.Rel(FlatDir, src)
+			if err != nil {
+				return res, err
+			}
+			if err := symlink(rel, target); err == nil {
+				res.Linked++
+				continue
+			}
+		}
+		b, err := os.ReadFile(src)
+		if err != nil {
+			return res, err
+		}
+		if err := os.WriteFile(target, b, 0o644); err != nil {
+			return res, err
+		}
+		res.Copied++
+	}
+	existing, err := os.ReadDir(FlatDir)
+	if err != nil {
+		return res, err
+	}
+	var stale []string
+	for _, d := range existing {
+		if strings.HasSuffix(d.Name(), ".yaml") && !keep[d.Name()] {
+			stale = append(stale, d.Name())
+		}
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		if err := os.Remove(filepath.Join(FlatDir, name)); err != nil {
+			return res, err
+		}
+		res.Removed++
+	}
+	return res, nil
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestBuildFlatView_FallsBackToCopy(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	oldLink := symlink
+	t.Cleanup(func() { symlink = oldLink })
+	symlink = func(string, string) error { return errors.New("symlinks unsupported") }
+
+	e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "T"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	src := filepath.Join(CitationsDir, "books", e.ID+".yaml")
+	if err := os.MkdirAll(filepath.Dir(src), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(e)
+	if err := os.WriteFile(src, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := BuildFlatView([]schema.Entry{e}, FlatSymlink)
+	if err != nil || res.Linked != 0 || res.Copied != 1 {
+		t.Fatalf("expected a copy fallback: %+v %v", res, err)
+	}
+	if _, err := BuildFlatView(nil, "hardlink"); err == nil {
+		t.Fatalf("expected invalid mode error")
+	}
+}
+
+func TestBuildFlatView_LibraryEntriesAreYAML(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	y := 2020
+	e := schema.Entry{ID: "00000000-0000-4000-8000-0000000000c1", Type: "book", APA7: schema.APA7{
+		Title: "Yes: \"No\"", Year: &y, Identifiers: schema.Identifiers{ISBN: "9780306406157"},
+		Authors: schema.Authors{{Family: "Doe", Given: "J."}, {Family: "Roe", Role: schema.RoleEditor}},
+	}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book", "no"}}}
+	res, err := BuildFlatView([]schema.Entry{e}, FlatSymlink)
+	if err != nil || res.Copied != 1 {
+		t.Fatalf("build: %+v %v", res, err)
+	}
+	b, err := os.ReadFile(filepath.Join(FlatDir, e.ID+".yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `id: "00000000-0000-4000-8000-0000000000c1"
+type: "book"
+apa7:
+  authors:
+    - family: "Doe"
+      given: "J."
+    - family: "Roe"
+      role: "editor"
+  year: 2020
+  title: "Yes: \"No\""
+  isbn: "9780306406157"
+annotation:
+  summary: "s"
+  keywords:
+    - "book"
+    - "no"
+`
+	if string(b) != want {
+		t.Fatalf("flat file:\n%s\nwant:\n%s", b, want)
+	}
+}
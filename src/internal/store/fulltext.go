@@ -0,0 +1,113 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"bibliography/src/internal/schema"
+)
+
+// Posting records that an entry (by index path) contains a token TF times.
+type Posting struct {
+	Path string `json:"path"`
+	TF   int    `json:"tf"`
+}
+
+// FullTextIndex maps a token to the entries containing it, sorted by path. Tokens are
+// the letter/digit runs of each entry's lowercased JSON record, so every field that
+// search matches (title, summary, keywords, the whole record) is covered.
+type FullTextIndex map[string][]Posting
+
+// BuildFullTextIndex writes data/metadata/fulltext.json, the inverted index search
+// uses to narrow title, summary, keyword, and full-record queries.
+func BuildFullTextIndex(entries []schema.Entry) (string, error) {
+	return writeIndex(FullTextJSON, fullTextIndex(entries))
+}
+
+func fullTextIndex(entries []schema.Entry) FullTextIndex {
+	index := FullTextIndex{}
+	for _, e := range entries {
+		b, _ := json.Marshal(e)
+		tf := map[string]int{}
+		for _, tok := range FullTextTokens(strings.ToLower(string(b))) {
+			tf[tok]++
+		}
+		for tok, n := range tf {
+			index[tok] = append(index[tok], Posting{Path: entryPath(e), TF: n})
+		}
+	}
+	for _, ps := range index {
+		sort.Slice(ps, func(i, j int) bool { return ps[i].Path < ps[j].Path })
+	}
+	return index
+}
+
+// FullTextTokens splits s into its runs of letters and digits.
+func FullTextTokens(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+// ReadFullTextIndex loads data/metadata/fulltext.json. It returns nil (and no error)
+// when the index is missing or older than the library, so callers fall back to
+// scanning entries rather than miss recent edits.
+func ReadFullTextIndex() (FullTextIndex, error) {
+	ixInfo, err := os.Stat(FullTextJSON)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bibInfo, err := os.Stat(BibFile); err != nil || bibInfo.ModTime().After(ixInfo.ModTime()) {
+		return nil, nil
+	}
+	b, err := os.ReadFile(FullTextJSON)
+	if err != nil {
+		return nil, err
+	}
+	var index FullTextIndex
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, fmt.Errorf("invalid index %s: %w", FullTextJSON, err)
+	}
+	return index, nil
+}
+
+// Candidates returns the (lowercased) ids of entries whose lowercased record may
+// contain any whitespace-separated term of q as a substring: a superset of the
+// entries a live substring scan would match. ok is false when the index cannot narrow
+// q, i.e. some term has no letters or digits.
+func (ix FullTextIndex) Candidates(q string) (map[string]bool, bool) {
+	terms := strings.Fields(strings.ToLower(q))
+	if ix == nil || len(terms) == 0 {
+		return nil, false
+	}
+	out := map[string]bool{}
+	for _, t := range terms {
+		// A match of t lies within the record, so each letter/digit run of t lies
+		// within one token; the longest run is the most selective.
+		piece := ""
+		for _, p := range FullTextTokens(t) {
+			if len(p) > len(piece) {
+				piece = p
+			}
+		}
+		if piece == "" {
+			return nil, false
+		}
+		for tok, ps := range ix {
+			if !strings.Contains(tok, piece) {
+				continue
+			}
+			for _, p := range ps {
+				out[strings.ToLower(IDFromIndexPath(p.Path))] = true
+			}
+		}
+	}
+	return out, true
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"os"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestFullTextIndex(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	a := schema.Entry{ID: "00000000-0000-4000-8000-000000000131", Type: "book", APA7: schema.APA7{Title: "Graph Graph Theory"}, Annotation: schema.Annotation{Summary: "graphs", Keywords: []string{"math"}}}
+	b := schema.Entry{ID: "00000000-0000-4000-8000-000000000132", Type: "book", APA7: schema.APA7{Title: "C++ Primer"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"c++"}}}
+	ix := fullTextIndex([]schema.Entry{b, a})
+	if ps := ix["graph"]; len(ps) != 1 || ps[0].Path != entryPath(a) || ps[0].TF != 2 {
+		t.Fatalf("graph postings = %+v", ps)
+	}
+	if ps := ix["book"]; len(ps) != 2 || ps[0].Path > ps[1].Path {
+		t.Fatalf("postings should be sorted by path: %+v", ps)
+	}
+	cands, ok := ix.Candidates("raph")
+	if !ok || len(cands) != 1 || !cands[a.ID] {
+		t.Fatalf("substring candidates = %v %v", cands, ok)
+	}
+	if cands, ok = ix.Candidates("c++ theory"); !ok || len(cands) != 2 {
+		t.Fatalf("terms are OR-ed: %v %v", cands, ok)
+	}
+	if _, ok = ix.Candidates("++"); ok {
+		t.Fatalf("a term without letters or digits cannot be narrowed")
+	}
+	if got, err := ReadFullTextIndex(); got != nil || err != nil {
+		t.Fatalf("missing index should read as nil: %v %v", got, err)
+	}
+}
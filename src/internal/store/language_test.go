@@ -0,0 +1,53 @@
+package store
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestBuildLanguageIndex(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	entries := []schema.Entry{
+		{ID: "00000000-0000-4000-8000-000000000121", Type: "book", APA7: schema.APA7{Title: "A", Language: "de"}},
+		{ID: "00000000-0000-4000-8000-000000000122", Type: "book", APA7: schema.APA7{Title: "B", Language: "fr"}},
+		{ID: "00000000-0000-4000-8000-000000000123", Type: "book", APA7: schema.APA7{Title: "C", Language: "de"}},
+		{ID: "00000000-0000-4000-8000-000000000124", Type: "book", APA7: schema.APA7{Title: "D"}},
+	}
+	if out, err := BuildLanguageIndex(entries); err != nil || out != LanguagesJSON {
+		t.Fatalf("BuildLanguageIndex = %q, %v", out, err)
+	}
+	index, err := ReadIndex(LanguagesJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index) != 2 || len(index["de"]) != 2 || len(index["fr"]) != 1 {
+		t.Fatalf("index = %v", index)
+	}
+	if IDFromIndexPath(index["de"][0]) != entries[0].ID || IDFromIndexPath(index["de"][1]) != entries[2].ID {
+		t.Fatalf("de = %v", index["de"])
+	}
+}
+
+func TestBibTeXLanguageRoundTrip(t *testing.T) {
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000125", Type: "book",
+		APA7:       schema.APA7{Title: "Über Dinge", Publisher: "Verlag", Language: "de", Authors: schema.Authors{{Family: "Müller", Given: "K."}}},
+		Annotation: schema.Annotation{Summary: "S", Keywords: []string{"k"}}}
+	bib := entryToBibTeX(e, bibKeyFor(e))
+	if !strings.Contains(bib, "language = {de}") {
+		t.Fatalf("missing language field:\n%s", bib)
+	}
+	records, err := parseBib(bib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := bibToEntries(records)
+	if len(got) != 1 || got[0].APA7.Language != "de" {
+		t.Fatalf("round trip: %+v", got)
+	}
+}
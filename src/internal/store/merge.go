@@ -0,0 +1,154 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bibliography/src/internal/schema"
+)
+
+// Merge preferences for fields set differently in both entries.
+const (
+	PreferInto = "into"
+	PreferFrom = "from"
+)
+
+// FieldConflict is a field that holds different non-empty values in the two entries.
+type FieldConflict struct {
+	Field string
+	Into  string
+	From  string
+}
+
+// mergeField is one mergeable field: its name, how it reads as text (empty when
+// unset), and how to copy it from another entry.
+type mergeField struct {
+	name string
+	text func(e *schema.Entry) string
+	take func(dst, src *schema.Entry)
+}
+
+// stringField adapts a string field of APA7 to mergeField.
+func stringField(name string, f func(a *schema.APA7) *string) mergeField {
+	return mergeField{
+		name: name,
+		text: func(e *schema.Entry) string { return strings.TrimSpace(*f(&e.APA7)) },
+		take: func(dst, src *schema.Entry) { *f(&dst.APA7) = *f(&src.APA7) },
+	}
+}
+
+// mergeFields lists the fields MergeEntry combines, by their YAML names.
+var mergeFields = []mergeField{
+	{
+		name: "type",
+		text: func(e *schema.Entry) string { return strings.TrimSpace(e.Type) },
+		take: func(dst, src *schema.Entry) { dst.Type = src.Type },
+	},
+	{
+		name: "authors",
+		text: func(e *schema.Entry) string {
+			s := authorField(e.APA7)
+			for _, role := range []string{schema.RoleEditor, schema.RoleTranslator, schema.RoleNarrator} {
+				if v := formatAuthors(e.APA7.Authors.WithRole(role)); v != "" {
+					s += "; " + role + ": " + v
+				}
+			}
+			return strings.TrimPrefix(s, "; ")
+		},
+		take: func(dst, src *schema.Entry) {
+			dst.APA7.Authors, dst.APA7.AuthorCount = src.APA7.Authors, src.APA7.AuthorCount
+		},
+	},
+	{
+		name: "year",
+		text: func(e *schema.Entry) string {
+			if e.APA7.Year == nil {
+				return ""
+			}
+			return fmt.Sprint(*e.APA7.Year)
+		},
+		take: func(dst, src *schema.Entry) { dst.APA7.Year = src.APA7.Year },
+	},
+	stringField("date", func(a *schema.APA7) *string { return &a.Date }),
+	stringField("title", func(a *schema.APA7) *string { return &a.Title }),
+	stringField("container_title", func(a *schema.APA7) *string { return &a.ContainerTitle }),
+	stringField("edition", func(a *schema.APA7) *string { return &a.Edition }),
+	stringField("publisher", func(a *schema.APA7) *string { return &a.Publisher }),
+	stringField("publisher_location", func(a *schema.APA7) *string { return &a.PublisherLocation }),
+	stringField("journal", func(a *schema.APA7) *string { return &a.Journal }),
+	stringField("volume", func(a *schema.APA7) *string { return &a.Volume }),
+	stringField("issue", func(a *schema.APA7) *string { return &a.Issue }),
+	stringField("pages", func(a *schema.APA7) *string { return &a.Pages }),
+	stringField("patent_number", func(a *schema.APA7) *string { return &a.PatentNumber }),
+	stringField("designation", func(a *schema.APA7) *string { return &a.Designation }),
+	stringField("standards_body", func(a *schema.APA7) *string { return &a.StandardsBody }),
+	stringField("medium", func(a *schema.APA7) *string { return &a.Medium }),
+	stringField("url", func(a *schema.APA7) *string { return &a.URL }),
+	stringField("bibtex_url", func(a *schema.APA7) *string { return &a.BibTeXURL }),
+	stringField("accessed", func(a *schema.APA7) *string { return &a.Accessed }),
+	stringField("archived_url", func(a *schema.APA7) *string { return &a.ArchivedURL }),
+	stringField("doi", func(a *schema.APA7) *string { return &a.DOI }),
+	stringField("isbn", func(a *schema.APA7) *string { return &a.ISBN }),
+	stringField("issn", func(a *schema.APA7) *string { return &a.ISSN }),
+	stringField("pmid", func(a *schema.APA7) *string { return &a.PMID }),
+	stringField("arxiv", func(a *schema.APA7) *string { return &a.ArXiv }),
+	stringField("bibcode", func(a *schema.APA7) *string { return &a.Bibcode }),
+	stringField("isrc", func(a *schema.APA7) *string { return &a.ISRC }),
+	{
+		name: "relations",
+		text: func(e *schema.Entry) string {
+			var parts []string
+			for _, rel := range e.APA7.Relations.List() {
+				parts = append(parts, rel.Label+": "+strings.Join(rel.Docs, ", "))
+			}
+			return strings.Join(parts, "; ")
+		},
+		take: func(dst, src *schema.Entry) { dst.APA7.Relations = src.APA7.Relations },
+	},
+}
+
+// MergeEntry combines from into into, keeping into's id: empty fields of into are
+// filled from from, keywords are unioned, and the longer summary wins. Fields set to
+// different values in both are returned as conflicts and resolved by prefer
+// (PreferInto keeps into's value, PreferFrom takes from's); with any other prefer
+// they keep into's value, and callers should refuse to write while conflicts remain.
+func MergeEntry(into, from schema.Entry, prefer string) (schema.Entry, []FieldConflict) {
+	merged := into
+	var conflicts []FieldConflict
+	for _, f := range mergeFields {
+		have, other := f.text(&into), f.text(&from)
+		switch {
+		case other == "" || have == other:
+		case have == "":
+			f.take(&merged, &from)
+		default:
+			conflicts = append(conflicts, FieldConflict{Field: f.name, Into: have, From: other})
+			if prefer == PreferFrom {
+				f.take(&merged, &from)
+			}
+		}
+	}
+	if len(strings.TrimSpace(from.Annotation.Summary)) > len(strings.TrimSpace(into.Annotation.Summary)) {
+		merged.Annotation.Summary = from.Annotation.Summary
+	}
+	merged.Annotation.Keywords = unionSorted(into.Annotation.Keywords, from.Annotation.Keywords)
+	return merged, conflicts
+}
+
+// unionSorted returns the lowercased, de-duplicated, sorted union of keyword lists.
+func unionSorted(lists ...[]string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, l := range lists {
+		for _, k := range l {
+			k = strings.ToLower(strings.TrimSpace(k))
+			if k != "" && !seen[k] {
+				seen[k] = true
+				out = append(out, k)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func mergeFixtures() (schema.Entry, schema.Entry) {
+	y := 2020
+	into := schema.Entry{ID: "00000000-0000-4000-8000-0000000000a1", Type: "article",
+		APA7:       schema.APA7{Title: "Go Concurrency", Journal: "J"},
+		Annotation: schema.Annotation{Summary: "short", Keywords: []string{"Go", "concurrency"}}}
+	from := schema.Entry{ID: "00000000-0000-4000-8000-0000000000a2", Type: "article",
+		APA7: schema.APA7{Title: "Go Concurrency", Year: &y, Volume: "3", Pages: "1-10",
+			Authors: schema.Authors{{Family: "Doe", Given: "J."}}, Identifiers: schema.Identifiers{DOI: "10.1234/go"}},
+		Annotation: schema.Annotation{Summary: "a longer summary", Keywords: []string{"go", "channels"}}}
+	return into, from
+}
+
+func TestMergeEntryFillsEmptyFields(t *testing.T) {
+	into, from := mergeFixtures()
+	got, conflicts := MergeEntry(into, from, "")
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+	if got.ID != into.ID || got.APA7.Journal != "J" {
+		t.Fatalf("target fields lost: %+v", got)
+	}
+	if got.APA7.Year == nil || *got.APA7.Year != 2020 || got.APA7.Volume != "3" || got.APA7.Pages != "1-10" || got.APA7.DOI != "10.1234/go" {
+		t.Fatalf("empty fields not filled: %+v", got.APA7)
+	}
+	if len(got.APA7.Authors) != 1 || got.APA7.Authors[0].Family != "Doe" {
+		t.Fatalf("authors not filled: %+v", got.APA7.Authors)
+	}
+}
+
+func TestMergeEntryUnionsKeywordsAndKeepsLongerSummary(t *testing.T) {
+	into, from := mergeFixtures()
+	got, _ := MergeEntry(into, from, "")
+	if want := []string{"channels", "concurrency", "go"}; !reflect.DeepEqual(got.Annotation.Keywords, want) {
+		t.Fatalf("keywords = %v, want %v", got.Annotation.Keywords, want)
+	}
+	if got.Annotation.Summary != "a longer summary" {
+		t.Fatalf("summary = %q", got.Annotation.Summary)
+	}
+	into.Annotation.Summary = "the target already has the longest summary"
+	if got, _ := MergeEntry(into, from, ""); got.Annotation.Summary != into.Annotation.Summary {
+		t.Fatalf("longer target summary replaced: %q", got.Annotation.Summary)
+	}
+}
+
+func TestMergeEntryConflicts(t *testing.T) {
+	into, from := mergeFixtures()
+	from.APA7.Journal = "Other Journal"
+	from.APA7.Title = "go concurrency"
+	got, conflicts := MergeEntry(into, from, "")
+	want := []FieldConflict{
+		{Field: "title", Into: "Go Concurrency", From: "go concurrency"},
+		{Field: "journal", Into: "J", From: "Other Journal"},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("conflicts = %+v, want %+v", conflicts, want)
+	}
+	if got.APA7.Journal != "J" {
+		t.Fatalf("unresolved conflict should keep the target value, got %q", got.APA7.Journal)
+	}
+	if got, _ := MergeEntry(into, from, PreferInto); got.APA7.Journal != "J" || got.APA7.Title != "Go Concurrency" {
+		t.Fatalf("--prefer into: %+v", got.APA7)
+	}
+	got, _ = MergeEntry(into, from, PreferFrom)
+	if got.APA7.Journal != "Other Journal" || got.APA7.Title != "go concurrency" || got.APA7.Volume != "3" {
+		t.Fatalf("--prefer from: %+v", got.APA7)
+	}
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestORCIDRoundTripCSLAndAuthorIndex(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	e := schema.Entry{ID: "00000000-0000-4000-8000-000000000093", Type: "article", APA7: schema.APA7{
+		Title: "Identified", Authors: schema.Authors{{Family: "Doe", Given: "J."}, {Family: "Carberry", Given: "J.", ORCID: "0000-0002-1825-0097"}},
+	}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	if !strings.Contains(string(b), "orcid = {, 0000-0002-1825-0097}") {
+		t.Fatalf("orcid field not written: %s", b)
+	}
+	list, err := ReadAll()
+	if err != nil || len(list) != 1 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	if !reflect.DeepEqual(list[0].APA7.Authors, e.APA7.Authors) {
+		t.Fatalf("orcid did not round-trip: %+v", list[0].APA7.Authors)
+	}
+	csl, err := EntriesToCSLJSON(list)
+	if err != nil || !strings.Contains(string(csl), `"ORCID": "https://orcid.org/0000-0002-1825-0097"`) {
+		t.Fatalf("csl-json orcid: %v %s", err, csl)
+	}
+	ix := authorIndex(list)
+	if got := ix["0000-0002-1825-0097"]; !reflect.DeepEqual(got, ix["Carberry, J."]) || len(got) != 1 {
+		t.Fatalf("orcid should be an alternate author key: %v", ix)
+	}
+}
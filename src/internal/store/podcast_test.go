@@ -0,0 +1,46 @@
+package store
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestPodcastRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	y := 2023
+	e := schema.Entry{ID: "00000000-0000-4000-8000-0000000000f2", Type: "podcast", APA7: schema.APA7{
+		Title: "Logging at Scale", Year: &y, Date: "2023-03-07", Authors: schema.Authors{{Family: "Jane Host"}},
+		ContainerTitle: "Talking Systems", Publisher: "Example Network", Issue: "42",
+		URL: "https://talking.example.com/42", Accessed: "2024-01-01",
+	}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"podcast"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	for _, want := range []string{"@misc{", "booktitle = {Talking Systems}", "publisher = {Example Network}", "number = {42}"} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("missing %q in:\n%s", want, b)
+		}
+	}
+	if SegmentForType("podcast") != "podcast" {
+		t.Fatalf("segment: %q", SegmentForType("podcast"))
+	}
+	got, err := ReadAll()
+	if err != nil || len(got) != 1 {
+		t.Fatalf("read: %v %d", err, len(got))
+	}
+	a := got[0].APA7
+	if got[0].Type != "podcast" || a.ContainerTitle != "Talking Systems" || a.Publisher != "Example Network" || a.Issue != "42" {
+		t.Fatalf("round trip: %s %+v", got[0].Type, a)
+	}
+	if bib := entryToBibTeX(e, bibKeyFor(e)); !strings.Contains(bib, "booktitle = {Talking Systems}") {
+		t.Fatalf("entryToBibTeX:\n%s", bib)
+	}
+}
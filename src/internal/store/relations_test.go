@@ -0,0 +1,40 @@
+package store
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestRelationsRoundTripAndNote(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	y := 2009
+	rel := &schema.Relations{Obsoletes: []string{"RFC 3164"}, UpdatedBy: []string{"RFC 8996", "RFC 9000"}}
+	e := schema.Entry{ID: "00000000-0000-4000-8000-0000000000f1", Type: "rfc", APA7: schema.APA7{Title: "The Syslog Protocol", Year: &y, ContainerTitle: "RFC 5424", Relations: rel}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"rfc"}}}
+	if _, err := WriteEntry(e); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, _ := os.ReadFile(BibFile)
+	for _, want := range []string{"obsoletes = {RFC 3164}", "updated_by = {RFC 8996, RFC 9000}", "note = {Obsoletes: RFC 3164; Updated by: RFC 8996"} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("missing %q in:\n%s", want, b)
+		}
+	}
+	got, err := ReadAll()
+	if err != nil || len(got) != 1 {
+		t.Fatalf("read: %v %d", err, len(got))
+	}
+	if !reflect.DeepEqual(got[0].APA7.Relations, rel) {
+		t.Fatalf("relations: %+v", got[0].APA7.Relations)
+	}
+	if bib := entryToBibTeX(e, bibKeyFor(e)); !strings.Contains(bib, "obsoletes = {RFC 3164}") || !strings.Contains(bib, "Updated by: RFC 8996") {
+		t.Fatalf("entryToBibTeX:\n%s", bib)
+	}
+}
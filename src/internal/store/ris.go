@@ -0,0 +1,145 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bibliography/src/internal/pages"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/stringsx"
+)
+
+// risTypeFor maps an entry type to its RIS TY code (the inverse of the importer's map).
+func risTypeFor(typ string) string {
+	switch strings.ToLower(strings.TrimSpace(typ)) {
+	case "article":
+		return "JOUR"
+	case "book":
+		return "BOOK"
+	case "website":
+		return "ELEC"
+	case "rfc", "standard":
+		return "STAND"
+	case "patent":
+		return "PAT"
+	case "video":
+		return "VIDEO"
+	case "movie":
+		return "MPCT"
+	case "song", "podcast":
+		return "SOUND"
+	case "report":
+		return "RPRT"
+	case "dataset":
+		return "DATA"
+	case "software":
+		return "COMP"
+	default:
+		return "GEN"
+	}
+}
+
+// ExportRIS reads all entries (see ReadAll: data/library.bib, else the legacy
+// YAML tree) and writes them to target as RIS, returning the number of records written.
+func ExportRIS(target string) (int, error) {
+	entries, err := ReadAll()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, fmt.Errorf("cannot create output directory for %s: %w", target, err)
+	}
+	if err := os.WriteFile(target, EntriesToRIS(entries), 0o644); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// EntriesToRIS renders entries as RIS records in the same order as EntriesToBibTeX.
+func EntriesToRIS(entries []schema.Entry) []byte {
+	var buf bytes.Buffer
+	sortForExport(entries)
+	for _, e := range entries {
+		buf.WriteString(entryToRIS(e))
+	}
+	return buf.Bytes()
+}
+
+// entryToRIS converts a schema.Entry into one RIS record ("TY  - ..." through "ER  - ").
+func entryToRIS(e schema.Entry) string {
+	var b strings.Builder
+	w := func(tag, v string) {
+		v = strings.Join(strings.Fields(v), " ")
+		if v != "" {
+			fmt.Fprintf(&b, "%s  - %s\n", tag, v)
+		}
+	}
+	a := e.APA7
+	w("TY", risTypeFor(e.Type))
+	w("ID", e.ID)
+	for _, au := range a.Authors {
+		name := strings.TrimSpace(au.Family)
+		if g := strings.TrimSpace(au.Given); g != "" && name != "" {
+			name += ", " + g
+		}
+		switch au.RoleOf() {
+		case schema.RoleEditor:
+			w("ED", name)
+		case schema.RoleTranslator:
+			w("A4", name)
+		case schema.RoleNarrator:
+			// RIS has no narrator tag.
+			continue
+		default:
+			w("AU", name)
+		}
+	}
+	w("TI", a.Title)
+	if a.Year != nil && *a.Year > 0 {
+		w("PY", fmt.Sprintf("%04d", *a.Year))
+	} else if len(a.Date) >= 4 {
+		w("PY", a.Date[:4])
+	}
+	if len(a.Date) > 4 {
+		w("DA", strings.ReplaceAll(a.Date, "-", "/"))
+	}
+	if strings.EqualFold(e.Type, "article") {
+		w("JO", stringsx.FirstNonEmpty(a.Journal, a.ContainerTitle))
+	} else {
+		w("T2", a.ContainerTitle)
+	}
+	w("VL", a.Volume)
+	w("IS", a.Issue)
+	sp, ep := pages.Split(a.Pages)
+	w("SP", sp)
+	w("EP", ep)
+	w("ET", a.Edition)
+	w("PB", stringsx.FirstNonEmpty(a.Publisher, a.StandardsBody))
+	w("CY", a.PublisherLocation)
+	w("SN", stringsx.FirstNonEmpty(a.ISBN, a.ISSN))
+	if strings.EqualFold(e.Type, "standard") {
+		w("M1", a.Designation)
+	}
+	// RIS has one accession slot (DB/AN); further accession ids go in notes ("arXiv: ...")
+	for i, acc := range a.Accessions() {
+		if i == 0 {
+			w("DB", acc.Database)
+			w("AN", acc.Number)
+		} else {
+			w("N1", acc.Database+": "+acc.Number)
+		}
+	}
+	w("DO", a.DOI)
+	w("UR", a.URL)
+	w("LA", a.Language)
+	w("Y2", strings.ReplaceAll(a.Accessed, "-", "/"))
+	w("AB", e.Annotation.Summary)
+	for _, k := range e.Annotation.Keywords {
+		w("KW", k)
+	}
+	b.WriteString("ER  - \n\n")
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/schema"
+)
+
+func TestEntryToRIS_ArticleAndBook(t *testing.T) {
+	y := 2021
+	article := schema.Entry{
+		ID:   "00000000-0000-4000-8000-000000000071",
+		Type: "article",
+		APA7: schema.APA7{
+			Authors: schema.Authors{{Family: "Doe", Given: "J."}, {Family: "Roe", Given: "A. B."}},
+			Year:    &y, Date: "2021-03-14", Title: "Measuring things", Journal: "Journal of Tests",
+			Volume: "12", Issue: "3", Pages: "101–118", URL: "https://doi.org/10.1/x", Accessed: "2024-05-06",
+			Identifiers: schema.Identifiers{DOI: "10.1/x"},
+		},
+		Annotation: schema.Annotation{Summary: "A study of things.", Keywords: []string{"article", "testing"}},
+	}
+	want := strings.Join([]string{
+		"TY  - JOUR",
+		"ID  - 00000000-0000-4000-8000-000000000071",
+		"AU  - Doe, J.",
+		"AU  - Roe, A. B.",
+		"TI  - Measuring things",
+		"PY  - 2021",
+		"DA  - 2021/03/14",
+		"JO  - Journal of Tests",
+		"VL  - 12",
+		"IS  - 3",
+		"SP  - 101",
+		"EP  - 118",
+		"DO  - 10.1/x",
+		"UR  - https://doi.org/10.1/x",
+		"Y2  - 2024/05/06",
+		"AB  - A study of things.",
+		"KW  - article",
+		"KW  - testing",
+		"ER  - ",
+		"", "",
+	}, "\n")
+	if got := entryToRIS(article); got != want {
+		t.Fatalf("article RIS:\n%s\nwant:\n%s", got, want)
+	}
+
+	by := 1999
+	book := schema.Entry{
+		ID:   "00000000-0000-4000-8000-000000000072",
+		Type: "book",
+		APA7: schema.APA7{
+			Authors: schema.Authors{{Family: "Hunt", Given: "A."}}, Year: &by, Title: "The Pragmatic Programmer",
+			Edition: "1", Publisher: "Addison-Wesley", PublisherLocation: "Boston",
+			Identifiers: schema.Identifiers{ISBN: "9780201616224"},
+		},
+		Annotation: schema.Annotation{Summary: "Practical advice.", Keywords: []string{"book"}},
+	}
+	want = strings.Join([]string{
+		"TY  - BOOK",
+		"ID  - 00000000-0000-4000-8000-000000000072",
+		"AU  - Hunt, A.",
+		"TI  - The Pragmatic Programmer",
+		"PY  - 1999",
+		"ET  - 1",
+		"PB  - Addison-Wesley",
+		"CY  - Boston",
+		"SN  - 9780201616224",
+		"AB  - Practical advice.",
+		"KW  - book",
+		"ER  - ",
+		"", "",
+	}, "\n")
+	if got := entryToRIS(book); got != want {
+		t.Fatalf("book RIS:\n%s\nwant:\n%s", got, want)
+	}
+
+	for typ, ty := range map[string]string{"website": "ELEC", "rfc": "STAND", "movie": "MPCT"} {
+		if got := risTypeFor(typ); got != ty {
+			t.Fatalf("risTypeFor(%s) = %s, want %s", typ, got, ty)
+		}
+	}
+}
+
+func TestExportRIS(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	p := filepath.Join(CitationsDir, "books", "00000000-0000-4000-8000-000000000073.yaml")
+	_ = os.MkdirAll(filepath.Dir(p), 0o755)
+	if err := os.WriteFile(p, []byte(`{"id":"00000000-0000-4000-8000-000000000073","type":"book","apa7":{"title":"B"},"annotation":{"summary":"s","keywords":["k"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	n, err := ExportRIS(filepath.Join("out", "refs.ris"))
+	if err != nil || n != 1 {
+		t.Fatalf("export: %d %v", n, err)
+	}
+	b, _ := os.ReadFile(filepath.Join("out", "refs.ris"))
+	if !strings.HasPrefix(string(b), "TY  - BOOK\n") || !strings.Contains(string(b), "ER  - \n") {
+		t.Fatalf("unexpected RIS:\n%s", b)
+	}
+}
+
+func TestExportRIS_ReadsLibrary(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	for _, title := range []string{"First", "Second"} {
+		e := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: title}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"k"}}}
+		if _, err := WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n, err := ExportRIS("refs.ris")
+	if err != nil || n != 2 {
+		t.Fatalf("export from library.bib: %d %v", n, err)
+	}
+	if b, _ := os.ReadFile("refs.ris"); strings.Count(string(b), "TY  - BOOK\n") != 2 {
+		t.Fatalf("unexpected RIS:\n%s", b)
+	}
+}
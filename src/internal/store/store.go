@@ -1,12 +1,14 @@
 package store
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -14,29 +16,89 @@ import (
 	// time removed; use dates.NowISO
 
 	"bibliography/src/internal/dates"
+	"bibliography/src/internal/isbn"
 	"bibliography/src/internal/schema"
 )
 
-const (
-	CitationsDir = "data/citations"
-	MetadataDir  = "data/metadata"
+// DefaultDataDir is the data directory used unless SetDataDir (the --data-dir flag or
+// BIB_DATA_DIR) chooses another.
+const DefaultDataDir = "data"
+
+// Library paths, all derived from DataDir by SetDataDir.
+var (
+	DataDir      string
+	CitationsDir string
+	MetadataDir  string
 	// BibFile is the consolidated BibTeX library written from all entries.
-	BibFile      = "data/library.bib"
-	KeywordsJSON = "data/metadata/keywords.json"
-	AuthorsJSON  = "data/metadata/authors.json"
-	TitlesJSON   = "data/metadata/titles.json"
-	ISBNJSON     = "data/metadata/isbn.json"
-	DOIJSON      = "data/metadata/doi.json"
+	BibFile       string
+	KeywordsJSON  string
+	AuthorsJSON   string
+	TitlesJSON    string
+	ISBNJSON      string
+	DOIJSON       string
+	DecadesJSON   string
+	LanguagesJSON string
+	FullTextJSON  string
 )
 
+func init() { SetDataDir(DefaultDataDir) }
+
+// FindRepoRoot walks up from the working directory to the nearest directory whose
+// data directory holds citations or a library (data/citations or data/library.bib),
+// the way git finds .git. ok is false when no ancestor qualifies.
+func FindRepoRoot() (root string, ok bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, marker := range []string{"citations", "library.bib"} {
+			if _, err := os.Stat(filepath.Join(dir, DefaultDataDir, marker)); err == nil {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// SetDataDir points every store path (citations, metadata indexes, the BibTeX
+// library, and the flat view) at dir; an empty dir restores DefaultDataDir.
+func SetDataDir(dir string) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		dir = DefaultDataDir
+	}
+	DataDir = filepath.ToSlash(filepath.Clean(dir))
+	CitationsDir = path.Join(DataDir, "citations")
+	MetadataDir = path.Join(DataDir, "metadata")
+	BibFile = path.Join(DataDir, "library.bib")
+	KeywordsJSON = path.Join(MetadataDir, "keywords.json")
+	AuthorsJSON = path.Join(MetadataDir, "authors.json")
+	TitlesJSON = path.Join(MetadataDir, "titles.json")
+	ISBNJSON = path.Join(MetadataDir, "isbn.json")
+	DOIJSON = path.Join(MetadataDir, "doi.json")
+	DecadesJSON = path.Join(MetadataDir, "decades.json")
+	LanguagesJSON = path.Join(MetadataDir, "languages.json")
+	FullTextJSON = path.Join(MetadataDir, "fulltext.json")
+	FlatDir = path.Join(DataDir, "flat")
+}
+
 // --- Small helpers to lower duplication and cognitive load ---
 
 // ensureMetaDir creates the metadata directory if missing.
 func ensureMetaDir() error { return os.MkdirAll(MetadataDir, 0o755) }
 
 // entryPath returns a stable identifier for an entry for use in indexes.
-// Since YAML files are removed, we reference the BibTeX file with an id anchor.
-func entryPath(e schema.Entry) string { return filepath.ToSlash(BibFile) + "::" + e.ID }
+// Since YAML files are removed, we reference the BibTeX file with an id anchor. The
+// reference names only the data directory itself ("data/library.bib"), so indexes do
+// not change with where the CLI is run from.
+func entryPath(e schema.Entry) string {
+	return path.Join(path.Base(DataDir), "library.bib") + "::" + e.ID
+}
 
 // writeJSON writes the given value to the target JSON file with indentation.
 func writeJSON(target string, v any) (string, error) {
@@ -50,6 +112,14 @@ func writeJSON(target string, v any) (string, error) {
 	return target, nil
 }
 
+// writeIndex writes a metadata index to target, creating the metadata directory.
+func writeIndex(target string, index any) (string, error) {
+	if err := ensureMetaDir(); err != nil {
+		return "", err
+	}
+	return writeJSON(target, index)
+}
+
 // dirForType maps an entry type to its subdirectory under data/citations.
 // Unknown types fall back to "citation"; some types use plural or aliased forms.
 func dirForType(typ string) string {
@@ -70,6 +140,10 @@ func dirForType(typ string) string {
 		return "site"
 	case "rfc":
 		return "rfc"
+	case "standard":
+		return "standard"
+	case "podcast":
+		return "podcast"
 	default:
 		return "citation"
 	}
@@ -94,6 +168,100 @@ func WriteEntry(e schema.Entry) (string, error) {
 	return entryPath(e), nil
 }
 
+// WriteEntries validates es and upserts them into the library with a single rewrite,
+// returning their BibTeX references. Nothing is written when any entry is invalid.
+func WriteEntries(es []schema.Entry) ([]string, error) {
+	paths := make([]string, len(es))
+	for i := range es {
+		if strings.TrimSpace(es[i].ID) == "" {
+			es[i].ID = schema.NewID()
+		}
+		if err := es[i].Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", es[i].ID, err)
+		}
+		paths[i] = entryPath(es[i])
+	}
+	if len(es) == 0 {
+		return paths, nil
+	}
+	if err := UpdateBibEntries(es); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// MoveEntry upserts e (whose Type has already been changed from oldType) into the
+// library and, when a legacy YAML file exists under the old type's segment, rewrites it
+// under the new segment and removes the old file. from/to are empty when there is no
+// legacy file to move.
+func MoveEntry(e schema.Entry, oldType string) (from, to string, err error) {
+	if _, err := WriteEntry(e); err != nil {
+		return "", "", err
+	}
+	from = path.Join(CitationsDir, dirForType(oldType), e.ID+".yaml")
+	if _, err := os.Stat(filepath.FromSlash(from)); errors.Is(err, fs.ErrNotExist) {
+		return "", "", nil
+	}
+	to = path.Join(CitationsDir, dirForType(e.Type), e.ID+".yaml")
+	if from == to {
+		// Same segment (e.g., report -> dataset both live under citation): rewrite in place.
+		from = ""
+	}
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filepath.FromSlash(to)), 0o755); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(filepath.FromSlash(to), append(b, '\n'), 0o644); err != nil {
+		return "", "", err
+	}
+	if from != "" {
+		if err := os.Remove(filepath.FromSlash(from)); err != nil {
+			return "", "", err
+		}
+	}
+	return from, to, nil
+}
+
+// Rename records a legacy YAML file moved from one path to another.
+type Rename struct {
+	From, To string
+}
+
+// RepairFilenames moves every valid legacy YAML file that is not stored at
+// data/citations/<segment>/<id>.yaml (because it was renamed or filed under another
+// type) to that path, so by-id lookups find it. Invalid files are left alone. It
+// returns the moves made; a move onto an existing file is an error.
+func RepairFilenames() ([]Rename, error) {
+	files, err := ScanYAMLFiles()
+	if err != nil {
+		return nil, err
+	}
+	var moved []Rename
+	for _, f := range files {
+		if f.Err != nil {
+			continue
+		}
+		to := path.Join(filepath.ToSlash(CitationsDir), dirForType(f.Entry.Type), f.Entry.ID+".yaml")
+		if f.Path == to {
+			continue
+		}
+		if _, err := os.Stat(filepath.FromSlash(to)); err == nil {
+			return moved, fmt.Errorf("cannot rename %s: %s already exists", f.Path, to)
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.FromSlash(to)), 0o755); err != nil {
+			return moved, err
+		}
+		if err := os.Rename(filepath.FromSlash(f.Path), filepath.FromSlash(to)); err != nil {
+			return moved, err
+		}
+		moved = append(moved, Rename{From: f.Path, To: to})
+	}
+	return moved, nil
+}
+
 // ReadAll loads, validates, and returns all entries under data/citations.
 func ReadAll() ([]schema.Entry, error) {
 	var entries []schema.Entry
@@ -136,9 +304,63 @@ func ReadAll() ([]schema.Entry, error) {
 
 // readAllYAML loads entries directly from YAML files under data/citations, bypassing BibTeX.
 func readAllYAML() ([]schema.Entry, error) {
-	var entries []schema.Entry
+	files, err := ReadAllYAMLFiles()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]schema.Entry, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, f.Entry)
+	}
+	return entries, nil
+}
+
+// YAMLFile pairs a legacy YAML entry with the path of the file it was read from.
+type YAMLFile struct {
+	Path  string
+	Entry schema.Entry
+	Err   error // set by ScanYAMLFiles for a file that is unreadable or invalid
+}
+
+// EntryPaths maps each entry id (lowercased) to where it is stored: its legacy YAML
+// file under data/citations when one exists, otherwise its library.bib reference.
+func EntryPaths(entries []schema.Entry) (map[string]string, error) {
+	files, err := ReadAllYAMLFiles()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(entries))
+	for _, e := range entries {
+		out[strings.ToLower(e.ID)] = entryPath(e)
+	}
+	for _, f := range files {
+		out[strings.ToLower(f.Entry.ID)] = f.Path
+	}
+	return out, nil
+}
+
+// ReadAllYAMLFiles loads and validates legacy YAML entries under data/citations,
+// returning each entry alongside its (slash-separated) file path.
+func ReadAllYAMLFiles() ([]YAMLFile, error) {
+	files, err := ScanYAMLFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if f.Err != nil {
+			return nil, f.Err
+		}
+	}
+	return files, nil
+}
+
+// ScanYAMLFiles reads every YAML file under data/citations like ReadAllYAMLFiles but
+// does not stop at a bad file: a file that cannot be decoded or fails validation is
+// returned with Err set (and, when it decoded, its Entry).
+func ScanYAMLFiles() ([]YAMLFile, error) {
+	var files []YAMLFile
 	if _, err := os.Stat(CitationsDir); errors.Is(err, fs.ErrNotExist) {
-		return entries, nil
+		return files, nil
 	}
 	err := filepath.WalkDir(CitationsDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -151,24 +373,24 @@ func readAllYAML() ([]schema.Entry, error) {
 		if err != nil {
 			return err
 		}
-		var e schema.Entry
-		if err := json.Unmarshal(data, &e); err != nil {
-			return fmt.Errorf("invalid YAML in %s: %w", path, err)
+		f := YAMLFile{Path: filepath.ToSlash(path)}
+		if err := json.Unmarshal(data, &f.Entry); err != nil {
+			f.Entry, f.Err = schema.Entry{}, fmt.Errorf("invalid YAML in %s: %w", path, err)
+		} else if err := f.Entry.Validate(); err != nil {
+			f.Err = fmt.Errorf("invalid entry in %s: %w", path, err)
 		}
-		if err := e.Validate(); err != nil {
-			return fmt.Errorf("invalid entry in %s: %w", path, err)
-		}
-		entries = append(entries, e)
+		files = append(files, f)
 		return nil
 	})
-	return entries, err
+	return files, err
 }
 
 // BuildKeywordIndex writes data/metadata/keywords.json mapping keyword -> list of entry YAML paths.
 func BuildKeywordIndex(entries []schema.Entry) (string, error) {
-	if err := ensureMetaDir(); err != nil {
-		return "", err
-	}
+	return writeIndex(KeywordsJSON, keywordIndex(entries))
+}
+
+func keywordIndex(entries []schema.Entry) map[string][]string {
 	index := map[string][]string{}
 	for _, e := range entries {
 		seen := map[string]bool{}
@@ -245,18 +467,45 @@ func BuildKeywordIndex(entries []schema.Entry) (string, error) {
 	for k := range index {
 		sort.Strings(index[k])
 	}
-	return writeJSON(KeywordsJSON, index)
+	return index
 }
 
 // BuildAuthorIndex writes data/metadata/authors.json mapping author name -> entry YAML paths.
-// Author key format is "Family, Given" when both present; otherwise the non-empty name.
+// Author key format is "Family, Given" when both present; otherwise the non-empty name;
+// authors' ORCID iDs are indexed as alternate keys.
 func BuildAuthorIndex(entries []schema.Entry) (string, error) {
-	if err := ensureMetaDir(); err != nil {
-		return "", err
-	}
+	return writeIndex(AuthorsJSON, authorIndex(entries))
+}
+
+func authorIndex(entries []schema.Entry) map[string][]string {
 	index := map[string][]string{}
+	for name, works := range GroupByAuthor(entries) {
+		for _, e := range works {
+			index[name] = append(index[name], entryPath(e))
+		}
+	}
+	// ORCID iDs are alternate keys, so an author is found however their name is spelled
+	for _, e := range entries {
+		seen := map[string]bool{}
+		for _, au := range e.APA7.Authors {
+			if id := strings.TrimSpace(au.ORCID); id != "" && !seen[id] {
+				seen[id] = true
+				index[id] = append(index[id], entryPath(e))
+			}
+		}
+	}
+	// Sort lists for determinism
+	for k := range index {
+		sort.Strings(index[k])
+	}
+	return index
+}
+
+// GroupByAuthor maps each author key (as used by the authors index) to their works,
+// counting each work once per author.
+func GroupByAuthor(entries []schema.Entry) map[string][]schema.Entry {
+	out := map[string][]schema.Entry{}
 	for _, e := range entries {
-		path := entryPath(e)
 		// Deduplicate per author per entry
 		perEntrySeen := map[string]bool{}
 		for _, au := range e.APA7.Authors {
@@ -271,33 +520,31 @@ func BuildAuthorIndex(entries []schema.Entry) (string, error) {
 				continue
 			}
 			perEntrySeen[name] = true
-			index[name] = append(index[name], path)
+			out[name] = append(out[name], e)
 		}
 	}
-	// Sort lists for determinism
-	for k := range index {
-		sort.Strings(index[k])
-	}
-	return writeJSON(AuthorsJSON, index)
+	return out
 }
 
 // BuildTitleIndex writes data/metadata/titles.json mapping entry YAML path -> tokenized title words.
 func BuildTitleIndex(entries []schema.Entry) (string, error) {
-	if err := ensureMetaDir(); err != nil {
-		return "", err
-	}
+	return writeIndex(TitlesJSON, titleIndex(entries))
+}
+
+func titleIndex(entries []schema.Entry) map[string][]string {
 	index := map[string][]string{}
 	for _, e := range entries {
 		index[entryPath(e)] = tokenizeWords(e.APA7.Title)
 	}
-	return writeJSON(TitlesJSON, index)
+	return index
 }
 
 // BuildISBNIndex writes data/metadata/isbn.json mapping entry YAML path -> ISBN for books with ISBNs.
 func BuildISBNIndex(entries []schema.Entry) (string, error) {
-	if err := ensureMetaDir(); err != nil {
-		return "", err
-	}
+	return writeIndex(ISBNJSON, isbnIndex(entries))
+}
+
+func isbnIndex(entries []schema.Entry) map[string]string {
 	index := map[string]string{}
 	for _, e := range entries {
 		if strings.ToLower(strings.TrimSpace(e.Type)) != "book" {
@@ -309,14 +556,15 @@ func BuildISBNIndex(entries []schema.Entry) (string, error) {
 		}
 		index[entryPath(e)] = isbn
 	}
-	return writeJSON(ISBNJSON, index)
+	return index
 }
 
 // BuildDOIIndex writes data/metadata/doi.json mapping entry YAML path -> DOI for entries with DOIs.
 func BuildDOIIndex(entries []schema.Entry) (string, error) {
-	if err := ensureMetaDir(); err != nil {
-		return "", err
-	}
+	return writeIndex(DOIJSON, doiIndex(entries))
+}
+
+func doiIndex(entries []schema.Entry) map[string]string {
 	index := map[string]string{}
 	for _, e := range entries {
 		doi := strings.TrimSpace(e.APA7.DOI)
@@ -325,12 +573,204 @@ func BuildDOIIndex(entries []schema.Entry) (string, error) {
 		}
 		index[entryPath(e)] = doi
 	}
-	return writeJSON(DOIJSON, index)
+	return index
+}
+
+// BuildDecadeIndex writes data/metadata/decades.json mapping decade ("1990s") -> entry
+// paths, by publication year; undated entries are left out.
+func BuildDecadeIndex(entries []schema.Entry) (string, error) {
+	return writeIndex(DecadesJSON, decadeIndex(entries))
+}
+
+func decadeIndex(entries []schema.Entry) map[string][]string {
+	index := map[string][]string{}
+	for _, e := range entries {
+		if d := Decade(PublicationYear(e)); d != "" {
+			index[d] = append(index[d], entryPath(e))
+		}
+	}
+	for k := range index {
+		sort.Strings(index[k])
+	}
+	return index
+}
+
+// BuildLanguageIndex writes data/metadata/languages.json mapping language code ("de")
+// -> entry paths; entries without a language are left out.
+func BuildLanguageIndex(entries []schema.Entry) (string, error) {
+	return writeIndex(LanguagesJSON, languageIndex(entries))
+}
+
+func languageIndex(entries []schema.Entry) map[string][]string {
+	index := map[string][]string{}
+	for _, e := range entries {
+		if l := strings.TrimSpace(e.APA7.Language); l != "" {
+			index[l] = append(index[l], entryPath(e))
+		}
+	}
+	for k := range index {
+		sort.Strings(index[k])
+	}
+	return index
+}
+
+// PublicationYear returns APA7.Year, else the year leading APA7.Date, else 0.
+func PublicationYear(e schema.Entry) int {
+	if e.APA7.Year != nil {
+		return *e.APA7.Year
+	}
+	if len(strings.TrimSpace(e.APA7.Date)) >= 4 {
+		var y int
+		if _, err := fmt.Sscanf(e.APA7.Date[:4], "%d", &y); err == nil {
+			return y
+		}
+	}
+	return 0
+}
+
+// Decade returns the decade bucket of a year ("1990s" for 1994), or "" for year <= 0.
+func Decade(year int) string {
+	if year <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%ds", year/10*10)
+}
+
+// ReadIndex loads a metadata index written by the Build*Index functions that maps a
+// key to a list of strings (keywords.json, authors.json, titles.json, decades.json,
+// languages.json). A missing index yields a nil map and no error.
+func ReadIndex(path string) (map[string][]string, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var index map[string][]string
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, fmt.Errorf("invalid index %s: %w", path, err)
+	}
+	return index, nil
+}
+
+// IDFromIndexPath returns the entry id from an index path ("data/library.bib::<id>"
+// or a legacy ".../<id>.yaml").
+func IDFromIndexPath(p string) string {
+	if i := strings.LastIndex(p, "::"); i >= 0 {
+		return p[i+2:]
+	}
+	return strings.TrimSuffix(filepath.Base(p), ".yaml")
+}
+
+// metadataIndex is one generated index: its name (for bib index --only), file (read
+// through a pointer so SetDataDir applies), and contents.
+type metadataIndex struct {
+	name  string
+	path  *string
+	build func([]schema.Entry) any
+}
+
+var metadataIndexes = []metadataIndex{
+	{"keywords", &KeywordsJSON, func(es []schema.Entry) any { return keywordIndex(es) }},
+	{"authors", &AuthorsJSON, func(es []schema.Entry) any { return authorIndex(es) }},
+	{"titles", &TitlesJSON, func(es []schema.Entry) any { return titleIndex(es) }},
+	{"isbn", &ISBNJSON, func(es []schema.Entry) any { return isbnIndex(es) }},
+	{"doi", &DOIJSON, func(es []schema.Entry) any { return doiIndex(es) }},
+	{"decades", &DecadesJSON, func(es []schema.Entry) any { return decadeIndex(es) }},
+	{"languages", &LanguagesJSON, func(es []schema.Entry) any { return languageIndex(es) }},
+	{"fulltext", &FullTextJSON, func(es []schema.Entry) any { return fullTextIndex(es) }},
+}
+
+// IndexNames lists the metadata index names accepted by BuildIndexes, in build order.
+func IndexNames() []string {
+	names := make([]string, 0, len(metadataIndexes))
+	for _, ix := range metadataIndexes {
+		names = append(names, ix.name)
+	}
+	return names
+}
+
+// selectIndexes returns the named indexes in build order; no names selects all.
+func selectIndexes(names []string) ([]metadataIndex, error) {
+	if len(names) == 0 {
+		return metadataIndexes, nil
+	}
+	want := map[string]bool{}
+	for _, n := range names {
+		n = strings.ToLower(strings.TrimSpace(n))
+		found := false
+		for _, ix := range metadataIndexes {
+			found = found || ix.name == n
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown index %q (want one of %s)", n, strings.Join(IndexNames(), ", "))
+		}
+		want[n] = true
+	}
+	var out []metadataIndex
+	for _, ix := range metadataIndexes {
+		if want[ix.name] {
+			out = append(out, ix)
+		}
+	}
+	return out, nil
+}
+
+// BuildAllIndexes rebuilds every metadata index and returns the written paths in order.
+func BuildAllIndexes(entries []schema.Entry) ([]string, error) {
+	return BuildIndexes(entries, nil)
+}
+
+// BuildIndexes rebuilds the named metadata indexes (all when names is empty) and
+// returns the written paths in order.
+func BuildIndexes(entries []schema.Entry, names []string) ([]string, error) {
+	selected, err := selectIndexes(names)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, ix := range selected {
+		p, err := writeIndex(*ix.path, ix.build(entries))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// CheckIndexes compares the named metadata indexes (all when names is empty) on disk
+// with what BuildIndexes would write, without writing, and returns the paths of those
+// that are missing or out of date.
+func CheckIndexes(entries []schema.Entry, names []string) ([]string, error) {
+	selected, err := selectIndexes(names)
+	if err != nil {
+		return nil, err
+	}
+	var drifted []string
+	for _, ix := range selected {
+		want, err := json.MarshalIndent(ix.build(entries), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		have, err := os.ReadFile(*ix.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err != nil || !bytes.Equal(have, want) {
+			drifted = append(drifted, *ix.path)
+		}
+	}
+	return drifted, nil
 }
 
 var nonWord = regexp.MustCompile(`[^a-zA-Z0-9]+`)
 var doiRegex = regexp.MustCompile(`(?i)10\.\d{4,9}/[-._;()/:A-Z0-9]+`)
 
+// TokenizeWords exposes the index tokenizer so keyword generators match the index.
+func TokenizeWords(s string) []string { return tokenizeWords(s) }
+
 // tokenizeWords splits a phrase into lowercased word tokens, filtering empties and 1-character tokens.
 func tokenizeWords(s string) []string {
 	s = strings.TrimSpace(s)
@@ -362,6 +802,58 @@ func ExtractDOI(s string) string {
 	return strings.TrimSpace(match)
 }
 
+// NormalizeDOI returns the lowercased DOI found in s (a bare DOI, doi: form, or DOI
+// URL) for identity comparisons, or "" when s holds no DOI.
+func NormalizeDOI(s string) string {
+	d := ExtractDOI(s)
+	if d == "" {
+		d = strings.TrimSpace(s)
+	}
+	return strings.ToLower(d)
+}
+
+// NormalizeISBN keeps only the digits and check character X of an ISBN and converts
+// an ISBN-10 to its ISBN-13 form, so both forms of one book compare equal.
+func NormalizeISBN(s string) string {
+	n := isbn.Normalize(s)
+	if n13, ok := isbn.To13(n); ok {
+		return n13
+	}
+	return n
+}
+
+// FindByDOI returns the entry whose DOI matches doi after normalization.
+func FindByDOI(doi string) (schema.Entry, bool, error) {
+	want := NormalizeDOI(doi)
+	if want == "" {
+		return schema.Entry{}, false, nil
+	}
+	return findEntry(func(e schema.Entry) bool { return NormalizeDOI(e.APA7.DOI) == want })
+}
+
+// FindByISBN returns the entry whose ISBN matches isbn after normalization.
+func FindByISBN(isbn string) (schema.Entry, bool, error) {
+	want := NormalizeISBN(isbn)
+	if want == "" {
+		return schema.Entry{}, false, nil
+	}
+	return findEntry(func(e schema.Entry) bool { return NormalizeISBN(e.APA7.ISBN) == want })
+}
+
+// findEntry returns the first stored entry matching ok.
+func findEntry(ok func(schema.Entry) bool) (schema.Entry, bool, error) {
+	entries, err := ReadAll()
+	if err != nil {
+		return schema.Entry{}, false, err
+	}
+	for _, e := range entries {
+		if ok(e) {
+			return e, true, nil
+		}
+	}
+	return schema.Entry{}, false, nil
+}
+
 // NormalizeArticleDOI ensures an article's DOI and URL are consistent (doi and https://doi.org/<doi>). Returns true if modified.
 func NormalizeArticleDOI(e *schema.Entry) bool {
 	if e == nil {
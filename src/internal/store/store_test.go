@@ -18,7 +18,7 @@ func TestWriteReadAndIndex(t *testing.T) {
 	_ = os.Chdir(dir)
 
 	e1 := schema.Entry{ID: schema.NewID(), Type: "website", APA7: schema.APA7{Title: "A", URL: "https://a", Accessed: "2025-01-01"}, Annotation: schema.Annotation{Summary: "s1", Keywords: []string{"Go", "APA7"}}}
-	e2 := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "B", ISBN: "123-456", DOI: "10.1/abc"}, Annotation: schema.Annotation{Summary: "s2", Keywords: []string{"go", "yaml"}}}
+	e2 := schema.Entry{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "B", Identifiers: schema.Identifiers{ISBN: "123-456", DOI: "10.1/abc"}}, Annotation: schema.Annotation{Summary: "s2", Keywords: []string{"go", "yaml"}}}
 
 	p1, err := WriteEntry(e1)
 	if err != nil {
@@ -140,14 +140,14 @@ func TestIndexIncludesRequestedFields(t *testing.T) {
 		ID:   schema.NewID(),
 		Type: "article",
 		APA7: schema.APA7{
-			Title:     "End to End Arguments in System Design",
-			Year:      &y1,
-			Journal:   "ACM",
-			Publisher: "ACM",
-			URL:       "https://doi.org/10.1145/1",
-			Accessed:  "2025-01-01",
-			Authors:   schema.Authors{{Family: "Doe", Given: "J."}},
-			DOI:       "10.1145/1",
+			Title:       "End to End Arguments in System Design",
+			Year:        &y1,
+			Journal:     "ACM",
+			Publisher:   "ACM",
+			URL:         "https://doi.org/10.1145/1",
+			Accessed:    "2025-01-01",
+			Authors:     schema.Authors{{Family: "Doe", Given: "J."}},
+			Identifiers: schema.Identifiers{DOI: "10.1145/1"},
 		},
 		Annotation: schema.Annotation{Summary: "s", Keywords: []string{"Networks"}},
 	}
@@ -349,7 +349,7 @@ func TestSegmentForType(t *testing.T) {
 
 func TestFilterByKeywordsAND_AndExtractDOI(t *testing.T) {
 	es := []schema.Entry{
-		{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "T", DOI: "10.1/x"}, Annotation: schema.Annotation{Keywords: []string{"Go", "YAML"}}},
+		{ID: schema.NewID(), Type: "article", APA7: schema.APA7{Title: "T", Identifiers: schema.Identifiers{DOI: "10.1/x"}}, Annotation: schema.Annotation{Keywords: []string{"Go", "YAML"}}},
 		{ID: schema.NewID(), Type: "book", APA7: schema.APA7{Title: "B"}, Annotation: schema.Annotation{Keywords: []string{"go"}}},
 	}
 	out := FilterByKeywordsAND(es, []string{"go", "yaml"})
@@ -360,3 +360,69 @@ func TestFilterByKeywordsAND_AndExtractDOI(t *testing.T) {
 		t.Fatalf("expected doi extracted")
 	}
 }
+
+func TestRepairFilenamesRelocatesMisnamedFile(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+	const id = "00000000-0000-4000-8000-0000000000e1"
+	e := schema.Entry{ID: id, Type: "book", APA7: schema.APA7{Title: "T"}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	b, _ := json.Marshal(e)
+	misnamed := filepath.Join(CitationsDir, "site", "renamed-by-hand.yaml")
+	_ = os.MkdirAll(filepath.Dir(misnamed), 0o755)
+	if err := os.WriteFile(misnamed, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := RepairFilenames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.ToSlash(filepath.Join(CitationsDir, "books", id+".yaml"))
+	if len(moved) != 1 || moved[0].To != want || moved[0].From != filepath.ToSlash(misnamed) {
+		t.Fatalf("moved = %+v", moved)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("file not at id-based path: %v", err)
+	}
+	if moved, err := RepairFilenames(); err != nil || len(moved) != 0 {
+		t.Fatalf("second run should be a no-op: %v %+v", err, moved)
+	}
+}
+
+func TestWriteEntriesUpsertsOnceAndRejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	old, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(old) })
+	_ = os.Chdir(dir)
+
+	book := func(id, title string) schema.Entry {
+		return schema.Entry{ID: id, Type: "book", APA7: schema.APA7{Title: title}, Annotation: schema.Annotation{Summary: "s", Keywords: []string{"book"}}}
+	}
+	a := book("00000000-0000-4000-8000-0000000000c1", "A")
+	if _, err := WriteEntry(a); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(BibFile)
+	if _, err := WriteEntries([]schema.Entry{book(a.ID, "A2"), book("00000000-0000-4000-8000-0000000000c2", "")}); err == nil {
+		t.Fatal("expected an error for the untitled entry")
+	}
+	if after, _ := os.ReadFile(BibFile); string(after) != string(before) {
+		t.Fatalf("an invalid batch should write nothing")
+	}
+	paths, err := WriteEntries([]schema.Entry{book(a.ID, "A2"), book("", "B")})
+	if err != nil || len(paths) != 2 || !strings.HasSuffix(paths[0], "::"+a.ID) {
+		t.Fatalf("write entries: %v %v", err, paths)
+	}
+	list, err := ReadAll()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("readall: %v %d", err, len(list))
+	}
+	titles := map[string]bool{}
+	for _, e := range list {
+		titles[e.APA7.Title] = true
+	}
+	if !titles["A2"] || !titles["B"] {
+		t.Fatalf("expected A replaced and B added: %v", titles)
+	}
+}
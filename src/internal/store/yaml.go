@@ -0,0 +1,125 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// marshalYAML renders v as block-style YAML following its `yaml` struct tags (names,
+// omitempty, and inline). Strings are always double-quoted, so values such as "no",
+// "1.0", or a title with a colon stay strings. It covers the shapes schema.Entry uses:
+// structs, pointers, strings, numbers, bools, and slices of those.
+func marshalYAML(v any) []byte {
+	var b bytes.Buffer
+	writeYAMLFields(&b, reflect.Indirect(reflect.ValueOf(v)), 0)
+	return b.Bytes()
+}
+
+type yamlField struct {
+	name string
+	v    reflect.Value
+}
+
+// yamlFields lists the fields of struct v in declaration order, flattening inline
+// structs and dropping zero-valued omitempty fields.
+func yamlFields(v reflect.Value) []yamlField {
+	var out []yamlField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if strings.Contains(opts, "inline") {
+			out = append(out, yamlFields(fv)...)
+			continue
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		out = append(out, yamlField{name, fv})
+	}
+	return out
+}
+
+func writeYAMLFields(b *bytes.Buffer, v reflect.Value, indent int) {
+	for _, f := range yamlFields(v) {
+		writeYAMLValue(b, f.name, f.v, indent)
+	}
+}
+
+// writeYAMLValue writes "key: value" at indent, nesting structs and slices below it.
+func writeYAMLValue(b *bytes.Buffer, key string, v reflect.Value, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			fmt.Fprintf(b, "%s%s: null\n", pad, key)
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		fmt.Fprintf(b, "%s%s:\n", pad, key)
+		writeYAMLFields(b, v, indent+2)
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			fmt.Fprintf(b, "%s%s: []\n", pad, key)
+			return
+		}
+		fmt.Fprintf(b, "%s%s:\n", pad, key)
+		for i := 0; i < v.Len(); i++ {
+			writeYAMLItem(b, v.Index(i), indent+2)
+		}
+	default:
+		fmt.Fprintf(b, "%s%s: %s\n", pad, key, yamlScalar(v))
+	}
+}
+
+// writeYAMLItem writes one "- " sequence item at indent; a struct item's fields follow
+// the dash, aligned two columns in.
+func writeYAMLItem(b *bytes.Buffer, v reflect.Value, indent int) {
+	pad := strings.Repeat(" ", indent)
+	v = reflect.Indirect(v)
+	if v.Kind() != reflect.Struct {
+		fmt.Fprintf(b, "%s- %s\n", pad, yamlScalar(v))
+		return
+	}
+	var item bytes.Buffer
+	writeYAMLFields(&item, v, indent+2)
+	if item.Len() == 0 {
+		fmt.Fprintf(b, "%s- {}\n", pad)
+		return
+	}
+	b.WriteString(pad + "- ")
+	b.Write(item.Bytes()[indent+2:])
+}
+
+// yamlScalar renders a scalar; strconv.Quote's escapes are valid in YAML
+// double-quoted strings.
+func yamlScalar(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Invalid:
+		return "null"
+	}
+	return strconv.Quote(fmt.Sprint(v.Interface()))
+}
@@ -10,3 +10,27 @@ func TestFirstNonEmpty(t *testing.T) {
 		t.Fatalf("FirstNonEmpty empty: want '', got %q", got)
 	}
 }
+
+func TestLevenshteinAndSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"graph", "grpah", 2},
+		{"café", "cafe", 1},
+	}
+	for _, c := range cases {
+		if got := Levenshtein(c.a, c.b); got != c.want {
+			t.Fatalf("Levenshtein(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+	if s := Similarity("", ""); s != 1 {
+		t.Fatalf("Similarity of empties: %v", s)
+	}
+	if s := Similarity("networks", "netwroks"); s != 0.75 {
+		t.Fatalf("Similarity(networks, netwroks) = %v, want 0.75", s)
+	}
+}
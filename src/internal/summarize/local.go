@@ -0,0 +1,81 @@
+package summarize
+
+import (
+	"sort"
+	"strings"
+
+	"bibliography/src/internal/store"
+)
+
+// DefaultLocalKeywords caps how many keywords LocalKeywords returns by default.
+const DefaultLocalKeywords = 8
+
+// stopwords are common English function words and filler that make poor keywords.
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		a about above after again against all also am an and any are as at be because been before being below
+		between both but by can could did do does doing down during each few for from further had has have having
+		he her here hers him his how however i if in into is it its itself just may me might more most must my no
+		nor not now of off on once only or other our ours out over own per same she should so some such than that
+		the their theirs them then there these they this those through to too under until up upon very via was we
+		were what when where which while who whom why will with within without would you your
+		using use used new based toward towards among across versus vs etc part one two three first second
+		introduction overview study approach paper chapter edition volume vol`) {
+		stopwords[w] = true
+	}
+}
+
+// LocalKeywords derives up to limit keywords from title and summary without calling
+// OpenAI: tokens are lowercased, stopwords, numbers, and short words are dropped, and
+// the rest are ranked by frequency (title words count double), then by first
+// appearance. Boilerplate "Bibliographic record ..." summaries are ignored.
+func LocalKeywords(title, summary string, limit int) []string {
+	if limit <= 0 {
+		limit = DefaultLocalKeywords
+	}
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(summary)), "bibliographic record") {
+		summary = ""
+	}
+	score := map[string]int{}
+	first := map[string]int{}
+	pos := 0
+	add := func(text string, weight int) {
+		for _, w := range store.TokenizeWords(text) {
+			if !keywordCandidate(w) {
+				continue
+			}
+			if _, ok := first[w]; !ok {
+				first[w] = pos
+			}
+			pos++
+			score[w] += weight
+		}
+	}
+	add(title, 2)
+	add(summary, 1)
+	out := make([]string, 0, len(score))
+	for w := range score {
+		out = append(out, w)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if score[out[i]] != score[out[j]] {
+			return score[out[i]] > score[out[j]]
+		}
+		return first[out[i]] < first[out[j]]
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// keywordCandidate keeps content-word tokens: at least three characters, not a
+// stopword, and not purely numeric.
+func keywordCandidate(w string) bool {
+	if len(w) < 3 || stopwords[w] {
+		return false
+	}
+	return strings.Trim(w, "0123456789") != ""
+}
@@ -0,0 +1,16 @@
+package summarize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocalKeywords(t *testing.T) {
+	got := LocalKeywords("Distributed Consensus in the Cloud: A Study of Raft", "We evaluate Raft consensus across 3 cloud regions.", 4)
+	if strings.Join(got, ",") != "consensus,cloud,raft,distributed" {
+		t.Fatalf("unexpected keywords: %v", got)
+	}
+	if got := LocalKeywords("Graphs", "Bibliographic record for Graphs (manually constructed).", 0); strings.Join(got, ",") != "graphs" {
+		t.Fatalf("boilerplate summary should be ignored: %v", got)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"os"
 	"strings"
-	"time"
 
 	"bibliography/src/internal/dates"
 	"bibliography/src/internal/httpx"
@@ -17,7 +16,7 @@ import (
 	"bibliography/src/internal/schema"
 )
 
-var client httpx.Doer = &http.Client{Timeout: 15 * time.Second}
+var client httpx.Doer = httpx.DefaultClient()
 
 // SetHTTPClient sets the HTTP client used for OpenAI API calls (for tests).
 func SetHTTPClient(c httpx.Doer) { client = c }
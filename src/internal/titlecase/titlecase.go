@@ -0,0 +1,94 @@
+// Package titlecase converts titles between APA7 sentence case and title case.
+package titlecase
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minorWords are the short conjunctions, articles, and prepositions APA7 keeps
+// lowercase in title case (words of four or more letters are always capitalized).
+var minorWords = map[string]bool{
+	"a": true, "an": true, "the": true,
+	"and": true, "as": true, "but": true, "for": true, "if": true, "nor": true, "or": true, "so": true, "yet": true,
+	"at": true, "by": true, "in": true, "of": true, "off": true, "on": true, "per": true, "to": true, "up": true, "via": true,
+}
+
+// SentenceCase converts a title-cased title to APA sentence case: the first word and
+// the first word after a colon (or ?, !) are capitalized, other words that are
+// capitalized only on their first letter are lowercased. Acronyms and mixed-case words
+// (APA, HTTP/2, iPhone, JavaScript) are kept, but proper nouns cannot be detected and
+// are lowercased like any other word.
+func SentenceCase(title string) string {
+	words := strings.Fields(title)
+	capNext := true
+	for i, w := range words {
+		rs := []rune(w)
+		if capNext {
+			words[i] = capitalize(w)
+		} else if unicode.IsUpper(rs[0]) && string(rs[1:]) == strings.ToLower(string(rs[1:])) && (len(rs) > 1 || rs[0] == 'A') {
+			words[i] = strings.ToLower(w)
+		}
+		capNext = endsClause(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// TitleCase converts a title to APA title case: the first word, the first word after
+// a colon (or ?, !), and every word except short minor words (see minorWords) are
+// capitalized, including each part of a hyphenated word. Acronyms and mixed-case
+// words are kept as written.
+func TitleCase(title string) string {
+	words := strings.Fields(title)
+	capNext := true
+	for i, w := range words {
+		parts := strings.Split(w, "-")
+		for j, p := range parts {
+			switch {
+			case mixedCase(p):
+			case (capNext && j == 0) || i == len(words)-1 || !minorWords[strings.ToLower(strings.Trim(p, ".,;:?!\"'()"))]:
+				parts[j] = capitalize(strings.ToLower(p))
+			default:
+				parts[j] = strings.ToLower(p)
+			}
+		}
+		words[i] = strings.Join(parts, "-")
+		capNext = endsClause(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize upper-cases the first letter of w, skipping leading punctuation such as
+// an opening quote or parenthesis.
+func capitalize(w string) string {
+	rs := []rune(w)
+	for i, r := range rs {
+		if unicode.IsLetter(r) {
+			rs[i] = unicode.ToUpper(r)
+			break
+		}
+		if unicode.IsDigit(r) {
+			break
+		}
+	}
+	return string(rs)
+}
+
+// mixedCase reports whether w has an upper-case letter after its first rune (an
+// acronym like "HTTP/2" or a name like "iPhone"), which case conversion leaves alone.
+func mixedCase(w string) bool {
+	rs := []rune(w)
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// endsClause reports whether the next word starts a new clause (after :, ?, or !).
+func endsClause(w string) bool {
+	rs := []rune(w)
+	last := rs[len(rs)-1]
+	return last == ':' || last == '?' || last == '!'
+}
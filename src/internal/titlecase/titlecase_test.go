@@ -0,0 +1,36 @@
+package titlecase
+
+import "testing"
+
+func TestSentenceCase(t *testing.T) {
+	cases := map[string]string{
+		"The Design Of Everyday Things":        "The design of everyday things",
+		"Learning APA Style: A Guide For iOS":  "Learning APA style: A guide for iOS",
+		"A Tale: Two Cities":                   "A tale: Two cities",
+		"a tale: two cities":                   "A tale: Two cities",
+		"Deploying HTTP/2 In Production":       "Deploying HTTP/2 in production",
+		"Why Does It Matter? An Answer":        "Why does it matter? An answer",
+		"\"Quoted\" Openings Stay Capitalized": "\"Quoted\" openings stay capitalized",
+	}
+	for in, want := range cases {
+		if got := SentenceCase(in); got != want {
+			t.Errorf("SentenceCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTitleCase(t *testing.T) {
+	cases := map[string]string{
+		"a tale: two cities":                  "A Tale: Two Cities",
+		"journal of the american society":     "Journal of the American Society",
+		"notes on HTTP/2 and the web":         "Notes on HTTP/2 and the Web",
+		"self-report measures in psychology":  "Self-Report Measures in Psychology",
+		"the iPhone: a history with an index": "The iPhone: A History With an Index",
+		"what it is for":                      "What It Is For",
+	}
+	for in, want := range cases {
+		if got := TitleCase(in); got != want {
+			t.Errorf("TitleCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
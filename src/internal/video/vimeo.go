@@ -0,0 +1,21 @@
+package video
+
+import (
+	"context"
+	"fmt"
+
+	"bibliography/src/internal/schema"
+)
+
+// FetchVimeo fetches minimal metadata for a Vimeo video via Vimeo's oEmbed endpoint:
+// title, uploader (as corporate author), upload date, and Vimeo as container/publisher.
+func FetchVimeo(ctx context.Context, pageURL string) (schema.Entry, error) {
+	if ProviderFor(pageURL) != ProviderVimeo {
+		return schema.Entry{}, fmt.Errorf("invalid vimeo url")
+	}
+	o, err := fetchOEmbed(ctx, "https://vimeo.com/api/oembed.json", pageURL, "vimeo")
+	if err != nil {
+		return schema.Entry{}, err
+	}
+	return entryFromOEmbed(o, pageURL, "Vimeo")
+}
@@ -0,0 +1,68 @@
+package video
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type routeDoer struct {
+	requested *string
+	body      string
+}
+
+func (d routeDoer) Do(req *http.Request) (*http.Response, error) {
+	*d.requested = req.URL.String()
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(d.body)), Header: make(http.Header)}, nil
+}
+
+func TestFetchVimeo_OEmbed(t *testing.T) {
+	var got string
+	SetHTTPClient(routeDoer{requested: &got, body: `{"type":"video","provider_name":"Vimeo","title":"Moon Landing Restored","author_name":"Jane Doe","upload_date":"2013-04-19 10:57:10"}`})
+	t.Cleanup(func() { SetHTTPClient(&http.Client{}) })
+
+	e, provider, err := Fetch(context.Background(), "https://vimeo.com/76979871")
+	if err != nil {
+		t.Fatalf("Fetch vimeo: %v", err)
+	}
+	if provider != ProviderVimeo || !strings.HasPrefix(got, "https://vimeo.com/api/oembed.json?") || !strings.Contains(got, "url=https%3A%2F%2Fvimeo.com%2F76979871") {
+		t.Fatalf("dispatch: provider=%q request=%q", provider, got)
+	}
+	if e.Type != "video" || e.APA7.Title != "Moon Landing Restored" || e.APA7.ContainerTitle != "Vimeo" || e.APA7.Publisher != "Vimeo" {
+		t.Fatalf("entry: %+v", e.APA7)
+	}
+	if len(e.APA7.Authors) != 1 || e.APA7.Authors[0].Family != "Jane Doe" {
+		t.Fatalf("authors: %+v", e.APA7.Authors)
+	}
+	if e.APA7.Date != "2013-04-19" || e.APA7.Year == nil || *e.APA7.Year != 2013 {
+		t.Fatalf("date: %q %v", e.APA7.Date, e.APA7.Year)
+	}
+	if e.Annotation.Summary != "Vimeo video: Moon Landing Restored by Jane Doe." {
+		t.Fatalf("summary: %q", e.Annotation.Summary)
+	}
+}
+
+func TestProviderFor(t *testing.T) {
+	cases := map[string]string{
+		"https://www.youtube.com/watch?v=abc": ProviderYouTube,
+		"https://youtu.be/abc":                ProviderYouTube,
+		"https://m.youtube.com/watch?v=abc":   ProviderYouTube,
+		"https://vimeo.com/123":               ProviderVimeo,
+		"https://player.vimeo.com/video/123":  ProviderVimeo,
+		"https://example.com/v/1":             "",
+		"https://notvimeo.com/1":              "",
+	}
+	for in, want := range cases {
+		if got := ProviderFor(in); got != want {
+			t.Errorf("ProviderFor(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if _, _, err := Fetch(context.Background(), "https://example.com/v/1"); err == nil {
+		t.Fatalf("unsupported host should fail")
+	}
+	if _, err := FetchVimeo(context.Background(), "https://www.youtube.com/watch?v=abc"); err == nil {
+		t.Fatalf("FetchVimeo should reject non-Vimeo urls")
+	}
+}
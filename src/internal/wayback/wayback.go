@@ -0,0 +1,74 @@
+// Package wayback submits URLs to the Internet Archive's Wayback Machine Save API and
+// returns the resulting snapshot URL.
+package wayback
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"bibliography/src/internal/httpx"
+)
+
+// Base is the Wayback Machine origin; snapshot paths are resolved against it.
+const Base = "https://web.archive.org"
+
+var client httpx.Doer = httpx.DefaultClient()
+
+// SetHTTPClient sets the HTTP client used for Save API requests (for tests).
+func SetHTTPClient(c httpx.Doer) { client = c }
+
+// Save asks the Wayback Machine to capture pageURL and returns the snapshot URL
+// (https://web.archive.org/web/<timestamp>/<url>). The snapshot is taken from the
+// Content-Location header, else a Location header, else the final request URL after
+// redirects.
+func Save(ctx context.Context, pageURL string) (string, error) {
+	pageURL = strings.TrimSpace(pageURL)
+	if u, err := url.Parse(pageURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("wayback: invalid url %q", pageURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, Base+"/save/"+pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	httpx.SetUA(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
+	if err != nil {
+		return "", fmt.Errorf("wayback: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("wayback: http %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	candidates := []string{resp.Header.Get("Content-Location"), resp.Header.Get("Location")}
+	if resp.Request != nil && resp.Request.URL != nil {
+		candidates = append(candidates, resp.Request.URL.String())
+	}
+	for _, c := range candidates {
+		if s, ok := snapshotURL(c); ok {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("wayback: no snapshot url in response for %s", pageURL)
+}
+
+// snapshotURL resolves a (possibly relative) /web/... reference against Base.
+func snapshotURL(ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", false
+	}
+	u, err := url.Parse(ref)
+	if err != nil || !strings.HasPrefix(u.Path, "/web/") {
+		return "", false
+	}
+	if u.Host != "" && !strings.EqualFold(u.Hostname(), "web.archive.org") {
+		return "", false
+	}
+	// Keep the archived URL verbatim: it follows /web/<timestamp>/ unescaped.
+	return Base + ref[strings.Index(ref, "/web/"):], true
+}
@@ -0,0 +1,72 @@
+package wayback
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"bibliography/src/internal/httpx"
+)
+
+func TestMain(m *testing.M) {
+	httpx.SetDefaultRetryPolicy(httpx.RetryPolicy{MaxAttempts: 3})
+	os.Exit(m.Run())
+}
+
+type fakeDoer func(*http.Request) *http.Response
+
+func (f fakeDoer) Do(req *http.Request) (*http.Response, error) { return f(req), nil }
+
+func resp(code int, h http.Header) *http.Response {
+	if h == nil {
+		h = http.Header{}
+	}
+	return &http.Response{StatusCode: code, Header: h, Body: io.NopCloser(strings.NewReader(""))}
+}
+
+func TestSave(t *testing.T) {
+	t.Cleanup(func() { SetHTTPClient(httpx.DefaultClient()) })
+	var got []string
+	calls := 0
+	SetHTTPClient(fakeDoer(func(req *http.Request) *http.Response {
+		got = append(got, req.URL.String())
+		if calls++; calls == 1 {
+			return resp(http.StatusTooManyRequests, nil)
+		}
+		return resp(http.StatusOK, http.Header{"Content-Location": {"/web/20240102030405/https://example.com/a?b=1"}})
+	}))
+	s, err := Save(context.Background(), "https://example.com/a?b=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "https://web.archive.org/web/20240102030405/https://example.com/a?b=1" {
+		t.Fatalf("snapshot = %q", s)
+	}
+	if len(got) != 2 || got[1] != "https://web.archive.org/save/https://example.com/a?b=1" {
+		t.Fatalf("requests = %v", got)
+	}
+}
+
+func TestSaveFallbacks(t *testing.T) {
+	t.Cleanup(func() { SetHTTPClient(httpx.DefaultClient()) })
+	SetHTTPClient(fakeDoer(func(req *http.Request) *http.Response {
+		return resp(http.StatusFound, http.Header{"Location": {"https://web.archive.org/web/2024/https://example.com/"}})
+	}))
+	if s, err := Save(context.Background(), "https://example.com/"); err != nil || s != "https://web.archive.org/web/2024/https://example.com/" {
+		t.Fatalf("location fallback: %q %v", s, err)
+	}
+	SetHTTPClient(fakeDoer(func(req *http.Request) *http.Response { return resp(http.StatusOK, nil) }))
+	if _, err := Save(context.Background(), "https://example.com/"); err == nil || !strings.Contains(err.Error(), "no snapshot") {
+		t.Fatalf("expected no snapshot error, got %v", err)
+	}
+	SetHTTPClient(fakeDoer(func(req *http.Request) *http.Response { return resp(http.StatusForbidden, nil) }))
+	if _, err := Save(context.Background(), "https://example.com/"); err == nil || !strings.Contains(err.Error(), "http 403") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	if _, err := Save(context.Background(), "not a url"); err == nil {
+		t.Fatalf("expected invalid url error")
+	}
+}
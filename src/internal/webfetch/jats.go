@@ -0,0 +1,225 @@
+package webfetch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"bibliography/src/internal/dates"
+	"bibliography/src/internal/names"
+	"bibliography/src/internal/sanitize"
+	"bibliography/src/internal/schema"
+	"bibliography/src/internal/stringsx"
+)
+
+// jatsArticle maps the parts of a JATS <article> document used for citations.
+type jatsArticle struct {
+	XMLName xml.Name `xml:"article"`
+	Journal struct {
+		Titles    []string `xml:"journal-title-group>journal-title"`
+		Title     string   `xml:"journal-title"`
+		Publisher string   `xml:"publisher>publisher-name"`
+		ISSN      []string `xml:"issn"`
+	} `xml:"front>journal-meta"`
+	Meta jatsMeta `xml:"front>article-meta"`
+}
+
+type jatsMeta struct {
+	IDs []struct {
+		Type  string `xml:"pub-id-type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"article-id"`
+	Title     jatsInner      `xml:"title-group>article-title"`
+	Contribs  []jatsContrib  `xml:"contrib-group>contrib"`
+	PubDates  []jatsDate     `xml:"pub-date"`
+	Volume    string         `xml:"volume"`
+	Issue     string         `xml:"issue"`
+	FPage     string         `xml:"fpage"`
+	LPage     string         `xml:"lpage"`
+	ELocation string         `xml:"elocation-id"`
+	Abstracts []jatsInner    `xml:"abstract"`
+	Keywords  []jatsKwdGroup `xml:"kwd-group"`
+}
+
+type jatsContrib struct {
+	Type   string   `xml:"contrib-type,attr"`
+	Name   jatsName `xml:"name"`
+	String string   `xml:"string-name"`
+	Collab string   `xml:"collab"`
+}
+
+type jatsName struct {
+	Surname string `xml:"surname"`
+	Given   string `xml:"given-names"`
+}
+
+type jatsDate struct {
+	PubType  string `xml:"pub-type,attr"`
+	DateType string `xml:"date-type,attr"`
+	Year     string `xml:"year"`
+	Month    string `xml:"month"`
+	Day      string `xml:"day"`
+}
+
+// jatsInner keeps the raw markup of mixed-content elements (<italic>, <sup>, <p>).
+type jatsInner struct {
+	Inner string `xml:",innerxml"`
+}
+
+type jatsKwdGroup struct {
+	Kwds []jatsInner `xml:"kwd"`
+}
+
+var reXMLTag = regexp.MustCompile(`(?s)<[^>]*>`)
+var reJATSSectionTitle = regexp.MustCompile(`(?is)<title>.*?</title>`)
+
+// isJATS reports whether a response looks like a JATS XML article: an XML content
+// type (not XHTML) and an <article-meta> element in the body.
+func isJATS(contentType string, body []byte) bool {
+	if !strings.Contains(contentType, "xml") || strings.Contains(contentType, "html") {
+		return false
+	}
+	return bytes.Contains(body, []byte("<article-meta"))
+}
+
+// parseJATS parses JATS XML (<article-meta> and <journal-meta>) and constructs an Entry.
+func parseJATS(b []byte, sourceURL string) (schema.Entry, error) {
+	var doc jatsArticle
+	d := xml.NewDecoder(bytes.NewReader(b))
+	// Publisher XML often references a DTD for entities like &nbsp;.
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	if err := d.Decode(&doc); err != nil {
+		return schema.Entry{}, fmt.Errorf("jats: %w", err)
+	}
+	m := doc.Meta
+	journal := stringsx.FirstNonEmpty(append(doc.Journal.Titles, doc.Journal.Title)...)
+	journal = jatsText(journal)
+
+	e := schema.Entry{Type: "article"}
+	e.APA7.Title = jatsText(m.Title.Inner)
+	e.APA7.Journal = journal
+	e.APA7.ContainerTitle = journal
+	e.APA7.Publisher = strings.TrimSpace(doc.Journal.Publisher)
+	e.APA7.Volume = strings.TrimSpace(m.Volume)
+	e.APA7.Issue = strings.TrimSpace(m.Issue)
+	e.APA7.Pages = jatsPages(m.FPage, m.LPage, m.ELocation)
+	if len(doc.Journal.ISSN) > 0 {
+		e.APA7.ISSN = strings.TrimSpace(doc.Journal.ISSN[0])
+	}
+	for _, id := range m.IDs {
+		if strings.EqualFold(id.Type, "doi") {
+			e.APA7.DOI = strings.TrimSpace(id.Value)
+			break
+		}
+	}
+	if y, date := jatsPubDate(m.PubDates); y > 0 {
+		e.APA7.Year = &y
+		e.APA7.Date = date
+	}
+	for _, c := range m.Contribs {
+		if c.Type != "" && c.Type != "author" {
+			continue
+		}
+		switch {
+		case strings.TrimSpace(c.Name.Surname) != "":
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: strings.TrimSpace(c.Name.Surname), Given: names.Initials(c.Name.Given)})
+		case strings.TrimSpace(c.String) != "":
+			if fam, giv := names.Split(c.String); fam != "" {
+				e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: fam, Given: giv})
+			}
+		case strings.TrimSpace(c.Collab) != "":
+			e.APA7.Authors = append(e.APA7.Authors, schema.Author{Family: jatsText(c.Collab)})
+		}
+	}
+	host := hostOf(sourceURL)
+	if e.APA7.Publisher == "" {
+		e.APA7.Publisher = host
+	}
+	if e.APA7.Title == "" {
+		e.APA7.Title = host
+	}
+	e.APA7.URL = sourceURL
+	e.APA7.Accessed = dates.NowISO()
+
+	var abstract string
+	if len(m.Abstracts) > 0 {
+		abstract = jatsText(reJATSSectionTitle.ReplaceAllString(m.Abstracts[0].Inner, " "))
+	}
+	if abstract != "" {
+		e.Annotation.Summary = abstract
+	} else {
+		e.Annotation.Summary = fmt.Sprintf("Bibliographic record for %s in %s.", e.APA7.Title, stringsx.FirstNonEmpty(journal, host))
+	}
+	e.Annotation.Keywords = []string{"article"}
+	for _, g := range m.Keywords {
+		for _, k := range g.Kwds {
+			e.Annotation.Keywords = append(e.Annotation.Keywords, jatsText(k.Inner))
+		}
+	}
+	e.ID = schema.NewID()
+	sanitize.CleanEntry(&e)
+	if err := e.Validate(); err != nil {
+		return schema.Entry{}, err
+	}
+	return e, nil
+}
+
+// jatsText strips inline markup from mixed content and collapses whitespace.
+func jatsText(s string) string {
+	s = html.UnescapeString(reXMLTag.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// jatsPages joins fpage/lpage ("12-19"), falling back to the electronic location id.
+func jatsPages(first, last, eloc string) string {
+	first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+	switch {
+	case first != "" && last != "" && last != first:
+		return first + "-" + last
+	case first != "":
+		return first
+	}
+	return strings.TrimSpace(eloc)
+}
+
+// jatsPubDate picks the publication date (preferring the final or electronic issue
+// date over collection dates) and returns its year and the most precise ISO date.
+func jatsPubDate(ds []jatsDate) (int, string) {
+	best := -1
+	for i, d := range ds {
+		if strings.TrimSpace(d.Year) == "" {
+			continue
+		}
+		if best < 0 {
+			best = i
+		}
+		switch strings.ToLower(stringsx.FirstNonEmpty(d.DateType, d.PubType)) {
+		case "pub", "epub", "ppub", "epub-ppub":
+			best = i
+		default:
+			continue
+		}
+		break
+	}
+	if best < 0 {
+		return 0, ""
+	}
+	d := ds[best]
+	y, err := strconv.Atoi(strings.TrimSpace(d.Year))
+	if err != nil || y <= 0 {
+		return 0, ""
+	}
+	date := fmt.Sprintf("%04d", y)
+	if mo, err := strconv.Atoi(strings.TrimSpace(d.Month)); err == nil && mo >= 1 && mo <= 12 {
+		date += fmt.Sprintf("-%02d", mo)
+		if day, err := strconv.Atoi(strings.TrimSpace(d.Day)); err == nil && day >= 1 && day <= 31 {
+			date += fmt.Sprintf("-%02d", day)
+		}
+	}
+	return y, date
+}
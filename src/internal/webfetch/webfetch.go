@@ -9,7 +9,6 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
-	"time"
 
 	"bibliography/src/internal/dates"
 	"bibliography/src/internal/httpx"
@@ -19,7 +18,7 @@ import (
 	"bibliography/src/internal/stringsx"
 )
 
-var client httpx.Doer = &http.Client{Timeout: 10 * time.Second}
+var client httpx.Doer = httpx.ProviderClient()
 
 // SetHTTPClient sets the HTTP client used for outbound requests (for tests).
 func SetHTTPClient(c httpx.Doer) { client = c }
@@ -53,7 +52,8 @@ func (e *HTTPStatusError) Error() string {
 }
 
 // FetchArticleByURL fetches a web page and tries to map it to an APA7 article entry
-// using OpenGraph, JSON-LD, and common meta tags.
+// using OpenGraph, JSON-LD, and common meta tags. PDF and JATS XML responses are
+// routed to their own parsers.
 func FetchArticleByURL(ctx context.Context, raw string) (schema.Entry, error) {
 	u := strings.TrimSpace(raw)
 	if _, err := url.ParseRequestURI(u); err != nil {
@@ -63,7 +63,7 @@ func FetchArticleByURL(ctx context.Context, raw string) (schema.Entry, error) {
 	if err != nil {
 		return schema.Entry{}, err
 	}
-	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept", "text/html, application/xml;q=0.9")
 	httpx.SetUA(req)
 	resp, err := client.Do(req)
 	if err != nil {
@@ -84,6 +84,9 @@ func FetchArticleByURL(ctx context.Context, raw string) (schema.Entry, error) {
 	if strings.Contains(ct, "pdf") || strings.HasSuffix(strings.ToLower(u), ".pdf") {
 		return pdfExtractor.BuildEntryFromPDF(ctx, bodyBytes, u)
 	}
+	if isJATS(ct, bodyBytes) {
+		return parseJATS(bodyBytes, u)
+	}
 
 	og, metaTitle := parseOpenGraphAndTitle(body)
 	ld := parseJSONLDArticle(body)
@@ -92,6 +95,10 @@ func FetchArticleByURL(ctx context.Context, raw string) (schema.Entry, error) {
 	site := stringsx.FirstNonEmpty(og["og:site_name"], ld.publisher, hostOf(u))
 	desc := stringsx.FirstNonEmpty(og["og:description"], ld.description, metaName(body, "description"))
 	pub := stringsx.FirstNonEmpty(ld.publisher, site)
+	if ld.report && strings.TrimSpace(ld.publisher) == "" && len(ld.affiliations) > 0 {
+		// Reports are issued by the authors' institution when no publisher is declared.
+		pub = ld.affiliations[0]
+	}
 
 	// Authors
 	var authors []schema.Author
@@ -128,7 +135,7 @@ func FetchArticleByURL(ctx context.Context, raw string) (schema.Entry, error) {
 
 	e := schema.Entry{Type: "article"}
 	e.APA7.Title = title
-	e.APA7.ContainerTitle = site
+	e.APA7.ContainerTitle = stringsx.FirstNonEmpty(ld.container, site)
 	e.APA7.Publisher = pub
 	if yearPtr != nil {
 		e.APA7.Year = yearPtr
@@ -138,6 +145,9 @@ func FetchArticleByURL(ctx context.Context, raw string) (schema.Entry, error) {
 	}
 	e.APA7.URL = u
 	e.APA7.Accessed = dates.NowISO()
+	if ld.doi != "" {
+		e.APA7.DOI = ld.doi
+	}
 	e.APA7.Authors = authors
 	if strings.TrimSpace(desc) != "" {
 		e.Annotation.Summary = desc
@@ -214,66 +224,201 @@ type ldArticle struct {
 
 type simplifiedLD struct {
 	headline, name, description, datePublished, publisher string
-	authors                                               []string
+	container, doi                                        string
+	report                                                bool
+	authors, affiliations                                 []string
 }
 
-// parseJSONLDArticle extracts a simplified article from JSON-LD if present.
+// parseJSONLDArticle extracts a simplified article from JSON-LD if present. Every
+// ld+json block is read and @graph containers are flattened; the node with the most
+// specific scholarly type wins, and @id references are resolved against the graph.
 func parseJSONLDArticle(body string) simplifiedLD {
-	m := reLDJSON.FindStringSubmatch(body)
-	if len(m) != 2 {
-		return simplifiedLD{}
-	}
-	payload := strings.TrimSpace(m[1])
-	// Could be array or object
-	dec := json.NewDecoder(strings.NewReader(payload))
-	dec.UseNumber()
-	var anyv any
-	if err := dec.Decode(&anyv); err != nil {
-		return simplifiedLD{}
+	var nodes []map[string]any
+	for _, m := range reLDJSON.FindAllStringSubmatch(body, -1) {
+		dec := json.NewDecoder(strings.NewReader(strings.TrimSpace(m[1])))
+		dec.UseNumber()
+		var anyv any
+		if err := dec.Decode(&anyv); err != nil {
+			continue
+		}
+		nodes = collectLDNodes(anyv, nodes)
 	}
-	// Find first object with @type Article/NewsArticle
+	// Find the best-ranked typed node; a lone untyped object is accepted as-is.
 	var obj map[string]any
-	switch t := anyv.(type) {
-	case []any:
-		for _, it := range t {
-			if o, ok := it.(map[string]any); ok {
-				if hasArticleType(o["@type"]) {
-					obj = o
-					break
-				}
-			}
+	best := 0
+	for _, n := range nodes {
+		if r := ldTypeRank(n["@type"]); r > 0 && (best == 0 || r < best) {
+			obj, best = n, r
 		}
-	case map[string]any:
-		obj = t
+	}
+	if obj == nil && len(nodes) == 1 {
+		obj = nodes[0]
 	}
 	if obj == nil {
 		return simplifiedLD{}
 	}
+	byID := map[string]map[string]any{}
+	for _, n := range nodes {
+		if id, ok := n["@id"].(string); ok && id != "" {
+			byID[id] = n
+		}
+	}
 	var out simplifiedLD
 	out.headline, _ = obj["headline"].(string)
 	out.name, _ = obj["name"].(string)
 	out.description, _ = obj["description"].(string)
 	out.datePublished, _ = obj["datePublished"].(string)
-	out.publisher = pickName(obj["publisher"])
+	out.publisher = pickName(resolveLD(obj["publisher"], byID))
+	out.container = stringsx.FirstNonEmpty(ldContainer(obj["isPartOf"], byID), ldContainer(obj["publication"], byID))
+	out.doi = stringsx.FirstNonEmpty(ldDOI(obj["sameAs"]), ldDOI(obj["identifier"]), ldDOI(obj["@id"]))
+	out.report = best == ldRankReport
 	// authors
-	out.authors = extractAuthors(obj["author"])
+	authors := resolveLD(obj["author"], byID)
+	out.authors = extractAuthors(authors)
+	out.affiliations = extractAffiliations(authors, byID)
+	return out
+}
+
+// collectLDNodes flattens JSON-LD arrays and @graph containers into a list of objects.
+func collectLDNodes(v any, out []map[string]any) []map[string]any {
+	switch t := v.(type) {
+	case []any:
+		for _, it := range t {
+			out = collectLDNodes(it, out)
+		}
+	case map[string]any:
+		if g, ok := t["@graph"]; ok {
+			out = collectLDNodes(g, out)
+			if _, typed := t["@type"]; !typed {
+				return out
+			}
+		}
+		out = append(out, t)
+	}
 	return out
 }
 
-// hasArticleType reports whether a JSON-LD @type contains an Article flavor.
-func hasArticleType(v any) bool {
+// JSON-LD node ranks; lower is preferred when a page carries several nodes.
+const (
+	ldRankScholarly = iota + 1
+	ldRankArticle
+	ldRankReport
+	ldRankBook
+	ldRankDataset
+)
+
+// ldTypeRank returns the preference rank of a JSON-LD @type, or 0 when it is not mapped.
+func ldTypeRank(v any) int {
+	best := 0
+	for _, t := range ldTypes(v) {
+		r := 0
+		switch {
+		case t == "scholarlyarticle":
+			r = ldRankScholarly
+		case strings.Contains(t, "article"):
+			r = ldRankArticle
+		case t == "report":
+			r = ldRankReport
+		case t == "book":
+			r = ldRankBook
+		case t == "dataset":
+			r = ldRankDataset
+		}
+		if r > 0 && (best == 0 || r < best) {
+			best = r
+		}
+	}
+	return best
+}
+
+// ldTypes returns the lower-cased @type values from a string or array.
+func ldTypes(v any) []string {
 	switch t := v.(type) {
 	case string:
-		tv := strings.ToLower(t)
-		return strings.Contains(tv, "article")
+		return []string{strings.ToLower(strings.TrimSpace(t))}
 	case []any:
+		var out []string
 		for _, it := range t {
-			if hasArticleType(it) {
-				return true
+			out = append(out, ldTypes(it)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// resolveLD replaces {"@id": "..."} references with the matching graph node.
+func resolveLD(v any, byID map[string]map[string]any) any {
+	switch t := v.(type) {
+	case []any:
+		out := make([]any, len(t))
+		for i, it := range t {
+			out[i] = resolveLD(it, byID)
+		}
+		return out
+	case map[string]any:
+		if id, ok := t["@id"].(string); ok && len(t) == 1 {
+			if n, ok := byID[id]; ok {
+				return n
 			}
 		}
 	}
-	return false
+	return v
+}
+
+// ldContainer returns the periodical or site name from isPartOf/publication values,
+// following issue -> volume -> periodical nesting. Plain web pages are skipped.
+func ldContainer(v any, byID map[string]map[string]any) string {
+	switch t := resolveLD(v, byID).(type) {
+	case string:
+		if s := strings.TrimSpace(t); !strings.Contains(s, "://") {
+			return s
+		}
+	case []any:
+		for _, it := range t {
+			if s := ldContainer(it, byID); s != "" {
+				return s
+			}
+		}
+	case map[string]any:
+		if s := ldContainer(t["isPartOf"], byID); s != "" {
+			return s
+		}
+		for _, typ := range ldTypes(t["@type"]) {
+			if typ == "webpage" {
+				return ""
+			}
+		}
+		return pickName(t)
+	}
+	return ""
+}
+
+// ldDOI returns the first DOI found in sameAs/identifier values (URLs, bare DOIs,
+// or PropertyValue objects).
+func ldDOI(v any) string {
+	switch t := v.(type) {
+	case string:
+		return matchDOI(t)
+	case []any:
+		for _, it := range t {
+			if d := ldDOI(it); d != "" {
+				return d
+			}
+		}
+	case map[string]any:
+		if val, ok := t["value"].(string); ok {
+			return matchDOI(val)
+		}
+		if id, ok := t["@id"].(string); ok {
+			return matchDOI(id)
+		}
+	}
+	return ""
+}
+
+// matchDOI extracts a DOI from s, trimming trailing punctuation.
+func matchDOI(s string) string {
+	return strings.TrimRight(reDOI.FindString(s), ".;,")
 }
 
 // pickName returns a name string from either a string or {name: "..."} map.
@@ -292,6 +437,17 @@ func pickName(v any) string {
 	return ""
 }
 
+// personName returns a display name from a JSON-LD Person/Organization object,
+// falling back to givenName + familyName when name is absent.
+func personName(m map[string]any) string {
+	if n, ok := m["name"].(string); ok && strings.TrimSpace(n) != "" {
+		return n
+	}
+	giv, _ := m["givenName"].(string)
+	fam, _ := m["familyName"].(string)
+	return strings.TrimSpace(giv + " " + fam)
+}
+
 // extractAuthors returns author name strings from common JSON-LD shapes.
 func extractAuthors(v any) []string {
 	var out []string
@@ -305,19 +461,51 @@ func extractAuthors(v any) []string {
 				continue
 			}
 			if m, ok := it.(map[string]any); ok {
-				if n, ok := m["name"].(string); ok {
+				if n := personName(m); n != "" {
 					out = append(out, n)
 				}
 			}
 		}
 	case map[string]any:
-		if n, ok := t["name"].(string); ok {
+		if n := personName(t); n != "" {
 			out = append(out, n)
 		}
 	}
 	return out
 }
 
+// extractAffiliations returns the distinct affiliation names of JSON-LD authors in order.
+func extractAffiliations(v any, byID map[string]map[string]any) []string {
+	var people []any
+	switch t := v.(type) {
+	case []any:
+		people = t
+	case map[string]any:
+		people = []any{t}
+	}
+	var out []string
+	seen := map[string]bool{}
+	for _, p := range people {
+		m, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		aff := resolveLD(m["affiliation"], byID)
+		list, ok := aff.([]any)
+		if !ok {
+			list = []any{aff}
+		}
+		for _, a := range list {
+			n := strings.TrimSpace(pickName(resolveLD(a, byID)))
+			if n != "" && !seen[n] {
+				seen[n] = true
+				out = append(out, n)
+			}
+		}
+	}
+	return out
+}
+
 // metaName finds a meta tag by name and returns its content value.
 func metaName(body string, name string) string {
 	// simple search
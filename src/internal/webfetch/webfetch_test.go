@@ -6,6 +6,8 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -131,3 +133,107 @@ func TestPDFHelpers(t *testing.T) {
 		t.Fatalf("htmlUnescape")
 	}
 }
+
+func TestParseJSONLD_GraphScholarlyArticle(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "jsonld_graph_article.html"))
+	if err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	ld := parseJSONLDArticle(string(b))
+	if ld.headline != "Deep learning for coral reef monitoring" || ld.container != "Journal of Marine Systems" || ld.publisher != "ScienceHub Press" {
+		t.Fatalf("ld: %+v", ld)
+	}
+	if ld.doi != "10.1016/j.jmarsys.2022.103701" {
+		t.Fatalf("doi: %q", ld.doi)
+	}
+	if len(ld.authors) != 2 || ld.authors[1] != "Kenji Sato" {
+		t.Fatalf("authors: %v", ld.authors)
+	}
+	if len(ld.affiliations) != 2 || ld.affiliations[0] != "University of the Sea" || ld.affiliations[1] != "Reef Institute" {
+		t.Fatalf("affiliations: %v", ld.affiliations)
+	}
+
+	old := client
+	defer func() { client = old }()
+	client = fakeHTTP{status: 200, body: string(b), headers: map[string]string{"Content-Type": "text/html"}}
+	e, err := FetchArticleByURL(context.Background(), "https://sciencehub.example.org/article/abc")
+	if err != nil {
+		t.Fatalf("FetchArticleByURL: %v", err)
+	}
+	if e.APA7.ContainerTitle != "Journal of Marine Systems" || e.APA7.DOI != "10.1016/j.jmarsys.2022.103701" || e.APA7.Publisher != "ScienceHub Press" {
+		t.Fatalf("entry: %+v", e.APA7)
+	}
+}
+
+func TestParseJSONLD_ReportAcrossScripts(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "jsonld_report.html"))
+	if err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	ld := parseJSONLDArticle(string(b))
+	if ld.name != "Grid resilience assessment 2021" || !ld.report || ld.doi != "10.2172/1834567" {
+		t.Fatalf("ld: %+v", ld)
+	}
+	if len(ld.authors) != 1 || ld.authors[0] != "Maria Keller" || len(ld.affiliations) != 1 || ld.affiliations[0] != "Northern Energy Laboratory" {
+		t.Fatalf("authors/affiliations: %v %v", ld.authors, ld.affiliations)
+	}
+
+	old := client
+	defer func() { client = old }()
+	client = fakeHTTP{status: 200, body: string(b), headers: map[string]string{"Content-Type": "text/html"}}
+	e, err := FetchArticleByURL(context.Background(), "https://nel.example.gov/reports/grid-2021")
+	if err != nil {
+		t.Fatalf("FetchArticleByURL: %v", err)
+	}
+	if e.APA7.Publisher != "Northern Energy Laboratory" || e.APA7.Title != "Grid resilience assessment 2021" || len(e.APA7.Authors) != 1 || e.APA7.Authors[0].Family != "Keller" {
+		t.Fatalf("entry: %+v", e.APA7)
+	}
+}
+
+func TestParseJSONLD_IgnoresUnmappedNodes(t *testing.T) {
+	body := `<script type="application/ld+json">[{"@type":"Organization","name":"Acme"},{"@type":"WebSite","name":"Acme Site"}]</script>`
+	if ld := parseJSONLDArticle(body); ld.name != "" || ld.headline != "" {
+		t.Fatalf("unmapped nodes should be ignored: %+v", ld)
+	}
+}
+
+func TestFetchArticleByURL_JATSPath(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "jats_article.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := client
+	defer func() { client = old }()
+	client = fakeHTTP{status: 200, body: string(b), headers: map[string]string{"Content-Type": "application/xml; charset=utf-8"}}
+	e, err := FetchArticleByURL(context.Background(), "https://journal.example.org/articles/42.xml")
+	if err != nil {
+		t.Fatalf("FetchArticleByURL jats: %v", err)
+	}
+	a := e.APA7
+	if a.Title != "Measuring in vitro reproducibility at scale" {
+		t.Fatalf("title: %q", a.Title)
+	}
+	if a.Journal != "Journal of Open Research" || a.ContainerTitle != a.Journal || a.Publisher != "Open Press" {
+		t.Fatalf("journal/publisher: %+v", a)
+	}
+	if a.Volume != "12" || a.Issue != "3" || a.Pages != "101-118" || a.DOI != "10.5555/jor.2021.042" || a.ISSN != "1234-5678" {
+		t.Fatalf("volume/issue/pages/ids: %+v", a)
+	}
+	if a.Year == nil || *a.Year != 2021 || a.Date != "2021-03-14" {
+		t.Fatalf("date: %v %q", a.Year, a.Date)
+	}
+	if len(a.Authors) != 3 || a.Authors[0].Family != "Okafor" || a.Authors[0].Given != "N. A." || a.Authors[2].Family != "Reproducibility Consortium" {
+		t.Fatalf("authors: %+v", a.Authors)
+	}
+	if e.Annotation.Summary != "We replicate 40 in vitro studies and report effect sizes." {
+		t.Fatalf("abstract: %q", e.Annotation.Summary)
+	}
+	if strings.Join(e.Annotation.Keywords, ",") != "article,reproducibility,meta-science" {
+		t.Fatalf("keywords: %v", e.Annotation.Keywords)
+	}
+
+	// XML without JATS markers (e.g., an RSS feed) is not treated as JATS.
+	if isJATS("application/xml", []byte("<rss><channel/></rss>")) || isJATS("application/xhtml+xml", b) {
+		t.Fatalf("isJATS should require an XML type and <article-meta>")
+	}
+}
@@ -0,0 +1,110 @@
+// Package zotero pages through a user's library via the Zotero Web API (v3).
+package zotero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"bibliography/src/internal/httpx"
+)
+
+// BaseURL is the Zotero Web API root.
+const BaseURL = "https://api.zotero.org"
+
+// pageSize is the largest page the API serves.
+const pageSize = 100
+
+// client is not the caching provider client: library contents are private and change.
+var client httpx.Doer = httpx.DefaultClient()
+
+// SetHTTPClient allows tests to inject a fake HTTP client.
+func SetHTTPClient(c httpx.Doer) { client = c }
+
+// ItemsURL returns the first page of items in CSL-JSON for user, or for one collection
+// of theirs when collection is non-empty.
+func ItemsURL(user, collection string) string {
+	p := "/users/" + url.PathEscape(user)
+	if collection != "" {
+		p += "/collections/" + url.PathEscape(collection)
+	}
+	return fmt.Sprintf("%s%s/items?format=csljson&limit=%d", BaseURL, p, pageSize)
+}
+
+// FetchItems returns the CSL-JSON items of a user's library (or one collection),
+// following the Link rel="next" header page by page. Paging stops early once
+// Total-Results items have been read. apiKey is sent as Zotero-API-Key; it may be
+// empty for a public library.
+func FetchItems(ctx context.Context, user, apiKey, collection string) ([]json.RawMessage, error) {
+	user = strings.TrimSpace(user)
+	if user == "" {
+		return nil, fmt.Errorf("zotero user id is required")
+	}
+	var items []json.RawMessage
+	next := ItemsURL(user, strings.TrimSpace(collection))
+	for next != "" {
+		page, h, err := fetchPage(ctx, next, apiKey)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		next = nextLink(h.Get("Link"))
+		if total, err := strconv.Atoi(strings.TrimSpace(h.Get("Total-Results"))); err == nil && len(items) >= total {
+			break
+		}
+		if len(page) == 0 {
+			break
+		}
+	}
+	return items, nil
+}
+
+// fetchPage GETs one page; the body is {"items": [...]}.
+func fetchPage(ctx context.Context, u, apiKey string) ([]json.RawMessage, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Zotero-API-Version", "3")
+	if k := strings.TrimSpace(apiKey); k != "" {
+		req.Header.Set("Zotero-API-Key", k)
+	}
+	httpx.SetUA(req)
+	resp, err := httpx.DoWithRetry(client, req, httpx.DefaultRetryPolicy())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, nil, fmt.Errorf("zotero: http %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	var body struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, nil, fmt.Errorf("zotero: %w", err)
+	}
+	return body.Items, resp.Header, nil
+}
+
+// nextLink returns the rel="next" target of an RFC 8288 Link header, or "".
+func nextLink(h string) string {
+	for _, part := range strings.Split(h, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok {
+			continue
+		}
+		for _, p := range strings.Split(params, ";") {
+			if k, v, _ := strings.Cut(strings.TrimSpace(p), "="); strings.EqualFold(k, "rel") && strings.Trim(v, `"`) == "next" {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package zotero
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// pagedHTTP serves canned pages by URL and records the requests.
+type pagedHTTP struct {
+	pages map[string]string
+	links map[string]string
+	total string
+	reqs  *[]*http.Request
+}
+
+func (p pagedHTTP) Do(req *http.Request) (*http.Response, error) {
+	*p.reqs = append(*p.reqs, req)
+	body, ok := p.pages[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("Not found")), Header: make(http.Header)}, nil
+	}
+	h := make(http.Header)
+	h.Set("Total-Results", p.total)
+	if l := p.links[req.URL.String()]; l != "" {
+		h.Set("Link", l)
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: h}, nil
+}
+
+func TestFetchItems_FollowsNextLinks(t *testing.T) {
+	first := ItemsURL("42", "ABCD")
+	second := BaseURL + "/users/42/collections/ABCD/items?format=csljson&limit=100&start=100"
+	var reqs []*http.Request
+	SetHTTPClient(pagedHTTP{
+		pages: map[string]string{
+			first:  `{"items":[{"id":"42/A","type":"book","title":"One"},{"id":"42/B","type":"book","title":"Two"}]}`,
+			second: `{"items":[{"id":"42/C","type":"article-journal","title":"Three"}]}`,
+		},
+		links: map[string]string{
+			first:  `<` + second + `>; rel="next", <` + second + `>; rel="last", <https://www.zotero.org/u/42>; rel="alternate"`,
+			second: `<` + first + `>; rel="first", <https://www.zotero.org/u/42>; rel="alternate"`,
+		},
+		total: "3",
+		reqs:  &reqs,
+	})
+	defer SetHTTPClient(&http.Client{})
+
+	items, err := FetchItems(context.Background(), "42", "secret", "ABCD")
+	if err != nil {
+		t.Fatalf("FetchItems: %v", err)
+	}
+	if len(items) != 3 || !strings.Contains(string(items[2]), `"Three"`) {
+		t.Fatalf("items: %d %s", len(items), items)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("want 2 requests, got %d", len(reqs))
+	}
+	if reqs[0].Header.Get("Zotero-API-Key") != "secret" || reqs[0].Header.Get("Zotero-API-Version") != "3" {
+		t.Fatalf("headers: %v", reqs[0].Header)
+	}
+}
+
+func TestFetchItems_StopsAtTotalResults(t *testing.T) {
+	first := ItemsURL("7", "")
+	var reqs []*http.Request
+	SetHTTPClient(pagedHTTP{
+		pages: map[string]string{first: `{"items":[{"id":"7/A","type":"book","title":"Only"}]}`},
+		links: map[string]string{first: `<` + BaseURL + `/users/7/items?start=100>; rel="next"`},
+		total: "1",
+		reqs:  &reqs,
+	})
+	defer SetHTTPClient(&http.Client{})
+
+	items, err := FetchItems(context.Background(), "7", "", "")
+	if err != nil || len(items) != 1 || len(reqs) != 1 {
+		t.Fatalf("items %d, requests %d, err %v", len(items), len(reqs), err)
+	}
+	if reqs[0].Header.Get("Zotero-API-Key") != "" {
+		t.Fatal("no key should be sent for a public library")
+	}
+	if first != "https://api.zotero.org/users/7/items?format=csljson&limit=100" {
+		t.Fatalf("url %q", first)
+	}
+}
+
+func TestFetchItems_HTTPError(t *testing.T) {
+	var reqs []*http.Request
+	SetHTTPClient(pagedHTTP{pages: map[string]string{}, reqs: &reqs})
+	defer SetHTTPClient(&http.Client{})
+	if _, err := FetchItems(context.Background(), "9", "bad", ""); err == nil || !strings.Contains(err.Error(), "zotero: http 404") {
+		t.Fatalf("expected http error, got %v", err)
+	}
+	if _, err := FetchItems(context.Background(), " ", "", ""); err == nil {
+		t.Fatal("expected error without a user id")
+	}
+}